@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"Project/CarDealearship/stores"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// APIKeyHeader carries the api key of partner integrations
+const APIKeyHeader = "X-API-Key"
+
+// TokenVerifier verifies a bearer token and returns its principal
+type TokenVerifier interface {
+	Verify(token string) (Principal, error)
+}
+
+type authenticator struct {
+	tokens TokenVerifier
+	keys   stores.APIKey
+}
+
+// nolint:revive // need not be exported
+// New factory function, tokens is nil when bearer tokens are not accepted
+func New(tokens TokenVerifier, keys stores.APIKey) authenticator {
+	return authenticator{tokens: tokens, keys: keys}
+}
+
+// HashAPIKey returns the hash an api key is stored and looked up by
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Authenticate returns the principal of the request of ctx, identified by a bearer token in the Authorization
+// header or by an api key in the X-API-Key header
+func (a authenticator) Authenticate(ctx *gofr.Context) (Principal, error) {
+	r := ctx.Request()
+
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return a.apiKey(ctx, key)
+	}
+
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return Principal{}, unauthenticated("missing credentials")
+	}
+
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") || strings.TrimSpace(parts[1]) == "" {
+		return Principal{}, unauthenticated("unsupported authorization scheme")
+	}
+
+	if a.tokens == nil {
+		return Principal{}, unauthenticated("bearer tokens are not accepted")
+	}
+
+	p, err := a.tokens.Verify(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return Principal{}, unauthenticated("invalid token: " + err.Error())
+	}
+
+	return p, nil
+}
+
+func (a authenticator) apiKey(ctx *gofr.Context, key string) (Principal, error) {
+	k, err := a.keys.GetAPIKeyByHash(ctx, HashAPIKey(key))
+	if err == sql.ErrNoRows {
+		return Principal{}, unauthenticated("invalid API key")
+	}
+
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if k.RevokedAt != nil {
+		return Principal{}, unauthenticated("API key has been revoked")
+	}
+
+	return Principal{Subject: "apikey:" + k.ID.String(), Name: k.Name, Roles: k.Roles, Method: MethodAPIKey}, nil
+}
+
+func unauthenticated(reason string) error {
+	return &errors.Response{StatusCode: http.StatusUnauthorized, Code: "UNAUTHENTICATED", Reason: reason}
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeVerifier map[string]Principal
+
+func (f fakeVerifier) Verify(token string) (Principal, error) {
+	p, ok := f[token]
+	if !ok {
+		return Principal{}, errors.Error("invalid signature")
+	}
+
+	return p, nil
+}
+
+func TestHashAPIKey(t *testing.T) {
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", HashAPIKey(""))
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKeys := stores.NewMockAPIKey(ctrl)
+	a := New(fakeVerifier{"good": {Subject: "u1", Roles: []string{"admin"}, Method: MethodJWT}}, mockKeys)
+
+	id := uuid.New()
+	revoked := time.Now()
+
+	testCases := []struct {
+		desc     string
+		headers  map[string]string
+		expected Principal
+		err      error
+		mock     []*gomock.Call
+	}{
+		{
+			desc: "bearer token", headers: map[string]string{"Authorization": "Bearer good"},
+			expected: Principal{Subject: "u1", Roles: []string{"admin"}, Method: MethodJWT},
+		},
+		{
+			desc: "invalid token", headers: map[string]string{"Authorization": "bearer bad"},
+			err: unauthenticated("invalid token: invalid signature"),
+		},
+		{
+			desc: "basic auth", headers: map[string]string{"Authorization": "Basic dTpw"},
+			err: unauthenticated("unsupported authorization scheme"),
+		},
+		{
+			desc: "no credentials", err: unauthenticated("missing credentials"),
+		},
+		{
+			desc: "api key", headers: map[string]string{APIKeyHeader: "k1"},
+			expected: Principal{Subject: "apikey:" + id.String(), Name: "crm", Roles: []string{"sales"},
+				Method: MethodAPIKey},
+			mock: []*gomock.Call{mockKeys.EXPECT().GetAPIKeyByHash(gomock.Any(), HashAPIKey("k1")).
+				Return(models.APIKey{ID: id, Name: "crm", Roles: []string{"sales"}}, nil)},
+		},
+		{
+			desc: "unknown api key", headers: map[string]string{APIKeyHeader: "k2"},
+			err: unauthenticated("invalid API key"),
+			mock: []*gomock.Call{mockKeys.EXPECT().GetAPIKeyByHash(gomock.Any(), HashAPIKey("k2")).
+				Return(models.APIKey{}, sql.ErrNoRows)},
+		},
+		{
+			desc: "revoked api key", headers: map[string]string{APIKeyHeader: "k3"},
+			err: unauthenticated("API key has been revoked"),
+			mock: []*gomock.Call{mockKeys.EXPECT().GetAPIKeyByHash(gomock.Any(), HashAPIKey("k3")).
+				Return(models.APIKey{ID: id, RevokedAt: &revoked}, nil)},
+		},
+		{
+			desc: "api key lookup error", headers: map[string]string{APIKeyHeader: "k4"},
+			err: errors.Error("db error"),
+			mock: []*gomock.Call{mockKeys.EXPECT().GetAPIKeyByHash(gomock.Any(), HashAPIKey("k4")).
+				Return(models.APIKey{}, errors.Error("db error"))},
+		},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "/cars", nil)
+		for k, v := range tc.headers {
+			r.Header.Set(k, v)
+		}
+
+		ctx := gofr.NewContext(nil, request.NewHTTPRequest(r), gofr.New())
+
+		p, err := a.Authenticate(ctx)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.expected, p, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	_, ok := FromContext(ctx)
+	assert.Equal(t, false, ok)
+
+	ctx.Context = WithPrincipal(ctx.Context, Principal{Subject: "u1"})
+
+	p, ok := FromContext(ctx)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "u1", p.Subject)
+}
@@ -0,0 +1,268 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// leeway is the clock skew tolerated on the exp and nbf claims
+const leeway = 30 * time.Second
+
+// MinSecretLength is the length below which an HMAC secret is refused, a short one can be brute-forced
+const MinSecretLength = 32
+
+// KeySet holds the public keys of a JWKS by key id, a key without an id is kept under ""
+type KeySet map[string]crypto.PublicKey
+
+// JWTConfig configures the verification of bearer tokens. HS* tokens are verified with Secret and RS*/ES*
+// tokens with Keys, an algorithm whose key is not configured is rejected.
+type JWTConfig struct {
+	Secret   []byte
+	Keys     KeySet
+	Issuer   string
+	Audience string
+}
+
+// Validate reports a Secret that is too short to be safe, the former sample secret included
+func (c JWTConfig) Validate() error {
+	if len(c.Secret) > 0 && len(c.Secret) < MinSecretLength {
+		return errors.Error("the JWT secret is shorter than " + strconv.Itoa(MinSecretLength) + " bytes")
+	}
+
+	return nil
+}
+
+type jwtVerifier struct {
+	config JWTConfig
+	parser *jwt.Parser
+	now    func() time.Time
+}
+
+// nolint:revive // need not be exported
+// NewJWTVerifier factory function
+func NewJWTVerifier(config JWTConfig) jwtVerifier {
+	// the claims are checked by the verifier, which tolerates a clock skew
+	return jwtVerifier{config: config, parser: jwt.NewParser(jwt.WithoutClaimsValidation()), now: time.Now}
+}
+
+type jwtClaims struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+// Verify checks the signature and the claims of a compact serialized JWT and returns its principal
+func (v jwtVerifier) Verify(token string) (Principal, error) {
+	var c jwtClaims
+
+	if _, err := v.parser.ParseWithClaims(token, &c, v.keyFunc); err != nil {
+		return Principal{}, tokenError(err)
+	}
+
+	if err := v.checkClaims(&c); err != nil {
+		return Principal{}, err
+	}
+
+	return Principal{Subject: c.Subject, Name: c.Name, Roles: c.Roles, Method: MethodJWT}, nil
+}
+
+// keyFunc returns the key a token is verified with: the secret for HS* tokens and the key of its kid for RS*
+// and ES* tokens. The token is then only valid when the key has the type its algorithm expects.
+func (v jwtVerifier) keyFunc(t *jwt.Token) (interface{}, error) {
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if len(v.config.Secret) > 0 {
+			return v.config.Secret, nil
+		}
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		kid, _ := t.Header["kid"].(string)
+
+		return v.key(kid)
+	}
+
+	return nil, errors.Error("unsupported algorithm " + t.Method.Alg())
+}
+
+// key returns the key with the given id, a token without an id can only be verified by a single key set
+func (v jwtVerifier) key(kid string) (crypto.PublicKey, error) {
+	if key, ok := v.config.Keys[kid]; ok {
+		return key, nil
+	}
+
+	if kid == "" && len(v.config.Keys) == 1 {
+		for _, key := range v.config.Keys {
+			return key, nil
+		}
+	}
+
+	return nil, errors.Error("unknown key " + kid)
+}
+
+// tokenError turns an error of the parser into the error a token is rejected with
+func tokenError(err error) error {
+	e, ok := err.(*jwt.ValidationError)
+
+	switch {
+	case !ok || e.Errors&jwt.ValidationErrorMalformed != 0:
+		return errors.Error("malformed token")
+	case e.Errors&jwt.ValidationErrorUnverifiable != 0:
+		// the errors of keyFunc tell which algorithm or key is missing
+		if inner, ok := e.Inner.(errors.Error); ok {
+			return inner
+		}
+
+		return errors.Error("unsupported algorithm")
+	default:
+		return errors.Error("invalid signature")
+	}
+}
+
+func (v jwtVerifier) checkClaims(c *jwtClaims) error {
+	now := v.now()
+
+	if c.ExpiresAt == nil {
+		return errors.Error("token has no expiry")
+	}
+
+	if !c.VerifyExpiresAt(now.Add(-leeway), true) {
+		return errors.Error("token expired")
+	}
+
+	if !c.VerifyNotBefore(now.Add(leeway), false) {
+		return errors.Error("token not yet valid")
+	}
+
+	if c.Subject == "" {
+		return errors.Error("token has no subject")
+	}
+
+	if v.config.Issuer != "" && !c.VerifyIssuer(v.config.Issuer, true) {
+		return errors.Error("unexpected issuer")
+	}
+
+	if v.config.Audience != "" && !c.VerifyAudience(v.config.Audience, true) {
+		return errors.Error("unexpected audience")
+	}
+
+	return nil
+}
+
+// LoadJWKS reads the public keys of a JWKS file
+func LoadJWKS(path string) (KeySet, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseJWKS(b)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS returns the RSA and EC signing keys of a JWKS document, keys of other types are skipped
+func ParseJWKS(b []byte) (KeySet, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	keys := make(KeySet, len(doc.Keys))
+
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		var (
+			key crypto.PublicKey
+			err error
+		)
+
+		switch k.Kty {
+		case "RSA":
+			key, err = rsaKey(k)
+		case "EC":
+			key, err = ecKey(k)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, errors.Error("invalid key " + k.Kid + ": " + err.Error())
+		}
+
+		keys[k.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func rsaKey(k jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	exp := new(big.Int).SetBytes(e)
+	if len(n) == 0 || !exp.IsInt64() || exp.Int64() < 3 || exp.Int64() > 1<<31-1 {
+		return nil, errors.Error("malformed RSA key")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+}
+
+func ecKey(k jwk) (*ecdsa.PublicKey, error) {
+	curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+
+	curve, ok := curves[k.Crv]
+	if !ok {
+		return nil, errors.Error("unsupported curve " + k.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+
+	// nolint:staticcheck // the point is only validated, not used for arithmetic
+	if !curve.IsOnCurve(key.X, key.Y) {
+		return nil, errors.Error("EC point is not on the curve")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func encodeJSON(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b64(b)
+}
+
+// signToken builds a JWT, key is a []byte secret for HS256, an *rsa.PrivateKey for RS256 or an
+// *ecdsa.PrivateKey for ES256
+func signToken(t *testing.T, alg, kid string, claims map[string]interface{}, key interface{}) string {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(alg), jwt.MapClaims(claims))
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	doc := map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "r1", "use": "sig", "n": b64(rsaKey.N.Bytes()),
+			"e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "e1", "crv": "P-256", "x": b64(ecKey.X.Bytes()), "y": b64(ecKey.Y.Bytes())},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": b64(rsaKey.N.Bytes()), "e": "AQAB"},
+		{"kty": "oct", "kid": "o1", "k": "c2VjcmV0"},
+	}}
+	b, _ := json.Marshal(doc)
+
+	keys, err := ParseJWKS(b)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(keys))
+	assert.Equal(t, true, rsaKey.PublicKey.Equal(keys["r1"]))
+	assert.Equal(t, true, ecKey.PublicKey.Equal(keys["e1"]))
+
+	_, err = ParseJWKS([]byte(`{"keys":[{"kty":"EC","kid":"bad","crv":"P-256","x":"AQ","y":"AQ"}]}`))
+	assert.Equal(t, errors.Error("invalid key bad: EC point is not on the curve"), err)
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	secret := []byte("top-secret")
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	v := NewJWTVerifier(JWTConfig{
+		Secret:   secret,
+		Keys:     KeySet{"r1": &rsaKey.PublicKey, "e1": &ecKey.PublicKey},
+		Issuer:   "https://id.example.com",
+		Audience: "car-dealership",
+	})
+	v.now = func() time.Time { return now }
+
+	claims := func(changes map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{"sub": "u1", "name": "Asha", "roles": []string{"sales"},
+			"iss": "https://id.example.com", "aud": []string{"crm", "car-dealership"}, "exp": now.Unix() + 60}
+		for k, v := range changes {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+
+			c[k] = v
+		}
+
+		return c
+	}
+
+	principal := Principal{Subject: "u1", Name: "Asha", Roles: []string{"sales"}, Method: MethodJWT}
+
+	testCases := []struct {
+		desc     string
+		token    string
+		expected Principal
+		err      error
+	}{
+		{desc: "HS256", token: signToken(t, "HS256", "", claims(nil), secret), expected: principal},
+		{desc: "RS256", token: signToken(t, "RS256", "r1", claims(nil), rsaKey), expected: principal},
+		{desc: "ES256", token: signToken(t, "ES256", "e1", claims(nil), ecKey), expected: principal},
+		{desc: "single audience", token: signToken(t, "HS256", "", claims(map[string]interface{}{"aud": "car-dealership"}),
+			secret), expected: principal},
+		{desc: "expired within leeway", token: signToken(t, "HS256", "",
+			claims(map[string]interface{}{"exp": now.Unix() - 10}), secret), expected: principal},
+		{desc: "expired", token: signToken(t, "HS256", "", claims(map[string]interface{}{"exp": now.Unix() - 60}),
+			secret), err: errors.Error("token expired")},
+		{desc: "no expiry", token: signToken(t, "HS256", "", claims(map[string]interface{}{"exp": nil}), secret),
+			err: errors.Error("token has no expiry")},
+		{desc: "not yet valid", token: signToken(t, "HS256", "", claims(map[string]interface{}{"nbf": now.Unix() + 60}),
+			secret), err: errors.Error("token not yet valid")},
+		{desc: "no subject", token: signToken(t, "HS256", "", claims(map[string]interface{}{"sub": nil}), secret),
+			err: errors.Error("token has no subject")},
+		{desc: "wrong issuer", token: signToken(t, "HS256", "", claims(map[string]interface{}{"iss": "evil"}), secret),
+			err: errors.Error("unexpected issuer")},
+		{desc: "wrong audience", token: signToken(t, "HS256", "", claims(map[string]interface{}{"aud": "crm"}), secret),
+			err: errors.Error("unexpected audience")},
+		{desc: "wrong secret", token: signToken(t, "HS256", "", claims(nil), []byte("guess")),
+			err: errors.Error("invalid signature")},
+		{desc: "unknown key", token: signToken(t, "RS256", "r2", claims(nil), rsaKey), err: errors.Error("unknown key r2")},
+		{desc: "RSA key with ES algorithm", token: signToken(t, "ES256", "r1", claims(nil), ecKey),
+			err: errors.Error("invalid signature")},
+		{desc: "alg none", token: encodeJSON(t, map[string]string{"alg": "none"}) + "." + encodeJSON(t, claims(nil)) + ".",
+			err: errors.Error("unsupported algorithm none")},
+		{desc: "malformed", token: "abc.def", err: errors.Error("malformed token")},
+	}
+
+	for i, tc := range testCases {
+		p, err := v.Verify(tc.token)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.expected, p, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestVerifyWithoutSecret(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	v := NewJWTVerifier(JWTConfig{Keys: KeySet{"": &rsaKey.PublicKey}})
+
+	claims := map[string]interface{}{"sub": "u1", "exp": time.Now().Unix() + 60}
+
+	// a public key must not be usable as an HMAC secret
+	_, err := v.Verify(signToken(t, "HS256", "", claims, []byte("")))
+	assert.Equal(t, errors.Error("unsupported algorithm HS256"), err)
+
+	p, err := v.Verify(signToken(t, "RS256", "", claims, rsaKey))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "u1", p.Subject)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		secret string
+		err    error
+	}{
+		{desc: "no secret", secret: ""},
+		{desc: "long secret", secret: strings.Repeat("k", MinSecretLength)},
+		{desc: "short secret", secret: "secret", err: errors.Error("the JWT secret is shorter than 32 bytes")},
+		{desc: "former sample secret", secret: "dev-only-secret-change-me",
+			err: errors.Error("the JWT secret is shorter than 32 bytes")},
+	}
+
+	for i, tc := range testCases {
+		err := JWTConfig{Secret: []byte(tc.secret)}.Validate()
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
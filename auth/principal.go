@@ -0,0 +1,30 @@
+package auth
+
+import "context"
+
+// Authentication methods of a principal
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Subject string
+	Name    string
+	Roles   []string
+	Method  string
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal of the request ctx belongs to. A *gofr.Context can be passed as is.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// Permission is an operation a role can be allowed to perform
+type Permission string
+
+// Permissions of the inventory
+const (
+	ReadCars   Permission = "cars:read"
+	WriteCars  Permission = "cars:write"
+	DeleteCars Permission = "cars:delete"
+	ImportCars Permission = "cars:import"
+	ExportCars Permission = "cars:export"
+	ReadCost   Permission = "cars:cost:read"
+	WriteCost  Permission = "cars:cost:write"
+	WriteMedia Permission = "media:write"
+
+	ReadWebhooks  Permission = "webhooks:read"
+	WriteWebhooks Permission = "webhooks:write"
+)
+
+// Roles, each one is allowed what the one before it is and more. Admin is allowed everything.
+const (
+	RoleViewer  = "viewer"
+	RoleSales   = "sales"
+	RoleManager = "manager"
+	RoleAdmin   = "admin"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleViewer:  {ReadCars},
+	RoleSales:   {ReadCars, WriteCars, WriteMedia},
+	RoleManager: {ReadCars, WriteCars, WriteMedia, DeleteCars, ImportCars, ExportCars, ReadCost, WriteCost},
+}
+
+// Allows reports whether one of the roles of p grants the permission
+func (p Principal) Allows(permission Permission) bool {
+	for _, role := range p.Roles {
+		if role == RoleAdmin {
+			return true
+		}
+
+		for _, granted := range rolePermissions[role] {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Can reports whether the principal of ctx has the permission, a request without one has none
+func Can(ctx context.Context, permission Permission) bool {
+	p, ok := FromContext(ctx)
+	return ok && p.Allows(permission)
+}
+
+// Check returns a 403 error naming the permission when the principal of ctx does not have it
+func Check(ctx context.Context, permission Permission) error {
+	if Can(ctx, permission) {
+		return nil
+	}
+
+	return &errors.Response{
+		StatusCode: http.StatusForbidden,
+		Code:       "FORBIDDEN",
+		Reason:     "missing permission " + string(permission),
+		Detail:     map[string]string{"permission": string(permission)},
+	}
+}
+
+// Require wraps a handler so that it is only run for principals with the permission
+func Require(permission Permission, h gofr.Handler) gofr.Handler {
+	return func(ctx *gofr.Context) (interface{}, error) {
+		if err := Check(ctx, permission); err != nil {
+			return nil, err
+		}
+
+		return h(ctx)
+	}
+}
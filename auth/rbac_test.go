@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllows(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		roles      []string
+		permission Permission
+		expected   bool
+	}{
+		{desc: "viewer reads", roles: []string{RoleViewer}, permission: ReadCars, expected: true},
+		{desc: "viewer cannot write", roles: []string{RoleViewer}, permission: WriteCars, expected: false},
+		{desc: "sales writes", roles: []string{RoleSales}, permission: WriteCars, expected: true},
+		{desc: "sales cannot delete", roles: []string{RoleSales}, permission: DeleteCars, expected: false},
+		{desc: "sales cannot read cost", roles: []string{RoleSales}, permission: ReadCost, expected: false},
+		{desc: "manager deletes", roles: []string{RoleManager}, permission: DeleteCars, expected: true},
+		{desc: "manager changes cost", roles: []string{RoleManager}, permission: WriteCost, expected: true},
+		{desc: "admin is allowed anything", roles: []string{RoleAdmin}, permission: "webhooks:write", expected: true},
+		{desc: "roles add up", roles: []string{"unknown", RoleViewer, RoleSales}, permission: WriteMedia, expected: true},
+		{desc: "no roles", permission: ReadCars, expected: false},
+	}
+
+	for i, tc := range testCases {
+		p := Principal{Subject: "u1", Roles: tc.roles}
+
+		assert.Equal(t, tc.expected, p.Allows(tc.permission), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	h := Require(DeleteCars, func(ctx *gofr.Context) (interface{}, error) {
+		return "Deleted successfully", nil
+	})
+
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	resp, err := h(ctx)
+	assert.Equal(t, nil, resp)
+	assert.Equal(t, &errors.Response{StatusCode: http.StatusForbidden, Code: "FORBIDDEN",
+		Reason: "missing permission cars:delete", Detail: map[string]string{"permission": "cars:delete"}}, err)
+
+	ctx.Context = WithPrincipal(ctx.Context, Principal{Subject: "u1", Roles: []string{RoleSales}})
+
+	_, err = h(ctx)
+	assert.Equal(t, Check(ctx, DeleteCars), err)
+
+	ctx.Context = WithPrincipal(ctx.Context, Principal{Subject: "u2", Roles: []string{RoleManager}})
+
+	resp, err = h(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "Deleted successfully", resp)
+}
@@ -0,0 +1,155 @@
+// Package cache keeps copies of datastore reads, in process or in redis. Values are json encoded, a key missed by
+// many callers at once is loaded once and reads fail open: a backend error is logged and the value is loaded.
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// loadTimeout bounds a load, which does not end with the request of the caller running it
+const loadTimeout = 10 * time.Second
+
+// Metrics counting the reads of every entity, they are registered in main
+const (
+	HitsMetric   = "cache_hits_total"
+	MissesMetric = "cache_misses_total"
+)
+
+// Metrics counts the hits and misses, gofr's metrics satisfy it
+type Metrics interface {
+	IncCounter(name string, labels ...string) error
+}
+
+// Backend keeps encoded values for a while
+type Backend interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Cache reads through a backend
+type Cache struct {
+	backend Backend
+	metrics Metrics
+	group   group
+}
+
+// New factory function, m can be nil
+func New(b Backend, m Metrics) *Cache {
+	return &Cache{backend: b, metrics: m, group: group{calls: make(map[string]*call)}}
+}
+
+// Ping reads a key from the backend to check that it can be reached
+func (c *Cache) Ping(ctx context.Context) error {
+	_, _, err := c.backend.Get(ctx, "health")
+	return err
+}
+
+// Fetch reads the value of key into dst. On a miss the value is loaded with load, once for all the callers
+// missing the key at the same time, and kept for ttl. entity labels the hit and miss metrics. load gets a copy
+// of ctx detached from its request, so that the callers waiting for the load do not fail when the request of
+// the caller running it is cancelled.
+func (c *Cache) Fetch(ctx *gofr.Context, entity, key string, ttl time.Duration, dst interface{},
+	load func(ctx *gofr.Context) (interface{}, error)) error {
+	b, ok, err := c.backend.Get(ctx, key)
+	if err != nil {
+		ctx.Logger.Warnf("error in reading %v from the cache: %v", key, err)
+	}
+
+	if ok && json.Unmarshal(b, dst) == nil {
+		c.count(HitsMetric, entity)
+		return nil
+	}
+
+	c.count(MissesMetric, entity)
+
+	b, err = c.group.do(key, func() ([]byte, error) {
+		ctx, cancel := detach(ctx, loadTimeout)
+		defer cancel()
+
+		v, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = c.backend.Set(ctx, key, b, ttl); err != nil {
+			ctx.Logger.Warnf("error in writing %v to the cache: %v", key, err)
+		}
+
+		return b, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, dst)
+}
+
+// Delete removes the keys, a backend error is logged as the values expire anyway
+func (c *Cache) Delete(ctx *gofr.Context, keys ...string) {
+	if err := c.backend.Delete(ctx, keys...); err != nil {
+		ctx.Logger.Errorf("error in deleting %v from the cache: %v", keys, err)
+	}
+}
+
+// Version returns the version kept under key, a new one when there is none. Keys built with a version are all
+// invalidated at once by deleting it.
+func (c *Cache) Version(ctx *gofr.Context, key string, ttl time.Duration) string {
+	b, ok, err := c.backend.Get(ctx, key)
+	if err == nil && ok {
+		return string(b)
+	}
+
+	v := make([]byte, 8)
+	_, _ = rand.Read(v)
+	version := hex.EncodeToString(v)
+
+	if err = c.backend.Set(ctx, key, []byte(version), ttl); err != nil {
+		ctx.Logger.Warnf("error in writing %v to the cache: %v", key, err)
+	}
+
+	return version
+}
+
+// detach returns a copy of ctx that keeps its values, like its span, but ends after timeout rather than with ctx
+func detach(ctx *gofr.Context, timeout time.Duration) (*gofr.Context, context.CancelFunc) {
+	parent := ctx.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	var cancel context.CancelFunc
+
+	c := *ctx
+	c.Context, cancel = context.WithTimeout(valuesOf{parent}, timeout)
+
+	return &c, cancel
+}
+
+// valuesOf is a context with the values of another one but without its deadline and cancellation
+type valuesOf struct {
+	context.Context
+}
+
+func (valuesOf) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (valuesOf) Done() <-chan struct{} { return nil }
+
+func (valuesOf) Err() error { return nil }
+
+func (c *Cache) count(metric, entity string) {
+	if c.metrics != nil {
+		_ = c.metrics.IncCounter(metric, entity)
+	}
+}
@@ -0,0 +1,207 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+)
+
+// counter records the counters incremented by entity
+type counter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (c *counter) IncCounter(name string, labels ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counts[name+" "+labels[0]]++
+
+	return nil
+}
+
+// failing is a backend that is down
+type failing struct{}
+
+func (failing) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errors.Error("connection refused")
+}
+
+func (failing) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.Error("connection refused")
+}
+
+func (failing) Delete(context.Context, ...string) error {
+	return errors.Error("connection refused")
+}
+
+func TestFetch(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	metrics := &counter{counts: make(map[string]int)}
+	c := New(NewLRU(10), metrics)
+
+	loads := 0
+	load := func(*gofr.Context) (interface{}, error) {
+		loads++
+		return models.Car{Name: "X5", Brand: "BMW"}, nil
+	}
+
+	var car models.Car
+
+	assert.Equal(t, nil, c.Fetch(ctx, "car", "car:1", time.Minute, &car, load))
+	assert.Equal(t, models.Car{Name: "X5", Brand: "BMW"}, car)
+
+	car = models.Car{}
+
+	assert.Equal(t, nil, c.Fetch(ctx, "car", "car:1", time.Minute, &car, load))
+	assert.Equal(t, models.Car{Name: "X5", Brand: "BMW"}, car, "the value is read from the cache")
+	assert.Equal(t, 1, loads)
+
+	c.Delete(ctx, "car:1")
+
+	assert.Equal(t, nil, c.Fetch(ctx, "car", "car:1", time.Minute, &car, load))
+	assert.Equal(t, 2, loads, "a deleted value is loaded again")
+
+	err := c.Fetch(ctx, "car", "car:2", time.Minute, &car, func(*gofr.Context) (interface{}, error) {
+		return nil, errors.Error("sql: no rows in result set")
+	})
+	assert.Equal(t, errors.Error("sql: no rows in result set"), err)
+
+	assert.Equal(t, map[string]int{"cache_hits_total car": 1, "cache_misses_total car": 3}, metrics.counts)
+}
+
+func TestFetchBackendDown(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	c := New(failing{}, nil)
+
+	var brand string
+
+	err := c.Fetch(ctx, "car", "car:1", time.Minute, &brand,
+		func(*gofr.Context) (interface{}, error) { return "BMW", nil })
+
+	assert.Equal(t, nil, err, "reads fail open")
+	assert.Equal(t, "BMW", brand)
+
+	c.Delete(ctx, "car:1")
+
+	assert.NotEqual(t, c.Version(ctx, "cars:version", time.Hour), c.Version(ctx, "cars:version", time.Hour))
+}
+
+func TestFetchLoadsOnce(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	c := New(NewLRU(10), nil)
+
+	var (
+		mu    sync.Mutex
+		loads int
+		wg    sync.WaitGroup
+	)
+
+	release := make(chan struct{})
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			var n int
+
+			_ = c.Fetch(ctx, "car", "car:1", time.Minute, &n, func(*gofr.Context) (interface{}, error) {
+				mu.Lock()
+				loads++
+				mu.Unlock()
+
+				<-release
+
+				return 42, nil
+			})
+
+			assert.Equal(t, 42, n)
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, 1, loads, "callers missing the same key wait for a single load")
+}
+
+// TestFetchDetached tests that a caller waiting for the load of another one gets its value even when the
+// request of the caller running the load is cancelled
+func TestFetchDetached(t *testing.T) {
+	c := New(NewLRU(10), nil)
+
+	leader := gofr.NewContext(nil, nil, gofr.New())
+	parent, cancel := context.WithCancel(context.WithValue(context.TODO(), ctxKey{}, "request"))
+	leader.Context = parent
+
+	started, release := make(chan struct{}), make(chan struct{})
+	loaded := make(chan error, 1)
+
+	go func() {
+		var n int
+
+		loaded <- c.Fetch(leader, "car", "car:1", time.Minute, &n, func(ctx *gofr.Context) (interface{}, error) {
+			close(started)
+			<-release
+
+			_, deadline := ctx.Deadline()
+			assert.True(t, deadline, "the load is bounded")
+			assert.Equal(t, "request", ctx.Value(ctxKey{}), "the load keeps the values of the request")
+
+			return 42, ctx.Err()
+		})
+	}()
+
+	<-started
+
+	waiter := make(chan int)
+
+	go func() {
+		var n int
+
+		_ = c.Fetch(gofr.NewContext(nil, nil, gofr.New()), "car", "car:1", time.Minute, &n,
+			func(*gofr.Context) (interface{}, error) { return 0, errors.Error("loaded twice") })
+
+		waiter <- n
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	close(release)
+
+	assert.Equal(t, 42, <-waiter)
+	assert.Equal(t, nil, <-loaded)
+}
+
+type ctxKey struct{}
+
+func TestVersion(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	c := New(NewLRU(10), nil)
+
+	v := c.Version(ctx, "cars:version", time.Hour)
+
+	assert.Len(t, v, 16)
+	assert.Equal(t, v, c.Version(ctx, "cars:version", time.Hour))
+
+	c.Delete(ctx, "cars:version")
+
+	assert.NotEqual(t, v, c.Version(ctx, "cars:version", time.Hour), "a deleted version is replaced")
+}
+
+// TestPing to test the backend is reached by a ping
+func TestPing(t *testing.T) {
+	assert.Equal(t, nil, New(NewLRU(10), nil).Ping(context.Background()))
+	assert.Equal(t, errors.Error("connection refused"), New(failing{}, nil).Ping(context.Background()))
+}
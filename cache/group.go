@@ -0,0 +1,43 @@
+package cache
+
+import "sync"
+
+type call struct {
+	done  chan struct{}
+	value []byte
+	err   error
+}
+
+// group runs a function once for the callers asking for the same key at the same time
+type group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// do runs fn unless a call for key is running, in which case it waits for that call and returns its result
+func (g *group) do(key string, fn func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+
+		return c.value, c.err
+	}
+
+	c := &call{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+
+		close(c.done)
+	}()
+
+	c.value, c.err = fn()
+
+	return c.value, c.err
+}
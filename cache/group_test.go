@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupDo(t *testing.T) {
+	g := group{calls: make(map[string]*call)}
+
+	var inner []byte
+
+	// a call for another key is not held up by a running one
+	outer, err := g.do("car:1", func() ([]byte, error) {
+		inner, _ = g.do("car:2", func() ([]byte, error) { return []byte("2"), nil })
+		return []byte("1"), nil
+	})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []byte("1"), outer)
+	assert.Equal(t, []byte("2"), inner)
+
+	_, err = g.do("car:1", func() ([]byte, error) { return nil, errors.Error("db down") })
+	assert.Equal(t, errors.Error("db down"), err)
+	assert.Empty(t, g.calls, "finished calls are forgotten")
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+type lru struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+// nolint:revive // need not be exported
+// NewLRU factory function, at most capacity values are kept in process and the least recently used go first
+func NewLRU(capacity int) *lru {
+	return &lru{capacity: capacity, entries: make(map[string]*list.Element), order: list.New(), now: time.Now}
+}
+
+// Get returns the value of key unless it has expired
+func (l *lru) Get(_ context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	e := el.Value.(*entry)
+	if !l.now().Before(e.expires) {
+		l.remove(el)
+		return nil, false, nil
+	}
+
+	l.order.MoveToFront(el)
+
+	return e.value, true, nil
+}
+
+// Set keeps value under key for ttl
+func (l *lru) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expires := l.now().Add(ttl)
+
+	if el, ok := l.entries[key]; ok {
+		e := el.Value.(*entry)
+		e.value, e.expires = value, expires
+		l.order.MoveToFront(el)
+
+		return nil
+	}
+
+	l.entries[key] = l.order.PushFront(&entry{key: key, value: value, expires: expires})
+
+	for l.order.Len() > l.capacity {
+		l.remove(l.order.Back())
+	}
+
+	return nil
+}
+
+// Delete removes the keys
+func (l *lru) Delete(_ context.Context, keys ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := l.entries[key]; ok {
+			l.remove(el)
+		}
+	}
+
+	return nil
+}
+
+func (l *lru) remove(el *list.Element) {
+	l.order.Remove(el)
+	delete(l.entries, el.Value.(*entry).key)
+}
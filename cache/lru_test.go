@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	l := NewLRU(2)
+	l.now = func() time.Time { return now }
+
+	_ = l.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = l.Set(ctx, "b", []byte("2"), time.Second)
+
+	v, ok, err := l.Get(ctx, "a")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, []byte("1"), v)
+
+	// b is the least recently used
+	_ = l.Set(ctx, "c", []byte("3"), time.Minute)
+
+	_, ok, _ = l.Get(ctx, "b")
+	assert.Equal(t, false, ok, "the least recently used value is evicted")
+
+	_ = l.Set(ctx, "a", []byte("4"), time.Second)
+
+	v, _, _ = l.Get(ctx, "a")
+	assert.Equal(t, []byte("4"), v, "a value is replaced")
+
+	now = now.Add(time.Second)
+
+	_, ok, _ = l.Get(ctx, "a")
+	assert.Equal(t, false, ok, "an expired value is not returned")
+	assert.Equal(t, 1, l.order.Len())
+
+	assert.Equal(t, nil, l.Delete(ctx, "c", "missing"))
+
+	_, ok, _ = l.Get(ctx, "c")
+	assert.Equal(t, false, ok)
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const redisPrefix = "cache:"
+
+type redisBackend struct {
+	client redis.Cmdable
+}
+
+// nolint:revive // need not be exported
+// NewRedis factory function, the values are shared by every instance using the same redis
+func NewRedis(client redis.Cmdable) redisBackend {
+	return redisBackend{client: client}
+}
+
+// Get returns the value of key, redis drops the expired ones
+func (r redisBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	b, err := r.client.Get(ctx, redisPrefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return b, true, nil
+}
+
+// Set keeps value under key for ttl
+func (r redisBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, redisPrefix+key, value, ttl).Err()
+}
+
+// Delete removes the keys
+func (r redisBackend) Delete(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, len(keys))
+	for i := range keys {
+		prefixed[i] = redisPrefix + keys[i]
+	}
+
+	return r.client.Del(ctx, prefixed...).Err()
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedis(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+
+	defer client.Close()
+
+	ctx := context.Background()
+	r := NewRedis(client)
+
+	_, ok, err := r.Get(ctx, "car:1")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, ok)
+
+	assert.Equal(t, nil, r.Set(ctx, "car:1", []byte(`{"Brand":"BMW"}`), time.Minute))
+	assert.Equal(t, time.Minute, s.TTL("cache:car:1"), "keys are prefixed")
+
+	v, ok, err := r.Get(ctx, "car:1")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, []byte(`{"Brand":"BMW"}`), v)
+
+	s.FastForward(time.Minute)
+
+	_, ok, _ = r.Get(ctx, "car:1")
+	assert.Equal(t, false, ok, "redis drops expired values")
+
+	_ = r.Set(ctx, "car:2", []byte("{}"), time.Minute)
+
+	assert.Equal(t, nil, r.Delete(ctx, "car:2", "car:3"))
+	assert.Equal(t, false, s.Exists("cache:car:2"))
+
+	s.Close()
+
+	_, _, err = r.Get(ctx, "car:1")
+	assert.Error(t, err)
+}
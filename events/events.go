@@ -0,0 +1,16 @@
+// Package events publishes the car lifecycle events recorded in the outbox by the car service. The relay
+// reads the events of committed writes from the outbox and hands them to a Publisher, so an event is never
+// lost nor published for a write that was rolled back. Delivery is at least once: consumers deduplicate on
+// the id of the event.
+package events
+
+import (
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// Publisher delivers an event to the downstream systems
+type Publisher interface {
+	Publish(ctx *gofr.Context, event models.Event) error
+}
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+type fanout struct {
+	publishers []Publisher
+}
+
+// nolint:revive // need not be exported
+// NewFanout factory function, the publishers are called in order. An event failing on one of them is published
+// again to all of them, so publishers writing to the database in the transaction of the relay, which is rolled
+// back along with the event, go last.
+func NewFanout(publishers ...Publisher) fanout {
+	return fanout{publishers: publishers}
+}
+
+// Publish publishes the event with every publisher, stopping at the first error
+func (f fanout) Publish(ctx *gofr.Context, event models.Event) error {
+	for _, p := range f.publishers {
+		if err := p.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
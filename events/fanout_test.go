@@ -0,0 +1,29 @@
+package events
+
+import (
+	"testing"
+
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFanoutPublish(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	event := models.Event{ID: uuid.New(), Type: models.EventCarCreated, CarID: uuid.New()}
+
+	first, last := NewMemory(), NewMemory()
+
+	assert.Equal(t, nil, NewFanout(first, last).Publish(ctx, event))
+	assert.Equal(t, []models.Event{event}, first.Events())
+	assert.Equal(t, []models.Event{event}, last.Events())
+
+	failing := failingPublisher{memory: NewMemory(), carID: event.CarID}
+	after := NewMemory()
+
+	assert.Equal(t, errors.Error("broker down"), NewFanout(failing, after).Publish(ctx, event))
+	assert.Empty(t, after.Events(), "the publishers after a failing one are not called")
+}
@@ -0,0 +1,239 @@
+package events
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// HubConfig configures a Hub
+type HubConfig struct {
+	// BatchSize is the number of events read from the outbox at a time
+	BatchSize int
+	// Buffer is the number of events kept for a subscriber that has not received them yet, a subscriber falling
+	// further behind is dropped
+	Buffer int
+	// GapTimeout is how long the hub waits for an event missing from the outbox, recorded by a transaction that
+	// has not committed yet, before going on without it
+	GapTimeout time.Duration
+}
+
+// Filter selects the events of the cars of a brand or of a dealership, an empty field selects every car
+type Filter struct {
+	Brand        string
+	DealershipID string
+}
+
+// Subscription receives the events matching its filter. Events is closed once the subscription ends, Dropped
+// then tells whether the hub dropped it for falling behind.
+type Subscription struct {
+	Events  <-chan models.Event
+	events  chan models.Event
+	filter  Filter
+	dropped bool
+}
+
+// Hub follows the outbox and broadcasts the events recorded in it to its subscribers. Every instance runs a hub
+// of its own and the position of an event is the same for all of them, so a client can resume on any instance.
+type Hub struct {
+	outbox stores.Outbox
+	config HubConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	started  bool
+	last     int64
+	gapSince time.Time
+	subs     map[*Subscription]struct{}
+}
+
+// NewHub factory function
+func NewHub(o stores.Outbox, config HubConfig) *Hub {
+	return &Hub{outbox: o, config: config, now: time.Now, subs: make(map[*Subscription]struct{})}
+}
+
+// Subscribe returns a subscription to the events matching f along with the position of the last event before it.
+// Every event after that position is sent to the subscription.
+func (h *Hub) Subscribe(f Filter) (*Subscription, int64) {
+	events := make(chan models.Event, h.config.Buffer)
+	s := &Subscription{Events: events, events: events, filter: f}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.subs[s] = struct{}{}
+
+	return s, h.last
+}
+
+// Unsubscribe ends a subscription, it can be called more than once
+func (h *Hub) Unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(s)
+}
+
+// Dropped reports whether the subscription ended because its subscriber fell behind
+func (h *Hub) Dropped(s *Subscription) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return s.dropped
+}
+
+// Replay calls fn with the events matching f between the positions after and upTo, for a subscriber resuming
+// from the event at position after
+func (h *Hub) Replay(ctx *gofr.Context, f Filter, after, upTo int64, fn func(models.Event) error) error {
+	for after < upTo {
+		events, err := h.outbox.GetEventsAfter(ctx, after, h.config.BatchSize)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		for i := range events {
+			if events[i].Seq > upTo {
+				return nil
+			}
+
+			if f.Matches(&events[i]) {
+				if err = fn(events[i]); err != nil {
+					return err
+				}
+			}
+
+			after = events[i].Seq
+		}
+	}
+
+	return nil
+}
+
+// Poll broadcasts the events recorded since the last poll and returns how many there were. The first poll only
+// finds the position of the latest event, so that subscribers start with the events that come after it.
+func (h *Hub) Poll(ctx *gofr.Context) (int, error) {
+	h.mu.Lock()
+	started, last := h.started, h.last
+	h.mu.Unlock()
+
+	if !started {
+		seq, err := h.outbox.GetLastSeq(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		h.mu.Lock()
+		h.started, h.last = true, seq
+		h.mu.Unlock()
+
+		return 0, nil
+	}
+
+	total := 0
+
+	for {
+		events, err := h.outbox.GetEventsAfter(ctx, last, h.config.BatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		n := h.broadcast(events)
+		total += n
+
+		if n < h.config.BatchSize {
+			return total, nil
+		}
+
+		last = events[n-1].Seq
+	}
+}
+
+// broadcast sends the events to the subscribers in order and returns how many were sent. It stops at a gap in
+// the positions until GapTimeout has passed, as the missing event can still be committed.
+func (h *Hub) broadcast(events []models.Event) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := range events {
+		if events[i].Seq != h.last+1 {
+			if h.gapSince.IsZero() {
+				h.gapSince = h.now()
+			}
+
+			if h.now().Sub(h.gapSince) < h.config.GapTimeout {
+				return i
+			}
+		}
+
+		h.gapSince = time.Time{}
+		h.last = events[i].Seq
+
+		for s := range h.subs {
+			if !s.filter.Matches(&events[i]) {
+				continue
+			}
+
+			select {
+			case s.events <- events[i]:
+			default:
+				s.dropped = true
+				h.remove(s)
+			}
+		}
+	}
+
+	return len(events)
+}
+
+// remove ends a subscription, h.mu must be held
+func (h *Hub) remove(s *Subscription) {
+	if _, ok := h.subs[s]; ok {
+		delete(h.subs, s)
+		close(s.events)
+	}
+}
+
+// Run polls the outbox every interval until the context of ctx is done
+func (h *Hub) Run(ctx *gofr.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := h.Poll(ctx); err != nil {
+			ctx.Logger.Errorf("error in polling the outbox for the event stream: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Matches reports whether the event is about a car selected by the filter
+func (f Filter) Matches(e *models.Event) bool {
+	if f.Brand == "" && f.DealershipID == "" {
+		return true
+	}
+
+	// every event carries the brand and the dealership of its car, see models.Event
+	var car struct {
+		Brand        string
+		DealershipID *string
+	}
+
+	if err := json.Unmarshal(e.Data, &car); err != nil {
+		return false
+	}
+
+	if f.Brand != "" && f.Brand != car.Brand {
+		return false
+	}
+
+	return f.DealershipID == "" || (car.DealershipID != nil && *car.DealershipID == f.DealershipID)
+}
@@ -0,0 +1,210 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func carEvent(seq int64, eventType string, data interface{}) models.Event {
+	payload, _ := json.Marshal(data)
+
+	return models.Event{Seq: seq, ID: uuid.New(), Type: eventType, CarID: uuid.New(), Data: payload}
+}
+
+// received returns the events waiting in the subscription
+func received(s *Subscription) []int64 {
+	var seqs []int64
+
+	for {
+		select {
+		case e, ok := <-s.Events:
+			if !ok {
+				return seqs
+			}
+
+			seqs = append(seqs, e.Seq)
+		default:
+			return seqs
+		}
+	}
+}
+
+func TestHubPoll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	now := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	h := NewHub(mockOutbox, HubConfig{BatchSize: 2, Buffer: 10, GapTimeout: 2 * time.Second})
+	h.now = func() time.Time { return now }
+
+	bmw := models.Car{Brand: "BMW"}
+	audi := models.Car{Brand: "Audi"}
+
+	mockOutbox.EXPECT().GetLastSeq(ctx).Return(int64(10), nil)
+
+	n, err := h.Poll(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, n, "the first poll starts after the latest event")
+
+	all, from := h.Subscribe(Filter{})
+	bmws, _ := h.Subscribe(Filter{Brand: "BMW"})
+
+	assert.Equal(t, int64(10), from)
+
+	gomock.InOrder(
+		mockOutbox.EXPECT().GetEventsAfter(ctx, int64(10), 2).Return([]models.Event{
+			carEvent(11, models.EventCarCreated, bmw), carEvent(12, models.EventCarCreated, audi)}, nil),
+		mockOutbox.EXPECT().GetEventsAfter(ctx, int64(12), 2).Return([]models.Event{
+			carEvent(13, models.EventCarStatusChanged, models.StatusChange{Brand: "BMW"})}, nil),
+	)
+
+	n, err = h.Poll(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, n, "full batches are followed by the next one")
+	assert.Equal(t, []int64{11, 12, 13}, received(all))
+	assert.Equal(t, []int64{11, 13}, received(bmws))
+
+	// 14 is recorded by a transaction that has not committed yet
+	mockOutbox.EXPECT().GetEventsAfter(ctx, int64(13), 2).Return([]models.Event{
+		carEvent(15, models.EventCarCreated, bmw)}, nil)
+
+	n, _ = h.Poll(ctx)
+	assert.Equal(t, 0, n, "a gap is waited for")
+
+	now = now.Add(time.Second)
+	mockOutbox.EXPECT().GetEventsAfter(ctx, int64(13), 2).Return([]models.Event{
+		carEvent(14, models.EventCarDeleted, audi), carEvent(15, models.EventCarCreated, bmw)}, nil)
+	mockOutbox.EXPECT().GetEventsAfter(ctx, int64(15), 2).Return([]models.Event{
+		carEvent(17, models.EventCarCreated, bmw)}, nil)
+
+	n, _ = h.Poll(ctx)
+	assert.Equal(t, 2, n, "the missing event was committed")
+	assert.Equal(t, []int64{14, 15}, received(all))
+
+	// 16 was rolled back
+	now = now.Add(2 * time.Second)
+	mockOutbox.EXPECT().GetEventsAfter(ctx, int64(15), 2).Return([]models.Event{
+		carEvent(17, models.EventCarCreated, bmw)}, nil)
+
+	n, _ = h.Poll(ctx)
+	assert.Equal(t, 1, n, "a gap is skipped after the gap timeout")
+	assert.Equal(t, []int64{17}, received(all))
+	assert.Equal(t, []int64{15, 17}, received(bmws))
+
+	mockOutbox.EXPECT().GetEventsAfter(ctx, int64(17), 2).Return(nil, errors.Error("db down"))
+
+	_, err = h.Poll(ctx)
+	assert.Equal(t, errors.Error("db down"), err)
+
+	h.Unsubscribe(all)
+	h.Unsubscribe(all)
+
+	_, ok := <-all.Events
+	assert.False(t, ok, "the events of an ended subscription are closed")
+	assert.False(t, h.Dropped(all))
+}
+
+func TestHubDropsSlowSubscribers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	h := NewHub(mockOutbox, HubConfig{BatchSize: 10, Buffer: 1})
+
+	mockOutbox.EXPECT().GetLastSeq(ctx).Return(int64(0), nil)
+	mockOutbox.EXPECT().GetEventsAfter(ctx, int64(0), 10).Return([]models.Event{
+		carEvent(1, models.EventCarCreated, models.Car{}), carEvent(2, models.EventCarCreated, models.Car{})}, nil)
+
+	_, _ = h.Poll(ctx)
+
+	slow, _ := h.Subscribe(Filter{})
+
+	_, err := h.Poll(ctx)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, []int64{1}, received(slow), "the events sent before it fell behind are kept")
+	assert.True(t, h.Dropped(slow))
+}
+
+func TestHubReplay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	h := NewHub(mockOutbox, HubConfig{BatchSize: 2})
+
+	bmw := models.Car{Brand: "BMW"}
+
+	gomock.InOrder(
+		mockOutbox.EXPECT().GetEventsAfter(ctx, int64(3), 2).Return([]models.Event{
+			carEvent(4, models.EventCarCreated, bmw), carEvent(5, models.EventCarCreated, models.Car{})}, nil),
+		mockOutbox.EXPECT().GetEventsAfter(ctx, int64(5), 2).Return([]models.Event{
+			carEvent(6, models.EventCarUpdated, bmw), carEvent(7, models.EventCarUpdated, bmw)}, nil),
+	)
+
+	var seqs []int64
+
+	err := h.Replay(ctx, Filter{Brand: "BMW"}, 3, 6, func(e models.Event) error {
+		seqs = append(seqs, e.Seq)
+		return nil
+	})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{4, 6}, seqs, "only the matching events up to the subscription are replayed")
+
+	err = h.Replay(ctx, Filter{}, 6, 6, nil)
+	assert.Equal(t, nil, err, "nothing is missing")
+
+	mockOutbox.EXPECT().GetEventsAfter(ctx, int64(3), 2).Return([]models.Event{
+		carEvent(4, models.EventCarCreated, bmw)}, nil)
+
+	err = h.Replay(ctx, Filter{}, 3, 6, func(e models.Event) error { return errors.Error("broken pipe") })
+	assert.Equal(t, errors.Error("broken pipe"), err)
+}
+
+func TestFilterMatches(t *testing.T) {
+	dealer := uuid.New()
+	car := models.Car{Brand: "BMW", DealershipID: &dealer}
+
+	testCases := []struct {
+		desc     string
+		filter   Filter
+		event    models.Event
+		expected bool
+	}{
+		{desc: "no filter", event: carEvent(1, models.EventCarCreated, car), expected: true},
+		{desc: "brand", filter: Filter{Brand: "BMW"}, event: carEvent(1, models.EventCarCreated, car), expected: true},
+		{desc: "other brand", filter: Filter{Brand: "Audi"}, event: carEvent(1, models.EventCarDeleted, car)},
+		{desc: "dealership", filter: Filter{DealershipID: dealer.String()},
+			event: carEvent(1, models.EventCarUpdated, car), expected: true},
+		{desc: "other dealership", filter: Filter{DealershipID: uuid.NewString()},
+			event: carEvent(1, models.EventCarUpdated, car)},
+		{desc: "no dealership", filter: Filter{DealershipID: dealer.String()},
+			event: carEvent(1, models.EventCarUpdated, models.Car{Brand: "BMW"})},
+		{desc: "status change", filter: Filter{Brand: "BMW", DealershipID: dealer.String()},
+			event: carEvent(1, models.EventCarStatusChanged, models.StatusChange{From: models.CarAvailable,
+				To: models.CarSold, Brand: "BMW", DealershipID: &dealer}), expected: true},
+		{desc: "corrupt data", filter: Filter{Brand: "BMW"}, event: models.Event{Data: json.RawMessage("{")}},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.expected, tc.filter.Matches(&tc.event), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,32 @@
+package events
+
+import (
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// PubSub is the part of the gofr pub/sub client the kafka publisher uses, k.PubSub is one. The topic is the
+// KAFKA_TOPIC of the gofr configuration.
+type PubSub interface {
+	PublishEvent(key string, value interface{}, headers map[string]string) error
+}
+
+type kafka struct {
+	client PubSub
+}
+
+// nolint:revive // need not be exported
+// NewKafka factory function
+func NewKafka(client PubSub) kafka {
+	return kafka{client: client}
+}
+
+// Publish sends the event keyed by its car, so that the events of a car land on one partition and are
+// consumed in order. The type and the id are set as headers for consumers to filter on without decoding.
+func (k kafka) Publish(_ *gofr.Context, event models.Event) error {
+	return k.client.PublishEvent(event.CarID.String(), event, map[string]string{
+		"type": event.Type,
+		"id":   event.ID.String(),
+	})
+}
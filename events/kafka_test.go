@@ -0,0 +1,52 @@
+package events
+
+import (
+	"testing"
+
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type published struct {
+	key     string
+	value   interface{}
+	headers map[string]string
+}
+
+type fakePubSub struct {
+	published []published
+	err       error
+}
+
+func (f *fakePubSub) PublishEvent(key string, value interface{}, headers map[string]string) error {
+	f.published = append(f.published, published{key: key, value: value, headers: headers})
+	return f.err
+}
+
+func TestKafkaPublish(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	event := models.Event{ID: uuid.New(), Type: models.EventCarDeleted, CarID: uuid.New()}
+
+	testCases := []struct {
+		desc string
+		err  error
+	}{
+		{desc: "published"},
+		{desc: "broker error", err: errors.Error("broker down")},
+	}
+
+	for i, tc := range testCases {
+		client := &fakePubSub{err: tc.err}
+
+		err := NewKafka(client).Publish(ctx, event)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, []published{{key: event.CarID.String(), value: event,
+			headers: map[string]string{"type": models.EventCarDeleted, "id": event.ID.String()}}},
+			client.published, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"Project/CarDealearship/models"
+	"sync"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+type memory struct {
+	mu     sync.Mutex
+	events []models.Event
+}
+
+// nolint:revive // need not be exported
+// NewMemory factory function, the events are kept in process for tests and local runs to look at
+func NewMemory() *memory {
+	return &memory{}
+}
+
+// Publish keeps the event
+func (m *memory) Publish(_ *gofr.Context, event models.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, event)
+
+	return nil
+}
+
+// Events returns the events published so far, in the order they were published
+func (m *memory) Events() []models.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]models.Event(nil), m.events...)
+}
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"testing"
+
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryPublish(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	m := NewMemory()
+
+	e1 := models.Event{ID: uuid.New(), Type: models.EventCarCreated, CarID: uuid.New()}
+	e2 := models.Event{ID: uuid.New(), Type: models.EventCarUpdated, CarID: e1.CarID}
+
+	assert.Empty(t, m.Events())
+
+	assert.Equal(t, nil, m.Publish(ctx, e1))
+	assert.Equal(t, nil, m.Publish(ctx, e2))
+
+	events := m.Events()
+	assert.Equal(t, []models.Event{e1, e2}, events)
+
+	events[0] = models.Event{}
+	assert.Equal(t, []models.Event{e1, e2}, m.Events(), "the returned events are a copy")
+}
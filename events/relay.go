@@ -0,0 +1,109 @@
+package events
+
+import (
+	"Project/CarDealearship/stores"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+type relay struct {
+	outbox    stores.Outbox
+	tx        stores.Transaction
+	publisher Publisher
+	batchSize int
+}
+
+// nolint:revive // need not be exported
+// NewRelay factory function, events are read from the outbox batchSize at a time
+func NewRelay(o stores.Outbox, tx stores.Transaction, p Publisher, batchSize int) relay {
+	return relay{outbox: o, tx: tx, publisher: p, batchSize: batchSize}
+}
+
+// Flush publishes the pending events of the outbox, oldest first, and returns how many were published. It stops
+// at the first event that fails to publish, the events before it are marked published and the rest are retried
+// by the next flush.
+func (r relay) Flush(ctx *gofr.Context) (int, error) {
+	total := 0
+
+	for {
+		n, more, err := r.flushBatch(ctx)
+		total += n
+
+		if err != nil || !more {
+			return total, err
+		}
+	}
+}
+
+// flushBatch publishes a batch of events in a transaction that keeps them locked from other relays. An event
+// published by a relay that fails to mark it is published again, which is the at least once of the package.
+func (r relay) flushBatch(ctx *gofr.Context) (published int, more bool, err error) {
+	var publishErr error
+
+	err = r.tx.WithTransaction(ctx, func(ctx *gofr.Context) error {
+		events, err := r.outbox.GetPendingEvents(ctx, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, 0, len(events))
+
+		for i := range events {
+			if publishErr = r.publisher.Publish(ctx, events[i]); publishErr != nil {
+				break
+			}
+
+			ids = append(ids, events[i].ID.String())
+		}
+
+		if err = r.outbox.MarkEventsPublished(ctx, ids); err != nil {
+			return err
+		}
+
+		published, more = len(ids), len(events) == r.batchSize
+
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return published, more, publishErr
+}
+
+// Prune deletes the events of the outbox published longer than retention ago, batchSize at a time, and returns
+// how many were deleted. The streams resume from the events left in the outbox, so retention is how long a client
+// can be away.
+func Prune(ctx *gofr.Context, o stores.Outbox, retention time.Duration, batchSize int) (int, error) {
+	total := 0
+
+	for {
+		n, err := o.DeletePublishedEvents(ctx, retention, batchSize)
+		total += n
+
+		if err != nil || n < batchSize {
+			return total, err
+		}
+	}
+}
+
+// Run flushes the outbox every interval until the context of ctx is done. ctx must not be shared with other
+// goroutines, as the transactions of the relay are kept in it.
+func (r relay) Run(ctx *gofr.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.Flush(ctx)
+		if err != nil {
+			ctx.Logger.Errorf("error in publishing car events, %v published: %v", n, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
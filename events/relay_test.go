@@ -0,0 +1,169 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func runInTransaction(ctx *gofr.Context, fn func(ctx *gofr.Context) error) error {
+	return fn(ctx)
+}
+
+// failingPublisher fails to publish the events of one car
+type failingPublisher struct {
+	*memory
+	carID uuid.UUID
+}
+
+func (f failingPublisher) Publish(ctx *gofr.Context, event models.Event) error {
+	if event.CarID == f.carID {
+		return errors.Error("broker down")
+	}
+
+	return f.memory.Publish(ctx, event)
+}
+
+func newEvents(n int) []models.Event {
+	events := make([]models.Event, n)
+	for i := range events {
+		events[i] = models.Event{ID: uuid.New(), Type: models.EventCarCreated, CarID: uuid.New()}
+	}
+
+	return events
+}
+
+func ids(events []models.Event) []string {
+	res := make([]string, len(events))
+	for i := range events {
+		res[i] = events[i].ID.String()
+	}
+
+	return res
+}
+
+func TestFlush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	events := newEvents(3)
+
+	testCases := []struct {
+		desc      string
+		setup     func()
+		failing   uuid.UUID
+		count     int
+		published []models.Event
+		err       error
+	}{
+		{
+			desc: "batches until the outbox is empty", count: 3, published: events,
+			setup: func() {
+				mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction).Times(2)
+				mockOutbox.EXPECT().GetPendingEvents(ctx, 2).Return(events[:2], nil)
+				mockOutbox.EXPECT().MarkEventsPublished(ctx, ids(events[:2])).Return(nil)
+				mockOutbox.EXPECT().GetPendingEvents(ctx, 2).Return(events[2:], nil)
+				mockOutbox.EXPECT().MarkEventsPublished(ctx, ids(events[2:])).Return(nil)
+			},
+		},
+		{
+			desc: "publish error keeps the rest pending", failing: events[1].CarID, count: 1,
+			published: events[:1], err: errors.Error("broker down"),
+			setup: func() {
+				mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+				mockOutbox.EXPECT().GetPendingEvents(ctx, 2).Return(events[:2], nil)
+				mockOutbox.EXPECT().MarkEventsPublished(ctx, ids(events[:1])).Return(nil)
+			},
+		},
+		{
+			desc: "outbox error", err: errors.Error("db down"),
+			setup: func() {
+				mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+				mockOutbox.EXPECT().GetPendingEvents(ctx, 2).Return(nil, errors.Error("db down"))
+			},
+		},
+		{
+			desc: "mark error rolls the batch back", published: events[:2], err: errors.Error("db down"),
+			setup: func() {
+				mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+				mockOutbox.EXPECT().GetPendingEvents(ctx, 2).Return(events[:2], nil)
+				mockOutbox.EXPECT().MarkEventsPublished(ctx, ids(events[:2])).Return(errors.Error("db down"))
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		tc.setup()
+
+		publisher := failingPublisher{memory: NewMemory(), carID: tc.failing}
+
+		n, err := NewRelay(mockOutbox, mockTx, publisher, 2).Flush(ctx)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.count, n, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.published, publisher.Events(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ctx.Context = cancelled
+
+	events := newEvents(1)
+	publisher := NewMemory()
+
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+	mockOutbox.EXPECT().GetPendingEvents(ctx, 10).Return(events, nil)
+	mockOutbox.EXPECT().MarkEventsPublished(ctx, ids(events)).Return(nil)
+
+	NewRelay(mockOutbox, mockTx, publisher, 10).Run(ctx, time.Hour)
+
+	assert.Equal(t, events, publisher.Events(), "the outbox is flushed before the context is checked")
+}
+
+func TestPrune(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	gomock.InOrder(
+		mockOutbox.EXPECT().DeletePublishedEvents(ctx, time.Hour, 10).Return(10, nil),
+		mockOutbox.EXPECT().DeletePublishedEvents(ctx, time.Hour, 10).Return(3, nil),
+		mockOutbox.EXPECT().DeletePublishedEvents(ctx, time.Hour, 10).Return(10, nil),
+		mockOutbox.EXPECT().DeletePublishedEvents(ctx, time.Hour, 10).Return(0, errors.Error("db down")),
+	)
+
+	n, err := Prune(ctx, mockOutbox, time.Hour, 10)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 13, n, "events are deleted until a batch is not full")
+
+	n, err = Prune(ctx, mockOutbox, time.Hour, 10)
+
+	assert.Equal(t, errors.Error("db down"), err)
+	assert.Equal(t, 10, n)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+const maxBatchItems = 100
+
+// BatchGet is a handler function to get up to maxBatchItems cars by their ids in a single request, every id
+// gets a result with its own status
+func (c handler) BatchGet(ctx *gofr.Context) (interface{}, error) {
+	var req models.BatchGetRequest
+	if err := ctx.Bind(&req); err != nil {
+		ctx.Logger.Errorf("error in binding: %v", err)
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	if err := checkBatchSize("IDs", len(req.IDs)); err != nil {
+		return nil, err
+	}
+
+	results, err := c.service.BatchGet(ctx, req.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.BatchResponse{Results: results}, nil
+}
+
+// BatchUpdate is a handler function to patch up to maxBatchItems cars in a single transaction, every patch gets
+// a result with its own status
+func (c handler) BatchUpdate(ctx *gofr.Context) (interface{}, error) {
+	var req models.BatchUpdateRequest
+	if err := ctx.Bind(&req); err != nil {
+		ctx.Logger.Errorf("error in binding: %v", err)
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	if err := checkBatchSize("Updates", len(req.Updates)); err != nil {
+		return nil, err
+	}
+
+	results, err := c.service.BatchUpdate(ctx, req.Updates)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.BatchResponse{Results: results}, nil
+}
+
+func checkBatchSize(param string, n int) error {
+	switch {
+	case n == 0:
+		return errors.MissingParam{Param: []string{param}}
+	case n > maxBatchItems:
+		return errors.InvalidParam{Param: []string{param}}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/service"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBatchGet to test the handler BatchGet
+func TestBatchGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	s := New(mockService)
+	app := gofr.New()
+
+	id := uuid.New()
+	results := []models.BatchResult{{ID: id.String(), Status: http.StatusOK, Car: &models.Car{ID: id}}}
+
+	testCases := []struct {
+		desc string
+		body string
+		resp interface{}
+		err  error
+		mock []*gomock.Call
+	}{
+		{
+			desc: "success", body: `{"IDs":["` + id.String() + `"]}`, resp: models.BatchResponse{Results: results},
+			mock: []*gomock.Call{mockService.EXPECT().BatchGet(gomock.Any(), []string{id.String()}).Return(results, nil)},
+		},
+		{
+			desc: "service error", body: `{"IDs":["` + id.String() + `"]}`, err: errors.Error("db down"),
+			mock: []*gomock.Call{mockService.EXPECT().BatchGet(gomock.Any(), gomock.Any()).
+				Return(nil, errors.Error("db down"))},
+		},
+		{desc: "no ids", body: `{"IDs":[]}`, err: errors.MissingParam{Param: []string{"IDs"}}},
+		{desc: "too many ids", body: batchBody("IDs", `"x"`), err: errors.InvalidParam{Param: []string{"IDs"}}},
+		{desc: "invalid body", body: `{"IDs":`, err: errors.InvalidParam{Param: []string{"body"}}},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest("POST", "/cars:batchGet", strings.NewReader(tc.body))
+		r.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), app)
+
+		resp, err := s.BatchGet(ctx)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestBatchUpdate to test the handler BatchUpdate
+func TestBatchUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	s := New(mockService)
+	app := gofr.New()
+
+	id := uuid.New().String()
+	patches := []models.CarPatch{{ID: id, Patch: json.RawMessage(`{"Status":"sold"}`)}}
+	results := []models.BatchResult{{ID: id, Status: http.StatusNotFound, Code: "NOT_FOUND", Error: "not found"}}
+
+	testCases := []struct {
+		desc string
+		body string
+		resp interface{}
+		err  error
+		mock []*gomock.Call
+	}{
+		{
+			desc: "success", body: `{"Updates":[{"ID":"` + id + `","Patch":{"Status":"sold"}}]}`,
+			resp: models.BatchResponse{Results: results},
+			mock: []*gomock.Call{mockService.EXPECT().BatchUpdate(gomock.Any(), patches).Return(results, nil)},
+		},
+		{
+			desc: "service error", body: `{"Updates":[{"ID":"` + id + `","Patch":{}}]}`, err: errors.Error("db down"),
+			mock: []*gomock.Call{mockService.EXPECT().BatchUpdate(gomock.Any(), gomock.Any()).
+				Return(nil, errors.Error("db down"))},
+		},
+		{desc: "no updates", body: `{}`, err: errors.MissingParam{Param: []string{"Updates"}}},
+		{desc: "too many updates", body: batchBody("Updates", `{"ID":"x","Patch":{}}`),
+			err: errors.InvalidParam{Param: []string{"Updates"}}},
+		{desc: "invalid body", body: `[]`, err: errors.InvalidParam{Param: []string{"body"}}},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest("POST", "/cars:batchUpdate", strings.NewReader(tc.body))
+		r.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), app)
+
+		resp, err := s.BatchUpdate(ctx)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// batchBody is a request body with one item more than a batch can have
+func batchBody(field, item string) string {
+	return `{"` + field + `":[` + strings.Repeat(item+",", maxBatchItems) + item + `]}`
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"Project/CarDealearship/models"
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// exportColumns are the column headers of csv and xlsx exports, they can be imported back with POST /cars/import
+var exportColumns = []string{"id", "name", "year", "brand", "fuel_type", "engine_id", "displacement", "cylinders",
+	"range", "cost_price"}
+
+type exporter interface {
+	Write(car models.Car) error
+	Close() error
+}
+
+type exportFormat struct {
+	contentType string
+	extension   string
+	new         func(w io.Writer) (exporter, error)
+}
+
+var exportFormats = map[string]exportFormat{
+	"csv":    {contentType: "text/csv; charset=utf-8", extension: "csv", new: newCSVExporter},
+	"ndjson": {contentType: "application/x-ndjson", extension: "ndjson", new: newNDJSONExporter},
+	"xlsx": {contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", extension: "xlsx",
+		new: newXLSXExporter},
+}
+
+// Export is a handler function to stream the cars along with their engines as csv (default), ndjson or xlsx.
+// Like GetByBrand the cars can be filtered with the brand parameter.
+func (c handler) Export(ctx *gofr.Context, w http.ResponseWriter) error {
+	name := ctx.Param("format")
+	if name == "" {
+		name = "csv"
+	}
+
+	format, ok := exportFormats[name]
+	if !ok {
+		return errors.InvalidParam{Param: []string{"format"}}
+	}
+
+	var out exporter
+
+	// the response is only started with the first car, so that an error in loading it can still be responded
+	start := func() error {
+		w.Header().Set("Content-Type", format.contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="cars.`+format.extension+`"`)
+
+		var err error
+
+		out, err = format.new(w)
+
+		return err
+	}
+
+	err := c.service.Export(ctx, ctx.Param("brand"), func(car models.Car) error {
+		if out == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		return out.Write(car)
+	})
+	if err != nil {
+		return err
+	}
+
+	if out == nil {
+		if err = start(); err != nil {
+			return err
+		}
+	}
+
+	return out.Close()
+}
+
+// exportRecord returns the values of a car in the order of exportColumns, nil for a value that is not set
+func exportRecord(car models.Car) []interface{} {
+	var cost interface{}
+	if car.CostPrice != nil {
+		cost = *car.CostPrice
+	}
+
+	return []interface{}{car.ID.String(), car.Name, car.Year, car.Brand, car.FuelType, car.Engine.EngineID.String(),
+		car.Engine.Displacement, car.Engine.Cylinders, car.Engine.Range, cost}
+}
+
+type csvExporter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVExporter(w io.Writer) (exporter, error) {
+	e := &csvExporter{w: csv.NewWriter(w), record: make([]string, len(exportColumns))}
+
+	if err := e.w.Write(exportColumns); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (e *csvExporter) Write(car models.Car) error {
+	for i, v := range exportRecord(car) {
+		switch v := v.(type) {
+		case int:
+			e.record[i] = strconv.Itoa(v)
+		case string:
+			e.record[i] = csvText(v)
+		default:
+			e.record[i] = ""
+		}
+	}
+
+	return e.w.Write(e.record)
+}
+
+func (e *csvExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvText keeps spreadsheet applications from running a text cell as a formula
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+
+	return s
+}
+
+type ndjsonExporter struct {
+	enc *json.Encoder
+}
+
+func newNDJSONExporter(w io.Writer) (exporter, error) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	return ndjsonExporter{enc: enc}, nil
+}
+
+func (e ndjsonExporter) Write(car models.Car) error {
+	return e.enc.Encode(car)
+}
+
+func (e ndjsonExporter) Close() error {
+	return nil
+}
+
+// xlsxParts are the fixed parts of a workbook holding the single worksheet xl/worksheets/sheet1.xml
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Target="xl/workbook.xml" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Cars" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Target="worksheets/sheet1.xml" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet"/>` +
+		`</Relationships>`},
+}
+
+// xlsxExporter writes an Office Open XML workbook. Zip entries can be written without seeking, so the
+// worksheet is streamed row by row like the other formats.
+type xlsxExporter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+func newXLSXExporter(w io.Writer) (exporter, error) {
+	zw := zip.NewWriter(w)
+
+	for _, p := range xlsxParts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = io.WriteString(f, p.content); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	e := &xlsxExporter{zw: zw, sheet: bufio.NewWriter(f)}
+
+	_, _ = e.sheet.WriteString(xml.Header +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	header := make([]interface{}, len(exportColumns))
+	for i := range exportColumns {
+		header[i] = exportColumns[i]
+	}
+
+	e.writeRow(header)
+
+	return e, nil
+}
+
+func (e *xlsxExporter) Write(car models.Car) error {
+	e.writeRow(exportRecord(car))
+
+	// bufio keeps the first write error and returns it from every later write
+	_, err := e.sheet.Write(nil)
+
+	return err
+}
+
+func (e *xlsxExporter) writeRow(values []interface{}) {
+	e.row++
+	row := strconv.Itoa(e.row)
+
+	_, _ = e.sheet.WriteString(`<row r="` + row + `">`)
+
+	for i, v := range values {
+		ref := string(rune('A'+i)) + row
+
+		switch v := v.(type) {
+		case int:
+			_, _ = e.sheet.WriteString(`<c r="` + ref + `"><v>` + strconv.Itoa(v) + `</v></c>`)
+		case string:
+			_, _ = e.sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+			_ = xml.EscapeText(e.sheet, []byte(v))
+			_, _ = e.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+
+	_, _ = e.sheet.WriteString(`</row>`)
+}
+
+func (e *xlsxExporter) Close() error {
+	_, _ = e.sheet.WriteString(`</sheetData></worksheet>`)
+
+	if err := e.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return e.zw.Close()
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/service"
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExport to test the handler Export
+func TestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	s := New(mockService)
+	app := gofr.New()
+
+	id := uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	cost := 65000
+	cars := []models.Car{
+		{ID: id, Name: `M3 "Competition", <LCI>`, Year: 2021, Brand: "BMW", FuelType: "Petrol", CostPrice: &cost,
+			Engine: models.Engine{EngineID: id, Displacement: 3000, Cylinders: 6}},
+		{ID: id, Name: "=HYPERLINK()", Year: 2022, Brand: "BMW", FuelType: "Electric",
+			Engine: models.Engine{EngineID: id, Range: 500}},
+	}
+
+	stream := func(cars []models.Car, err error) func(*gofr.Context, string, func(models.Car) error) error {
+		return func(_ *gofr.Context, _ string, fn func(models.Car) error) error {
+			for _, c := range cars {
+				if err := fn(c); err != nil {
+					return err
+				}
+			}
+
+			return err
+		}
+	}
+
+	testCases := []struct {
+		desc        string
+		target      string
+		contentType string
+		body        string
+		err         error
+		mock        []*gomock.Call
+	}{
+		{
+			desc: "csv by default", target: "/cars/export?brand=BMW", contentType: "text/csv; charset=utf-8",
+			body: "id,name,year,brand,fuel_type,engine_id,displacement,cylinders,range,cost_price\n" +
+				id.String() + `,"M3 ""Competition"", <LCI>",2021,BMW,Petrol,` + id.String() + ",3000,6,0,65000\n" +
+				id.String() + ",'=HYPERLINK(),2022,BMW,Electric," + id.String() + ",0,0,500,\n",
+			mock: []*gomock.Call{mockService.EXPECT().Export(gomock.Any(), "BMW", gomock.Any()).
+				DoAndReturn(stream(cars, nil))},
+		},
+		{
+			desc: "ndjson", target: "/cars/export?format=ndjson", contentType: "application/x-ndjson",
+			body: `{"ID":"` + id.String() + `","Engine":{"id":"` + id.String() + `","displacement":3000,"cylinders":6},` +
+				`"Name":"M3 \"Competition\", <LCI>","Year":2021,"Brand":"BMW","FuelType":"Petrol","CostPrice":65000}` + "\n",
+			mock: []*gomock.Call{mockService.EXPECT().Export(gomock.Any(), "", gomock.Any()).
+				DoAndReturn(stream(cars[:1], nil))},
+		},
+		{
+			desc: "empty csv", target: "/cars/export?format=csv&brand=Audi", contentType: "text/csv; charset=utf-8",
+			body: "id,name,year,brand,fuel_type,engine_id,displacement,cylinders,range,cost_price\n",
+			mock: []*gomock.Call{mockService.EXPECT().Export(gomock.Any(), "Audi", gomock.Any()).
+				DoAndReturn(stream(nil, nil))},
+		},
+		{
+			desc: "unknown format", target: "/cars/export?format=pdf", err: errors.InvalidParam{Param: []string{"format"}},
+		},
+		{
+			desc: "error before the first car", target: "/cars/export", err: errors.Error("db error"),
+			mock: []*gomock.Call{mockService.EXPECT().Export(gomock.Any(), "", gomock.Any()).
+				DoAndReturn(stream(nil, errors.Error("db error")))},
+		},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest("GET", tc.target, nil)
+		w := httptest.NewRecorder()
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), app)
+
+		err := s.Export(ctx, w)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.contentType, w.Header().Get("Content-Type"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.body, w.Body.String(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestExportXLSX to test that the xlsx export is a workbook with the cars in its worksheet
+func TestExportXLSX(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	s := New(mockService)
+
+	id := uuid.MustParse("8f443772-132b-4ae5-9f8f-9960649b3fb4")
+	car := models.Car{ID: id, Name: "A4 & <S4>", Year: 2020, Brand: "Audi", FuelType: "Diesel",
+		Engine: models.Engine{EngineID: id, Displacement: 2000, Cylinders: 4}}
+
+	mockService.EXPECT().Export(gomock.Any(), "", gomock.Any()).
+		DoAndReturn(func(_ *gofr.Context, _ string, fn func(models.Car) error) error {
+			return fn(car)
+		})
+
+	r := httptest.NewRequest("GET", "/cars/export?format=xlsx", nil)
+	w := httptest.NewRecorder()
+	ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), gofr.New())
+
+	assert.Equal(t, nil, s.Export(ctx, w))
+	assert.Equal(t, `attachment; filename="cars.xlsx"`, w.Header().Get("Content-Disposition"))
+
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if !assert.Equal(t, nil, err) {
+		return
+	}
+
+	parts := make(map[string]string)
+
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if !assert.Equal(t, nil, err) {
+			return
+		}
+
+		b, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		parts[f.Name] = string(b)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		_, ok := parts[name]
+		assert.Equal(t, true, ok, "missing part %s", name)
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+
+	assert.Equal(t, true, strings.Contains(sheet,
+		`<c r="A1" t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`), sheet)
+	assert.Equal(t, true, strings.Contains(sheet,
+		`<c r="B2" t="inlineStr"><is><t xml:space="preserve">A4 &amp; &lt;S4&gt;</t></is></c><c r="C2"><v>2020</v></c>`),
+		sheet)
+	assert.Equal(t, true, strings.HasSuffix(sheet, `</row></sheetData></worksheet>`), sheet)
+}
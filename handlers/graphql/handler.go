@@ -0,0 +1,95 @@
+package graphql
+
+import (
+	"Project/CarDealearship/service"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+// maxDepth bounds the nesting of a query, cars and engines refer to each other. It leaves room for the
+// introspection query of GraphQL clients.
+const maxDepth = 15
+
+type handler struct {
+	schema      *gql.Schema
+	cars        service.Cars
+	dealerships service.Dealerships
+}
+
+// nolint:revive // need not be exported
+// New factory function
+func New(c service.Cars, d service.Dealerships) handler {
+	return handler{
+		schema:      gql.MustParseSchema(schema, &resolver{cars: c}, gql.MaxDepth(maxDepth)),
+		cars:        c,
+		dealerships: d,
+	}
+}
+
+type query struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Serve is the stream handler of the GraphQL endpoint. The result is written as is rather than through the
+// gofr responder, GraphQL clients expect data and errors at the top level of the body.
+func (h handler) Serve(ctx *gofr.Context, w http.ResponseWriter) error {
+	var q query
+	if err := ctx.Bind(&q); err != nil {
+		ctx.Logger.Errorf("error in binding: %v", err)
+		return errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	if strings.TrimSpace(q.Query) == "" {
+		return errors.MissingParam{Param: []string{"query"}}
+	}
+
+	req := newState(ctx, h.cars, h.dealerships)
+	res := h.schema.Exec(context.WithValue(ctx, stateKey{}, req), q.Query, q.OperationName, q.Variables)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(w).Encode(res)
+}
+
+// resolverError is the error of a resolver, its code is reported in the extensions of the GraphQL error
+type resolverError struct {
+	code    string
+	message string
+}
+
+func (e resolverError) Error() string {
+	return e.message
+}
+
+func (e resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// convert maps the errors of the service layer to resolver errors. The details of unexpected errors are only
+// logged, like the gofr responder does for the REST api.
+func convert(ctx *gofr.Context, err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case resolverError:
+		return e
+	case errors.EntityNotFound:
+		return resolverError{code: "NOT_FOUND", message: e.Error()}
+	case errors.InvalidParam, errors.MissingParam:
+		return resolverError{code: "BAD_USER_INPUT", message: e.Error()}
+	case *errors.Response:
+		return resolverError{code: e.Code, message: e.Reason}
+	}
+
+	ctx.Logger.Errorf("error in resolving graphql query: %v", err)
+
+	return resolverError{code: "INTERNAL", message: "internal error"}
+}
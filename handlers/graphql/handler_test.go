@@ -0,0 +1,358 @@
+package graphql
+
+import (
+	"Project/CarDealearship/auth"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/service"
+	"Project/CarDealearship/service/traced"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type result struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// codes are the extension codes of the errors of the result
+func (r result) codes() []string {
+	var codes []string
+	for _, e := range r.Errors {
+		codes = append(codes, e.Extensions["code"].(string))
+	}
+
+	return codes
+}
+
+func serve(t *testing.T, h handler, role, body string) result {
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), gofr.New())
+	ctx.Context = auth.WithPrincipal(ctx.Context, auth.Principal{Subject: "u1", Roles: []string{role}})
+
+	if err := h.Serve(ctx, w); err != nil {
+		t.Fatal(err)
+	}
+
+	var res result
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	return res
+}
+
+func body(query string) string {
+	b, _ := json.Marshal(map[string]string{"query": query})
+	return string(b)
+}
+
+var (
+	id1 = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	id2 = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	id3 = uuid.MustParse("00000000-0000-0000-0000-000000000003")
+	d1  = uuid.MustParse("00000000-0000-0000-0000-0000000000d1")
+)
+
+// TestCars tests that the engines and dealerships of a list of cars are each fetched in a single call. The cars
+// carry the ids of their engines but not their data, like the cars of a brand read by the store.
+func TestCars(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCars := service.NewMockCars(ctrl)
+	mockDealerships := service.NewMockDealerships(ctrl)
+	h := New(mockCars, mockDealerships)
+
+	mockCars.EXPECT().GetByBrand(gomock.Any(), "BMW", false).Return([]models.Car{
+		{ID: id1, Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol", DealershipID: &d1,
+			Engine: models.Engine{EngineID: id1}},
+		{ID: id2, Name: "X7", Year: 2022, Brand: "BMW", FuelType: "Petrol", DealershipID: &d1,
+			Engine: models.Engine{EngineID: id2}},
+		{ID: id3, Name: "i3", Year: 2015, Brand: "BMW", FuelType: "Electric", Engine: models.Engine{EngineID: id3}},
+	}, nil)
+	mockCars.EXPECT().GetEngines(gomock.Any(), []string{id1.String(), id2.String()}).Times(1).Return([]models.Engine{
+		{EngineID: id1, Displacement: 3000, Cylinders: 6}, {EngineID: id2, Displacement: 4400, Cylinders: 8},
+	}, nil)
+	mockDealerships.EXPECT().GetByIDs(gomock.Any(), []string{d1.String()}).
+		Return([]models.Dealership{{ID: d1, Name: "Downtown Motors", City: "Bangalore"}}, nil)
+
+	res := serve(t, h, auth.RoleViewer, body(`{ cars(brand: "BMW", yearFrom: 2018) {
+		name year engine { cylinders } dealership { name } } }`))
+
+	assert.Equal(t, 0, len(res.Errors))
+	assert.JSONEq(t, `{"cars": [
+		{"name": "X5", "year": 2020, "engine": {"cylinders": 6}, "dealership": {"name": "Downtown Motors"}},
+		{"name": "X7", "year": 2022, "engine": {"cylinders": 8}, "dealership": {"name": "Downtown Motors"}}
+	]}`, string(res.Data))
+}
+
+// TestCarsTraced tests that the engines and the dealerships of the cars, which are loaded concurrently with the
+// context of the request, go through the traced service without racing on that context. Run it with -race.
+func TestCarsTraced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCars := service.NewMockCars(ctrl)
+	mockDealerships := service.NewMockDealerships(ctrl)
+	h := New(traced.NewCars(mockCars), mockDealerships)
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	// the loads wait for each other so that they overlap, then read the context they were given like a store
+	// would, the dealerships for a while so that the engines end meanwhile
+	var started sync.WaitGroup
+
+	started.Add(2)
+
+	overlap := func(ctx *gofr.Context, d time.Duration) trace.SpanContext {
+		started.Done()
+
+		done := make(chan struct{})
+
+		go func() {
+			started.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+
+		span := trace.SpanFromContext(ctx).SpanContext()
+
+		for end := time.Now().Add(d); time.Now().Before(end); {
+			span = trace.SpanFromContext(ctx).SpanContext()
+		}
+
+		return span
+	}
+
+	var engineSpan trace.SpanContext
+
+	mockCars.EXPECT().GetByBrand(gomock.Any(), "BMW", false).Return([]models.Car{
+		{ID: id1, Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol", DealershipID: &d1},
+		{ID: id2, Name: "X7", Year: 2022, Brand: "BMW", FuelType: "Petrol", DealershipID: &d1},
+	}, nil)
+	mockCars.EXPECT().GetEngines(gomock.Any(), []string{id1.String(), id2.String()}).
+		DoAndReturn(func(ctx *gofr.Context, _ []string) ([]models.Engine, error) {
+			engineSpan = overlap(ctx, 0)
+
+			return []models.Engine{{EngineID: id1, Cylinders: 6}, {EngineID: id2, Cylinders: 8}}, nil
+		})
+	mockDealerships.EXPECT().GetByIDs(gomock.Any(), []string{d1.String()}).
+		DoAndReturn(func(ctx *gofr.Context, _ []string) ([]models.Dealership, error) {
+			overlap(ctx, 20*time.Millisecond)
+
+			return []models.Dealership{{ID: d1, Name: "Downtown Motors"}}, nil
+		})
+
+	res := serve(t, h, auth.RoleViewer, body(`{ cars(brand: "BMW") { engine { cylinders } dealership { name } } }`))
+
+	assert.Equal(t, 0, len(res.Errors))
+	assert.JSONEq(t, `{"cars": [
+		{"engine": {"cylinders": 6}, "dealership": {"name": "Downtown Motors"}},
+		{"engine": {"cylinders": 8}, "dealership": {"name": "Downtown Motors"}}
+	]}`, string(res.Data))
+	assert.True(t, engineSpan.IsValid(), "the engines are loaded with a context carrying the span of the service")
+}
+
+// TestCar tests the traversal from a car to its engine and on to its dealership
+func TestCar(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCars := service.NewMockCars(ctrl)
+	mockDealerships := service.NewMockDealerships(ctrl)
+	h := New(mockCars, mockDealerships)
+
+	cost := 21000
+	updated := time.Now()
+	car := models.Car{ID: id1, Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol", CostPrice: &cost,
+		DealershipID: &d1, Engine: models.Engine{EngineID: id1, Displacement: 3000, Cylinders: 6, UpdatedAt: &updated}}
+
+	mockCars.EXPECT().GetByIDs(gomock.Any(), []string{id1.String()}).Return([]models.Car{car}, nil)
+	mockCars.EXPECT().GetByIDs(gomock.Any(), []string{id2.String()}).Return([]models.Car{}, nil)
+	mockCars.EXPECT().GetByIDs(gomock.Any(), []string{id3.String()}).Return(nil, errors.Error("db down"))
+	mockDealerships.EXPECT().GetByIDs(gomock.Any(), []string{d1.String()}).
+		Return([]models.Dealership{{ID: d1, Name: "Downtown Motors", City: "Bangalore"}}, nil)
+
+	testCases := []struct {
+		desc  string
+		query string
+		data  string
+		codes []string
+	}{
+		{desc: "car to engine to dealership", query: `{ car(id: "` + id1.String() + `") {
+			costPrice engine { displacement car { name } dealership { city } } } }`,
+			data: `{"car": {"costPrice": 21000, "engine": {"displacement": 3000, "car": {"name": "X5"},
+				"dealership": {"city": "Bangalore"}}}}`},
+		{desc: "not found", query: `{ car(id: "` + id2.String() + `") { name } }`, data: `{"car": null}`,
+			codes: []string{"NOT_FOUND"}},
+		{desc: "invalid id", query: `{ car(id: "abc") { name } }`, data: `{"car": null}`,
+			codes: []string{"BAD_USER_INPUT"}},
+		{desc: "service error", query: `{ car(id: "` + id3.String() + `") { name } }`, data: `{"car": null}`,
+			codes: []string{"INTERNAL"}},
+	}
+
+	for i, tc := range testCases {
+		res := serve(t, h, auth.RoleViewer, body(tc.query))
+
+		assert.JSONEq(t, tc.data, string(res.Data), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.codes, res.codes(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestEngines tests that the cars of a list of engines are fetched in a single call
+func TestEngines(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCars := service.NewMockCars(ctrl)
+	h := New(mockCars, service.NewMockDealerships(ctrl))
+
+	ids := []string{id1.String(), id2.String(), id3.String()}
+
+	mockCars.EXPECT().GetEngines(gomock.Any(), ids).Return([]models.Engine{
+		{EngineID: id1, Displacement: 3000, Cylinders: 6}, {EngineID: id2, Range: 400},
+	}, nil)
+	mockCars.EXPECT().GetByIDs(gomock.Any(), ids).Return([]models.Car{
+		{ID: id1, Name: "X5", Engine: models.Engine{EngineID: id1, Displacement: 3000, Cylinders: 6}},
+		{ID: id2, Name: "Model 3", Engine: models.Engine{EngineID: id2, Range: 400}},
+	}, nil)
+
+	res := serve(t, h, auth.RoleViewer, body(`{ engines(ids: ["`+strings.Join(ids, `", "`)+`"]) {
+		range car { name dealership { name } } } }`))
+
+	assert.Equal(t, 0, len(res.Errors))
+	assert.JSONEq(t, `{"engines": [
+		{"range": 0, "car": {"name": "X5", "dealership": null}},
+		{"range": 400, "car": {"name": "Model 3", "dealership": null}}
+	]}`, string(res.Data))
+}
+
+// TestMutations tests the mutations along with their permissions
+func TestMutations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCars := service.NewMockCars(ctrl)
+	h := New(mockCars, service.NewMockDealerships(ctrl))
+
+	input := `{name: "X5", year: 2020, brand: "BMW", fuelType: "Petrol", dealershipId: "` + d1.String() +
+		`", engine: {displacement: 3000, cylinders: 6, range: 0}}`
+	car := models.Car{Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol", DealershipID: &d1,
+		Engine: models.Engine{Displacement: 3000, Cylinders: 6}}
+	created := car
+	created.ID = id1
+	created.Engine.EngineID = id1
+
+	mockCars.EXPECT().Create(gomock.Any(), &car, false).Return(created, nil)
+	mockCars.EXPECT().Create(gomock.Any(), &car, true).Return(created, nil)
+	mockCars.EXPECT().Update(gomock.Any(), id1.String(), &car).Return(created, nil)
+	mockCars.EXPECT().Delete(gomock.Any(), id1.String()).Return(nil)
+	mockCars.EXPECT().Delete(gomock.Any(), id2.String()).
+		Return(errors.EntityNotFound{Entity: "Car", ID: id2.String()})
+
+	testCases := []struct {
+		desc  string
+		role  string
+		query string
+		data  string
+		codes []string
+	}{
+		{desc: "create", role: auth.RoleSales, query: `mutation { createCar(input: ` + input + `) { id engine { id } } }`,
+			data: `{"createCar": {"id": "` + id1.String() + `", "engine": {"id": "` + id1.String() + `"}}}`},
+		{desc: "create duplicate", role: auth.RoleSales, query: `mutation { createCar(input: ` + input +
+			`, allowDuplicate: true) { id } }`, data: `{"createCar": {"id": "` + id1.String() + `"}}`},
+		{desc: "create forbidden", role: auth.RoleViewer, query: `mutation { createCar(input: ` + input + `) { id } }`,
+			data: `null`, codes: []string{"FORBIDDEN"}},
+		{desc: "update", role: auth.RoleSales, query: `mutation { updateCar(id: "` + id1.String() + `", input: ` +
+			input + `) { name } }`, data: `{"updateCar": {"name": "X5"}}`},
+		{desc: "invalid dealership", role: auth.RoleSales, query: `mutation { createCar(input: {name: "X5",
+			year: 2020, brand: "BMW", fuelType: "Petrol", dealershipId: "abc", engine: {displacement: 3000,
+			cylinders: 6, range: 0}}) { id } }`, data: `null`, codes: []string{"BAD_USER_INPUT"}},
+		{desc: "delete", role: auth.RoleManager, query: `mutation { deleteCar(id: "` + id1.String() + `") }`,
+			data: `{"deleteCar": true}`},
+		{desc: "delete not found", role: auth.RoleManager, query: `mutation { deleteCar(id: "` + id2.String() +
+			`") }`, data: `null`, codes: []string{"NOT_FOUND"}},
+		{desc: "delete forbidden", role: auth.RoleSales, query: `mutation { deleteCar(id: "` + id1.String() + `") }`,
+			data: `null`, codes: []string{"FORBIDDEN"}},
+	}
+
+	for i, tc := range testCases {
+		res := serve(t, h, tc.role, body(tc.query))
+
+		assert.JSONEq(t, tc.data, string(res.Data), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.codes, res.codes(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestServeErrors tests the bodies that are rejected before the query is run
+func TestServeErrors(t *testing.T) {
+	h := New(nil, nil)
+
+	testCases := []struct {
+		desc string
+		body string
+		err  error
+	}{
+		{desc: "invalid json", body: `{`, err: errors.InvalidParam{Param: []string{"body"}}},
+		{desc: "missing query", body: `{"query": " "}`, err: errors.MissingParam{Param: []string{"query"}}},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), gofr.New())
+
+		err := h.Serve(ctx, w)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestConvert tests the mapping of service errors to error codes
+func TestConvert(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	testCases := []struct {
+		desc string
+		err  error
+		out  error
+	}{
+		{desc: "nil", err: nil, out: nil},
+		{desc: "not found", err: errors.EntityNotFound{Entity: "Car", ID: "1"},
+			out: resolverError{code: "NOT_FOUND", message: errors.EntityNotFound{Entity: "Car", ID: "1"}.Error()}},
+		{desc: "missing param", err: errors.MissingParam{Param: []string{"brand"}},
+			out: resolverError{code: "BAD_USER_INPUT", message: errors.MissingParam{Param: []string{"brand"}}.Error()}},
+		{desc: "forbidden", err: auth.Check(ctx, auth.ReadCars),
+			out: resolverError{code: "FORBIDDEN", message: "missing permission cars:read"}},
+		{desc: "unexpected", err: errors.Error("db down"), out: resolverError{code: "INTERNAL", message: "internal error"}},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.out, convert(ctx, tc.err), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,88 @@
+package graphql
+
+import (
+	"sort"
+	"sync"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// fetchFunc loads the entities with the given ids keyed by id, ids that do not exist are left out
+type fetchFunc func(ctx *gofr.Context, ids []string) (map[string]interface{}, error)
+
+// loader batches the lookups of one kind of entity made while resolving a request. The resolvers of lists
+// prime the ids their items are going to need, the first load then fetches every primed id in a single call
+// and the loads of the other items are served from what it fetched.
+type loader struct {
+	fetch   fetchFunc
+	mu      sync.Mutex
+	pending map[string]bool
+	done    map[string]bool
+	values  map[string]interface{}
+}
+
+func newLoader(fetch fetchFunc) *loader {
+	return &loader{
+		fetch:   fetch,
+		pending: make(map[string]bool),
+		done:    make(map[string]bool),
+		values:  make(map[string]interface{}),
+	}
+}
+
+// prime registers ids to be fetched along with the next load
+func (l *loader) prime(ids ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, id := range ids {
+		if !l.done[id] {
+			l.pending[id] = true
+		}
+	}
+}
+
+// add records an entity that was loaded by other means so that it is not fetched again
+func (l *loader) add(id string, value interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.pending, id)
+	l.done[id] = true
+	l.values[id] = value
+}
+
+// load returns the entity with the id, found is false when it does not exist
+func (l *loader) load(ctx *gofr.Context, id string) (value interface{}, found bool, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.done[id] {
+		l.pending[id] = true
+
+		ids := make([]string, 0, len(l.pending))
+		for k := range l.pending {
+			ids = append(ids, k)
+		}
+
+		sort.Strings(ids)
+
+		values, err := l.fetch(ctx, ids)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, k := range ids {
+			delete(l.pending, k)
+			l.done[k] = true
+		}
+
+		for k, v := range values {
+			l.values[k] = v
+		}
+	}
+
+	value, found = l.values[id]
+
+	return value, found, nil
+}
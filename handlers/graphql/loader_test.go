@@ -0,0 +1,85 @@
+package graphql
+
+import (
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLoader tests that primed ids are fetched along with the first load and never again
+func TestLoader(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	var calls [][]string
+
+	l := newLoader(func(_ *gofr.Context, ids []string) (map[string]interface{}, error) {
+		calls = append(calls, ids)
+
+		res := make(map[string]interface{})
+
+		for _, id := range ids {
+			if id != "missing" {
+				res[id] = "value " + id
+			}
+		}
+
+		return res, nil
+	})
+
+	l.prime("b", "a", "missing")
+	l.add("c", "added")
+
+	testCases := []struct {
+		desc  string
+		id    string
+		value interface{}
+		found bool
+		calls [][]string
+	}{
+		{desc: "fetches the primed ids", id: "a", value: "value a", found: true,
+			calls: [][]string{{"a", "b", "missing"}}},
+		{desc: "served from the batch", id: "b", value: "value b", found: true,
+			calls: [][]string{{"a", "b", "missing"}}},
+		{desc: "missing ids are not fetched again", id: "missing",
+			calls: [][]string{{"a", "b", "missing"}}},
+		{desc: "added by other means", id: "c", value: "added", found: true,
+			calls: [][]string{{"a", "b", "missing"}}},
+		{desc: "ids that were not primed", id: "d", value: "value d", found: true,
+			calls: [][]string{{"a", "b", "missing"}, {"d"}}},
+	}
+
+	for i, tc := range testCases {
+		value, found, err := l.load(ctx, tc.id)
+
+		assert.Equal(t, nil, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.value, value, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.found, found, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.calls, calls, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestLoaderError tests that the ids of a failed fetch are tried again by the next load
+func TestLoaderError(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	fail := true
+
+	l := newLoader(func(_ *gofr.Context, ids []string) (map[string]interface{}, error) {
+		if fail {
+			return nil, errors.Error("db down")
+		}
+
+		return map[string]interface{}{"a": 1}, nil
+	})
+
+	_, _, err := l.load(ctx, "a")
+	assert.Equal(t, errors.Error("db down"), err)
+
+	fail = false
+
+	value, found, err := l.load(ctx, "a")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, true, found)
+}
@@ -0,0 +1,449 @@
+package graphql
+
+import (
+	"Project/CarDealearship/auth"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/service"
+	"context"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+type stateKey struct{}
+
+// state is the state of one GraphQL request, it reaches the root resolvers through their context and is
+// handed down to the resolvers of the fields
+type state struct {
+	ctx         *gofr.Context
+	cars        *loader
+	engines     *loader
+	dealerships *loader
+}
+
+func newState(ctx *gofr.Context, c service.Cars, d service.Dealerships) *state {
+	req := &state{ctx: ctx}
+
+	// cars are loaded along with their engines, so those do not have to be fetched again
+	req.cars = newLoader(func(ctx *gofr.Context, ids []string) (map[string]interface{}, error) {
+		cars, err := c.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		res := make(map[string]interface{}, len(cars))
+
+		for i := range cars {
+			id := cars[i].ID.String()
+			res[id] = cars[i]
+
+			if cars[i].Engine.UpdatedAt != nil {
+				req.engines.add(id, cars[i].Engine)
+			}
+
+			if cars[i].DealershipID != nil {
+				req.dealerships.prime(cars[i].DealershipID.String())
+			}
+		}
+
+		return res, nil
+	})
+
+	req.engines = newLoader(func(ctx *gofr.Context, ids []string) (map[string]interface{}, error) {
+		engines, err := c.GetEngines(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		res := make(map[string]interface{}, len(engines))
+		for i := range engines {
+			res[engines[i].EngineID.String()] = engines[i]
+		}
+
+		return res, nil
+	})
+
+	req.dealerships = newLoader(func(ctx *gofr.Context, ids []string) (map[string]interface{}, error) {
+		dealerships, err := d.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		res := make(map[string]interface{}, len(dealerships))
+		for i := range dealerships {
+			res[dealerships[i].ID.String()] = dealerships[i]
+		}
+
+		return res, nil
+	})
+
+	return req
+}
+
+func fromContext(ctx context.Context) *state {
+	return ctx.Value(stateKey{}).(*state)
+}
+
+// check returns the resolver error of a principal missing the permission
+func (r *state) check(permission auth.Permission) error {
+	return convert(r.ctx, auth.Check(r.ctx, permission))
+}
+
+// car returns the car with the id, or nil when it does not exist
+func (r *state) car(id string) (*carResolver, error) {
+	v, ok, err := r.cars.load(r.ctx, id)
+	if err != nil || !ok {
+		return nil, convert(r.ctx, err)
+	}
+
+	return &carResolver{req: r, car: v.(models.Car)}, nil
+}
+
+// engine returns the engine with the id, or nil when it does not exist
+func (r *state) engine(id string) (*engineResolver, error) {
+	v, ok, err := r.engines.load(r.ctx, id)
+	if err != nil || !ok {
+		return nil, convert(r.ctx, err)
+	}
+
+	return &engineResolver{req: r, engine: v.(models.Engine)}, nil
+}
+
+// dealership returns the dealership with the id, or nil when it does not exist
+func (r *state) dealership(id string) (*dealershipResolver, error) {
+	v, ok, err := r.dealerships.load(r.ctx, id)
+	if err != nil || !ok {
+		return nil, convert(r.ctx, err)
+	}
+
+	return &dealershipResolver{dealership: v.(models.Dealership)}, nil
+}
+
+// resolver is the root resolver, the queries and mutations delegate to the car service
+type resolver struct {
+	cars service.Cars
+}
+
+type carsArgs struct {
+	Brand    string
+	FuelType *string
+	YearFrom *int32
+	YearTo   *int32
+}
+
+// matches reports whether the car passes the optional filters of the cars query
+func (a carsArgs) matches(c *models.Car) bool {
+	return (a.FuelType == nil || *a.FuelType == c.FuelType) &&
+		(a.YearFrom == nil || c.Year >= int(*a.YearFrom)) &&
+		(a.YearTo == nil || c.Year <= int(*a.YearTo))
+}
+
+type carInput struct {
+	Name         string
+	Year         int32
+	Brand        string
+	FuelType     string
+	CostPrice    *int32
+	DealershipID *gql.ID
+	Engine       engineInput
+}
+
+type engineInput struct {
+	Displacement int32
+	Cylinders    int32
+	Range        int32
+}
+
+// model converts the input to the car model used by the service layer
+func (in carInput) model() (models.Car, error) {
+	c := models.Car{Name: in.Name, Year: int(in.Year), Brand: in.Brand, FuelType: in.FuelType,
+		Engine: models.Engine{Displacement: int(in.Engine.Displacement), Cylinders: int(in.Engine.Cylinders),
+			Range: int(in.Engine.Range)}}
+
+	if in.CostPrice != nil {
+		cost := int(*in.CostPrice)
+		c.CostPrice = &cost
+	}
+
+	if in.DealershipID != nil {
+		id, err := uuid.Parse(string(*in.DealershipID))
+		if err != nil {
+			return models.Car{}, errors.InvalidParam{Param: []string{"dealershipId"}}
+		}
+
+		c.DealershipID = &id
+	}
+
+	return c, nil
+}
+
+// Car resolves a car by its id
+func (r *resolver) Car(ctx context.Context, args struct{ ID gql.ID }) (*carResolver, error) {
+	req := fromContext(ctx)
+	if err := req.check(auth.ReadCars); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(req.ctx, args.ID, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := req.car(id)
+	if err == nil && c == nil {
+		err = convert(req.ctx, errors.EntityNotFound{Entity: "Car", ID: id})
+	}
+
+	return c, err
+}
+
+// Cars resolves the cars of a brand narrowed down by the optional filters. The engines and dealerships of
+// the cars are primed so that they are fetched in a single call each.
+func (r *resolver) Cars(ctx context.Context, args carsArgs) ([]*carResolver, error) {
+	req := fromContext(ctx)
+	if err := req.check(auth.ReadCars); err != nil {
+		return nil, err
+	}
+
+	cars, err := r.cars.GetByBrand(req.ctx, args.Brand, false)
+	if err != nil {
+		return nil, convert(req.ctx, err)
+	}
+
+	res := make([]*carResolver, 0, len(cars))
+
+	for i := range cars {
+		if !args.matches(&cars[i]) {
+			continue
+		}
+
+		id := cars[i].ID.String()
+		req.cars.add(id, cars[i])
+		req.engines.prime(id)
+
+		if cars[i].DealershipID != nil {
+			req.dealerships.prime(cars[i].DealershipID.String())
+		}
+
+		res = append(res, &carResolver{req: req, car: cars[i]})
+	}
+
+	return res, nil
+}
+
+// Engine resolves an engine by its id
+func (r *resolver) Engine(ctx context.Context, args struct{ ID gql.ID }) (*engineResolver, error) {
+	req := fromContext(ctx)
+	if err := req.check(auth.ReadCars); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(req.ctx, args.ID, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := req.engine(id)
+	if err == nil && e == nil {
+		err = convert(req.ctx, errors.EntityNotFound{Entity: "Engine", ID: id})
+	}
+
+	return e, err
+}
+
+// Engines resolves the engines with the given ids in their order, ids that do not exist are skipped
+func (r *resolver) Engines(ctx context.Context, args struct{ IDs []gql.ID }) ([]*engineResolver, error) {
+	req := fromContext(ctx)
+	if err := req.check(auth.ReadCars); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(args.IDs))
+
+	for i := range args.IDs {
+		id, err := parseID(req.ctx, args.IDs[i], "ids")
+		if err != nil {
+			return nil, err
+		}
+
+		ids[i] = id
+	}
+
+	req.engines.prime(ids...)
+	req.cars.prime(ids...)
+
+	res := make([]*engineResolver, 0, len(ids))
+
+	for _, id := range ids {
+		e, err := req.engine(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if e != nil {
+			res = append(res, e)
+		}
+	}
+
+	return res, nil
+}
+
+// CreateCar creates a car along with its engine, a likely duplicate of a car is only created with allowDuplicate
+func (r *resolver) CreateCar(ctx context.Context, args struct {
+	Input          carInput
+	AllowDuplicate *bool
+}) (*carResolver, error) {
+	req := fromContext(ctx)
+	if err := req.check(auth.WriteCars); err != nil {
+		return nil, err
+	}
+
+	c, err := args.Input.model()
+	if err != nil {
+		return nil, convert(req.ctx, err)
+	}
+
+	c, err = r.cars.Create(req.ctx, &c, args.AllowDuplicate != nil && *args.AllowDuplicate)
+	if err != nil {
+		return nil, convert(req.ctx, err)
+	}
+
+	// the engine was written along with the car, so it does not have to be fetched
+	req.engines.add(c.ID.String(), c.Engine)
+
+	return &carResolver{req: req, car: c}, nil
+}
+
+// UpdateCar replaces a car along with its engine
+func (r *resolver) UpdateCar(ctx context.Context, args struct {
+	ID    gql.ID
+	Input carInput
+}) (*carResolver, error) {
+	req := fromContext(ctx)
+	if err := req.check(auth.WriteCars); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(req.ctx, args.ID, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := args.Input.model()
+	if err != nil {
+		return nil, convert(req.ctx, err)
+	}
+
+	c, err = r.cars.Update(req.ctx, id, &c)
+	if err != nil {
+		return nil, convert(req.ctx, err)
+	}
+
+	req.engines.add(c.ID.String(), c.Engine)
+
+	return &carResolver{req: req, car: c}, nil
+}
+
+// DeleteCar deletes a car along with its engine
+func (r *resolver) DeleteCar(ctx context.Context, args struct{ ID gql.ID }) (bool, error) {
+	req := fromContext(ctx)
+	if err := req.check(auth.DeleteCars); err != nil {
+		return false, err
+	}
+
+	id, err := parseID(req.ctx, args.ID, "id")
+	if err != nil {
+		return false, err
+	}
+
+	if err = r.cars.Delete(req.ctx, id); err != nil {
+		return false, convert(req.ctx, err)
+	}
+
+	return true, nil
+}
+
+type carResolver struct {
+	req *state
+	car models.Car
+}
+
+func (r *carResolver) ID() gql.ID       { return gql.ID(r.car.ID.String()) }
+func (r *carResolver) Name() string     { return r.car.Name }
+func (r *carResolver) Year() int32      { return int32(r.car.Year) }
+func (r *carResolver) Brand() string    { return r.car.Brand }
+func (r *carResolver) FuelType() string { return r.car.FuelType }
+
+// CostPrice is null for principals not allowed to read it, the service layer redacts it
+func (r *carResolver) CostPrice() *int32 {
+	if r.car.CostPrice == nil {
+		return nil
+	}
+
+	cost := int32(*r.car.CostPrice)
+
+	return &cost
+}
+
+// Engine is batched through the engine loader unless the car was loaded along with it. Cars loaded without their
+// engine still carry its id, so only the engines read from the database, which have UpdatedAt set, are used.
+func (r *carResolver) Engine() (*engineResolver, error) {
+	if r.car.Engine.UpdatedAt != nil {
+		return &engineResolver{req: r.req, engine: r.car.Engine}, nil
+	}
+
+	return r.req.engine(r.car.ID.String())
+}
+
+func (r *carResolver) Dealership() (*dealershipResolver, error) {
+	if r.car.DealershipID == nil {
+		return nil, nil
+	}
+
+	return r.req.dealership(r.car.DealershipID.String())
+}
+
+type engineResolver struct {
+	req    *state
+	engine models.Engine
+}
+
+func (r *engineResolver) ID() gql.ID          { return gql.ID(r.engine.EngineID.String()) }
+func (r *engineResolver) Displacement() int32 { return int32(r.engine.Displacement) }
+func (r *engineResolver) Cylinders() int32    { return int32(r.engine.Cylinders) }
+func (r *engineResolver) Range() int32        { return int32(r.engine.Range) }
+
+// Car is the car the engine belongs to, it shares the id of the engine
+func (r *engineResolver) Car() (*carResolver, error) {
+	return r.req.car(r.engine.EngineID.String())
+}
+
+// Dealership is the dealership of the car the engine belongs to
+func (r *engineResolver) Dealership() (*dealershipResolver, error) {
+	c, err := r.Car()
+	if err != nil || c == nil {
+		return nil, err
+	}
+
+	return c.Dealership()
+}
+
+type dealershipResolver struct {
+	dealership models.Dealership
+}
+
+func (r *dealershipResolver) ID() gql.ID   { return gql.ID(r.dealership.ID.String()) }
+func (r *dealershipResolver) Name() string { return r.dealership.Name }
+func (r *dealershipResolver) City() string { return r.dealership.City }
+
+// parseID checks that an id argument is a uuid, and returns it in the form used as the key of the loaders
+func parseID(ctx *gofr.Context, id gql.ID, param string) (string, error) {
+	u, err := uuid.Parse(string(id))
+	if err != nil {
+		return "", convert(ctx, errors.InvalidParam{Param: []string{param}})
+	}
+
+	return u.String(), nil
+}
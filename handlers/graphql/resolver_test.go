@@ -0,0 +1,61 @@
+package graphql
+
+import (
+	"Project/CarDealearship/models"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMatches tests the optional filters of the cars query
+func TestMatches(t *testing.T) {
+	diesel := "Diesel"
+	from, to := int32(2015), int32(2020)
+	car := models.Car{Brand: "BMW", FuelType: "Diesel", Year: 2018}
+
+	testCases := []struct {
+		desc    string
+		args    carsArgs
+		matches bool
+	}{
+		{desc: "no filters", args: carsArgs{Brand: "BMW"}, matches: true},
+		{desc: "all filters", args: carsArgs{FuelType: &diesel, YearFrom: &from, YearTo: &to}, matches: true},
+		{desc: "too old", args: carsArgs{YearFrom: &to}},
+		{desc: "too new", args: carsArgs{YearTo: &from}},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.matches, tc.args.matches(&car), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestModel tests the conversion of the car input to the car model
+func TestModel(t *testing.T) {
+	cost := int32(21000)
+	dealership := gql.ID(id1.String())
+	invalid := gql.ID("abc")
+	price := 21000
+
+	testCases := []struct {
+		desc  string
+		input carInput
+		car   models.Car
+		err   error
+	}{
+		{desc: "all fields", input: carInput{Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol",
+			CostPrice: &cost, DealershipID: &dealership, Engine: engineInput{Displacement: 3000, Cylinders: 6}},
+			car: models.Car{Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol", CostPrice: &price,
+				DealershipID: &id1, Engine: models.Engine{Displacement: 3000, Cylinders: 6}}},
+		{desc: "invalid dealership", input: carInput{Name: "X5", DealershipID: &invalid},
+			err: errors.InvalidParam{Param: []string{"dealershipId"}}},
+	}
+
+	for i, tc := range testCases {
+		car, err := tc.input.model()
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.car, car, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
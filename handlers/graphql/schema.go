@@ -0,0 +1,64 @@
+package graphql
+
+// schema is the GraphQL schema of the inventory. The id of an engine is the id of the car it belongs to.
+const schema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	car(id: ID!): Car
+	cars(brand: String!, fuelType: String, yearFrom: Int, yearTo: Int): [Car!]!
+	engine(id: ID!): Engine
+	engines(ids: [ID!]!): [Engine!]!
+}
+
+type Mutation {
+	createCar(input: CarInput!, allowDuplicate: Boolean): Car!
+	updateCar(id: ID!, input: CarInput!): Car!
+	deleteCar(id: ID!): Boolean!
+}
+
+type Car {
+	id: ID!
+	name: String!
+	year: Int!
+	brand: String!
+	fuelType: String!
+	costPrice: Int
+	engine: Engine
+	dealership: Dealership
+}
+
+type Engine {
+	id: ID!
+	displacement: Int!
+	cylinders: Int!
+	range: Int!
+	car: Car
+	dealership: Dealership
+}
+
+type Dealership {
+	id: ID!
+	name: String!
+	city: String!
+}
+
+input CarInput {
+	name: String!
+	year: Int!
+	brand: String!
+	fuelType: String!
+	costPrice: Int
+	dealershipId: ID
+	engine: EngineInput!
+}
+
+input EngineInput {
+	displacement: Int!
+	cylinders: Int!
+	range: Int!
+}
+`
@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"Project/CarDealearship/middleware"
 	"Project/CarDealearship/models"
 	"Project/CarDealearship/service"
 	"developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 	"strconv"
+	"strings"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
 )
 
 type handler struct {
@@ -22,7 +29,8 @@ type response struct {
 	Customers []models.Car
 }
 
-// GetByID function is the delivery function to get a car by its id
+// GetByID function is the delivery function to get a car by its id, it was last modified when the car or its
+// engine last changed
 func (c handler) GetByID(ctx *gofr.Context) (interface{}, error) {
 	id := ctx.PathParam("id")
 
@@ -31,6 +39,8 @@ func (c handler) GetByID(ctx *gofr.Context) (interface{}, error) {
 		return resp, err
 	}
 
+	setLastModified(ctx, &resp)
+
 	return resp, nil
 }
 
@@ -39,9 +49,13 @@ func (c handler) GetByBrand(ctx *gofr.Context) (interface{}, error) {
 	brand := ctx.Param("brand")
 	isEngine := ctx.Param("isEngine")
 
+	if isEngine == "" {
+		return nil, errors.MissingParam{Param: []string{"isEngine"}}
+	}
+
 	isEng, err := strconv.ParseBool(isEngine)
 	if err != nil {
-		return nil, err
+		return nil, errors.InvalidParam{Param: []string{"isEngine"}}
 	}
 
 	resp, err := c.service.GetByBrand(ctx, brand, isEng)
@@ -49,20 +63,64 @@ func (c handler) GetByBrand(ctx *gofr.Context) (interface{}, error) {
 		return nil, err
 	}
 
+	// a list has no Last-Modified, as a car leaving it, deleted or moved to another brand, changes it without
+	// a later time in the cars left, it is only validated by its ETag
 	r := response{Customers: resp}
 
 	return r, nil
 }
 
-// Create is the delivery function to create a model of a car
+// Search is a handler function to find the cars matching the free text query q, most relevant first.
+func (c handler) Search(ctx *gofr.Context) (interface{}, error) {
+	limit := defaultSearchLimit
+
+	if l := ctx.Param("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 || n > maxSearchLimit {
+			return nil, errors.InvalidParam{Param: []string{"limit"}}
+		}
+
+		limit = n
+	}
+
+	resp, err := c.service.Search(ctx, ctx.Param("q"), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Compare is a handler function to compare the cars listed in the comma separated ids parameter
+func (c handler) Compare(ctx *gofr.Context) (interface{}, error) {
+	ids := ctx.Param("ids")
+	if ids == "" {
+		return nil, errors.MissingParam{Param: []string{"ids"}}
+	}
+
+	resp, err := c.service.Compare(ctx, strings.Split(ids, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Create is the delivery function to create a model of a car, a likely duplicate of a car is only created with
+// allowDuplicate=true
 func (c handler) Create(ctx *gofr.Context) (interface{}, error) {
+	allowDuplicate, err := boolParam(ctx, "allowDuplicate")
+	if err != nil {
+		return nil, err
+	}
+
 	var car models.Car
 	if err := ctx.Bind(&car); err != nil {
 		ctx.Logger.Errorf("error in binding: %v", err)
 		return nil, errors.InvalidParam{Param: []string{"body"}}
 	}
 
-	resp, err := c.service.Create(ctx, &car)
+	resp, err := c.service.Create(ctx, &car, allowDuplicate)
 	if err != nil {
 		return nil, err
 	}
@@ -104,3 +162,34 @@ func (c handler) Delete(ctx *gofr.Context) (interface{}, error) {
 
 	return "Deleted successfully", nil
 }
+
+// Duplicates is a handler function to report the cars likely entered more than once, for them to be cleaned up
+func (c handler) Duplicates(ctx *gofr.Context) (interface{}, error) {
+	return c.service.Duplicates(ctx)
+}
+
+// setLastModified reports when the car or its engine last changed to the Conditional middleware
+func setLastModified(ctx *gofr.Context, car *models.Car) {
+	if car.UpdatedAt != nil {
+		middleware.SetLastModified(ctx, *car.UpdatedAt)
+	}
+
+	if car.Engine.UpdatedAt != nil {
+		middleware.SetLastModified(ctx, *car.Engine.UpdatedAt)
+	}
+}
+
+// boolParam reads an optional boolean query parameter, false when it is absent
+func boolParam(ctx *gofr.Context, name string) (bool, error) {
+	v := ctx.Param(name)
+	if v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.InvalidParam{Param: []string{name}}
+	}
+
+	return b, nil
+}
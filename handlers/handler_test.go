@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"Project/CarDealearship/middleware"
 	"Project/CarDealearship/models"
 	"Project/CarDealearship/service"
 	"developer.zopsmart.com/go/gofr/pkg/errors"
@@ -10,8 +11,11 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 // TestGetByID to test the handler GetByID
@@ -82,3 +86,282 @@ func TestGetByID(t *testing.T) {
 		assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
 	}
 }
+
+// TestGetByBrand to test the handler GetByBrand
+func TestGetByBrand(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	s := New(mockService)
+	app := gofr.New()
+
+	cars := []models.Car{{ID: uuid.New(), Name: "X5", Brand: "BMW"}}
+
+	testCases := []struct {
+		desc   string
+		target string
+		resp   interface{}
+		err    error
+		mock   []*gomock.Call
+	}{
+		{
+			desc:   "success case",
+			target: "/cars?brand=BMW&isEngine=true",
+			resp:   response{Customers: cars},
+			mock:   []*gomock.Call{mockService.EXPECT().GetByBrand(gomock.Any(), "BMW", true).Return(cars, nil)},
+		},
+		{
+			desc:   "missing isEngine",
+			target: "/cars?brand=BMW",
+			err:    errors.MissingParam{Param: []string{"isEngine"}},
+		},
+		{
+			desc:   "invalid isEngine",
+			target: "/cars?brand=BMW&isEngine=maybe",
+			err:    errors.InvalidParam{Param: []string{"isEngine"}},
+		},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest("GET", tc.target, nil)
+		w := httptest.NewRecorder()
+
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), app)
+
+		resp, err := s.GetByBrand(ctx)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.err == nil {
+			assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+// TestSearch to test the handler Search
+func TestSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	s := New(mockService)
+	app := gofr.New()
+
+	id := uuid.New()
+	results := []models.SearchResult{{Car: models.Car{ID: id, Name: "Model 3", Brand: "Tesla"}, Score: 2.5}}
+
+	testCases := []struct {
+		desc   string
+		target string
+		resp   interface{}
+		err    error
+		mock   []*gomock.Call
+	}{
+		{
+			desc:   "success case",
+			target: "/cars/search?q=tesla",
+			resp:   results,
+			mock:   []*gomock.Call{mockService.EXPECT().Search(gomock.Any(), "tesla", 20).Return(results, nil)},
+		},
+		{
+			desc:   "custom limit",
+			target: "/cars/search?q=tesla&limit=5",
+			resp:   results,
+			mock:   []*gomock.Call{mockService.EXPECT().Search(gomock.Any(), "tesla", 5).Return(results, nil)},
+		},
+		{
+			desc:   "invalid limit",
+			target: "/cars/search?q=tesla&limit=abc",
+			err:    errors.InvalidParam{Param: []string{"limit"}},
+		},
+		{
+			desc:   "limit too large",
+			target: "/cars/search?q=tesla&limit=1000",
+			err:    errors.InvalidParam{Param: []string{"limit"}},
+		},
+		{
+			desc:   "missing query",
+			target: "/cars/search",
+			err:    errors.MissingParam{Param: []string{"q"}},
+			mock: []*gomock.Call{mockService.EXPECT().Search(gomock.Any(), "", 20).
+				Return(nil, errors.MissingParam{Param: []string{"q"}})},
+		},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest("GET", tc.target, nil)
+		w := httptest.NewRecorder()
+
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), app)
+
+		resp, err := s.Search(ctx)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.err == nil {
+			assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+// TestCompare to test the handler Compare
+func TestCompare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	s := New(mockService)
+	app := gofr.New()
+
+	id1 := uuid.New().String()
+	id2 := uuid.New().String()
+	comparison := models.Comparison{Cars: []models.Car{{Name: "X5"}, {Name: "Cayenne"}}}
+
+	mockService.EXPECT().Compare(gomock.Any(), []string{id1, id2}).Return(comparison, nil)
+	mockService.EXPECT().Compare(gomock.Any(), []string{id1, id2}).
+		Return(models.Comparison{}, errors.EntityNotFound{Entity: "Car", ID: id2})
+
+	testCases := []struct {
+		desc   string
+		target string
+		resp   interface{}
+		err    error
+	}{
+		{desc: "success case", target: "/cars/compare?ids=" + id1 + "," + id2, resp: comparison},
+		{desc: "not found", target: "/cars/compare?ids=" + id1 + "," + id2,
+			err: errors.EntityNotFound{Entity: "Car", ID: id2}},
+		{desc: "missing ids", target: "/cars/compare", err: errors.MissingParam{Param: []string{"ids"}}},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest("GET", tc.target, nil)
+		w := httptest.NewRecorder()
+
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), app)
+
+		resp, err := s.Compare(ctx)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.err == nil {
+			assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+// TestCreate to test the handler Create along with its override of the duplicate check
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	s := New(mockService)
+	app := gofr.New()
+
+	body := `{"Name":"X5","Year":2020,"Brand":"BMW","FuelType":"Petrol","VIN":"WBAKS4100K0000000"}`
+	car := models.Car{Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol", VIN: "WBAKS4100K0000000"}
+	created := car
+	created.ID = uuid.New()
+	conflict := &errors.Response{StatusCode: http.StatusConflict, Code: "DUPLICATE_CAR"}
+
+	mockService.EXPECT().Create(gomock.Any(), &car, false).Return(models.Car{}, conflict)
+	mockService.EXPECT().Create(gomock.Any(), &car, true).Return(created, nil)
+
+	testCases := []struct {
+		desc   string
+		target string
+		resp   interface{}
+		err    error
+	}{
+		{desc: "likely duplicate", target: "/car", err: conflict},
+		{desc: "duplicate allowed", target: "/car?allowDuplicate=true", resp: created},
+		{desc: "invalid override", target: "/car?allowDuplicate=yes",
+			err: errors.InvalidParam{Param: []string{"allowDuplicate"}}},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), app)
+
+		resp, err := s.Create(ctx)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.err == nil {
+			assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+// TestDuplicates to test the handler Duplicates
+func TestDuplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	s := New(mockService)
+
+	groups := []models.DuplicateGroup{{Reason: models.DuplicateVIN, Cars: []models.Car{{Name: "X5"}, {Name: "X5"}}}}
+
+	mockService.EXPECT().Duplicates(gomock.Any()).Return(groups, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/cars/duplicates", nil)
+	ctx := gofr.NewContext(responder.NewContextualResponder(httptest.NewRecorder(), r), request.NewHTTPRequest(r),
+		gofr.New())
+
+	resp, err := s.Duplicates(ctx)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, groups, resp)
+}
+
+// TestLastModified to test that GetByID reports when its car last changed while GetByBrand, whose lists can
+// change without a later time in them, does not
+func TestLastModified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	s := New(mockService)
+	app := gofr.New()
+
+	updated := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	engineUpdated := time.Date(2022, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	id := uuid.New()
+	car := models.Car{ID: id, Name: "X5", Brand: "BMW", UpdatedAt: &updated,
+		Engine: models.Engine{EngineID: id, UpdatedAt: &engineUpdated}}
+	listed := models.Car{ID: id, Name: "X5", Brand: "BMW", UpdatedAt: &updated}
+
+	mockService.EXPECT().GetByID(gomock.Any(), id.String()).Return(car, nil)
+	mockService.EXPECT().GetByBrand(gomock.Any(), "BMW", false).Return([]models.Car{listed}, nil)
+
+	testCases := []struct {
+		desc         string
+		target       string
+		lastModified string
+	}{
+		{desc: "car", target: "/car/" + id.String(), lastModified: "Wed, 02 Mar 2022 10:00:00 GMT"},
+		{desc: "list", target: "/cars?brand=BMW&isEngine=false"},
+	}
+
+	handler := middleware.Conditional(middleware.CacheRoute{Path: "/car/{id}"}, middleware.CacheRoute{Path: "/cars"})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res := responder.NewContextualResponder(w, r)
+			ctx := gofr.NewContext(res, request.NewHTTPRequest(r), app)
+			ctx.SetPathParams(map[string]string{"id": id.String()})
+
+			h := s.GetByBrand
+			if r.URL.Path != "/cars" {
+				h = s.GetByID
+			}
+
+			res.Respond(h(ctx))
+		}))
+
+	for i, tc := range testCases {
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.lastModified, w.Header().Get("Last-Modified"), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
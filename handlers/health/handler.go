@@ -0,0 +1,48 @@
+package health
+
+import (
+	"Project/CarDealearship/health"
+	"Project/CarDealearship/models"
+	"encoding/json"
+	"net/http"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// contentType is the media type of health reports, it keeps a failing report from being rewritten into a
+// problem like the other json errors
+const contentType = "application/health+json"
+
+type handler struct {
+	health *health.Health
+}
+
+// nolint:revive // need not be exported
+// New factory function
+func New(h *health.Health) handler {
+	return handler{health: h}
+}
+
+// Live is the delivery function of the liveness probe, it answers 200 as long as the process serves requests
+func (h handler) Live(ctx *gofr.Context, w http.ResponseWriter) error {
+	return write(w, h.health.Live())
+}
+
+// Ready is the delivery function of the readiness probe, it answers 503 with the failed checks while the
+// process starts, shuts down or misses a dependency
+func (h handler) Ready(ctx *gofr.Context, w http.ResponseWriter) error {
+	return write(w, h.health.Ready(ctx))
+}
+
+func write(w http.ResponseWriter, report models.HealthReport) error {
+	status := http.StatusOK
+	if report.Status != health.StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	return json.NewEncoder(w).Encode(report)
+}
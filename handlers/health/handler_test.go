@@ -0,0 +1,65 @@
+package health
+
+import (
+	"Project/CarDealearship/health"
+	"Project/CarDealearship/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReady to test the readiness probe fails with 503 and the report until the process is serving
+func TestReady(t *testing.T) {
+	hc := health.New(time.Second)
+	hc.Register("database", health.CheckerFunc(func(context.Context) error { return nil }))
+
+	h := New(hc)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = context.Background()
+
+	testCases := []struct {
+		desc   string
+		setup  func()
+		status int
+		report string
+	}{
+		{desc: "starting", setup: func() {}, status: http.StatusServiceUnavailable, report: health.StatusDown},
+		{desc: "serving", setup: hc.SetServing, status: http.StatusOK, report: health.StatusUp},
+		{desc: "draining", setup: hc.SetDraining, status: http.StatusServiceUnavailable, report: health.StatusDown},
+	}
+
+	for i, tc := range testCases {
+		tc.setup()
+
+		w := httptest.NewRecorder()
+
+		assert.Equal(t, nil, h.Ready(ctx, w), "TEST[%d], failed.\n%s", i, tc.desc)
+
+		var report models.HealthReport
+
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.report, report.Status, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, "application/health+json", w.Header().Get("Content-Type"), "TEST[%d], failed.\n%s", i,
+			tc.desc)
+	}
+}
+
+// TestLive to test the liveness probe does not depend on the checks
+func TestLive(t *testing.T) {
+	hc := health.New(time.Second)
+	hc.Register("database", health.CheckerFunc(func(context.Context) error { return errors.Error("down") }))
+
+	w := httptest.NewRecorder()
+
+	assert.Equal(t, nil, New(hc).Live(gofr.NewContext(nil, nil, gofr.New()), w))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "{\"Status\":\"UP\",\"Checks\":[]}\n", w.Body.String())
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"Project/CarDealearship/models"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+const maxImportRows = 5000
+
+// csvColumns maps the accepted CSV header names to the car field they fill
+var csvColumns = map[string]string{
+	"name":         "name",
+	"year":         "year",
+	"brand":        "brand",
+	"fuel_type":    "fuel_type",
+	"fueltype":     "fuel_type",
+	"displacement": "displacement",
+	"cylinders":    "cylinders",
+	"range":        "range",
+	"cost_price":   "cost_price",
+	"costprice":    "cost_price",
+	"vin":          "vin",
+}
+
+var requiredColumns = []string{"name", "year", "brand", "fuel_type"}
+
+// Import is a handler function to create cars in bulk from a CSV (text/csv) or NDJSON (application/x-ndjson)
+// body. With dryRun=true the rows are only validated.
+func (c handler) Import(ctx *gofr.Context) (interface{}, error) {
+	dryRun, err := boolParam(ctx, "dryRun")
+	if err != nil {
+		return nil, err
+	}
+
+	r := ctx.Request()
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var rows []models.ImportRow
+
+	switch mediaType {
+	case "text/csv":
+		rows, err = parseCSV(r.Body)
+	case "application/x-ndjson", "application/jsonl":
+		rows, err = parseNDJSON(r.Body)
+	default:
+		return nil, errors.InvalidParam{Param: []string{"Content-Type"}}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.MissingParam{Param: []string{"body"}}
+	}
+
+	resp, err := c.service.Import(ctx, rows, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// parseCSV reads cars from a CSV file with a header row, rows are numbered from the first line after the header
+func parseCSV(body io.Reader) ([]models.ImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	columns := make(map[string]int, len(header))
+
+	for i, h := range header {
+		if field, ok := csvColumns[strings.ToLower(strings.TrimSpace(h))]; ok {
+			columns[field] = i
+		}
+	}
+
+	for _, col := range requiredColumns {
+		if _, ok := columns[col]; !ok {
+			return nil, errors.MissingParam{Param: []string{col}}
+		}
+	}
+
+	var rows []models.ImportRow
+
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, errors.InvalidParam{Param: []string{"body"}}
+		}
+
+		row := models.ImportRow{Row: n}
+
+		if err != nil {
+			row.Error = err.Error()
+		} else {
+			row.Car, row.Error = csvCar(record, columns)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// csvCar builds a car from a CSV record, returning a description of the first field that is not valid
+func csvCar(record []string, columns map[string]int) (models.Car, string) {
+	value := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	number := func(field string, dst *int) string {
+		v := value(field)
+		if v == "" {
+			return ""
+		}
+
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return field + ": " + strconv.Quote(v) + " is not a number"
+		}
+
+		*dst = n
+
+		return ""
+	}
+
+	car := models.Car{Name: value("name"), Brand: value("brand"), FuelType: value("fuel_type"), VIN: value("vin")}
+
+	numbers := []struct {
+		field string
+		dst   *int
+	}{
+		{"year", &car.Year},
+		{"displacement", &car.Engine.Displacement},
+		{"cylinders", &car.Engine.Cylinders},
+		{"range", &car.Engine.Range},
+	}
+
+	for _, n := range numbers {
+		if msg := number(n.field, n.dst); msg != "" {
+			return models.Car{}, msg
+		}
+	}
+
+	if value("cost_price") != "" {
+		var cost int
+		if msg := number("cost_price", &cost); msg != "" {
+			return models.Car{}, msg
+		}
+
+		car.CostPrice = &cost
+	}
+
+	return car, ""
+}
+
+// parseNDJSON reads one JSON encoded car per line, blank lines are skipped but still counted
+func parseNDJSON(body io.Reader) ([]models.ImportRow, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []models.ImportRow
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, errors.InvalidParam{Param: []string{"body"}}
+		}
+
+		row := models.ImportRow{Row: n}
+
+		if err := json.Unmarshal([]byte(line), &row.Car); err != nil {
+			row.Error = "invalid JSON: " + err.Error()
+		}
+
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/service"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestImport to test the handler Import
+func TestImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	s := New(mockService)
+	app := gofr.New()
+
+	cost := 42000
+	x5 := models.Car{Name: "X5", Year: 2019, Brand: "BMW", FuelType: "Diesel",
+		Engine: models.Engine{Displacement: 3000, Cylinders: 6}}
+	x5WithCost := x5
+	x5WithCost.CostPrice = &cost
+	model3 := models.Car{Name: "Model 3", Year: 2020, Brand: "Tesla", FuelType: "Electric",
+		Engine: models.Engine{Range: 500}}
+	report := models.ImportReport{Total: 2}
+
+	csvBody := "Name,Year,Brand,Fuel_Type,Displacement,Cylinders,Range,Cost_Price\n" +
+		"X5,2019,BMW,Diesel,3000,6,,42000\n" +
+		"\"Model 3\",2020,Tesla,Electric,,,500\n" +
+		"A4,twenty,Audi,Diesel,,,\n"
+	ndjsonBody := `{"Name":"X5","Year":2019,"Brand":"BMW","FuelType":"Diesel",` +
+		`"Engine":{"displacement":3000,"cylinders":6}}` + "\n\n" + `{"Name":` + "\n"
+
+	testCases := []struct {
+		desc        string
+		target      string
+		contentType string
+		body        string
+		resp        interface{}
+		err         error
+		mock        []*gomock.Call
+	}{
+		{
+			desc: "csv", target: "/cars/import", contentType: "text/csv; charset=utf-8", body: csvBody, resp: report,
+			mock: []*gomock.Call{mockService.EXPECT().Import(gomock.Any(), []models.ImportRow{
+				{Row: 1, Car: x5WithCost}, {Row: 2, Car: model3}, {Row: 3, Error: `year: "twenty" is not a number`},
+			}, false).Return(report, nil)},
+		},
+		{
+			desc: "ndjson dry run", target: "/cars/import?dryRun=true", contentType: "application/x-ndjson",
+			body: ndjsonBody, resp: report,
+			mock: []*gomock.Call{mockService.EXPECT().Import(gomock.Any(), []models.ImportRow{
+				{Row: 1, Car: x5}, {Row: 3, Error: "invalid JSON: unexpected end of JSON input"},
+			}, true).Return(report, nil)},
+		},
+		{
+			desc: "missing csv column", target: "/cars/import", contentType: "text/csv", body: "name,year,brand\n",
+			err: errors.MissingParam{Param: []string{"fuel_type"}},
+		},
+		{
+			desc: "unsupported content type", target: "/cars/import", contentType: "application/xml", body: "<cars/>",
+			err: errors.InvalidParam{Param: []string{"Content-Type"}},
+		},
+		{
+			desc: "invalid dry run", target: "/cars/import?dryRun=maybe", contentType: "text/csv", body: csvBody,
+			err: errors.InvalidParam{Param: []string{"dryRun"}},
+		},
+		{
+			desc: "empty file", target: "/cars/import", contentType: "application/x-ndjson", body: "\n",
+			err: errors.MissingParam{Param: []string{"body"}},
+		},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest("POST", tc.target, strings.NewReader(tc.body))
+		r.Header.Set("Content-Type", tc.contentType)
+
+		w := httptest.NewRecorder()
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), app)
+
+		resp, err := s.Import(ctx)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.err == nil {
+			assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
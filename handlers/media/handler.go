@@ -0,0 +1,140 @@
+package media
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/service"
+	"io"
+	"net/http"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/template"
+)
+
+const (
+	maxMemory   = 32 << 20
+	maxFileSize = 10 << 20
+	maxFiles    = 10
+	// maxBodySize bounds the whole form, the files above the memory limit spill to disk
+	maxBodySize = maxFiles*maxFileSize + 1<<20
+)
+
+type handler struct {
+	service service.Media
+}
+
+// nolint:revive // need not be exported
+// New factory function
+func New(m service.Media) handler {
+	return handler{service: m}
+}
+
+type orderRequest struct {
+	IDs []string `json:"IDs"`
+}
+
+// Upload is the delivery function to attach the files of a multipart form to a car
+func (h handler) Upload(ctx *gofr.Context) (interface{}, error) {
+	id := ctx.PathParam("id")
+
+	r := ctx.Request()
+	if r.ContentLength > maxBodySize {
+		return nil, &errors.Response{StatusCode: http.StatusRequestEntityTooLarge, Code: "PAYLOAD_TOO_LARGE",
+			Reason: "the files of an upload must not exceed 100MB in total"}
+	}
+
+	// a body of unknown length is cut at the limit and fails to parse
+	r.Body = http.MaxBytesReader(nil, r.Body, maxBodySize)
+
+	if err := r.ParseMultipartForm(maxMemory); err != nil {
+		ctx.Logger.Errorf("error in parsing multipart form: %v", err)
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	n := 0
+	for _, headers := range r.MultipartForm.File {
+		n += len(headers)
+	}
+
+	if n > maxFiles {
+		return nil, errors.InvalidParam{Param: []string{"files"}}
+	}
+
+	files := make([]models.Upload, 0, n)
+
+	for _, headers := range r.MultipartForm.File {
+		for _, fh := range headers {
+			if fh.Size > maxFileSize {
+				return nil, errors.InvalidParam{Param: []string{fh.Filename}}
+			}
+
+			f, err := fh.Open()
+			if err != nil {
+				return nil, err
+			}
+
+			content, err := io.ReadAll(f)
+			_ = f.Close()
+
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, models.Upload{FileName: fh.Filename, Content: content})
+		}
+	}
+
+	return h.service.Upload(ctx, id, files)
+}
+
+// GetByCarID is the delivery function to list the media of a car
+func (h handler) GetByCarID(ctx *gofr.Context) (interface{}, error) {
+	return h.service.GetByCarID(ctx, ctx.PathParam("id"))
+}
+
+// Reorder is the delivery function to change the display order of the media of a car
+func (h handler) Reorder(ctx *gofr.Context) (interface{}, error) {
+	var req orderRequest
+	if err := ctx.Bind(&req); err != nil {
+		ctx.Logger.Errorf("error in binding: %v", err)
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	return h.service.Reorder(ctx, ctx.PathParam("id"), req.IDs)
+}
+
+// SetCover is the delivery function to select the cover photo of a car
+func (h handler) SetCover(ctx *gofr.Context) (interface{}, error) {
+	return h.service.SetCover(ctx, ctx.PathParam("id"), ctx.PathParam("mediaID"))
+}
+
+// Delete is the delivery function to remove a media item from a car
+func (h handler) Delete(ctx *gofr.Context) (interface{}, error) {
+	if err := h.service.Delete(ctx, ctx.PathParam("id"), ctx.PathParam("mediaID")); err != nil {
+		return nil, err
+	}
+
+	return "Deleted successfully", nil
+}
+
+// File is the delivery function serving the content of a stored file
+func (h handler) File(ctx *gofr.Context) (interface{}, error) {
+	key := ctx.PathParam("key")
+	if key == "" {
+		return nil, errors.MissingParam{Param: []string{"key"}}
+	}
+
+	r, contentType, err := h.service.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.File{Content: content, ContentType: contentType}, nil
+}
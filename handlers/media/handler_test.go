@@ -0,0 +1,188 @@
+package media
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/service"
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/template"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContext(method, target, contentType string, body io.Reader, params map[string]string) *gofr.Context {
+	r := httptest.NewRequest(method, target, body)
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+
+	w := httptest.NewRecorder()
+	ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), gofr.New())
+	ctx.SetPathParams(params)
+
+	return ctx
+}
+
+// TestUpload tests the handler Upload
+func TestUpload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockMedia(ctrl)
+	h := New(mockService)
+
+	carID := uuid.New().String()
+	created := []models.Media{{ID: uuid.New(), Kind: models.MediaDocument, FileName: "brochure.pdf"}}
+
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("files", "brochure.pdf")
+	_, _ = fw.Write([]byte("%PDF-1.4"))
+	_ = mw.Close()
+
+	mockService.EXPECT().Upload(gomock.Any(), carID,
+		[]models.Upload{{FileName: "brochure.pdf", Content: []byte("%PDF-1.4")}}).Return(created, nil)
+
+	ctx := newContext("POST", "/car/"+carID+"/media", mw.FormDataContentType(), &body, map[string]string{"id": carID})
+
+	resp, err := h.Upload(ctx)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, created, resp)
+
+	ctx = newContext("POST", "/car/"+carID+"/media", "application/json", strings.NewReader("{}"),
+		map[string]string{"id": carID})
+
+	resp, err = h.Upload(ctx)
+
+	assert.Equal(t, errors.InvalidParam{Param: []string{"body"}}, err)
+	assert.Equal(t, nil, resp)
+}
+
+// TestUploadLimits tests that uploads above the body or file count limits are rejected before the service
+func TestUploadLimits(t *testing.T) {
+	h := New(nil)
+	carID := uuid.New().String()
+
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+
+	for i := 0; i <= maxFiles; i++ {
+		fw, _ := mw.CreateFormFile("files", "brochure.pdf")
+		_, _ = fw.Write([]byte("%PDF-1.4"))
+	}
+
+	_ = mw.Close()
+
+	ctx := newContext("POST", "/car/"+carID+"/media", mw.FormDataContentType(), &body, map[string]string{"id": carID})
+
+	_, err := h.Upload(ctx)
+
+	assert.Equal(t, errors.InvalidParam{Param: []string{"files"}}, err, "too many files")
+
+	ctx = newContext("POST", "/car/"+carID+"/media", mw.FormDataContentType(), strings.NewReader(""),
+		map[string]string{"id": carID})
+	ctx.Request().ContentLength = maxBodySize + 1
+
+	_, err = h.Upload(ctx)
+
+	if assert.IsType(t, &errors.Response{}, err, "body too large") {
+		assert.Equal(t, http.StatusRequestEntityTooLarge, err.(*errors.Response).StatusCode)
+	}
+}
+
+// TestReorder tests the handler Reorder
+func TestReorder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockMedia(ctrl)
+	h := New(mockService)
+
+	carID := uuid.New().String()
+	ids := []string{uuid.New().String(), uuid.New().String()}
+
+	mockService.EXPECT().Reorder(gomock.Any(), carID, ids).Return([]models.Media{}, nil)
+
+	ctx := newContext("PUT", "/car/"+carID+"/media/order", "application/json",
+		strings.NewReader(`{"IDs":["`+ids[0]+`","`+ids[1]+`"]}`), map[string]string{"id": carID})
+
+	resp, err := h.Reorder(ctx)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.Media{}, resp)
+
+	ctx = newContext("PUT", "/car/"+carID+"/media/order", "application/json", strings.NewReader(`{"IDs":`),
+		map[string]string{"id": carID})
+
+	_, err = h.Reorder(ctx)
+
+	assert.Equal(t, errors.InvalidParam{Param: []string{"body"}}, err)
+}
+
+// TestSetCoverAndDelete tests the handlers SetCover and Delete
+func TestSetCoverAndDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockMedia(ctrl)
+	h := New(mockService)
+
+	carID := uuid.New().String()
+	mediaID := uuid.New().String()
+	params := map[string]string{"id": carID, "mediaID": mediaID}
+
+	mockService.EXPECT().SetCover(gomock.Any(), carID, mediaID).Return([]models.Media{}, nil)
+	mockService.EXPECT().Delete(gomock.Any(), carID, mediaID).Return(nil)
+	mockService.EXPECT().Delete(gomock.Any(), carID, mediaID).Return(errors.EntityNotFound{Entity: "Media", ID: mediaID})
+
+	resp, err := h.SetCover(newContext("PUT", "/", "", nil, params))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.Media{}, resp)
+
+	resp, err = h.Delete(newContext("DELETE", "/", "", nil, params))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "Deleted successfully", resp)
+
+	resp, err = h.Delete(newContext("DELETE", "/", "", nil, params))
+	assert.Equal(t, errors.EntityNotFound{Entity: "Media", ID: mediaID}, err)
+	assert.Equal(t, nil, resp)
+}
+
+// TestFile tests the handler File
+func TestFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockMedia(ctrl)
+	h := New(mockService)
+
+	mockService.EXPECT().Open(gomock.Any(), "a.png").
+		Return(io.NopCloser(strings.NewReader("png")), "image/png", nil)
+	mockService.EXPECT().Open(gomock.Any(), "b.png").
+		Return(nil, "", errors.EntityNotFound{Entity: "Media", ID: "b.png"})
+
+	resp, err := h.File(newContext("GET", "/media/a.png", "", nil, map[string]string{"key": "a.png"}))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, template.File{Content: []byte("png"), ContentType: "image/png"}, resp)
+
+	resp, err = h.File(newContext("GET", "/media/b.png", "", nil, map[string]string{"key": "b.png"}))
+	assert.Equal(t, errors.EntityNotFound{Entity: "Media", ID: "b.png"}, err)
+	assert.Equal(t, nil, resp)
+
+	_, err = h.File(newContext("GET", "/media/", "", nil, nil))
+	assert.Equal(t, errors.MissingParam{Param: []string{"key"}}, err)
+}
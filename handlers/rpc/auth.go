@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"Project/CarDealearship/auth"
+	"Project/CarDealearship/middleware"
+	"context"
+	"net/http"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// credentialHeaders are the metadata keys passed on to the authenticator as the headers of the same name
+var credentialHeaders = []string{"Authorization", auth.APIKeyHeader}
+
+// authenticate returns ctx with the principal of the credentials of the call. The authenticator of the
+// HTTP api reads them from a request built out of the metadata.
+func authenticate(ctx context.Context, k *gofr.Gofr, a middleware.Authenticator, method string) (context.Context,
+	error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, method, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	for _, h := range credentialHeaders {
+		if v := md.Get(h); len(v) > 0 {
+			r.Header.Set(h, v[0])
+		}
+	}
+
+	c := gofr.NewContext(nil, request.NewHTTPRequest(r), k)
+	c.Context = ctx
+
+	p, err := a.Authenticate(c)
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	return auth.WithPrincipal(ctx, p), nil
+}
+
+func unaryAuthenticate(k *gofr.Gofr, a middleware.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, k, a, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func streamAuthenticate(k *gofr.Gofr, a middleware.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), k, a, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream is a server stream whose context carries the principal of the call
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/pb"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// toCar converts a car to its message, the engine is left out when it was not loaded
+func toCar(c *models.Car) *pb.Car {
+	res := &pb.Car{Id: c.ID.String(), Name: c.Name, Year: int32(c.Year), Brand: c.Brand, FuelType: c.FuelType}
+
+	if c.CostPrice != nil {
+		cost := int64(*c.CostPrice)
+		res.CostPrice = &cost
+	}
+
+	if c.Engine.EngineID != uuid.Nil {
+		res.Engine = toEngine(&c.Engine)
+	}
+
+	if c.DealershipID != nil {
+		res.DealershipId = c.DealershipID.String()
+	}
+
+	return res
+}
+
+func toEngine(e *models.Engine) *pb.Engine {
+	return &pb.Engine{Id: e.EngineID.String(), Displacement: int32(e.Displacement), Cylinders: int32(e.Cylinders),
+		Range: int32(e.Range)}
+}
+
+// fromCar converts the car of a request to the model used by the service layer, its id is ignored
+func fromCar(c *pb.Car) (models.Car, error) {
+	if c == nil {
+		return models.Car{}, errors.MissingParam{Param: []string{"car"}}
+	}
+
+	res := models.Car{Name: c.GetName(), Year: int(c.GetYear()), Brand: c.GetBrand(), FuelType: c.GetFuelType(),
+		Engine: models.Engine{Displacement: int(c.GetEngine().GetDisplacement()),
+			Cylinders: int(c.GetEngine().GetCylinders()), Range: int(c.GetEngine().GetRange())}}
+
+	if c.CostPrice != nil {
+		cost := int(c.GetCostPrice())
+		res.CostPrice = &cost
+	}
+
+	if c.GetDealershipId() != "" {
+		id, err := uuid.Parse(c.GetDealershipId())
+		if err != nil {
+			return models.Car{}, errors.InvalidParam{Param: []string{"dealership_id"}}
+		}
+
+		res.DealershipID = &id
+	}
+
+	return res, nil
+}
@@ -0,0 +1,66 @@
+package rpc
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/pb"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestToCar tests the conversion of cars to messages
+func TestToCar(t *testing.T) {
+	id := uuid.New()
+	dealership := uuid.New()
+	cost := 21000
+	cost64 := int64(cost)
+
+	testCases := []struct {
+		desc string
+		car  models.Car
+		msg  *pb.Car
+	}{
+		{desc: "without engine", car: models.Car{ID: id, Name: "X5"}, msg: &pb.Car{Id: id.String(), Name: "X5"}},
+		{desc: "all fields", car: models.Car{ID: id, Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol",
+			CostPrice: &cost, DealershipID: &dealership, Engine: models.Engine{EngineID: id, Cylinders: 6}},
+			msg: &pb.Car{Id: id.String(), Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol",
+				CostPrice: &cost64, DealershipId: dealership.String(), Engine: &pb.Engine{Id: id.String(), Cylinders: 6}}},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.msg.String(), toCar(&tc.car).String(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestFromCar tests the conversion of the car of a request to the model
+func TestFromCar(t *testing.T) {
+	dealership := uuid.New()
+	cost64 := int64(21000)
+	cost := 21000
+
+	testCases := []struct {
+		desc string
+		msg  *pb.Car
+		car  models.Car
+		err  error
+	}{
+		{desc: "all fields", msg: &pb.Car{Id: uuid.NewString(), Name: "X5", Year: 2020, Brand: "BMW",
+			FuelType: "Petrol", CostPrice: &cost64, DealershipId: dealership.String(),
+			Engine: &pb.Engine{Displacement: 3000, Cylinders: 6}},
+			car: models.Car{Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol", CostPrice: &cost,
+				DealershipID: &dealership, Engine: models.Engine{Displacement: 3000, Cylinders: 6}}},
+		{desc: "without engine", msg: &pb.Car{Name: "i3"}, car: models.Car{Name: "i3"}},
+		{desc: "missing car", err: errors.MissingParam{Param: []string{"car"}}},
+		{desc: "invalid dealership", msg: &pb.Car{DealershipId: "abc"},
+			err: errors.InvalidParam{Param: []string{"dealership_id"}}},
+	}
+
+	for i, tc := range testCases {
+		car, err := fromCar(tc.msg)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.car, car, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,39 @@
+package rpc
+
+import (
+	"net/http"
+
+	"Project/CarDealearship/problem"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// codeOfStatus maps the HTTP status of an error response to the gRPC code of the same meaning
+var codeOfStatus = map[int]codes.Code{
+	http.StatusBadRequest:      codes.InvalidArgument,
+	http.StatusUnauthorized:    codes.Unauthenticated,
+	http.StatusForbidden:       codes.PermissionDenied,
+	http.StatusNotFound:        codes.NotFound,
+	http.StatusConflict:        codes.AlreadyExists,
+	http.StatusTooManyRequests: codes.ResourceExhausted,
+}
+
+// statusError maps the errors of the service layer to gRPC statuses, through the responses problem.Map maps them
+// to for the HTTP api. The details of unexpected errors are only logged.
+func statusError(ctx *gofr.Context, err error) error {
+	// errors of the stream itself, like a cancelled call, already are statuses
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	res := problem.Map(err)
+	if code, ok := codeOfStatus[res.StatusCode]; ok {
+		return status.Error(code, res.Reason)
+	}
+
+	ctx.Logger.Errorf("error in serving grpc call: %v", err)
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,53 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestStatusError tests the mapping of gofr errors to gRPC statuses
+func TestStatusError(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	testCases := []struct {
+		desc string
+		err  error
+		code codes.Code
+		msg  string
+	}{
+		{desc: "nil", code: codes.OK},
+		{desc: "not found", err: errors.EntityNotFound{Entity: "Car", ID: "1"}, code: codes.NotFound,
+			msg: errors.EntityNotFound{Entity: "Car", ID: "1"}.Error()},
+		{desc: "invalid param", err: errors.InvalidParam{Param: []string{"id"}}, code: codes.InvalidArgument,
+			msg: errors.InvalidParam{Param: []string{"id"}}.Error()},
+		{desc: "missing param", err: errors.MissingParam{Param: []string{"car"}}, code: codes.InvalidArgument,
+			msg: errors.MissingParam{Param: []string{"car"}}.Error()},
+		{desc: "forbidden", err: &errors.Response{StatusCode: http.StatusForbidden, Reason: "missing permission"},
+			code: codes.PermissionDenied, msg: "missing permission"},
+		{desc: "rate limited", err: &errors.Response{StatusCode: http.StatusTooManyRequests, Reason: "slow down"},
+			code: codes.ResourceExhausted, msg: "slow down"},
+		{desc: "unmapped response", err: &errors.Response{StatusCode: http.StatusTeapot, Reason: "teapot"},
+			code: codes.Internal, msg: "internal error"},
+		{desc: "status", err: status.Error(codes.Canceled, context.Canceled.Error()), code: codes.Canceled,
+			msg: context.Canceled.Error()},
+		{desc: "no rows", err: sql.ErrNoRows, code: codes.NotFound, msg: "entity not found"},
+		{desc: "no rows of a db error", err: errors.DB{Err: sql.ErrNoRows}, code: codes.NotFound,
+			msg: "entity not found"},
+		{desc: "unexpected", err: errors.Error("db down"), code: codes.Internal, msg: "internal error"},
+	}
+
+	for i, tc := range testCases {
+		s := status.Convert(statusError(ctx, tc.err))
+
+		assert.Equal(t, tc.code, s.Code(), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.msg, s.Message(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
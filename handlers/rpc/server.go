@@ -0,0 +1,247 @@
+package rpc
+
+import (
+	"Project/CarDealearship/auth"
+	"Project/CarDealearship/middleware"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/pb"
+	"Project/CarDealearship/service"
+	"context"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+// maxBatchItems is the most ids a batch call takes, as for the batch endpoints of the HTTP api
+const maxBatchItems = 100
+
+type carServer struct {
+	pb.UnimplementedCarServiceServer
+	k    *gofr.Gofr
+	cars service.Cars
+}
+
+type engineServer struct {
+	pb.UnimplementedEngineServiceServer
+	k    *gofr.Gofr
+	cars service.Cars
+}
+
+// New returns the gRPC server of the car and engine services. Callers authenticate with the credentials of
+// the HTTP api, sent as authorization or x-api-key metadata.
+func New(k *gofr.Gofr, a middleware.Authenticator, c service.Cars) *grpc.Server {
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(unaryAuthenticate(k, a)),
+		grpc.ChainStreamInterceptor(streamAuthenticate(k, a)))
+
+	pb.RegisterCarServiceServer(s, carServer{k: k, cars: c})
+	pb.RegisterEngineServiceServer(s, engineServer{k: k, cars: c})
+
+	return s
+}
+
+// newContext returns the gofr context the services are called with, it carries the principal of ctx
+func newContext(ctx context.Context, k *gofr.Gofr) *gofr.Context {
+	c := gofr.NewContext(nil, nil, k)
+	c.Context = ctx
+
+	return c
+}
+
+// GetCar returns a car with its engine
+func (s carServer) GetCar(ctx context.Context, req *pb.GetCarRequest) (*pb.Car, error) {
+	c := newContext(ctx, s.k)
+	if err := auth.Check(c, auth.ReadCars); err != nil {
+		return nil, statusError(c, err)
+	}
+
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	car, err := s.cars.GetByID(c, id)
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	return toCar(&car), nil
+}
+
+// ListCars returns the cars of a brand, with their engines when asked for
+func (s carServer) ListCars(ctx context.Context, req *pb.ListCarsRequest) (*pb.ListCarsResponse, error) {
+	c := newContext(ctx, s.k)
+	if err := auth.Check(c, auth.ReadCars); err != nil {
+		return nil, statusError(c, err)
+	}
+
+	cars, err := s.cars.GetByBrand(c, req.GetBrand(), req.GetIncludeEngine())
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	res := &pb.ListCarsResponse{Cars: make([]*pb.Car, len(cars))}
+	for i := range cars {
+		res.Cars[i] = toCar(&cars[i])
+	}
+
+	return res, nil
+}
+
+// StreamCars sends the cars of a brand, or of the whole inventory, as they are read from the store. Like the
+// export of the HTTP api it needs the export permission.
+func (s carServer) StreamCars(req *pb.StreamCarsRequest, stream pb.CarService_StreamCarsServer) error {
+	c := newContext(stream.Context(), s.k)
+	if err := auth.Check(c, auth.ExportCars); err != nil {
+		return statusError(c, err)
+	}
+
+	return statusError(c, s.cars.Export(c, req.GetBrand(), func(car models.Car) error {
+		return stream.Send(toCar(&car))
+	}))
+}
+
+// CreateCar creates a car along with its engine
+func (s carServer) CreateCar(ctx context.Context, req *pb.CreateCarRequest) (*pb.Car, error) {
+	c := newContext(ctx, s.k)
+	if err := auth.Check(c, auth.WriteCars); err != nil {
+		return nil, statusError(c, err)
+	}
+
+	car, err := fromCar(req.GetCar())
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	// the request has no override, likely duplicates of a car are rejected with AlreadyExists
+	car, err = s.cars.Create(c, &car, false)
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	return toCar(&car), nil
+}
+
+// UpdateCar replaces a car along with its engine
+func (s carServer) UpdateCar(ctx context.Context, req *pb.UpdateCarRequest) (*pb.Car, error) {
+	c := newContext(ctx, s.k)
+	if err := auth.Check(c, auth.WriteCars); err != nil {
+		return nil, statusError(c, err)
+	}
+
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	car, err := fromCar(req.GetCar())
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	car, err = s.cars.Update(c, id, &car)
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	return toCar(&car), nil
+}
+
+// DeleteCar deletes a car along with its engine
+func (s carServer) DeleteCar(ctx context.Context, req *pb.DeleteCarRequest) (*pb.DeleteCarResponse, error) {
+	c := newContext(ctx, s.k)
+	if err := auth.Check(c, auth.DeleteCars); err != nil {
+		return nil, statusError(c, err)
+	}
+
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	if err = s.cars.Delete(c, id); err != nil {
+		return nil, statusError(c, err)
+	}
+
+	return &pb.DeleteCarResponse{}, nil
+}
+
+// GetEngine returns an engine
+func (s engineServer) GetEngine(ctx context.Context, req *pb.GetEngineRequest) (*pb.Engine, error) {
+	c := newContext(ctx, s.k)
+	if err := auth.Check(c, auth.ReadCars); err != nil {
+		return nil, statusError(c, err)
+	}
+
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	engines, err := s.cars.GetEngines(c, []string{id})
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	if len(engines) == 0 {
+		return nil, statusError(c, errors.EntityNotFound{Entity: "Engine", ID: id})
+	}
+
+	return toEngine(&engines[0]), nil
+}
+
+// BatchGetEngines returns the engines with up to maxBatchItems given ids in their order, ids that do not exist
+// are skipped
+func (s engineServer) BatchGetEngines(ctx context.Context,
+	req *pb.BatchGetEnginesRequest) (*pb.BatchGetEnginesResponse, error) {
+	c := newContext(ctx, s.k)
+	if err := auth.Check(c, auth.ReadCars); err != nil {
+		return nil, statusError(c, err)
+	}
+
+	if len(req.GetIds()) > maxBatchItems {
+		return nil, statusError(c, errors.InvalidParam{Param: []string{"ids"}})
+	}
+
+	ids := make([]string, len(req.GetIds()))
+
+	for i, id := range req.GetIds() {
+		parsed, err := parseID(id, "ids")
+		if err != nil {
+			return nil, statusError(c, err)
+		}
+
+		ids[i] = parsed
+	}
+
+	engines, err := s.cars.GetEngines(c, ids)
+	if err != nil {
+		return nil, statusError(c, err)
+	}
+
+	byID := make(map[string]*models.Engine, len(engines))
+	for i := range engines {
+		byID[engines[i].EngineID.String()] = &engines[i]
+	}
+
+	res := &pb.BatchGetEnginesResponse{Engines: make([]*pb.Engine, 0, len(ids))}
+
+	for _, id := range ids {
+		if e, ok := byID[id]; ok {
+			res.Engines = append(res.Engines, toEngine(e))
+		}
+	}
+
+	return res, nil
+}
+
+// parseID checks that an id is a uuid and returns it in its canonical form
+func parseID(id, param string) (string, error) {
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return "", errors.InvalidParam{Param: []string{param}}
+	}
+
+	return u.String(), nil
+}
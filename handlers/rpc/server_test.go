@@ -0,0 +1,274 @@
+package rpc
+
+import (
+	"Project/CarDealearship/auth"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/pb"
+	"Project/CarDealearship/service"
+	"context"
+	"database/sql"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// roleAuthenticator accepts bearer tokens naming the role of the principal
+type roleAuthenticator struct{}
+
+func (roleAuthenticator) Authenticate(ctx *gofr.Context) (auth.Principal, error) {
+	role := strings.TrimPrefix(ctx.Request().Header.Get("Authorization"), "Bearer ")
+	if role == "" {
+		return auth.Principal{}, &errors.Response{StatusCode: http.StatusUnauthorized, Code: "UNAUTHENTICATED",
+			Reason: "missing credentials"}
+	}
+
+	return auth.Principal{Subject: "u1", Roles: []string{role}}, nil
+}
+
+// dial serves the services with cars on an in-memory listener and returns a connection to it
+func dial(t *testing.T, cars service.Cars) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	s := New(gofr.New(), roleAuthenticator{}, cars)
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		s.Stop()
+	})
+
+	return conn
+}
+
+func withRole(role string) context.Context {
+	if role == "" {
+		return context.Background()
+	}
+
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+role)
+}
+
+// TestGetCar tests the GetCar call along with the mapping of its errors
+func TestGetCar(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCars := service.NewMockCars(ctrl)
+	client := pb.NewCarServiceClient(dial(t, mockCars))
+
+	id := uuid.New()
+	missing, noRows := uuid.New(), uuid.New()
+	cost := 21000
+	car := models.Car{ID: id, Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol", CostPrice: &cost,
+		Engine: models.Engine{EngineID: id, Displacement: 3000, Cylinders: 6}}
+	cost64 := int64(cost)
+
+	mockCars.EXPECT().GetByID(gomock.Any(), id.String()).Return(car, nil)
+	mockCars.EXPECT().GetByID(gomock.Any(), missing.String()).
+		Return(models.Car{}, errors.EntityNotFound{Entity: "Car", ID: missing.String()})
+	mockCars.EXPECT().GetByID(gomock.Any(), noRows.String()).Return(models.Car{}, sql.ErrNoRows)
+	mockCars.EXPECT().GetByID(gomock.Any(), uuid.Nil.String()).Return(models.Car{}, errors.Error("db down"))
+
+	testCases := []struct {
+		desc string
+		role string
+		id   string
+		car  *pb.Car
+		code codes.Code
+	}{
+		{desc: "found", role: auth.RoleViewer, id: id.String(), car: &pb.Car{Id: id.String(), Name: "X5", Year: 2020,
+			Brand: "BMW", FuelType: "Petrol", CostPrice: &cost64,
+			Engine: &pb.Engine{Id: id.String(), Displacement: 3000, Cylinders: 6}}, code: codes.OK},
+		{desc: "not found", role: auth.RoleViewer, id: missing.String(), code: codes.NotFound},
+		{desc: "no rows", role: auth.RoleViewer, id: noRows.String(), code: codes.NotFound},
+		{desc: "invalid id", role: auth.RoleViewer, id: "abc", code: codes.InvalidArgument},
+		{desc: "unexpected error", role: auth.RoleViewer, id: uuid.Nil.String(), code: codes.Internal},
+		{desc: "unauthenticated", id: id.String(), code: codes.Unauthenticated},
+		{desc: "no role", role: "guest", id: id.String(), code: codes.PermissionDenied},
+	}
+
+	for i, tc := range testCases {
+		res, err := client.GetCar(withRole(tc.role), &pb.GetCarRequest{Id: tc.id})
+
+		assert.Equal(t, tc.code, status.Code(err), "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.car != nil {
+			assert.Equal(t, tc.car.String(), res.String(), "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+// TestListCars tests the ListCars call
+func TestListCars(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCars := service.NewMockCars(ctrl)
+	client := pb.NewCarServiceClient(dial(t, mockCars))
+
+	id := uuid.New()
+	dealership := uuid.New()
+
+	mockCars.EXPECT().GetByBrand(gomock.Any(), "BMW", false).
+		Return([]models.Car{{ID: id, Name: "X5", Brand: "BMW", DealershipID: &dealership}}, nil)
+
+	res, err := client.ListCars(withRole(auth.RoleViewer), &pb.ListCarsRequest{Brand: "BMW"})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(res.GetCars()))
+	assert.Equal(t, (&pb.Car{Id: id.String(), Name: "X5", Brand: "BMW", DealershipId: dealership.String()}).String(),
+		res.GetCars()[0].String())
+}
+
+// TestStreamCars tests that the cars are sent one message each, and that the call needs the export permission
+func TestStreamCars(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCars := service.NewMockCars(ctrl)
+	client := pb.NewCarServiceClient(dial(t, mockCars))
+
+	cars := []models.Car{{ID: uuid.New(), Name: "X5", Brand: "BMW"}, {ID: uuid.New(), Name: "X7", Brand: "BMW"}}
+
+	mockCars.EXPECT().Export(gomock.Any(), "BMW", gomock.Any()).
+		DoAndReturn(func(_ *gofr.Context, _ string, fn func(models.Car) error) error {
+			for _, c := range cars {
+				if err := fn(c); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+	stream, err := client.StreamCars(withRole(auth.RoleManager), &pb.StreamCarsRequest{Brand: "BMW"})
+	assert.Equal(t, nil, err)
+
+	var names []string
+
+	for {
+		car, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+
+		if !assert.Equal(t, nil, err) {
+			break
+		}
+
+		names = append(names, car.GetName())
+	}
+
+	assert.Equal(t, []string{"X5", "X7"}, names)
+
+	stream, err = client.StreamCars(withRole(auth.RoleSales), &pb.StreamCarsRequest{Brand: "BMW"})
+	assert.Equal(t, nil, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+// TestWriteCars tests the CreateCar, UpdateCar and DeleteCar calls
+func TestWriteCars(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCars := service.NewMockCars(ctrl)
+	client := pb.NewCarServiceClient(dial(t, mockCars))
+
+	id := uuid.New()
+	input := &pb.Car{Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol",
+		Engine: &pb.Engine{Displacement: 3000, Cylinders: 6}}
+	car := models.Car{Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol",
+		Engine: models.Engine{Displacement: 3000, Cylinders: 6}}
+	created := car
+	created.ID = id
+	created.Engine.EngineID = id
+
+	mockCars.EXPECT().Create(gomock.Any(), &car, false).Return(created, nil)
+	mockCars.EXPECT().Update(gomock.Any(), id.String(), &car).Return(models.Car{}, errors.InvalidParam{})
+	mockCars.EXPECT().Delete(gomock.Any(), id.String()).Return(nil)
+
+	res, err := client.CreateCar(withRole(auth.RoleSales), &pb.CreateCarRequest{Car: input})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, id.String(), res.GetId())
+	assert.Equal(t, id.String(), res.GetEngine().GetId())
+
+	_, err = client.CreateCar(withRole(auth.RoleSales), &pb.CreateCarRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.UpdateCar(withRole(auth.RoleSales), &pb.UpdateCarRequest{Id: id.String(), Car: input})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.DeleteCar(withRole(auth.RoleSales), &pb.DeleteCarRequest{Id: id.String()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.DeleteCar(withRole(auth.RoleManager), &pb.DeleteCarRequest{Id: id.String()})
+	assert.Equal(t, nil, err)
+}
+
+// TestEngines tests the GetEngine and BatchGetEngines calls
+func TestEngines(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCars := service.NewMockCars(ctrl)
+	client := pb.NewEngineServiceClient(dial(t, mockCars))
+
+	id1, id2, missing := uuid.New(), uuid.New(), uuid.New()
+	e1 := models.Engine{EngineID: id1, Displacement: 3000, Cylinders: 6}
+	e2 := models.Engine{EngineID: id2, Range: 400}
+
+	mockCars.EXPECT().GetEngines(gomock.Any(), []string{id1.String()}).Return([]models.Engine{e1}, nil)
+	mockCars.EXPECT().GetEngines(gomock.Any(), []string{missing.String()}).Return([]models.Engine{}, nil)
+	mockCars.EXPECT().GetEngines(gomock.Any(), []string{id2.String(), missing.String(), id1.String()}).
+		Return([]models.Engine{e1, e2}, nil)
+
+	engine, err := client.GetEngine(withRole(auth.RoleViewer), &pb.GetEngineRequest{Id: id1.String()})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int32(6), engine.GetCylinders())
+
+	_, err = client.GetEngine(withRole(auth.RoleViewer), &pb.GetEngineRequest{Id: missing.String()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	res, err := client.BatchGetEngines(withRole(auth.RoleViewer),
+		&pb.BatchGetEnginesRequest{Ids: []string{id2.String(), missing.String(), id1.String()}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(res.GetEngines()))
+	assert.Equal(t, id2.String(), res.GetEngines()[0].GetId())
+	assert.Equal(t, id1.String(), res.GetEngines()[1].GetId())
+
+	_, err = client.BatchGetEngines(withRole(auth.RoleViewer), &pb.BatchGetEnginesRequest{Ids: []string{"abc"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	tooMany := make([]string, maxBatchItems+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.NewString()
+	}
+
+	_, err = client.BatchGetEngines(withRole(auth.RoleViewer), &pb.BatchGetEnginesRequest{Ids: tooMany})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "more than maxBatchItems ids are rejected")
+}
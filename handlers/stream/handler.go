@@ -0,0 +1,260 @@
+package stream
+
+import (
+	"Project/CarDealearship/auth"
+	"Project/CarDealearship/events"
+	"Project/CarDealearship/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// errSlowClient ends the stream of a client that fell behind, it resumes by reconnecting from its last event
+const errSlowClient = errors.Error("client fell behind the event stream")
+
+// notResuming is the position of a client that is not resuming, it only gets the events that come next
+const notResuming = -1
+
+var upgrader = websocket.Upgrader{ReadBufferSize: 1024, WriteBufferSize: 4096}
+
+// Config configures the streams
+type Config struct {
+	// Heartbeat is the interval of the keep alive comments and pings sent on idle streams
+	Heartbeat time.Duration
+	// WriteTimeout bounds the write of a message to a websocket
+	WriteTimeout time.Duration
+}
+
+type handler struct {
+	hub    *events.Hub
+	config Config
+}
+
+// nolint:revive // need not be exported
+// New factory function
+func New(h *events.Hub, config Config) handler {
+	return handler{hub: h, config: config}
+}
+
+// message is a car event sent over a websocket, ID is the position to resume from
+type message struct {
+	ID    int64        `json:"ID"`
+	Event models.Event `json:"Event"`
+}
+
+// Events is the delivery function streaming the car events as Server-Sent Events. The events can be filtered by
+// brand and dealershipID, and a client reconnecting with Last-Event-ID first gets the events it missed.
+func (h handler) Events(ctx *gofr.Context, w http.ResponseWriter) error {
+	f, after, err := subscription(ctx)
+	if err != nil {
+		return err
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.Error("response writer does not support streaming")
+	}
+
+	sub, upTo := h.hub.Subscribe(f)
+	defer h.hub.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// proxies like nginx buffer responses unless told otherwise
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	send := func(e models.Event) error {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		if _, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Seq, e.Type, data); err != nil {
+			return err
+		}
+
+		flusher.Flush()
+
+		return nil
+	}
+
+	heartbeat := func() error {
+		if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+			return err
+		}
+
+		flusher.Flush()
+
+		return nil
+	}
+
+	return h.follow(ctx, sub, f, after, upTo, send, heartbeat)
+}
+
+// WebSocket is the delivery function streaming the same events as Events over a websocket, each one in a
+// message. The position to resume from is given with the lastEventId parameter.
+func (h handler) WebSocket(ctx *gofr.Context, w http.ResponseWriter) error {
+	f, after, err := subscription(ctx)
+	if err != nil {
+		return err
+	}
+
+	sub, upTo := h.hub.Subscribe(f)
+	defer h.hub.Unsubscribe(sub)
+
+	// the upgrader responds to a request it can not upgrade itself
+	conn, err := upgrader.Upgrade(w, ctx.Request(), nil)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	c, cancel := context.WithCancel(ctx.Context)
+	defer cancel()
+
+	ctx.Context = c
+
+	// clients only send control frames, reading them answers pings and ends the stream once the client is gone
+	go func() {
+		defer cancel()
+
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(e models.Event) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(h.config.WriteTimeout))
+		return conn.WriteJSON(message{ID: e.Seq, Event: e})
+	}
+
+	heartbeat := func() error {
+		return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(h.config.WriteTimeout))
+	}
+
+	err = h.follow(ctx, sub, f, after, upTo, send, heartbeat)
+
+	code, reason := websocket.CloseNormalClosure, ""
+	if err == errSlowClient {
+		code, reason = websocket.CloseTryAgainLater, "fell behind, reconnect with lastEventId"
+	}
+
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(h.config.WriteTimeout))
+
+	return err
+}
+
+// follow sends the events missed since the position after, then the events of the subscription as they come,
+// until the client is gone or falls behind. Idle streams get a heartbeat.
+func (h handler) follow(ctx *gofr.Context, sub *events.Subscription, f events.Filter, after, upTo int64,
+	send func(models.Event) error, heartbeat func() error) error {
+	if after == notResuming {
+		after = upTo
+	}
+
+	sent := after
+
+	err := h.hub.Replay(ctx, f, after, upTo, func(e models.Event) error {
+		sent = e.Seq
+		redact(ctx, &e)
+
+		return send(e)
+	})
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(h.config.Heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err = heartbeat(); err != nil {
+				return err
+			}
+		case e, ok := <-sub.Events:
+			if !ok {
+				if h.hub.Dropped(sub) {
+					return errSlowClient
+				}
+
+				return nil
+			}
+
+			// a client resuming from another instance can be ahead of this one
+			if e.Seq <= sent {
+				continue
+			}
+
+			sent = e.Seq
+			redact(ctx, &e)
+
+			if err = send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// subscription reads the filter of a stream request and the position of the last event its client received
+func subscription(ctx *gofr.Context) (events.Filter, int64, error) {
+	f := events.Filter{Brand: ctx.Param("brand")}
+
+	if d := ctx.Param("dealershipID"); d != "" {
+		id, err := uuid.Parse(d)
+		if err != nil {
+			return f, 0, errors.InvalidParam{Param: []string{"dealershipID"}}
+		}
+
+		f.DealershipID = id.String()
+	}
+
+	last := ctx.Header("Last-Event-ID")
+	if last == "" {
+		last = ctx.Param("lastEventId")
+	}
+
+	if last == "" {
+		return f, notResuming, nil
+	}
+
+	after, err := strconv.ParseInt(last, 10, 64)
+	if err != nil || after < 0 {
+		return f, 0, errors.InvalidParam{Param: []string{"Last-Event-ID"}}
+	}
+
+	return f, after, nil
+}
+
+// redact clears the cost price of the car of an event sent to a principal not allowed to read it
+func redact(ctx *gofr.Context, e *models.Event) {
+	if e.Type == models.EventCarStatusChanged || auth.Can(ctx, auth.ReadCost) {
+		return
+	}
+
+	var c models.Car
+	if err := json.Unmarshal(e.Data, &c); err != nil || c.CostPrice == nil {
+		return
+	}
+
+	c.CostPrice = nil
+	e.Data, _ = json.Marshal(c)
+}
@@ -0,0 +1,225 @@
+package stream
+
+import (
+	"Project/CarDealearship/auth"
+	"Project/CarDealearship/events"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+var config = Config{Heartbeat: time.Hour, WriteTimeout: time.Second}
+
+// newServer serves the event stream on /sse and the websocket on /ws to a principal with the given role
+func newServer(h handler, role string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), gofr.New())
+		ctx.Context = auth.WithPrincipal(r.Context(), auth.Principal{Subject: "screen", Roles: []string{role}})
+
+		serve := h.Events
+		if r.URL.Path == "/ws" {
+			serve = h.WebSocket
+		}
+
+		if err := serve(ctx, w); err != nil {
+			ctx.Logger.Errorf("error in streaming: %v", err)
+		}
+	}))
+}
+
+// newHub returns a hub that starts after the event at position last
+func newHub(t *testing.T, mockOutbox *stores.MockOutbox, last int64) *events.Hub {
+	hub := events.NewHub(mockOutbox, events.HubConfig{BatchSize: 10, Buffer: 10})
+
+	mockOutbox.EXPECT().GetLastSeq(gomock.Any()).Return(last, nil)
+
+	_, err := hub.Poll(gofr.NewContext(nil, nil, gofr.New()))
+	assert.Equal(t, nil, err)
+
+	return hub
+}
+
+func carEvent(seq int64, eventType string, data interface{}) models.Event {
+	payload, _ := json.Marshal(data)
+
+	return models.Event{Seq: seq, ID: uuid.New(), Type: eventType, CarID: uuid.New(), Data: payload}
+}
+
+// readEvent reads the lines of the next server-sent event
+func readEvent(r *bufio.Reader) string {
+	var lines []string
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil || line == "\n" {
+			return strings.Join(lines, "")
+		}
+
+		lines = append(lines, line)
+	}
+}
+
+// sse returns the lines of the server-sent event of e
+func sse(e models.Event) string {
+	data, _ := json.Marshal(e)
+	return "id: " + strconv.FormatInt(e.Seq, 10) + "\nevent: " + e.Type + "\ndata: " + string(data) + "\n"
+}
+
+func TestEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	hub := newHub(t, mockOutbox, 12)
+	server := newServer(New(hub, config), auth.RoleViewer)
+
+	defer server.Close()
+
+	cost := 52000
+	created := carEvent(11, models.EventCarCreated, models.Car{Name: "X5", Brand: "BMW", CostPrice: &cost})
+	redacted := created
+	redacted.Data, _ = json.Marshal(models.Car{Name: "X5", Brand: "BMW"})
+	sold := carEvent(13, models.EventCarStatusChanged, models.StatusChange{From: models.CarAvailable,
+		To: models.CarSold, Brand: "BMW"})
+
+	mockOutbox.EXPECT().GetEventsAfter(gomock.Any(), int64(10), 10).Return([]models.Event{created,
+		carEvent(12, models.EventCarCreated, models.Car{Brand: "Audi"})}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/sse?brand=BMW", nil)
+	req.Header.Set("Last-Event-ID", "10")
+
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.Equal(t, nil, err) {
+		return
+	}
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	r := bufio.NewReader(resp.Body)
+
+	assert.Equal(t, sse(redacted), readEvent(r), "the missed events are replayed without the cost price")
+
+	mockOutbox.EXPECT().GetEventsAfter(gomock.Any(), int64(12), 10).Return([]models.Event{sold}, nil)
+
+	_, err = hub.Poll(gofr.NewContext(nil, nil, gofr.New()))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, sse(sold), readEvent(r), "new events follow")
+}
+
+func TestEventsHeartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hub := newHub(t, stores.NewMockOutbox(ctrl), 0)
+	server := newServer(New(hub, Config{Heartbeat: 10 * time.Millisecond}), auth.RoleViewer)
+
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/sse")
+	if !assert.Equal(t, nil, err) {
+		return
+	}
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, ": keep-alive\n", readEvent(bufio.NewReader(resp.Body)))
+}
+
+func TestWebSocket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	hub := newHub(t, mockOutbox, 5)
+	server := newServer(New(hub, config), auth.RoleManager)
+
+	defer server.Close()
+
+	dealer := uuid.New()
+	cost := 52000
+	created := carEvent(5, models.EventCarCreated, models.Car{Brand: "BMW", DealershipID: &dealer, CostPrice: &cost})
+	deleted := carEvent(7, models.EventCarDeleted, models.Car{Brand: "BMW", DealershipID: &dealer})
+
+	mockOutbox.EXPECT().GetEventsAfter(gomock.Any(), int64(4), 10).Return([]models.Event{created}, nil)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+
+		"/ws?lastEventId=4&dealershipID="+dealer.String(), nil)
+	if !assert.Equal(t, nil, err) {
+		return
+	}
+
+	defer conn.Close()
+
+	var msg message
+
+	assert.Equal(t, nil, conn.ReadJSON(&msg))
+	assert.Equal(t, message{ID: 5, Event: created}, withSeq(msg), "managers get the cost price")
+
+	mockOutbox.EXPECT().GetEventsAfter(gomock.Any(), int64(5), 10).Return([]models.Event{
+		carEvent(6, models.EventCarCreated, models.Car{Brand: "BMW"}), deleted}, nil)
+
+	_, err = hub.Poll(gofr.NewContext(nil, nil, gofr.New()))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, nil, conn.ReadJSON(&msg))
+	assert.Equal(t, message{ID: 7, Event: deleted}, withSeq(msg), "events of other dealerships are filtered")
+}
+
+// withSeq sets the position of the event of a message received over a websocket, which only carries it as ID
+func withSeq(msg message) message {
+	msg.Event.Seq = msg.ID
+	return msg
+}
+
+func TestSubscriptionErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := New(events.NewHub(stores.NewMockOutbox(ctrl), events.HubConfig{}), config)
+
+	testCases := []struct {
+		desc   string
+		target string
+		header string
+		err    error
+	}{
+		{desc: "invalid dealership", target: "/cars/stream?dealershipID=42",
+			err: errors.InvalidParam{Param: []string{"dealershipID"}}},
+		{desc: "invalid last event id", target: "/cars/stream", header: "abc",
+			err: errors.InvalidParam{Param: []string{"Last-Event-ID"}}},
+		{desc: "negative last event id", target: "/cars/stream?lastEventId=-1",
+			err: errors.InvalidParam{Param: []string{"Last-Event-ID"}}},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+		if tc.header != "" {
+			r.Header.Set("Last-Event-ID", tc.header)
+		}
+
+		w := httptest.NewRecorder()
+		ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), gofr.New())
+
+		assert.Equal(t, tc.err, h.Events(ctx, w), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.err, h.WebSocket(ctx, w), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
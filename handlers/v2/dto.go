@@ -0,0 +1,136 @@
+package v2
+
+import (
+	"Project/CarDealearship/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The v2 contract is camelCase throughout and kept apart from models, so that the storage models can change
+// without breaking clients
+
+// CarRequest is the body of POST /v2/cars and PUT /v2/cars/{id}
+type CarRequest struct {
+	Name         string        `json:"name"`
+	Year         int           `json:"year"`
+	Brand        string        `json:"brand"`
+	FuelType     string        `json:"fuelType"`
+	Status       string        `json:"status,omitempty"`
+	CostPrice    *int          `json:"costPrice,omitempty"`
+	DealershipID *uuid.UUID    `json:"dealershipId,omitempty"`
+	VIN          string        `json:"vin,omitempty"`
+	Engine       EngineRequest `json:"engine"`
+}
+
+type EngineRequest struct {
+	Displacement int `json:"displacement"`
+	Cylinders    int `json:"cylinders"`
+	Range        int `json:"range"`
+}
+
+type Car struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	Year         int        `json:"year"`
+	Brand        string     `json:"brand"`
+	FuelType     string     `json:"fuelType"`
+	Status       string     `json:"status,omitempty"`
+	CostPrice    *int       `json:"costPrice,omitempty"`
+	DealershipID *uuid.UUID `json:"dealershipId,omitempty"`
+	VIN          string     `json:"vin,omitempty"`
+	Engine       *Engine    `json:"engine,omitempty"`
+	Media        []Media    `json:"media,omitempty"`
+}
+
+type Engine struct {
+	ID           uuid.UUID `json:"id"`
+	Displacement int       `json:"displacement"`
+	Cylinders    int       `json:"cylinders"`
+	Range        int       `json:"range"`
+}
+
+type Media struct {
+	ID           uuid.UUID `json:"id"`
+	Kind         string    `json:"kind"`
+	ContentType  string    `json:"contentType"`
+	FileName     string    `json:"fileName"`
+	Size         int64     `json:"size"`
+	Position     int       `json:"position"`
+	IsCover      bool      `json:"isCover"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnailUrl,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// CarList is the body of GET /v2/cars
+type CarList struct {
+	Cars []Car `json:"cars"`
+}
+
+type SearchResult struct {
+	Car   Car     `json:"car"`
+	Score float64 `json:"score"`
+}
+
+type SearchResults struct {
+	Results []SearchResult `json:"results"`
+}
+
+type Comparison struct {
+	Cars       []Car               `json:"cars"`
+	Attributes []ComparedAttribute `json:"attributes"`
+}
+
+type ComparedAttribute struct {
+	Name    string        `json:"name"`
+	Values  []interface{} `json:"values"`
+	Differs bool          `json:"differs"`
+}
+
+func (c CarRequest) model() models.Car {
+	return models.Car{
+		Name:         c.Name,
+		Year:         c.Year,
+		Brand:        c.Brand,
+		FuelType:     c.FuelType,
+		Status:       c.Status,
+		CostPrice:    c.CostPrice,
+		DealershipID: c.DealershipID,
+		VIN:          c.VIN,
+		Engine: models.Engine{
+			Displacement: c.Engine.Displacement,
+			Cylinders:    c.Engine.Cylinders,
+			Range:        c.Engine.Range,
+		},
+	}
+}
+
+// newCar returns the v2 shape of a car, the engine is left out when it was not loaded
+func newCar(c models.Car) Car {
+	car := Car{ID: c.ID, Name: c.Name, Year: c.Year, Brand: c.Brand, FuelType: c.FuelType, Status: c.Status,
+		CostPrice: c.CostPrice, DealershipID: c.DealershipID, VIN: c.VIN}
+
+	if c.Engine != (models.Engine{}) {
+		car.Engine = &Engine{ID: c.Engine.EngineID, Displacement: c.Engine.Displacement,
+			Cylinders: c.Engine.Cylinders, Range: c.Engine.Range}
+	}
+
+	for _, m := range c.Media {
+		car.Media = append(car.Media, Media{ID: m.ID, Kind: m.Kind, ContentType: m.ContentType,
+			FileName: m.FileName, Size: m.Size, Position: m.Position, IsCover: m.IsCover, URL: m.URL,
+			ThumbnailURL: m.ThumbnailURL, CreatedAt: m.CreatedAt})
+	}
+
+	return car
+}
+
+func newCars(cars []models.Car) []Car {
+	resp := make([]Car, 0, len(cars))
+
+	for i := range cars {
+		resp = append(resp, newCar(cars[i]))
+	}
+
+	return resp
+}
@@ -0,0 +1,62 @@
+package v2
+
+import (
+	"Project/CarDealearship/models"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCar(t *testing.T) {
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	dealer := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	cost := 30000
+	created := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		desc string
+		car  models.Car
+		json string
+	}{
+		{desc: "car without engine", car: models.Car{ID: id, Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol"},
+			json: `{"id":"11111111-1111-1111-1111-111111111111","name":"X5","year":2020,"brand":"BMW",` +
+				`"fuelType":"Petrol"}`},
+		{desc: "car with status, dealership, engine, cost and media", car: models.Car{ID: id, Name: "X5", Year: 2020,
+			Brand: "BMW", FuelType: "Petrol", Status: models.CarReserved, CostPrice: &cost, DealershipID: &dealer,
+			Engine: models.Engine{EngineID: id, Displacement: 3000, Cylinders: 6},
+			Media: []models.Media{{ID: id, CarID: id, Kind: models.MediaImage, ContentType: "image/jpeg",
+				FileName: "front.jpg", Size: 10, IsCover: true, URL: "http://media/front.jpg", CreatedAt: created,
+				StorageKey: "cars/front.jpg"}}},
+			json: `{"id":"11111111-1111-1111-1111-111111111111","name":"X5","year":2020,"brand":"BMW",` +
+				`"fuelType":"Petrol","status":"reserved","costPrice":30000,` +
+				`"dealershipId":"22222222-2222-2222-2222-222222222222","engine":{"id":"11111111-1111-1111-1111-111111111111",` +
+				`"displacement":3000,"cylinders":6,"range":0},"media":[{"id":"11111111-1111-1111-1111-111111111111",` +
+				`"kind":"image","contentType":"image/jpeg","fileName":"front.jpg","size":10,"position":0,` +
+				`"isCover":true,"url":"http://media/front.jpg","createdAt":"2022-01-02T03:04:05Z"}]}`},
+	}
+
+	for i, tc := range testCases {
+		b, err := json.Marshal(newCar(tc.car))
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.JSONEq(t, tc.json, string(b), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestCarRequestModel(t *testing.T) {
+	var req CarRequest
+
+	err := json.Unmarshal([]byte(`{"name":"X5","year":2020,"brand":"BMW","fuelType":"Petrol","status":"sold",`+
+		`"costPrice":100,"dealershipId":"22222222-2222-2222-2222-222222222222",`+
+		`"engine":{"displacement":3000,"cylinders":6,"range":0}}`), &req)
+
+	cost := 100
+	dealer := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.Car{Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol", Status: models.CarSold,
+		CostPrice: &cost, DealershipID: &dealer, Engine: models.Engine{Displacement: 3000, Cylinders: 6}}, req.model())
+}
@@ -0,0 +1,181 @@
+package v2
+
+import (
+	"Project/CarDealearship/service"
+	"strconv"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type handler struct {
+	service service.Cars
+}
+
+// nolint:revive // need not be exported
+// New factory function
+func New(c service.Cars) handler {
+	return handler{service: c}
+}
+
+// GetByID is the v2 handler function to get a car by its id along with its engine and media
+func (h handler) GetByID(ctx *gofr.Context) (interface{}, error) {
+	c, err := h.service.GetByID(ctx, ctx.PathParam("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	return newCar(c), nil
+}
+
+// List is the v2 handler function to list the cars of a brand, the engines are included with
+// includeEngine=true
+func (h handler) List(ctx *gofr.Context) (interface{}, error) {
+	brand := ctx.Param("brand")
+	if brand == "" {
+		return nil, errors.MissingParam{Param: []string{"brand"}}
+	}
+
+	includeEngine := false
+
+	if v := ctx.Param("includeEngine"); v != "" {
+		var err error
+
+		if includeEngine, err = strconv.ParseBool(v); err != nil {
+			return nil, errors.InvalidParam{Param: []string{"includeEngine"}}
+		}
+	}
+
+	cars, err := h.service.GetByBrand(ctx, brand, includeEngine)
+	if err != nil {
+		return nil, err
+	}
+
+	return CarList{Cars: newCars(cars)}, nil
+}
+
+// Search is the v2 handler function to find the cars matching the free text query q, most relevant first
+func (h handler) Search(ctx *gofr.Context) (interface{}, error) {
+	limit := defaultSearchLimit
+
+	if l := ctx.Param("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 || n > maxSearchLimit {
+			return nil, errors.InvalidParam{Param: []string{"limit"}}
+		}
+
+		limit = n
+	}
+
+	results, err := h.service.Search(ctx, ctx.Param("q"), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := SearchResults{Results: make([]SearchResult, 0, len(results))}
+
+	for _, r := range results {
+		resp.Results = append(resp.Results, SearchResult{Car: newCar(r.Car), Score: r.Score})
+	}
+
+	return resp, nil
+}
+
+// Compare is the v2 handler function to compare the cars listed in the comma separated ids parameter
+func (h handler) Compare(ctx *gofr.Context) (interface{}, error) {
+	ids := ctx.Param("ids")
+	if ids == "" {
+		return nil, errors.MissingParam{Param: []string{"ids"}}
+	}
+
+	c, err := h.service.Compare(ctx, strings.Split(ids, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := Comparison{Cars: newCars(c.Cars), Attributes: make([]ComparedAttribute, 0, len(c.Attributes))}
+
+	for _, a := range c.Attributes {
+		resp.Attributes = append(resp.Attributes, ComparedAttribute{Name: a.Name, Values: a.Values, Differs: a.Differs})
+	}
+
+	return resp, nil
+}
+
+// Create is the v2 handler function to create a car, a likely duplicate of a car is only created with
+// allowDuplicate=true
+func (h handler) Create(ctx *gofr.Context) (interface{}, error) {
+	allowDuplicate, err := boolParam(ctx, "allowDuplicate")
+	if err != nil {
+		return nil, err
+	}
+
+	var req CarRequest
+	if err := ctx.Bind(&req); err != nil {
+		ctx.Logger.Errorf("error in binding: %v", err)
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	car := req.model()
+
+	c, err := h.service.Create(ctx, &car, allowDuplicate)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCar(c), nil
+}
+
+// Update is the v2 handler function to update a car, the cost price is kept when the request has none
+func (h handler) Update(ctx *gofr.Context) (interface{}, error) {
+	id := ctx.PathParam("id")
+	if id == "" {
+		return nil, errors.MissingParam{Param: []string{"id"}}
+	}
+
+	var req CarRequest
+	if err := ctx.Bind(&req); err != nil {
+		ctx.Logger.Errorf("error in binding: %v", err)
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	car := req.model()
+
+	c, err := h.service.Update(ctx, id, &car)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCar(c), nil
+}
+
+// Delete is the v2 handler function to delete a car, it responds without a body
+func (h handler) Delete(ctx *gofr.Context) (interface{}, error) {
+	id := ctx.PathParam("id")
+	if id == "" {
+		return nil, errors.MissingParam{Param: []string{"id"}}
+	}
+
+	return nil, h.service.Delete(ctx, id)
+}
+
+// boolParam reads an optional boolean query parameter, false when it is absent
+func boolParam(ctx *gofr.Context, name string) (bool, error) {
+	v := ctx.Param(name)
+	if v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.InvalidParam{Param: []string{name}}
+	}
+
+	return b, nil
+}
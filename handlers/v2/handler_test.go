@@ -0,0 +1,255 @@
+package v2
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/service"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContext(app *gofr.Gofr, method, target, body string, params map[string]string) *gofr.Context {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), app)
+	ctx.SetPathParams(params)
+
+	return ctx
+}
+
+// TestGetByID to test the v2 handler GetByID
+func TestGetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	h := New(mockService)
+	app := gofr.New()
+
+	id := uuid.New()
+	car := models.Car{ID: id, Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol",
+		Engine: models.Engine{EngineID: id, Displacement: 3000, Cylinders: 6}}
+
+	mockService.EXPECT().GetByID(gomock.Any(), id.String()).Return(car, nil)
+	mockService.EXPECT().GetByID(gomock.Any(), id.String()).
+		Return(models.Car{}, errors.EntityNotFound{Entity: "Car", ID: id.String()})
+
+	testCases := []struct {
+		desc string
+		resp interface{}
+		err  error
+	}{
+		{desc: "success case", resp: Car{ID: id, Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol",
+			Engine: &Engine{ID: id, Displacement: 3000, Cylinders: 6}}},
+		{desc: "not found", err: errors.EntityNotFound{Entity: "Car", ID: id.String()}},
+	}
+
+	for i, tc := range testCases {
+		ctx := newContext(app, "GET", "/v2/cars/"+id.String(), "", map[string]string{"id": id.String()})
+
+		resp, err := h.GetByID(ctx)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestList to test the v2 handler List
+func TestList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	h := New(mockService)
+	app := gofr.New()
+
+	id := uuid.New()
+	car := models.Car{ID: id, Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol"}
+
+	mockService.EXPECT().GetByBrand(gomock.Any(), "BMW", false).Return([]models.Car{car}, nil)
+	mockService.EXPECT().GetByBrand(gomock.Any(), "BMW", true).Return(nil, nil)
+	mockService.EXPECT().GetByBrand(gomock.Any(), "BMW", false).Return(nil, errors.Error("db down"))
+
+	testCases := []struct {
+		desc   string
+		target string
+		resp   interface{}
+		err    error
+	}{
+		{desc: "success case", target: "/v2/cars?brand=BMW", resp: CarList{Cars: []Car{{ID: id, Name: "X5",
+			Year: 2020, Brand: "BMW", FuelType: "Petrol"}}}},
+		{desc: "no cars", target: "/v2/cars?brand=BMW&includeEngine=true", resp: CarList{Cars: []Car{}}},
+		{desc: "service error", target: "/v2/cars?brand=BMW", err: errors.Error("db down")},
+		{desc: "missing brand", target: "/v2/cars", err: errors.MissingParam{Param: []string{"brand"}}},
+		{desc: "invalid includeEngine", target: "/v2/cars?brand=BMW&includeEngine=maybe",
+			err: errors.InvalidParam{Param: []string{"includeEngine"}}},
+	}
+
+	for i, tc := range testCases {
+		resp, err := h.List(newContext(app, "GET", tc.target, "", nil))
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestSearch to test the v2 handler Search
+func TestSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	h := New(mockService)
+	app := gofr.New()
+
+	id := uuid.New()
+
+	mockService.EXPECT().Search(gomock.Any(), "bmw", 5).
+		Return([]models.SearchResult{{Car: models.Car{ID: id, Name: "X5"}, Score: 1.5}}, nil)
+
+	testCases := []struct {
+		desc   string
+		target string
+		resp   interface{}
+		err    error
+	}{
+		{desc: "success case", target: "/v2/cars/search?q=bmw&limit=5",
+			resp: SearchResults{Results: []SearchResult{{Car: Car{ID: id, Name: "X5"}, Score: 1.5}}}},
+		{desc: "invalid limit", target: "/v2/cars/search?q=bmw&limit=500",
+			err: errors.InvalidParam{Param: []string{"limit"}}},
+	}
+
+	for i, tc := range testCases {
+		resp, err := h.Search(newContext(app, "GET", tc.target, "", nil))
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestCompare to test the v2 handler Compare
+func TestCompare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	h := New(mockService)
+	app := gofr.New()
+
+	id1, id2 := uuid.New(), uuid.New()
+
+	mockService.EXPECT().Compare(gomock.Any(), []string{id1.String(), id2.String()}).Return(models.Comparison{
+		Cars:       []models.Car{{ID: id1, Name: "X5"}, {ID: id2, Name: "Cayenne"}},
+		Attributes: []models.ComparedAttribute{{Name: "Year", Values: []interface{}{2020, 2021}, Differs: true}},
+	}, nil)
+
+	testCases := []struct {
+		desc   string
+		target string
+		resp   interface{}
+		err    error
+	}{
+		{desc: "success case", target: "/v2/cars/compare?ids=" + id1.String() + "," + id2.String(),
+			resp: Comparison{Cars: []Car{{ID: id1, Name: "X5"}, {ID: id2, Name: "Cayenne"}},
+				Attributes: []ComparedAttribute{{Name: "Year", Values: []interface{}{2020, 2021}, Differs: true}}}},
+		{desc: "missing ids", target: "/v2/cars/compare", err: errors.MissingParam{Param: []string{"ids"}}},
+	}
+
+	for i, tc := range testCases {
+		resp, err := h.Compare(newContext(app, "GET", tc.target, "", nil))
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestCreateAndUpdate to test the v2 handlers Create and Update
+func TestCreateAndUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	h := New(mockService)
+	app := gofr.New()
+
+	id := uuid.New()
+	body := `{"name":"X5","year":2020,"brand":"BMW","fuelType":"Petrol","engine":{"displacement":3000,"cylinders":6}}`
+	car := models.Car{Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol",
+		Engine: models.Engine{Displacement: 3000, Cylinders: 6}}
+	created := car
+	created.ID, created.Engine.EngineID = id, id
+	resp := Car{ID: id, Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol",
+		Engine: &Engine{ID: id, Displacement: 3000, Cylinders: 6}}
+
+	mockService.EXPECT().Create(gomock.Any(), &car, false).Return(created, nil)
+	mockService.EXPECT().Create(gomock.Any(), &car, true).Return(created, nil)
+	mockService.EXPECT().Update(gomock.Any(), id.String(), &car).Return(created, nil)
+	mockService.EXPECT().Update(gomock.Any(), id.String(), &car).Return(models.Car{}, errors.Error("db down"))
+
+	testCases := []struct {
+		desc    string
+		handler gofr.Handler
+		target  string
+		body    string
+		params  map[string]string
+		resp    interface{}
+		err     error
+	}{
+		{desc: "create", handler: h.Create, body: body, resp: resp},
+		{desc: "create duplicate", handler: h.Create, target: "/v2/cars?allowDuplicate=true", body: body, resp: resp},
+		{desc: "create with invalid override", handler: h.Create, target: "/v2/cars?allowDuplicate=maybe", body: body,
+			err: errors.InvalidParam{Param: []string{"allowDuplicate"}}},
+		{desc: "create with invalid body", handler: h.Create, body: `{"name":`,
+			err: errors.InvalidParam{Param: []string{"body"}}},
+		{desc: "update", handler: h.Update, body: body, params: map[string]string{"id": id.String()}, resp: resp},
+		{desc: "update error", handler: h.Update, body: body, params: map[string]string{"id": id.String()},
+			err: errors.Error("db down")},
+		{desc: "update without id", handler: h.Update, body: body, err: errors.MissingParam{Param: []string{"id"}}},
+	}
+
+	for i, tc := range testCases {
+		if tc.target == "" {
+			tc.target = "/v2/cars"
+		}
+
+		resp, err := tc.handler(newContext(app, "POST", tc.target, tc.body, tc.params))
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestDelete to test the v2 handler Delete
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := service.NewMockCars(ctrl)
+	h := New(mockService)
+	app := gofr.New()
+
+	id := uuid.New().String()
+
+	mockService.EXPECT().Delete(gomock.Any(), id).Return(nil)
+	mockService.EXPECT().Delete(gomock.Any(), id).Return(errors.EntityNotFound{Entity: "Car", ID: id})
+
+	testCases := []struct {
+		desc   string
+		params map[string]string
+		err    error
+	}{
+		{desc: "success case", params: map[string]string{"id": id}},
+		{desc: "not found", params: map[string]string{"id": id}, err: errors.EntityNotFound{Entity: "Car", ID: id}},
+		{desc: "missing id", err: errors.MissingParam{Param: []string{"id"}}},
+	}
+
+	for i, tc := range testCases {
+		resp, err := h.Delete(newContext(app, "DELETE", "/v2/cars/"+id, "", tc.params))
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Nil(t, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
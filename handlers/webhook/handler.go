@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/service"
+	"strconv"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 100
+)
+
+type handler struct {
+	service service.Webhooks
+}
+
+// nolint:revive // need not be exported
+// New factory function
+func New(w service.Webhooks) handler {
+	return handler{service: w}
+}
+
+// GetAll is the delivery function to list the webhooks
+func (h handler) GetAll(ctx *gofr.Context) (interface{}, error) {
+	return h.service.GetAll(ctx)
+}
+
+// GetByID is the delivery function to fetch a webhook
+func (h handler) GetByID(ctx *gofr.Context) (interface{}, error) {
+	return h.service.GetByID(ctx, ctx.PathParam("id"))
+}
+
+// Create is the delivery function to subscribe a URL to car events, the response is the only one holding
+// the secret the payloads are signed with
+func (h handler) Create(ctx *gofr.Context) (interface{}, error) {
+	var w models.Webhook
+	if err := ctx.Bind(&w); err != nil {
+		ctx.Logger.Errorf("error in binding: %v", err)
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	return h.service.Create(ctx, &w)
+}
+
+// Update is the delivery function to change the URL, the events or the state of a webhook
+func (h handler) Update(ctx *gofr.Context) (interface{}, error) {
+	var w models.Webhook
+	if err := ctx.Bind(&w); err != nil {
+		ctx.Logger.Errorf("error in binding: %v", err)
+		return nil, errors.InvalidParam{Param: []string{"body"}}
+	}
+
+	return h.service.Update(ctx, ctx.PathParam("id"), &w)
+}
+
+// Delete is the delivery function to remove a webhook along with its deliveries
+func (h handler) Delete(ctx *gofr.Context) (interface{}, error) {
+	if err := h.service.Delete(ctx, ctx.PathParam("id")); err != nil {
+		return nil, err
+	}
+
+	return "Deleted successfully", nil
+}
+
+// Deliveries is the delivery function to list the latest deliveries of a webhook, optionally of one status
+func (h handler) Deliveries(ctx *gofr.Context) (interface{}, error) {
+	limit := defaultDeliveryLimit
+
+	if l := ctx.Param("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 || n > maxDeliveryLimit {
+			return nil, errors.InvalidParam{Param: []string{"limit"}}
+		}
+
+		limit = n
+	}
+
+	return h.service.GetDeliveries(ctx, ctx.PathParam("id"), ctx.Param("status"), limit)
+}
+
+// Retry is the delivery function to send a dead delivery again
+func (h handler) Retry(ctx *gofr.Context) (interface{}, error) {
+	return h.service.Retry(ctx, ctx.PathParam("id"), ctx.PathParam("deliveryID"))
+}
@@ -0,0 +1,189 @@
+package webhook
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/service"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContext(method, target string, body io.Reader, params map[string]string) *gofr.Context {
+	r := httptest.NewRequest(method, target, body)
+	r.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	ctx := gofr.NewContext(responder.NewContextualResponder(w, r), request.NewHTTPRequest(r), gofr.New())
+	ctx.SetPathParams(params)
+
+	return ctx
+}
+
+// TestGet tests the handlers GetAll and GetByID
+func TestGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockWebhooks(ctrl)
+	h := New(mockService)
+
+	w := models.Webhook{ID: uuid.New(), URL: "https://crm.example.com/hooks", Active: true}
+	id := w.ID.String()
+
+	mockService.EXPECT().GetAll(gomock.Any()).Return([]models.Webhook{w}, nil)
+	mockService.EXPECT().GetByID(gomock.Any(), id).Return(w, nil)
+
+	resp, err := h.GetAll(newContext("GET", "/webhooks", nil, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.Webhook{w}, resp)
+
+	resp, err = h.GetByID(newContext("GET", "/webhooks/"+id, nil, map[string]string{"id": id}))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, w, resp)
+}
+
+// TestCreate tests the handler Create
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockWebhooks(ctrl)
+	h := New(mockService)
+
+	input := models.Webhook{URL: "https://crm.example.com/hooks", Events: []string{models.EventCarCreated}}
+	created := models.Webhook{ID: uuid.New(), URL: input.URL, Events: input.Events, Active: true, Secret: "s1"}
+
+	mockService.EXPECT().Create(gomock.Any(), &input).Return(created, nil)
+
+	testCases := []struct {
+		desc string
+		body string
+		resp interface{}
+		err  error
+	}{
+		{desc: "created", body: `{"URL":"https://crm.example.com/hooks","Events":["CarCreated"]}`, resp: created},
+		{desc: "invalid body", body: `{"URL":`, err: errors.InvalidParam{Param: []string{"body"}}},
+	}
+
+	for i, tc := range testCases {
+		resp, err := h.Create(newContext("POST", "/webhooks", strings.NewReader(tc.body), nil))
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.resp, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestUpdate tests the handler Update
+func TestUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockWebhooks(ctrl)
+	h := New(mockService)
+
+	id := uuid.New().String()
+	input := models.Webhook{URL: "https://crm.example.com/hooks"}
+	updated := models.Webhook{ID: uuid.MustParse(id), URL: input.URL}
+
+	mockService.EXPECT().Update(gomock.Any(), id, &input).Return(updated, nil)
+
+	resp, err := h.Update(newContext("PUT", "/webhooks/"+id, strings.NewReader(`{"URL":"https://crm.example.com/hooks"}`),
+		map[string]string{"id": id}))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, updated, resp)
+
+	resp, err = h.Update(newContext("PUT", "/webhooks/"+id, strings.NewReader("["), map[string]string{"id": id}))
+	assert.Equal(t, errors.InvalidParam{Param: []string{"body"}}, err)
+	assert.Equal(t, nil, resp)
+}
+
+// TestDelete tests the handler Delete
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockWebhooks(ctrl)
+	h := New(mockService)
+
+	id := uuid.New().String()
+
+	mockService.EXPECT().Delete(gomock.Any(), id).Return(nil)
+	mockService.EXPECT().Delete(gomock.Any(), "missing").Return(errors.EntityNotFound{Entity: "Webhook", ID: "missing"})
+
+	resp, err := h.Delete(newContext("DELETE", "/webhooks/"+id, nil, map[string]string{"id": id}))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "Deleted successfully", resp)
+
+	resp, err = h.Delete(newContext("DELETE", "/webhooks/missing", nil, map[string]string{"id": "missing"}))
+	assert.Equal(t, errors.EntityNotFound{Entity: "Webhook", ID: "missing"}, err)
+	assert.Equal(t, nil, resp)
+}
+
+// TestDeliveries tests the handler Deliveries
+func TestDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockWebhooks(ctrl)
+	h := New(mockService)
+
+	id := uuid.New().String()
+	deliveries := []models.WebhookDelivery{{ID: uuid.New(), Status: models.DeliveryDead}}
+
+	testCases := []struct {
+		desc   string
+		target string
+		status string
+		limit  int
+		err    error
+	}{
+		{desc: "default limit", target: "/webhooks/" + id + "/deliveries", limit: 50},
+		{desc: "dead ones", target: "/webhooks/" + id + "/deliveries?status=dead&limit=10", status: "dead", limit: 10},
+		{desc: "invalid limit", target: "/webhooks/" + id + "/deliveries?limit=abc",
+			err: errors.InvalidParam{Param: []string{"limit"}}},
+		{desc: "limit too large", target: "/webhooks/" + id + "/deliveries?limit=500",
+			err: errors.InvalidParam{Param: []string{"limit"}}},
+	}
+
+	for i, tc := range testCases {
+		if tc.err == nil {
+			mockService.EXPECT().GetDeliveries(gomock.Any(), id, tc.status, tc.limit).Return(deliveries, nil)
+		}
+
+		resp, err := h.Deliveries(newContext("GET", tc.target, nil, map[string]string{"id": id}))
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.err == nil {
+			assert.Equal(t, deliveries, resp, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+// TestRetry tests the handler Retry
+func TestRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockWebhooks(ctrl)
+	h := New(mockService)
+
+	id, deliveryID := uuid.New().String(), uuid.New().String()
+	retried := models.WebhookDelivery{ID: uuid.MustParse(deliveryID), Status: models.DeliveryPending}
+
+	mockService.EXPECT().Retry(gomock.Any(), id, deliveryID).Return(retried, nil)
+
+	resp, err := h.Retry(newContext("POST", "/webhooks/"+id+"/deliveries/"+deliveryID+"/retry", nil,
+		map[string]string{"id": id, "deliveryID": deliveryID}))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, retried, resp)
+}
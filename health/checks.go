@@ -0,0 +1,38 @@
+package health
+
+import (
+	"Project/CarDealearship/migrations"
+	"context"
+	"strconv"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+)
+
+// Migrations checks that the schema has the version of the latest migration, it is behind while migrations run
+// or when a newer process migrated it and this one was not deployed yet
+func Migrations(db migrations.DB, all []migrations.Migration) Checker {
+	want := 0
+	if len(all) > 0 {
+		want = all[len(all)-1].Version
+	}
+
+	return CheckerFunc(func(ctx context.Context) error {
+		version, err := migrations.Version(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		if version != want {
+			return errors.Error("schema version is " + strconv.Itoa(version) + ", want " + strconv.Itoa(want))
+		}
+
+		return nil
+	})
+}
+
+// PubSub checks that the pub/sub backend can be reached, gofr's clients satisfy the interface
+func PubSub(p interface{ Ping() error }) Checker {
+	return CheckerFunc(func(context.Context) error {
+		return p.Ping()
+	})
+}
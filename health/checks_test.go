@@ -0,0 +1,64 @@
+package health
+
+import (
+	"Project/CarDealearship/migrations"
+	"context"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMigrations to test the schema must have the version of the latest migration
+func TestMigrations(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	createVersionTable := "CREATE TABLE IF NOT EXISTS schema_migrations " +
+		"(version INT PRIMARY KEY, description VARCHAR(255), applied_at DATETIME)"
+	selectVersion := "SELECT COALESCE(MAX(version),0) FROM schema_migrations"
+	all := []migrations.Migration{{Version: 1}, {Version: 2}}
+
+	testCases := []struct {
+		desc    string
+		version int
+		err     error
+	}{
+		{desc: "latest", version: 2},
+		{desc: "behind", version: 1, err: errors.Error("schema version is 1, want 2")},
+	}
+
+	for i, tc := range testCases {
+		mock.ExpectExec(createVersionTable).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectVersion).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(tc.version))
+
+		err := Migrations(db, all).Check(context.Background())
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	mock.ExpectExec(createVersionTable).WillReturnError(errors.Error("connection refused"))
+
+	assert.Equal(t, errors.Error("connection refused"), Migrations(db, all).Check(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+type pinger struct {
+	err error
+}
+
+func (p pinger) Ping() error {
+	return p.err
+}
+
+// TestPubSub to test the pub/sub backend is pinged
+func TestPubSub(t *testing.T) {
+	assert.Equal(t, nil, PubSub(pinger{}).Check(context.Background()))
+	assert.Equal(t, errors.Error("broker down"), PubSub(pinger{err: errors.Error("broker down")}).
+		Check(context.Background()))
+}
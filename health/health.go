@@ -0,0 +1,145 @@
+// Package health tells whether the process is alive and whether it is ready to serve requests. Readiness
+// follows the lifecycle of the process and the checks of the dependencies it needs, like the database.
+package health
+
+import (
+	"Project/CarDealearship/models"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Statuses of a report and of its checks
+const (
+	StatusUp   = "UP"
+	StatusDown = "DOWN"
+)
+
+// lifecycle states, only a serving process is ready
+const (
+	starting int32 = iota
+	serving
+	draining
+)
+
+// lifecycleCheck is the name of the check reporting why a process that is not serving is not ready
+const lifecycleCheck = "lifecycle"
+
+// Checker checks that a dependency can be used
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc lets a function be a Checker
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+type check struct {
+	name    string
+	checker Checker
+}
+
+// Health runs the registered checks for readiness. A process is starting until SetServing, while its migrations
+// run, and draining after SetDraining, while it shuts down.
+type Health struct {
+	checks  []check
+	timeout time.Duration
+	state   int32
+}
+
+// New factory function, every check gets at most timeout
+func New(timeout time.Duration) *Health {
+	return &Health{timeout: timeout}
+}
+
+// Register adds a check of readiness, it must be called before the checks are run
+func (h *Health) Register(name string, c Checker) {
+	h.checks = append(h.checks, check{name: name, checker: c})
+}
+
+// SetServing marks the start of the process as done
+func (h *Health) SetServing() {
+	atomic.CompareAndSwapInt32(&h.state, starting, serving)
+}
+
+// SetDraining marks the process as shutting down, it is not ready from then on
+func (h *Health) SetDraining() {
+	atomic.StoreInt32(&h.state, draining)
+}
+
+// Live reports the process as up, a process that can answer is alive
+func (h *Health) Live() models.HealthReport {
+	return models.HealthReport{Status: StatusUp, Checks: []models.HealthCheck{}}
+}
+
+// Ready runs the checks at the same time and reports each one with its latency. The process is ready when it
+// is serving and every check passed.
+func (h *Health) Ready(ctx context.Context) models.HealthReport {
+	report := models.HealthReport{Status: StatusUp, Checks: make([]models.HealthCheck, len(h.checks))}
+
+	var wg sync.WaitGroup
+
+	for i := range h.checks {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			report.Checks[i] = h.run(ctx, h.checks[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, c := range report.Checks {
+		if c.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	switch atomic.LoadInt32(&h.state) {
+	case starting:
+		report.Status = StatusDown
+		report.Checks = append(report.Checks, models.HealthCheck{Name: lifecycleCheck, Status: StatusDown,
+			Error: "starting"})
+	case draining:
+		report.Status = StatusDown
+		report.Checks = append(report.Checks, models.HealthCheck{Name: lifecycleCheck, Status: StatusDown,
+			Error: "shutting down"})
+	}
+
+	return report
+}
+
+func (h *Health) run(ctx context.Context, c check) models.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+
+	// a check that does not honour its context still gets no more than the timeout
+	go func() { done <- c.checker.Check(ctx) }()
+
+	var err error
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := models.HealthCheck{Name: c.name, Status: StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+
+	if err != nil {
+		result.Status, result.Error = StatusDown, err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"Project/CarDealearship/models"
+	"context"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReady to test readiness follows the lifecycle of the process and the checks of its dependencies
+func TestReady(t *testing.T) {
+	up := CheckerFunc(func(context.Context) error { return nil })
+	down := CheckerFunc(func(context.Context) error { return errors.Error("connection refused") })
+	hanging := CheckerFunc(func(context.Context) error { select {} })
+
+	testCases := []struct {
+		desc   string
+		checks map[string]Checker
+		setup  func(h *Health)
+		status string
+		want   []models.HealthCheck
+	}{
+		{desc: "starting", checks: map[string]Checker{"database": up}, setup: func(*Health) {}, status: StatusDown,
+			want: []models.HealthCheck{{Name: "database", Status: StatusUp},
+				{Name: lifecycleCheck, Status: StatusDown, Error: "starting"}}},
+		{desc: "serving", checks: map[string]Checker{"database": up}, setup: (*Health).SetServing, status: StatusUp,
+			want: []models.HealthCheck{{Name: "database", Status: StatusUp}}},
+		{desc: "failed check", checks: map[string]Checker{"database": down}, setup: (*Health).SetServing,
+			status: StatusDown, want: []models.HealthCheck{{Name: "database", Status: StatusDown,
+				Error: "connection refused"}}},
+		{desc: "timed out check", checks: map[string]Checker{"cache": hanging}, setup: (*Health).SetServing,
+			status: StatusDown, want: []models.HealthCheck{{Name: "cache", Status: StatusDown,
+				Error: context.DeadlineExceeded.Error()}}},
+		{desc: "draining", setup: func(h *Health) {
+			h.SetServing()
+			h.SetDraining()
+			h.SetServing()
+		}, status: StatusDown, want: []models.HealthCheck{{Name: lifecycleCheck, Status: StatusDown,
+			Error: "shutting down"}}},
+	}
+
+	for i, tc := range testCases {
+		h := New(10 * time.Millisecond)
+
+		for name, c := range tc.checks {
+			h.Register(name, c)
+		}
+
+		tc.setup(h)
+
+		report := h.Ready(context.Background())
+
+		for j := range report.Checks {
+			assert.True(t, report.Checks[j].LatencyMs >= 0, "TEST[%d], failed.\n%s", i, tc.desc)
+			report.Checks[j].LatencyMs = 0
+		}
+
+		assert.Equal(t, tc.status, report.Status, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.want, report.Checks, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestLive to test a process is alive whatever its dependencies
+func TestLive(t *testing.T) {
+	h := New(time.Second)
+	h.Register("database", CheckerFunc(func(context.Context) error { return errors.Error("connection refused") }))
+
+	assert.Equal(t, models.HealthReport{Status: StatusUp, Checks: []models.HealthCheck{}}, h.Live())
+}
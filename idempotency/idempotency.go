@@ -0,0 +1,29 @@
+// Package idempotency keeps the responses of the requests sent with an Idempotency-Key, so that a retried request
+// is answered with the response of the first one instead of being run again.
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Record is what is kept for a key. It is pending while the first request runs, then holds its response.
+type Record struct {
+	// Fingerprint identifies the request, a key can not be reused for another one
+	Fingerprint string      `json:"fingerprint"`
+	Pending     bool        `json:"pending,omitempty"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// Store keeps the records of the keys for a while
+type Store interface {
+	// Reserve keeps rec for key unless the key has a record already, which is returned along with false
+	Reserve(ctx context.Context, key string, rec Record, ttl time.Duration) (Record, bool, error)
+	// Save replaces the record of key
+	Save(ctx context.Context, key string, rec Record, ttl time.Duration) error
+	// Release forgets key
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,78 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is the number of reservations between two sweeps of the expired records
+const sweepEvery = 1024
+
+type entry struct {
+	record  Record
+	expires time.Time
+}
+
+type memory struct {
+	mu       sync.Mutex
+	entries  map[string]entry
+	reserved int
+	now      func() time.Time
+}
+
+// nolint:revive // need not be exported
+// NewMemory factory function, the records are kept in process and so are not shared between instances
+func NewMemory() *memory {
+	return &memory{entries: make(map[string]entry), now: time.Now}
+}
+
+// Reserve keeps rec for key unless the key has a record that has not expired
+func (m *memory) Reserve(_ context.Context, key string, rec Record, ttl time.Duration) (Record, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	m.reserved++
+	if m.reserved%sweepEvery == 0 {
+		m.sweep(now)
+	}
+
+	if e, ok := m.entries[key]; ok && now.Before(e.expires) {
+		return e.record, false, nil
+	}
+
+	m.entries[key] = entry{record: rec, expires: now.Add(ttl)}
+
+	return rec, true, nil
+}
+
+// Save replaces the record of key
+func (m *memory) Save(_ context.Context, key string, rec Record, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = entry{record: rec, expires: m.now().Add(ttl)}
+
+	return nil
+}
+
+// Release forgets key
+func (m *memory) Release(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+
+	return nil
+}
+
+// sweep drops the expired records
+func (m *memory) sweep(now time.Time) {
+	for key, e := range m.entries {
+		if !now.Before(e.expires) {
+			delete(m.entries, key)
+		}
+	}
+}
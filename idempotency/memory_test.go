@@ -0,0 +1,64 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemory(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	pending := Record{Fingerprint: "a", Pending: true}
+	done := Record{Fingerprint: "a", Status: http.StatusCreated, Header: http.Header{"Content-Type": {"application/json"}},
+		Body: []byte(`{"data":{}}`)}
+
+	rec, ok, err := m.Reserve(ctx, "k", pending, time.Minute)
+	assert.Equal(t, nil, err)
+	assert.True(t, ok, "a new key is reserved")
+	assert.Equal(t, pending, rec)
+
+	rec, ok, _ = m.Reserve(ctx, "k", Record{Fingerprint: "b", Pending: true}, time.Minute)
+	assert.False(t, ok, "a reserved key is kept")
+	assert.Equal(t, pending, rec)
+
+	assert.Equal(t, nil, m.Save(ctx, "k", done, time.Hour))
+
+	now = now.Add(time.Minute)
+
+	rec, ok, _ = m.Reserve(ctx, "k", pending, time.Minute)
+	assert.False(t, ok)
+	assert.Equal(t, done, rec, "the saved record is kept for its own ttl")
+
+	now = now.Add(time.Hour)
+
+	_, ok, _ = m.Reserve(ctx, "k", pending, time.Minute)
+	assert.True(t, ok, "an expired key is reserved again")
+
+	assert.Equal(t, nil, m.Release(ctx, "k"))
+
+	_, ok, _ = m.Reserve(ctx, "k", pending, time.Minute)
+	assert.True(t, ok, "a released key is reserved again")
+}
+
+func TestMemorySweep(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	_, _, _ = m.Reserve(context.Background(), "a", Record{}, time.Second)
+	_, _, _ = m.Reserve(context.Background(), "b", Record{}, time.Minute)
+
+	now = now.Add(time.Second)
+
+	m.sweep(now)
+
+	assert.NotContains(t, m.entries, "a", "an expired record is dropped")
+	assert.Contains(t, m.entries, "b", "a live record is kept")
+}
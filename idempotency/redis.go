@@ -0,0 +1,67 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const redisPrefix = "idempotency:"
+
+type redisStore struct {
+	client redis.Cmdable
+}
+
+// nolint:revive // need not be exported
+// NewRedis factory function, the records are shared by every instance using the same redis
+func NewRedis(client redis.Cmdable) redisStore {
+	return redisStore{client: client}
+}
+
+// Reserve keeps rec for key unless the key has a record, records expire on their own
+func (r redisStore) Reserve(ctx context.Context, key string, rec Record, ttl time.Duration) (Record, bool, error) {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	ok, err := r.client.SetNX(ctx, redisPrefix+key, b, ttl).Result()
+	if err != nil || ok {
+		return rec, ok, err
+	}
+
+	b, err = r.client.Get(ctx, redisPrefix+key).Bytes()
+	if err == redis.Nil {
+		// the record expired in between
+		return r.Reserve(ctx, key, rec, ttl)
+	}
+
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	var existing Record
+
+	if err = json.Unmarshal(b, &existing); err != nil {
+		return Record{}, false, err
+	}
+
+	return existing, false, nil
+}
+
+// Save replaces the record of key
+func (r redisStore) Save(ctx context.Context, key string, rec Record, ttl time.Duration) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(ctx, redisPrefix+key, b, ttl).Err()
+}
+
+// Release forgets key
+func (r redisStore) Release(ctx context.Context, key string) error {
+	return r.client.Del(ctx, redisPrefix+key).Err()
+}
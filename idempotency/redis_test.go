@@ -0,0 +1,61 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedis(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+
+	defer client.Close()
+
+	r := NewRedis(client)
+	ctx := context.Background()
+	pending := Record{Fingerprint: "a", Pending: true}
+	done := Record{Fingerprint: "a", Status: http.StatusCreated, Header: http.Header{"Content-Type": {"application/json"}},
+		Body: []byte(`{"data":{}}`)}
+
+	rec, ok, err := r.Reserve(ctx, "k", pending, time.Minute)
+	assert.Equal(t, nil, err)
+	assert.True(t, ok, "a new key is reserved")
+	assert.Equal(t, pending, rec)
+	assert.Equal(t, time.Minute, s.TTL("idempotency:k"))
+
+	rec, ok, err = r.Reserve(ctx, "k", Record{Fingerprint: "b", Pending: true}, time.Minute)
+	assert.Equal(t, nil, err)
+	assert.False(t, ok, "a reserved key is kept")
+	assert.Equal(t, pending, rec)
+
+	assert.Equal(t, nil, r.Save(ctx, "k", done, time.Hour))
+	assert.Equal(t, time.Hour, s.TTL("idempotency:k"))
+
+	rec, ok, _ = r.Reserve(ctx, "k", pending, time.Minute)
+	assert.False(t, ok)
+	assert.Equal(t, done, rec)
+
+	s.FastForward(time.Hour)
+
+	_, ok, _ = r.Reserve(ctx, "k", pending, time.Minute)
+	assert.True(t, ok, "an expired key is reserved again")
+
+	assert.Equal(t, nil, r.Release(ctx, "k"))
+	assert.False(t, s.Exists("idempotency:k"))
+
+	assert.Equal(t, nil, s.Set("idempotency:bad", "{"))
+
+	_, _, err = r.Reserve(ctx, "bad", pending, time.Minute)
+	assert.NotNil(t, err, "a corrupt record is an error")
+
+	s.Close()
+
+	_, _, err = r.Reserve(ctx, "k", pending, time.Minute)
+	assert.NotNil(t, err, "redis is down")
+}
@@ -1,27 +1,590 @@
 package main
 
 import (
+	"Project/CarDealearship/auth"
+	"Project/CarDealearship/cache"
+	"Project/CarDealearship/events"
 	"Project/CarDealearship/handlers"
+	"Project/CarDealearship/handlers/graphql"
+	healthHandler "Project/CarDealearship/handlers/health"
+	mediaHandler "Project/CarDealearship/handlers/media"
+	"Project/CarDealearship/handlers/rpc"
+	"Project/CarDealearship/handlers/stream"
+	v2 "Project/CarDealearship/handlers/v2"
+	webhookHandler "Project/CarDealearship/handlers/webhook"
+	"Project/CarDealearship/health"
+	"Project/CarDealearship/idempotency"
+	"Project/CarDealearship/metrics"
+	"Project/CarDealearship/middleware"
+	"Project/CarDealearship/migrations"
+	"Project/CarDealearship/openapi"
+	"Project/CarDealearship/problem"
+	"Project/CarDealearship/ratelimit"
+	"Project/CarDealearship/service"
 	car2 "Project/CarDealearship/service/car"
+	dealershipService "Project/CarDealearship/service/dealership"
+	mediaService "Project/CarDealearship/service/media"
+	"Project/CarDealearship/service/traced"
+	webhookService "Project/CarDealearship/service/webhook"
+	"Project/CarDealearship/shutdown"
+	"Project/CarDealearship/stores"
+	"Project/CarDealearship/stores/apikey"
+	"Project/CarDealearship/stores/blob"
+	"Project/CarDealearship/stores/cached"
 	"Project/CarDealearship/stores/car"
+	"Project/CarDealearship/stores/dealership"
 	"Project/CarDealearship/stores/engine"
+	"Project/CarDealearship/stores/instrumented"
+	"Project/CarDealearship/stores/media"
+	"Project/CarDealearship/stores/outbox"
+	"Project/CarDealearship/stores/search"
+	"Project/CarDealearship/stores/transaction"
+	"Project/CarDealearship/stores/webhook"
+	"Project/CarDealearship/webhooks"
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"google.golang.org/grpc"
 )
 
+// v1Successors are the v1 car routes replaced by v2 ones, their responses carry a Deprecation header
+var v1Successors = []middleware.DeprecatedRoute{
+	{Method: http.MethodGet, Path: "/car/{id}", Successor: "/v2/cars/{id}"},
+	{Method: http.MethodGet, Path: "/cars", Successor: "/v2/cars"},
+	{Method: http.MethodGet, Path: "/cars/search", Successor: "/v2/cars/search"},
+	{Method: http.MethodGet, Path: "/cars/compare", Successor: "/v2/cars/compare"},
+	{Method: http.MethodPost, Path: "/car", Successor: "/v2/cars"},
+	{Method: http.MethodPut, Path: "/car/{id}", Successor: "/v2/cars/{id}"},
+	{Method: http.MethodDelete, Path: "/car/{id}", Successor: "/v2/cars/{id}"},
+}
+
 func main() {
 	k := gofr.New()
 	k.Server.ValidateHeaders = false
 
-	st := car.New()
-	engin := engine.New()
-	svc := car2.New(st, engin)
+	m := newMetrics(k)
+	c := newCache(k)
+	hc := newHealth(k, c)
+	st := cached.NewCar(instrumented.NewCar(car.New(), m), c, configDuration(k, "CACHE_CAR_TTL", "5m"),
+		configDuration(k, "CACHE_LIST_TTL", "1m"))
+	engin := cached.NewEngine(instrumented.NewEngine(engine.New(), m), c, configDuration(k, "CACHE_ENGINE_TTL", "30m"))
+	mediaStore := media.New()
+	outboxStore := outbox.New()
+	blobStore := newBlobStore(k)
+	carService := car2.New(st, engin, mediaStore, blobStore, search.New(), transaction.New(), outboxStore, m)
+	svc := traced.NewCars(carService)
 	h := handlers.New(svc)
 
-	k.GET("/car/{id}", h.GetByID)
-	k.GET("/cars", h.GetByBrand)
-	k.POST("/car", h.Create)
-	k.PUT("/car/{id}", h.Update)
+	mh := mediaHandler.New(mediaService.New(st, mediaStore, blobStore, transaction.New()))
+
+	authenticator := auth.New(newTokenVerifier(k), apikey.New())
+
+	drainer := shutdown.NewDrainer()
+
+	// errors are responded as problem details, including the ones of the middlewares
+	k.Server.UseMiddleware(middleware.Problems())
+	// the probes answer until the process exits and the event streams resume on another instance
+	k.Server.UseMiddleware(middleware.Drain(drainer, "/health/", "/cars/stream"))
+	rateLimits := newRateLimitBackend(k)
+
+	// every client is limited by its ip before its credentials are checked, so that they cannot be guessed
+	k.Server.UseMiddleware(middleware.RateLimit(k, rateLimits, middleware.RateLimitConfig{
+		Default: configLimit(k, "RATE_LIMIT_IP", "1200/m:200"), TrustProxy: configTrustProxy(k), Scope: "ip|",
+	}))
+	// media files are linked from listings and stay public, like the health endpoints and the api document
+	k.Server.UseMiddleware(middleware.Authenticate(k, authenticator, "/health/", "/media/", "/openapi.json"))
+	k.Server.UseMiddleware(middleware.RateLimit(k, rateLimits, newRateLimitConfig(k)))
+	k.Server.UseMiddleware(middleware.Idempotency(k, newIdempotencyStore(k), middleware.IdempotencyConfig{
+		TTL:         configDuration(k, "IDEMPOTENCY_TTL", "24h"),
+		LockTimeout: configDuration(k, "IDEMPOTENCY_LOCK_TIMEOUT", "1m"),
+		TrustProxy:  configTrustProxy(k),
+		MaxBody:     int64(configInt(k, "IDEMPOTENCY_MAX_BODY", "1048576")),
+		MaxResponse: configInt(k, "IDEMPOTENCY_MAX_RESPONSE", "1048576"),
+	}))
+	k.Server.UseMiddleware(middleware.Deprecate(k.Config.Get("API_V1_SUNSET"), v1Successors...))
+	k.Server.UseMiddleware(middleware.Conditional(newCacheRoutes(k)...))
+
+	k.GET("/car/{id}", handle(auth.ReadCars, h.GetByID))
+	k.GET("/cars", handle(auth.ReadCars, h.GetByBrand))
+	k.GET("/cars/search", handle(auth.ReadCars, h.Search))
+	k.GET("/cars/compare", handle(auth.ReadCars, h.Compare))
+	k.GET("/cars/duplicates", handle(auth.ReadCars, h.Duplicates))
+	middleware.Mount(k, http.MethodGet, "/cars/export", middleware.RequireStream(auth.ExportCars, h.Export))
+
+	hub := events.NewHub(outboxStore, events.HubConfig{
+		BatchSize:  configInt(k, "STREAM_BATCH_SIZE", "100"),
+		Buffer:     configInt(k, "STREAM_BUFFER", "64"),
+		GapTimeout: configDuration(k, "STREAM_GAP_TIMEOUT", "2s"),
+	})
+	sh := stream.New(hub, stream.Config{
+		Heartbeat:    configDuration(k, "STREAM_HEARTBEAT", "15s"),
+		WriteTimeout: configDuration(k, "STREAM_WRITE_TIMEOUT", "10s"),
+	})
+
+	middleware.Mount(k, http.MethodGet, "/cars/stream", middleware.RequireStream(auth.ReadCars, sh.Events))
+	middleware.Mount(k, http.MethodGet, "/cars/stream/ws", middleware.RequireStream(auth.ReadCars, sh.WebSocket))
+	k.POST("/car", handle(auth.WriteCars, h.Create))
+	k.POST("/cars/import", handle(auth.ImportCars, h.Import))
+	k.POST("/cars:batchGet", handle(auth.ReadCars, h.BatchGet))
+	k.POST("/cars:batchUpdate", handle(auth.WriteCars, h.BatchUpdate))
+	k.PUT("/car/{id}", handle(auth.WriteCars, h.Update))
+	k.DELETE("/car/{id}", handle(auth.DeleteCars, h.Delete))
+
+	h2 := v2.New(svc)
+
+	// routes are matched in the order they are registered, /v2/cars/{id} would match /v2/cars/search
+	k.GET("/v2/cars/search", handle(auth.ReadCars, h2.Search))
+	k.GET("/v2/cars/compare", handle(auth.ReadCars, h2.Compare))
+	k.GET("/v2/cars/{id}", handle(auth.ReadCars, h2.GetByID))
+	k.GET("/v2/cars", handle(auth.ReadCars, h2.List))
+	k.POST("/v2/cars", handle(auth.WriteCars, h2.Create))
+	k.PUT("/v2/cars/{id}", handle(auth.WriteCars, h2.Update))
+	k.DELETE("/v2/cars/{id}", handle(auth.DeleteCars, h2.Delete))
+
+	// the resolvers check the permission of every query and mutation
+	gh := graphql.New(svc, dealershipService.New(dealership.New()))
+	middleware.Mount(k, http.MethodPost, "/graphql", gh.Serve)
+
+	k.GET("/car/{id}/media", handle(auth.ReadCars, mh.GetByCarID))
+	k.POST("/car/{id}/media", handle(auth.WriteMedia, mh.Upload))
+	k.PUT("/car/{id}/media/order", handle(auth.WriteMedia, mh.Reorder))
+	k.PUT("/car/{id}/media/{mediaID}/cover", handle(auth.WriteMedia, mh.SetCover))
+	k.DELETE("/car/{id}/media/{mediaID}", handle(auth.WriteMedia, mh.Delete))
+	k.GET("/media/{key}", problem.Handler(mh.File))
+
+	webhookStore := webhook.New()
+	wh := webhookHandler.New(webhookService.New(webhookStore))
+
+	k.GET("/webhooks", handle(auth.ReadWebhooks, wh.GetAll))
+	k.POST("/webhooks", handle(auth.WriteWebhooks, wh.Create))
+	k.GET("/webhooks/{id}/deliveries", handle(auth.ReadWebhooks, wh.Deliveries))
+	k.POST("/webhooks/{id}/deliveries/{deliveryID}/retry", handle(auth.WriteWebhooks, wh.Retry))
+	k.GET("/webhooks/{id}", handle(auth.ReadWebhooks, wh.GetByID))
+	k.PUT("/webhooks/{id}", handle(auth.WriteWebhooks, wh.Update))
+	k.DELETE("/webhooks/{id}", handle(auth.WriteWebhooks, wh.Delete))
+
+	middleware.Mount(k, http.MethodGet, "/openapi.json",
+		openapi.Handler(openapi.Build(k.Config.Get("APP_NAME"), k.Config.Get("APP_VERSION"))))
+
+	hh := healthHandler.New(hc)
+	middleware.Mount(k, http.MethodGet, "/health/live", hh.Live)
+	middleware.Mount(k, http.MethodGet, "/health/ready", hh.Ready)
+
+	gs := rpc.New(k, authenticator, svc)
+	workers := shutdown.NewGroup()
+
+	go awaitShutdown(k, hc, drainer, gs, workers)
+
+	// the server is up while the migrations run so that the probes can tell, it is ready once they are done. The
+	// startup is one of the workers, so that a shutdown during the migrations stops them before the database
+	// pool is closed.
+	workers.Go(func(parent context.Context) {
+		if err := migrations.Run(parent, k.DB(), migrations.All()); err != nil {
+			if parent.Err() != nil {
+				k.Logger.Infof("migrations stopped by the shutdown: %v", err)
+				return
+			}
+
+			k.Logger.Fatalf("error in running migrations: %v", err)
+		}
+
+		ctx := gofr.NewContext(nil, nil, k)
+		ctx.Context = parent
+
+		if err := carService.Reindex(ctx); err != nil {
+			k.Logger.Errorf("error in building the search index: %v", err)
+		}
+
+		go serveGRPC(k, gs)
+
+		workers.Go(func(ctx context.Context) { relayEvents(ctx, k, outboxStore, webhookStore) })
+		workers.Go(func(ctx context.Context) { pruneOutbox(ctx, k, outboxStore) })
+		workers.Go(func(ctx context.Context) { dispatchWebhooks(ctx, k, webhookStore) })
+		workers.Go(func(ctx context.Context) { followOutbox(ctx, k, hub) })
+
+		if m != nil {
+			workers.Go(func(ctx context.Context) { reportInventory(ctx, k, svc) })
+		}
+
+		hc.SetServing()
+	})
 
 	k.Start()
+}
+
+// handle is the gofr handler of a route, h is only run for principals with the permission and its errors are
+// mapped to the status and code they are responded with
+func handle(permission auth.Permission, h gofr.Handler) gofr.Handler {
+	return problem.Handler(auth.Require(permission, h))
+}
+
+// awaitShutdown stops the application on SIGINT or SIGTERM. Readiness fails right away and the requests are still
+// served for SHUTDOWN_READINESS_DELAY, then the HTTP requests and the gRPC calls in flight are drained while new
+// ones are turned away, the workers stop once the events of the drained requests are published and the database
+// pool is closed. It all gets SHUTDOWN_TIMEOUT.
+func awaitShutdown(k *gofr.Gofr, hc *health.Health, d *shutdown.Drainer, gs *grpc.Server, workers *shutdown.Group) {
+	timeout := configDuration(k, "SHUTDOWN_TIMEOUT", "25s")
+	delay := configDuration(k, "SHUTDOWN_READINESS_DELAY", "5s")
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	<-ctx.Done()
+	stop()
+
+	hc.SetDraining()
+	k.Logger.Infof("shutting down, draining the requests in flight for up to %v", timeout)
+
+	shutdown.Run(k.Logger, timeout,
+		shutdown.Step{Name: "readiness delay", Run: func(ctx context.Context) error {
+			return shutdown.Delay(ctx, delay)
+		}},
+		shutdown.Step{Name: "http requests", Run: d.Drain},
+		shutdown.Step{Name: "grpc calls", Run: func(ctx context.Context) error {
+			err := shutdown.Wait(ctx, gs.GracefulStop)
+			if err != nil {
+				gs.Stop()
+			}
+
+			return err
+		}},
+		shutdown.Step{Name: "workers", Run: workers.Stop},
+		shutdown.Step{Name: "database", Run: func(context.Context) error { return k.DB().Close() }},
+	)
+
+	os.Exit(0)
+}
+
+// serveGRPC serves the gRPC api on GRPC_SERVER_PORT next to the HTTP server
+func serveGRPC(k *gofr.Gofr, s *grpc.Server) {
+	lis, err := net.Listen("tcp", ":"+k.Config.GetOrDefault("GRPC_SERVER_PORT", "9001"))
+	if err != nil {
+		k.Logger.Fatalf("error in listening for grpc: %v", err)
+	}
+
+	if err = s.Serve(lis); err != nil {
+		k.Logger.Errorf("error in serving grpc: %v", err)
+	}
+}
+
+// relayEvents publishes the car events of the outbox every EVENTS_RELAY_INTERVAL, EVENTS_BATCH_SIZE events at a
+// time, to kafka when a pub/sub backend is configured and to the deliveries of the subscribed webhooks. Once
+// parent is done the outbox is flushed a last time.
+func relayEvents(parent context.Context, k *gofr.Gofr, o stores.Outbox, w stores.Webhook) {
+	interval := configDuration(k, "EVENTS_RELAY_INTERVAL", "1s")
+	batchSize := configInt(k, "EVENTS_BATCH_SIZE", "100")
+
+	// deliveries are created in the transaction of the relay, so they are published last
+	publishers := []events.Publisher{webhooks.NewPublisher(w)}
+
+	if k.PubSub != nil && k.PubSub.IsSet() {
+		publishers = append([]events.Publisher{events.NewKafka(k.PubSub)}, publishers...)
+	} else {
+		k.Logger.Warn("pub/sub is not configured, car events are only delivered to webhooks")
+	}
+
+	// the relay keeps its transactions in its context, so it gets one of its own
+	ctx := gofr.NewContext(nil, nil, k)
+	ctx.Context = parent
+
+	relay := events.NewRelay(o, transaction.New(), events.NewFanout(publishers...), batchSize)
+	relay.Run(ctx, interval)
+
+	// the events recorded by the requests drained on shutdown are published before the process exits
+	ctx.Context = context.Background()
+
+	if _, err := relay.Flush(ctx); err != nil {
+		k.Logger.Errorf("error in flushing the outbox: %v", err)
+	}
+}
+
+// pruneOutbox deletes the events published longer than OUTBOX_RETENTION ago every OUTBOX_PRUNE_INTERVAL
+func pruneOutbox(parent context.Context, k *gofr.Gofr, o stores.Outbox) {
+	retention := configDuration(k, "OUTBOX_RETENTION", "168h")
+	batchSize := configInt(k, "EVENTS_BATCH_SIZE", "100")
+
+	ticker := time.NewTicker(configDuration(k, "OUTBOX_PRUNE_INTERVAL", "1h"))
+	defer ticker.Stop()
+
+	ctx := gofr.NewContext(nil, nil, k)
+	ctx.Context = parent
+
+	for {
+		if n, err := events.Prune(ctx, o, retention, batchSize); err != nil {
+			k.Logger.Errorf("error in pruning the outbox, %v deleted: %v", n, err)
+		}
+
+		select {
+		case <-parent.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchWebhooks sends the due webhook deliveries every WEBHOOK_DISPATCH_INTERVAL, failed ones are retried
+// with a backoff starting at WEBHOOK_RETRY_BASE until WEBHOOK_MAX_ATTEMPTS attempts were made
+func dispatchWebhooks(parent context.Context, k *gofr.Gofr, w stores.Webhook) {
+	interval := configDuration(k, "WEBHOOK_DISPATCH_INTERVAL", "5s")
+	timeout := configDuration(k, "WEBHOOK_TIMEOUT", "10s")
+	batchSize := configInt(k, "WEBHOOK_BATCH_SIZE", "50")
+	workers := configInt(k, "WEBHOOK_WORKERS", "10")
+
+	config := webhooks.Config{
+		BatchSize:   batchSize,
+		Workers:     workers,
+		MaxAttempts: configInt(k, "WEBHOOK_MAX_ATTEMPTS", "8"),
+		RetryBase:   configDuration(k, "WEBHOOK_RETRY_BASE", "30s"),
+		RetryMax:    configDuration(k, "WEBHOOK_RETRY_MAX", "1h"),
+		// a claimed delivery is only sent again by another instance once the attempts of its whole batch, made
+		// workers at a time, have surely timed out
+		Lease: time.Duration((batchSize+workers-1)/workers+1) * timeout,
+	}
+
+	ctx := gofr.NewContext(nil, nil, k)
+	ctx.Context = parent
+
+	webhooks.NewDispatcher(w, transaction.New(), &http.Client{Timeout: timeout}, config).Run(ctx, interval)
+}
+
+// followOutbox broadcasts the events recorded in the outbox to the streams every STREAM_POLL_INTERVAL
+func followOutbox(parent context.Context, k *gofr.Gofr, hub *events.Hub) {
+	interval := configDuration(k, "STREAM_POLL_INTERVAL", "500ms")
+
+	ctx := gofr.NewContext(nil, nil, k)
+	ctx.Context = parent
+
+	hub.Run(ctx, interval)
+}
+
+// reportInventory sets the inventory gauges every INVENTORY_METRICS_INTERVAL
+func reportInventory(parent context.Context, k *gofr.Gofr, svc service.Cars) {
+	ticker := time.NewTicker(configDuration(k, "INVENTORY_METRICS_INTERVAL", "1m"))
+	defer ticker.Stop()
+
+	ctx := gofr.NewContext(nil, nil, k)
+	ctx.Context = parent
+
+	for {
+		if err := svc.ReportInventory(ctx); err != nil {
+			k.Logger.Errorf("error in reporting the inventory: %v", err)
+		}
+
+		select {
+		case <-parent.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// configDuration reads a positive duration from the config, the application does not start with an invalid one
+func configDuration(k *gofr.Gofr, key, defaultValue string) time.Duration {
+	d, err := time.ParseDuration(k.Config.GetOrDefault(key, defaultValue))
+	if err != nil || d <= 0 {
+		k.Logger.Fatalf("invalid %v: %v", key, k.Config.Get(key))
+	}
+
+	return d
+}
+
+// configInt reads a positive number from the config, the application does not start with an invalid one
+func configInt(k *gofr.Gofr, key, defaultValue string) int {
+	n, err := strconv.Atoi(k.Config.GetOrDefault(key, defaultValue))
+	if err != nil || n <= 0 {
+		k.Logger.Fatalf("invalid %v: %v", key, k.Config.Get(key))
+	}
+
+	return n
+}
+
+// configLimit reads a rate limit from the config, the application does not start with an invalid one
+func configLimit(k *gofr.Gofr, key, defaultValue string) ratelimit.Limit {
+	limit, err := ratelimit.ParseLimit(k.Config.GetOrDefault(key, defaultValue))
+	if err != nil {
+		k.Logger.Fatalf("error in %v: %v", key, err)
+	}
+
+	return limit
+}
+
+// configTrustProxy reports whether the clients reach the api through a proxy appending their ip to X-Forwarded-For
+func configTrustProxy(k *gofr.Gofr) bool {
+	return k.Config.Get("RATE_LIMIT_TRUST_PROXY") == "true"
+}
+
+// newBlobStore returns the blob store selected by BLOB_STORE, files are kept on the local filesystem by default
+func newBlobStore(k *gofr.Gofr) stores.Blob {
+	if k.Config.Get("BLOB_STORE") == "s3" {
+		return blob.NewS3(blob.S3Config{
+			Endpoint:  k.Config.Get("S3_ENDPOINT"),
+			Region:    k.Config.GetOrDefault("S3_REGION", "us-east-1"),
+			Bucket:    k.Config.Get("S3_BUCKET"),
+			AccessKey: k.Config.Get("S3_ACCESS_KEY"),
+			SecretKey: k.Config.Get("S3_SECRET_KEY"),
+			PublicURL: k.Config.Get("S3_PUBLIC_URL"),
+		})
+	}
+
+	return blob.NewLocal(k.Config.GetOrDefault("MEDIA_DIR", "./media"),
+		k.Config.GetOrDefault("MEDIA_BASE_URL", "http://localhost:9000/media"))
+}
+
+// newTokenVerifier returns the verifier of bearer tokens signed with AUTH_JWT_SECRET or a key of the
+// AUTH_JWKS_FILE, nil when neither is configured and only api keys are accepted
+func newTokenVerifier(k *gofr.Gofr) auth.TokenVerifier {
+	config := auth.JWTConfig{
+		Secret:   []byte(k.Config.Get("AUTH_JWT_SECRET")),
+		Issuer:   k.Config.Get("AUTH_JWT_ISSUER"),
+		Audience: k.Config.Get("AUTH_JWT_AUDIENCE"),
+	}
+
+	if path := k.Config.Get("AUTH_JWKS_FILE"); path != "" {
+		keys, err := auth.LoadJWKS(path)
+		if err != nil {
+			k.Logger.Fatalf("error in loading the JWKS file %v: %v", path, err)
+		}
+
+		config.Keys = keys
+	}
+
+	if len(config.Secret) == 0 && len(config.Keys) == 0 {
+		return nil
+	}
+
+	if err := config.Validate(); err != nil {
+		k.Logger.Fatalf("error in AUTH_JWT_SECRET: %v", err)
+	}
+
+	return auth.NewJWTVerifier(config)
+}
+
+// newRateLimitBackend returns the backend selected by RATE_LIMIT_BACKEND, buckets are kept in memory by default
+// and in redis when the limits have to be shared by every instance
+func newRateLimitBackend(k *gofr.Gofr) ratelimit.Backend {
+	if k.Config.Get("RATE_LIMIT_BACKEND") == "redis" {
+		if k.Redis == nil || !k.Redis.IsSet() {
+			k.Logger.Fatalf("RATE_LIMIT_BACKEND is redis but redis is not configured")
+		}
+
+		return ratelimit.NewRedis(k.Redis)
+	}
+
+	return ratelimit.NewMemory()
+}
+
+// newIdempotencyStore returns the store selected by IDEMPOTENCY_BACKEND, responses are kept in memory by default
+// and in redis when a retry may reach another instance
+func newIdempotencyStore(k *gofr.Gofr) idempotency.Store {
+	if k.Config.Get("IDEMPOTENCY_BACKEND") == "redis" {
+		if k.Redis == nil || !k.Redis.IsSet() {
+			k.Logger.Fatalf("IDEMPOTENCY_BACKEND is redis but redis is not configured")
+		}
+
+		return idempotency.NewRedis(k.Redis)
+	}
+
+	return idempotency.NewMemory()
+}
+
+// newCache returns the cache of the car and engine reads, kept in memory by default and in redis when
+// CACHE_BACKEND is redis, so that every instance sees the invalidations of the others
+func newCache(k *gofr.Gofr) *cache.Cache {
+	var m cache.Metrics
+
+	if k.Metric != nil {
+		for _, name := range []string{cache.HitsMetric, cache.MissesMetric} {
+			if err := k.Metric.NewCounter(name, "reads of the cache of the car and engine lookups", "entity"); err != nil {
+				k.Logger.Errorf("error in registering metric %v: %v", name, err)
+			}
+		}
+
+		m = k.Metric
+	}
+
+	if k.Config.Get("CACHE_BACKEND") == "redis" {
+		if k.Redis == nil || !k.Redis.IsSet() {
+			k.Logger.Fatalf("CACHE_BACKEND is redis but redis is not configured")
+		}
+
+		return cache.New(cache.NewRedis(k.Redis), m)
+	}
+
+	return cache.New(cache.NewLRU(configInt(k, "CACHE_SIZE", "10000")), m)
+}
+
+// newMetrics registers the business metrics with gofr's, which are exposed on the metrics endpoint. They are not
+// recorded when gofr has no metrics.
+func newMetrics(k *gofr.Gofr) metrics.Recorder {
+	if k.Metric == nil {
+		return nil
+	}
+
+	if err := metrics.Register(k.Metric); err != nil {
+		k.Logger.Errorf("error in registering the business metrics: %v", err)
+	}
+
+	return k.Metric
+}
+
+// newHealth returns the readiness checks of the dependencies, every one gets HEALTH_CHECK_TIMEOUT. Pub/sub is
+// only checked when it is configured.
+func newHealth(k *gofr.Gofr, c *cache.Cache) *health.Health {
+	h := health.New(configDuration(k, "HEALTH_CHECK_TIMEOUT", "2s"))
+	h.Register("database", health.CheckerFunc(k.DB().PingContext))
+	h.Register("migrations", health.Migrations(k.DB(), migrations.All()))
+	h.Register("cache", health.CheckerFunc(c.Ping))
+
+	if k.PubSub != nil && k.PubSub.IsSet() {
+		h.Register("pubsub", health.PubSub(k.PubSub))
+	}
+
+	return h
+}
+
+// newCacheRoutes reads the routes answering conditional requests and their Cache-Control header from
+// HTTP_CACHE_ROUTES, the responses depend on the caller so they are private
+func newCacheRoutes(k *gofr.Gofr) []middleware.CacheRoute {
+	routes, err := middleware.ParseCacheRoutes(k.Config.GetOrDefault("HTTP_CACHE_ROUTES",
+		"GET /car/{id}=private, no-cache; GET /cars=private, no-cache"))
+	if err != nil {
+		k.Logger.Fatalf("error in HTTP_CACHE_ROUTES: %v", err)
+	}
+
+	return routes
+}
+
+// newRateLimitConfig reads RATE_LIMIT_DEFAULT and the route limits of RATE_LIMIT_ROUTES and RATE_LIMIT_FILE,
+// the routes of the file come first
+func newRateLimitConfig(k *gofr.Gofr) middleware.RateLimitConfig {
+	config := middleware.RateLimitConfig{TrustProxy: configTrustProxy(k)}
+
+	if k.Config.Get("RATE_LIMIT_DEFAULT") != "" {
+		config.Default = configLimit(k, "RATE_LIMIT_DEFAULT", "")
+	}
+
+	routes := k.Config.Get("RATE_LIMIT_ROUTES")
+
+	if path := k.Config.Get("RATE_LIMIT_FILE"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			k.Logger.Fatalf("error in reading the rate limit file %v: %v", path, err)
+		}
+
+		routes = string(b) + "\n" + routes
+	}
+
+	rules, err := ratelimit.ParseRules(routes)
+	if err != nil {
+		k.Logger.Fatalf("error in rate limit routes: %v", err)
+	}
+
+	config.Rules = rules
 
+	return config
 }
@@ -0,0 +1,97 @@
+// Package metrics names the business metrics of the dealership. They are registered with gofr's metrics in main,
+// which are exposed on the metrics endpoint, and recorded through a Recorder by the services and stores.
+package metrics
+
+import (
+	"time"
+)
+
+// Metrics of the changes to the cars, labelled by brand and fuel type
+const (
+	CarsCreated = "cars_created_total"
+	CarsUpdated = "cars_updated_total"
+	CarsDeleted = "cars_deleted_total"
+)
+
+// Inventory is the number of cars by status and dealership
+const Inventory = "cars_inventory"
+
+// ValidationFailures counts the cars rejected on create, labelled by the reason
+const ValidationFailures = "car_validation_failures_total"
+
+// StoreQueryDuration is the latency in seconds of the store methods, labelled by store and method
+const StoreQueryDuration = "store_query_duration_seconds"
+
+// NoDealership is the dealership label of the cars that are not at one
+const NoDealership = "none"
+
+// queryBuckets are the buckets of the store latencies, from a cached row to a slow scan
+var queryBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5}
+
+// Recorder records the metrics, gofr's metrics satisfy it
+type Recorder interface {
+	IncCounter(name string, labels ...string) error
+	SetGauge(name string, value float64, labels ...string) error
+	ObserveHistogram(name string, value float64, labels ...string) error
+}
+
+// Registry creates the metrics, gofr's metrics satisfy it
+type Registry interface {
+	NewCounter(name, help string, labels ...string) error
+	NewGauge(name, help string, labels ...string) error
+	NewHistogram(name, help string, buckets []float64, labels ...string) error
+}
+
+// Register creates the metrics of the package
+func Register(r Registry) error {
+	counters := []struct {
+		name, help string
+		labels     []string
+	}{
+		{CarsCreated, "cars created", []string{"brand", "fuel_type"}},
+		{CarsUpdated, "cars updated", []string{"brand", "fuel_type"}},
+		{CarsDeleted, "cars deleted", []string{"brand", "fuel_type"}},
+		{ValidationFailures, "cars rejected on create because of an invalid attribute", []string{"reason"}},
+	}
+
+	for _, c := range counters {
+		if err := r.NewCounter(c.name, c.help, c.labels...); err != nil {
+			return err
+		}
+	}
+
+	if err := r.NewGauge(Inventory, "cars by status and dealership", "status", "dealership"); err != nil {
+		return err
+	}
+
+	return r.NewHistogram(StoreQueryDuration, "latency of the store methods in seconds", queryBuckets, "store",
+		"method")
+}
+
+// Or returns r, or a recorder dropping the metrics when r is nil
+func Or(r Recorder) Recorder {
+	if r == nil {
+		return nop{}
+	}
+
+	return r
+}
+
+// Since observes the time elapsed since start as the latency of a store method, it is meant to be deferred
+func Since(r Recorder, store, method string, start time.Time) {
+	_ = r.ObserveHistogram(StoreQueryDuration, time.Since(start).Seconds(), store, method)
+}
+
+type nop struct{}
+
+func (nop) IncCounter(string, ...string) error {
+	return nil
+}
+
+func (nop) SetGauge(string, float64, ...string) error {
+	return nil
+}
+
+func (nop) ObserveHistogram(string, float64, ...string) error {
+	return nil
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// registry keeps the labels of the metrics created, keyed by name, and fails on the metric named fail
+type registry struct {
+	labels map[string][]string
+	fail   string
+}
+
+func (r *registry) add(name string, labels []string) error {
+	if name == r.fail {
+		return errors.Error("duplicate metric " + name)
+	}
+
+	r.labels[name] = labels
+
+	return nil
+}
+
+func (r *registry) NewCounter(name, _ string, labels ...string) error {
+	return r.add(name, labels)
+}
+
+func (r *registry) NewGauge(name, _ string, labels ...string) error {
+	return r.add(name, labels)
+}
+
+func (r *registry) NewHistogram(name, _ string, buckets []float64, labels ...string) error {
+	if len(buckets) == 0 {
+		return errors.Error("no buckets")
+	}
+
+	return r.add(name, labels)
+}
+
+// recorder keeps the last histogram observation
+type recorder struct {
+	nop
+	name   string
+	value  float64
+	labels []string
+}
+
+func (r *recorder) ObserveHistogram(name string, value float64, labels ...string) error {
+	r.name, r.value, r.labels = name, value, labels
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		desc   string
+		fail   string
+		err    error
+		labels map[string][]string
+	}{
+		{"success", "", nil, map[string][]string{
+			CarsCreated:        {"brand", "fuel_type"},
+			CarsUpdated:        {"brand", "fuel_type"},
+			CarsDeleted:        {"brand", "fuel_type"},
+			ValidationFailures: {"reason"},
+			Inventory:          {"status", "dealership"},
+			StoreQueryDuration: {"store", "method"},
+		}},
+		{"counter error", CarsUpdated, errors.Error("duplicate metric " + CarsUpdated), map[string][]string{
+			CarsCreated: {"brand", "fuel_type"},
+		}},
+		{"gauge error", Inventory, errors.Error("duplicate metric " + Inventory), map[string][]string{
+			CarsCreated:        {"brand", "fuel_type"},
+			CarsUpdated:        {"brand", "fuel_type"},
+			CarsDeleted:        {"brand", "fuel_type"},
+			ValidationFailures: {"reason"},
+		}},
+	}
+
+	for i, tc := range tests {
+		r := &registry{labels: make(map[string][]string), fail: tc.fail}
+
+		assert.Equal(t, tc.err, Register(r), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.labels, r.labels, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestOr(t *testing.T) {
+	r := &recorder{}
+
+	assert.Equal(t, r, Or(r))
+	assert.Equal(t, nop{}, Or(nil))
+	assert.Equal(t, nil, Or(nil).IncCounter(CarsCreated, "BMW", "Petrol"))
+}
+
+func TestSince(t *testing.T) {
+	r := &recorder{}
+
+	Since(r, "car", "GetCarByID", time.Now().Add(-time.Second))
+
+	assert.Equal(t, StoreQueryDuration, r.name)
+	assert.Equal(t, []string{"car", "GetCarByID"}, r.labels)
+	assert.True(t, r.value >= 1, "the latency is in seconds")
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"Project/CarDealearship/auth"
+	"net/http"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+)
+
+// Authenticator returns the principal of the request of ctx
+type Authenticator interface {
+	Authenticate(ctx *gofr.Context) (auth.Principal, error)
+}
+
+// Authenticate rejects requests without valid credentials and puts the principal of the others on the request
+// context, where auth.FromContext finds it. Requests for a path starting with one of the public prefixes are
+// served without credentials.
+func Authenticate(k *gofr.Gofr, a Authenticator, public ...string) gofr.Middleware {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, prefix := range public {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					inner.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			res := responder.NewContextualResponder(w, r)
+			ctx := gofr.NewContext(res, request.NewHTTPRequest(r), k)
+			ctx.Context = r.Context()
+
+			p, err := a.Authenticate(ctx)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="car-dealership"`)
+				res.Respond(nil, err)
+
+				return
+			}
+
+			inner.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), p)))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"Project/CarDealearship/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) Authenticate(ctx *gofr.Context) (auth.Principal, error) {
+	if ctx.Request().Header.Get("Authorization") != "Bearer good" {
+		return auth.Principal{}, &errors.Response{StatusCode: http.StatusUnauthorized, Code: "UNAUTHENTICATED",
+			Reason: "missing credentials"}
+	}
+
+	return auth.Principal{Subject: "u1"}, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := auth.FromContext(r.Context())
+		_, _ = w.Write([]byte("hello " + p.Subject))
+	})
+
+	h := Authenticate(gofr.New(), fakeAuthenticator{}, "/media/", "/health/")(inner)
+
+	testCases := []struct {
+		desc          string
+		target        string
+		authorization string
+		status        int
+		body          string
+	}{
+		{desc: "authenticated", target: "/cars", authorization: "Bearer good", status: http.StatusOK, body: "hello u1"},
+		{desc: "public path", target: "/media/a.jpg", status: http.StatusOK, body: "hello "},
+		{desc: "unauthenticated", target: "/cars", status: http.StatusUnauthorized},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+		r.Header.Set("Authorization", tc.authorization)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.status == http.StatusOK {
+			assert.Equal(t, tc.body, w.Body.String(), "TEST[%d], failed.\n%s", i, tc.desc)
+		} else {
+			assert.Equal(t, `Bearer realm="car-dealership"`, w.Header().Get("WWW-Authenticate"),
+				"TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
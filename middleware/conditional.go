@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"Project/CarDealearship/route"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// CacheRoute is a GET route whose responses carry an ETag and, when its handler reports one, a Last-Modified
+// header. Control is the Cache-Control header of its responses.
+type CacheRoute struct {
+	Path    string
+	Control string
+}
+
+// ParseCacheRoutes reads routes like "GET /car/{id}=private, max-age=60; GET /cars=private, no-cache"
+// separated by semicolons or new lines, as Cache-Control directives are separated by commas
+func ParseCacheRoutes(s string) ([]CacheRoute, error) {
+	var routes []CacheRoute
+
+	for _, r := range strings.FieldsFunc(s, func(c rune) bool { return c == ';' || c == '\n' }) {
+		r = strings.TrimSpace(r)
+		if r == "" || strings.HasPrefix(r, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(r, "=", 2)
+		route := strings.Fields(parts[0])
+
+		if len(parts) != 2 || len(route) != 2 || strings.ToUpper(route[0]) != http.MethodGet {
+			return nil, errors.Error("invalid cache route " + strconv.Quote(r))
+		}
+
+		routes = append(routes, CacheRoute{Path: route[1], Control: strings.TrimSpace(parts[1])})
+	}
+
+	return routes, nil
+}
+
+type lastModifiedKey struct{}
+
+// lastModified is the latest change reported by the handler of a request
+type lastModified struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+// SetLastModified reports a change to the resource of a response served through Conditional, the latest one
+// becomes its Last-Modified header. It does nothing for other requests.
+func SetLastModified(ctx context.Context, t time.Time) {
+	lm, ok := ctx.Value(lastModifiedKey{}).(*lastModified)
+	if !ok {
+		return
+	}
+
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	if t.After(lm.t) {
+		lm.t = t
+	}
+}
+
+// Conditional validates the successful responses of the routes. Their body is hashed into an ETag, and a
+// request whose If-None-Match holds it, or whose If-Modified-Since is not before the Last-Modified reported
+// by the handler, gets 304 Not Modified without the body. The responses depend on the permissions of the
+// caller, so they vary by credentials.
+func Conditional(routes ...CacheRoute) gofr.Middleware {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, ok := matchCacheRoute(routes, r)
+			if !ok {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			lm := &lastModified{}
+			bw := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+
+			inner.ServeHTTP(bw, r.WithContext(context.WithValue(r.Context(), lastModifiedKey{}, lm)))
+
+			if bw.status != http.StatusOK {
+				w.WriteHeader(bw.status)
+				_, _ = w.Write(bw.body.Bytes())
+
+				return
+			}
+
+			sum := sha256.Sum256(bw.body.Bytes())
+			etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+			w.Header().Set("ETag", etag)
+			w.Header().Add("Vary", "Authorization, X-API-Key")
+
+			if route.Control != "" {
+				w.Header().Set("Cache-Control", route.Control)
+			}
+
+			if !lm.t.IsZero() {
+				w.Header().Set("Last-Modified", lm.t.UTC().Format(http.TimeFormat))
+			}
+
+			if notModified(r, etag, lm.t) {
+				w.Header().Del("Content-Type")
+				w.Header().Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(bw.body.Bytes())
+		})
+	}
+}
+
+func matchCacheRoute(routes []CacheRoute, r *http.Request) (CacheRoute, bool) {
+	if r.Method != http.MethodGet {
+		return CacheRoute{}, false
+	}
+
+	for _, cr := range routes {
+		if _, ok := route.Match(cr.Path, r.URL.Path); ok {
+			return cr, true
+		}
+	}
+
+	return CacheRoute{}, false
+}
+
+// notModified evaluates the preconditions of a GET request, If-Modified-Since is only used without
+// If-None-Match as RFC 7232 asks
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modified.IsZero() {
+		return false
+	}
+
+	// the header only has a precision of seconds
+	return !modified.Truncate(time.Second).After(since)
+}
+
+// bufferedWriter keeps the response of a conditional route until its ETag is known
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCacheRoutes(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		input  string
+		routes []CacheRoute
+		err    error
+	}{
+		{desc: "empty"},
+		{desc: "routes", input: "GET /car/{id}=private, max-age=60; get /cars=private, no-cache\n# comment\n",
+			routes: []CacheRoute{{Path: "/car/{id}", Control: "private, max-age=60"},
+				{Path: "/cars", Control: "private, no-cache"}}},
+		{desc: "no header", input: "GET /cars=", routes: []CacheRoute{{Path: "/cars"}}},
+		{desc: "missing method", input: "/cars=no-cache", err: errors.Error(`invalid cache route "/cars=no-cache"`)},
+		{desc: "not a GET route", input: "PUT /car/{id}=no-store",
+			err: errors.Error(`invalid cache route "PUT /car/{id}=no-store"`)},
+	}
+
+	for i, tc := range testCases {
+		routes, err := ParseCacheRoutes(tc.input)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.routes, routes, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestConditional(t *testing.T) {
+	modified := time.Date(2022, 3, 1, 10, 0, 0, 500, time.UTC)
+	body := `{"data":{"Name":"X5"}}`
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		SetLastModified(r.Context(), modified.Add(-time.Hour))
+		SetLastModified(r.Context(), modified)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/car/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	})
+
+	handler := Conditional(CacheRoute{Path: "/car/{id}", Control: "private, max-age=60"})(inner)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/car/42", nil))
+
+	etag := w.Header().Get("ETag")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, w.Body.String())
+	assert.Equal(t, "private, max-age=60", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "Tue, 01 Mar 2022 10:00:00 GMT", w.Header().Get("Last-Modified"))
+	assert.Equal(t, "Authorization, X-API-Key", w.Header().Get("Vary"))
+	assert.Equal(t, 34, len(etag), "the etag is a quoted hash of the body")
+
+	testCases := []struct {
+		desc    string
+		method  string
+		target  string
+		headers map[string]string
+		status  int
+	}{
+		{desc: "matching etag", target: "/car/42", headers: map[string]string{"If-None-Match": etag},
+			status: http.StatusNotModified},
+		{desc: "one of the etags, weak", target: "/car/42",
+			headers: map[string]string{"If-None-Match": `"abc", W/` + etag}, status: http.StatusNotModified},
+		{desc: "any etag", target: "/car/42", headers: map[string]string{"If-None-Match": "*"},
+			status: http.StatusNotModified},
+		{desc: "changed etag", target: "/car/42", headers: map[string]string{"If-None-Match": `"abc"`},
+			status: http.StatusOK},
+		{desc: "etag is used over the date", target: "/car/42", headers: map[string]string{"If-None-Match": `"abc"`,
+			"If-Modified-Since": "Tue, 01 Mar 2022 10:00:00 GMT"}, status: http.StatusOK},
+		{desc: "not modified since", target: "/car/42",
+			headers: map[string]string{"If-Modified-Since": "Tue, 01 Mar 2022 10:00:00 GMT"}, status: http.StatusNotModified},
+		{desc: "modified since", target: "/car/42",
+			headers: map[string]string{"If-Modified-Since": "Tue, 01 Mar 2022 09:59:59 GMT"}, status: http.StatusOK},
+		{desc: "invalid date", target: "/car/42", headers: map[string]string{"If-Modified-Since": "yesterday"},
+			status: http.StatusOK},
+		{desc: "error response", target: "/car/missing", headers: map[string]string{"If-None-Match": "*"},
+			status: http.StatusNotFound},
+		{desc: "other route", target: "/cars", headers: map[string]string{"If-None-Match": "*"},
+			status: http.StatusOK},
+		{desc: "other method", method: http.MethodPut, target: "/car/42", headers: map[string]string{"If-None-Match": "*"},
+			status: http.StatusOK},
+	}
+
+	for i, tc := range testCases {
+		method := tc.method
+		if method == "" {
+			method = http.MethodGet
+		}
+
+		r := httptest.NewRequest(method, tc.target, nil)
+		for k, v := range tc.headers {
+			r.Header.Set(k, v)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.status == http.StatusNotModified {
+			assert.Equal(t, "", w.Body.String(), "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, etag, w.Header().Get("ETag"), "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, "", w.Header().Get("Content-Type"), "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestSetLastModifiedOutsideConditional(t *testing.T) {
+	SetLastModified(context.TODO(), time.Now())
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"Project/CarDealearship/route"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// DeprecatedRoute is a route replaced by the route at Successor. Both paths can hold {param} segments like a
+// gofr route, the params of the successor are filled from the request.
+type DeprecatedRoute struct {
+	Method    string
+	Path      string
+	Successor string
+}
+
+// Deprecate marks the responses of the deprecated routes with the Deprecation header and links them to their
+// successor. sunset is the http date after which the routes are removed, the Sunset header is left out when
+// it is empty.
+func Deprecate(sunset string, routes ...DeprecatedRoute) gofr.Middleware {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, dr := range routes {
+				params, ok := route.Match(dr.Path, r.URL.Path)
+				if !ok || dr.Method != r.Method {
+					continue
+				}
+
+				successor := dr.Successor
+				for name, value := range params {
+					successor = strings.ReplaceAll(successor, "{"+name+"}", value)
+				}
+
+				w.Header().Set("Deprecation", "true")
+				w.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+
+				if sunset != "" {
+					w.Header().Set("Sunset", sunset)
+				}
+
+				break
+			}
+
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
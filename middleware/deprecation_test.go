@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecate(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	routes := []DeprecatedRoute{
+		{Method: http.MethodGet, Path: "/car/{id}", Successor: "/v2/cars/{id}"},
+		{Method: http.MethodGet, Path: "/cars", Successor: "/v2/cars"},
+	}
+
+	testCases := []struct {
+		desc   string
+		sunset string
+		method string
+		target string
+		link   string
+	}{
+		{desc: "route with params", method: http.MethodGet, target: "/car/42",
+			link: `</v2/cars/42>; rel="successor-version"`},
+		{desc: "sunset", sunset: "Sun, 31 Jan 2027 00:00:00 GMT", method: http.MethodGet, target: "/cars?brand=BMW",
+			link: `</v2/cars>; rel="successor-version"`},
+		{desc: "other method", method: http.MethodPut, target: "/car/42"},
+		{desc: "longer path", method: http.MethodGet, target: "/car/42/media"},
+		{desc: "successor route", method: http.MethodGet, target: "/v2/cars"},
+	}
+
+	for i, tc := range testCases {
+		w := httptest.NewRecorder()
+
+		Deprecate(tc.sunset, routes...)(inner).ServeHTTP(w, httptest.NewRequest(tc.method, tc.target, nil))
+
+		deprecation := ""
+		if tc.link != "" {
+			deprecation = "true"
+		}
+
+		assert.Equal(t, deprecation, w.Header().Get("Deprecation"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.link, w.Header().Get("Link"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.sunset, w.Header().Get("Sunset"), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
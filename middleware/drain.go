@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"Project/CarDealearship/shutdown"
+	"net/http"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+)
+
+// Drain counts the requests in flight with d, so that the shutdown waits for them, and turns new requests away
+// with 503 once d drains. The requests under the untracked path prefixes, like the probes and the event streams,
+// are let through and not waited for: the probes must answer until the end and a stream resumes elsewhere.
+func Drain(d *shutdown.Drainer, untracked ...string) gofr.Middleware {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, prefix := range untracked {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					inner.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			if !d.Acquire() {
+				// the client is sent to another instance over a new connection
+				w.Header().Set("Connection", "close")
+				w.Header().Set("Retry-After", "1")
+
+				responder.NewContextualResponder(w, r).Respond(nil, &errors.Response{
+					StatusCode: http.StatusServiceUnavailable, Code: "SHUTTING_DOWN",
+					Reason: "the server is shutting down, retry the request",
+				})
+
+				return
+			}
+
+			defer d.Release()
+
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"Project/CarDealearship/shutdown"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrain(t *testing.T) {
+	d := shutdown.NewDrainer()
+	started, finish := make(chan struct{}), make(chan struct{})
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/car/1" {
+			close(started)
+			<-finish
+		}
+	})
+
+	handler := Drain(d, "/health/", "/cars/stream")(inner)
+
+	served := make(chan int)
+
+	go func() {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/car/1", nil))
+		served <- w.Code
+	}()
+
+	<-started
+
+	drained := make(chan error)
+
+	go func() {
+		drained <- d.Drain(context.Background())
+	}()
+
+	select {
+	case <-drained:
+		t.Fatal("drained with a request in flight")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	testCases := []struct {
+		desc   string
+		path   string
+		status int
+	}{
+		{desc: "probe", path: "/health/ready", status: http.StatusOK},
+		{desc: "stream", path: "/cars/stream", status: http.StatusOK},
+		{desc: "new request", path: "/cars", status: http.StatusServiceUnavailable},
+	}
+
+	for i, tc := range testCases {
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.status == http.StatusServiceUnavailable {
+			assert.Equal(t, "close", w.Header().Get("Connection"), "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, "1", w.Header().Get("Retry-After"), "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.True(t, strings.Contains(w.Body.String(), "SHUTTING_DOWN"), "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+
+	close(finish)
+
+	assert.Equal(t, http.StatusOK, <-served, "the request in flight completes")
+	assert.Equal(t, nil, <-drained)
+}
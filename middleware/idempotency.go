@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"Project/CarDealearship/idempotency"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+)
+
+// maxIdempotencyKey is the length limit of an Idempotency-Key
+const maxIdempotencyKey = 255
+
+// replayedHeaders are the headers of a response kept with it, the others are set anew for every request
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+// IdempotencyConfig configures Idempotency
+type IdempotencyConfig struct {
+	// TTL is how long the response of a key is kept
+	TTL time.Duration
+	// LockTimeout is how long a key is held by the request running with it, a retry after that runs again in
+	// case the request was lost with its instance
+	LockTimeout time.Duration
+	// TrustProxy takes the ip of anonymous clients from X-Forwarded-For, as for RateLimit
+	TrustProxy bool
+	// MaxBody is the size limit of the body of a request with a key, which is read in memory to fingerprint it
+	MaxBody int64
+	// MaxResponse is the size limit of a kept response
+	MaxResponse int
+}
+
+// Idempotency runs a mutating request sent with an Idempotency-Key header once per client and key. The response
+// is kept for TTL and a retry gets it again, with the Idempotent-Replayed header, instead of being run. A key
+// reused for another request is rejected with 422, a retry arriving while the request runs with 409 and a body
+// larger than MaxBody with 413. Server errors and responses larger than MaxResponse are not kept so that the
+// request can be retried, and keys work as if absent when the store fails. It is registered after Authenticate,
+// clients are told apart like by RateLimit.
+func Idempotency(k *gofr.Gofr, s idempotency.Store, config IdempotencyConfig) gofr.Middleware {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("Idempotency-Key")
+			if key == "" || !mutating(r.Method) {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			res := responder.NewContextualResponder(w, r)
+
+			if len(key) > maxIdempotencyKey {
+				res.Respond(nil, errors.InvalidParam{Param: []string{"Idempotency-Key"}})
+				return
+			}
+
+			if r.ContentLength > config.MaxBody {
+				res.Respond(nil, bodyTooLarge(config.MaxBody))
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, config.MaxBody+1))
+			if err != nil {
+				res.Respond(nil, errors.InvalidParam{Param: []string{"body"}})
+				return
+			}
+
+			if int64(len(body)) > config.MaxBody {
+				res.Respond(nil, bodyTooLarge(config.MaxBody))
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			key = clientKey(r, config.TrustProxy) + "|" + key
+			fingerprint := requestFingerprint(r, body)
+
+			rec, ok, err := s.Reserve(r.Context(), key, idempotency.Record{Fingerprint: fingerprint, Pending: true},
+				config.LockTimeout)
+			if err != nil {
+				k.Logger.Errorf("error in reserving idempotency key of %v %v: %v", r.Method, r.URL.Path, err)
+				inner.ServeHTTP(w, r)
+
+				return
+			}
+
+			if !ok {
+				replay(w, res, rec, fingerprint)
+				return
+			}
+
+			rw := &recordingWriter{ResponseWriter: w, status: http.StatusOK, limit: config.MaxResponse}
+			inner.ServeHTTP(rw, r)
+
+			if rw.truncated {
+				k.Logger.Warnf("response of %v %v is too large to be kept for its idempotency key", r.Method,
+					r.URL.Path)
+			}
+
+			if rw.status >= http.StatusInternalServerError || rw.truncated {
+				err = s.Release(r.Context(), key)
+			} else {
+				err = s.Save(r.Context(), key, rw.record(fingerprint), config.TTL)
+			}
+
+			if err != nil {
+				k.Logger.Errorf("error in keeping the response of idempotency key of %v %v: %v", r.Method,
+					r.URL.Path, err)
+			}
+		})
+	}
+}
+
+func mutating(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch ||
+		method == http.MethodDelete
+}
+
+// requestFingerprint identifies a request by its method, target and body
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+
+	_, _ = io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	_, _ = h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func bodyTooLarge(limit int64) error {
+	return &errors.Response{StatusCode: http.StatusRequestEntityTooLarge, Code: "PAYLOAD_TOO_LARGE",
+		Reason: fmt.Sprintf("the body of a request with an Idempotency-Key is limited to %v bytes", limit)}
+}
+
+// replay answers a retry with the record of its key
+func replay(w http.ResponseWriter, res responder.Responder, rec idempotency.Record, fingerprint string) {
+	switch {
+	case rec.Fingerprint != fingerprint:
+		res.Respond(nil, &errors.Response{StatusCode: http.StatusUnprocessableEntity, Code: "IDEMPOTENCY_KEY_REUSED",
+			Reason: "the Idempotency-Key was used for another request"})
+	case rec.Pending:
+		w.Header().Set("Retry-After", "1")
+		res.Respond(nil, &errors.Response{StatusCode: http.StatusConflict, Code: "IDEMPOTENCY_KEY_IN_USE",
+			Reason: "a request with the Idempotency-Key is running"})
+	default:
+		for name, values := range rec.Header {
+			w.Header()[name] = values
+		}
+
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(rec.Status)
+		_, _ = w.Write(rec.Body)
+	}
+}
+
+// recordingWriter writes a response through and keeps a copy of it, up to limit bytes
+type recordingWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	switch {
+	case w.truncated:
+	case w.body.Len()+len(b) > w.limit:
+		w.truncated = true
+		w.body = bytes.Buffer{}
+	default:
+		w.body.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) record(fingerprint string) idempotency.Record {
+	header := make(http.Header)
+
+	for _, name := range replayedHeaders {
+		if v := w.Header().Values(name); len(v) > 0 {
+			header[name] = v
+		}
+	}
+
+	return idempotency.Record{Fingerprint: fingerprint, Status: w.status, Header: header, Body: w.body.Bytes()}
+}
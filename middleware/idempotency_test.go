@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"Project/CarDealearship/idempotency"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+)
+
+var idempotencyConfig = IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Minute, MaxBody: 64, MaxResponse: 64}
+
+type failingStore struct{}
+
+func (failingStore) Reserve(context.Context, string, idempotency.Record, time.Duration) (idempotency.Record, bool,
+	error) {
+	return idempotency.Record{}, false, errors.Error("connection refused")
+}
+
+func (failingStore) Save(context.Context, string, idempotency.Record, time.Duration) error {
+	return errors.Error("connection refused")
+}
+
+func (failingStore) Release(context.Context, string) error {
+	return errors.Error("connection refused")
+}
+
+func TestIdempotency(t *testing.T) {
+	large := `{"name":"` + strings.Repeat("a", 64) + `"}`
+	calls := 0
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if r.URL.Path == "/large" {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(large[:40]))
+			_, _ = w.Write([]byte(large[40:]))
+
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/car/1")
+		w.Header().Set("X-Correlation-ID", "abc")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	})
+
+	s := idempotency.NewMemory()
+	h := Idempotency(gofr.New(), s, idempotencyConfig)(inner)
+
+	pending := httptest.NewRequest(http.MethodPost, "/car", nil)
+	_, _, _ = s.Reserve(context.Background(), "ip:192.0.2.1|running",
+		idempotency.Record{Fingerprint: requestFingerprint(pending, []byte(`{"name":"a"}`)), Pending: true}, time.Minute)
+
+	testCases := []struct {
+		desc     string
+		method   string
+		target   string
+		key      string
+		body     string
+		status   int
+		response string
+		replayed string
+		calls    int
+	}{
+		{desc: "first request", method: http.MethodPost, target: "/car", key: "k1", body: `{"name":"a"}`,
+			status: http.StatusCreated, response: `{"name":"a"}`, calls: 1},
+		{desc: "retry", method: http.MethodPost, target: "/car", key: "k1", body: `{"name":"a"}`,
+			status: http.StatusCreated, response: `{"name":"a"}`, replayed: "true", calls: 1},
+		{desc: "key reused for another body", method: http.MethodPost, target: "/car", key: "k1", body: `{"name":"b"}`,
+			status: http.StatusUnprocessableEntity, response: "IDEMPOTENCY_KEY_REUSED", calls: 1},
+		{desc: "key reused for another route", method: http.MethodPut, target: "/car/1", key: "k1",
+			body: `{"name":"a"}`, status: http.StatusUnprocessableEntity, response: "IDEMPOTENCY_KEY_REUSED", calls: 1},
+		{desc: "request running", method: http.MethodPost, target: "/car", key: "running", body: `{"name":"a"}`,
+			status: http.StatusConflict, response: "IDEMPOTENCY_KEY_IN_USE", calls: 1},
+		{desc: "server error", method: http.MethodPost, target: "/fail", key: "k2", body: `{}`,
+			status: http.StatusInternalServerError, calls: 2},
+		{desc: "server error retried", method: http.MethodPost, target: "/fail", key: "k2", body: `{}`,
+			status: http.StatusInternalServerError, calls: 3},
+		{desc: "without key", method: http.MethodPost, target: "/car", body: `{"name":"a"}`,
+			status: http.StatusCreated, response: `{"name":"a"}`, calls: 4},
+		{desc: "without key again", method: http.MethodPost, target: "/car", body: `{"name":"a"}`,
+			status: http.StatusCreated, response: `{"name":"a"}`, calls: 5},
+		{desc: "safe method", method: http.MethodGet, target: "/car", key: "k1", status: http.StatusCreated, calls: 6},
+		{desc: "key too long", method: http.MethodPost, target: "/car", key: strings.Repeat("k", 256), body: `{}`,
+			status: http.StatusBadRequest, calls: 6},
+		{desc: "body too large", method: http.MethodPost, target: "/car", key: "k3", body: large,
+			status: http.StatusRequestEntityTooLarge, response: "PAYLOAD_TOO_LARGE", calls: 6},
+		{desc: "response too large to keep", method: http.MethodPost, target: "/large", key: "k4", body: `{}`,
+			status: http.StatusCreated, response: large, calls: 7},
+		{desc: "response too large retried", method: http.MethodPost, target: "/large", key: "k4", body: `{}`,
+			status: http.StatusCreated, response: large, calls: 8},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+		r.Header.Set("Idempotency-Key", tc.key)
+
+		w := httptest.NewRecorder()
+
+		h.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Contains(t, w.Body.String(), tc.response, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.replayed, w.Header().Get("Idempotent-Replayed"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.calls, calls, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.replayed != "" {
+			assert.Equal(t, "/car/1", w.Header().Get("Location"), "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Empty(t, w.Header().Get("X-Correlation-ID"), "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+
+		if tc.status == http.StatusConflict {
+			assert.Equal(t, "1", w.Header().Get("Retry-After"), "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+func TestIdempotencyStoreError(t *testing.T) {
+	calls := 0
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	h := Idempotency(gofr.New(), failingStore{}, idempotencyConfig)(inner)
+
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest(http.MethodPost, "/car", strings.NewReader(`{}`))
+		r.Header.Set("Idempotency-Key", "k1")
+
+		w := httptest.NewRecorder()
+
+		h.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusCreated, w.Code, "TEST[%d], failed.\n%s", i, "store error")
+		assert.Equal(t, i+1, calls, "TEST[%d], failed.\n%s", i, "store error")
+	}
+}
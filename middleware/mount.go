@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"Project/CarDealearship/auth"
+	"Project/CarDealearship/problem"
+	"bufio"
+	"net"
+	"net/http"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/request"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+)
+
+// StreamHandler writes its response straight to w, for endpoints whose body is streamed instead of returned
+type StreamHandler func(ctx *gofr.Context, w http.ResponseWriter) error
+
+// Mount serves method and path with h. The route is registered with gofr so that it is matched, and goes
+// through the other middlewares, like any other route, but h is run from a middleware ahead of the gofr
+// handler so that it can write the body as it goes.
+func Mount(k *gofr.Gofr, method, path string, h StreamHandler) {
+	k.Server.UseMiddleware(mount(k, method, path, h))
+
+	routes := map[string]func(string, gofr.Handler){
+		http.MethodGet: k.GET, http.MethodPost: k.POST, http.MethodPut: k.PUT, http.MethodDelete: k.DELETE,
+	}
+
+	routes[method](path, mounted)
+}
+
+// RequireStream wraps a stream handler so that it is only run for principals with the permission, like
+// auth.Require does for gofr handlers
+func RequireStream(permission auth.Permission, h StreamHandler) StreamHandler {
+	return func(ctx *gofr.Context, w http.ResponseWriter) error {
+		if err := auth.Check(ctx, permission); err != nil {
+			return err
+		}
+
+		return h(ctx, w)
+	}
+}
+
+// mounted is the gofr handler of a mounted route, it is only reached when the mount middleware is missing
+func mounted(ctx *gofr.Context) (interface{}, error) {
+	return nil, errors.Error("route is served by a mount middleware")
+}
+
+// mount returns the middleware serving method and path with h. An error returned before anything was written
+// is mapped and sent through the gofr responder like the error of any other handler, after that it can only be
+// logged.
+func mount(k *gofr.Gofr, method, path string, h StreamHandler) gofr.Middleware {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != method || r.URL.Path != path {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			res := responder.NewContextualResponder(w, r)
+			ctx := gofr.NewContext(res, request.NewHTTPRequest(r), k)
+			ctx.Context = r.Context()
+
+			sw := &streamWriter{ResponseWriter: w}
+
+			if err := h(ctx, sw); err != nil {
+				if sw.written {
+					ctx.Logger.Errorf("error in streaming %v %v: %v", method, path, err)
+					return
+				}
+
+				res.Respond(nil, problem.Of(ctx, err))
+			}
+		})
+	}
+}
+
+// streamWriter records whether the response was started, flushes through to the client and lets websocket
+// handlers take the connection over
+type streamWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *streamWriter) WriteHeader(status int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *streamWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *streamWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.written = true
+		f.Flush()
+	}
+}
+
+func (w *streamWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.Error("response writer does not support hijacking")
+	}
+
+	w.written = true
+
+	return h.Hijack()
+}
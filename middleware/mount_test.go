@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"Project/CarDealearship/auth"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMount(t *testing.T) {
+	app := gofr.New()
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	h := mount(app, http.MethodGet, "/stream", func(ctx *gofr.Context, w http.ResponseWriter) error {
+		_, _ = w.Write([]byte(ctx.Param("msg")))
+
+		if ctx.Param("fail") == "late" {
+			return errors.Error("broken pipe")
+		}
+
+		return nil
+	})(inner)
+
+	testCases := []struct {
+		desc   string
+		method string
+		target string
+		status int
+		body   string
+	}{
+		{desc: "mounted route", method: http.MethodGet, target: "/stream?msg=hello", status: http.StatusOK, body: "hello"},
+		{desc: "other path", method: http.MethodGet, target: "/cars", status: http.StatusTeapot},
+		{desc: "other method", method: http.MethodPost, target: "/stream", status: http.StatusTeapot},
+		{desc: "error after writing", method: http.MethodGet, target: "/stream?msg=part&fail=late",
+			status: http.StatusOK, body: "part"},
+	}
+
+	for i, tc := range testCases {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(tc.method, tc.target, nil))
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.body, w.Body.String(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestRequireStream(t *testing.T) {
+	h := RequireStream(auth.ExportCars, func(ctx *gofr.Context, w http.ResponseWriter) error {
+		_, err := w.Write([]byte("id,name\n"))
+		return err
+	})
+
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = auth.WithPrincipal(ctx.Context, auth.Principal{Subject: "u1", Roles: []string{auth.RoleSales}})
+
+	w := httptest.NewRecorder()
+	assert.Equal(t, auth.Check(ctx, auth.ExportCars), h(ctx, w))
+	assert.Equal(t, "", w.Body.String())
+
+	ctx.Context = auth.WithPrincipal(ctx.Context, auth.Principal{Subject: "u2", Roles: []string{auth.RoleManager}})
+
+	w = httptest.NewRecorder()
+	assert.Equal(t, nil, h(ctx, w))
+	assert.Equal(t, "id,name\n", w.Body.String())
+}
+
+func TestStreamWriterHijack(t *testing.T) {
+	sw := &streamWriter{ResponseWriter: httptest.NewRecorder()}
+
+	_, _, err := sw.Hijack()
+	assert.Equal(t, errors.Error("response writer does not support hijacking"), err)
+	assert.False(t, sw.written)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &streamWriter{ResponseWriter: w}
+
+		conn, buf, err := sw.Hijack()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer conn.Close()
+
+		_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+		_ = buf.Flush()
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if !assert.Equal(t, nil, err) {
+		return
+	}
+
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "hijacked", string(body))
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/problem"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the id of a request, the one gofr correlates its logs with when it set one
+const RequestIDHeader = "X-Correlation-ID"
+
+// errorBody is the body gofr responds errors with
+type errorBody struct {
+	Errors []errors.Response `json:"errors"`
+}
+
+// Problems rewrites the json error responses of the middlewares and handlers after it into problem details
+// (RFC 7807), with the id of the request. Other responses are written through, so it must come first.
+func Problems() gofr.Middleware {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := w.Header().Get(RequestIDHeader)
+			if id == "" {
+				if id = r.Header.Get(RequestIDHeader); id == "" {
+					id = uuid.NewString()
+				}
+
+				w.Header().Set(RequestIDHeader, id)
+			}
+
+			pw := &problemWriter{ResponseWriter: w}
+
+			inner.ServeHTTP(pw, r)
+
+			if pw.status == 0 {
+				return
+			}
+
+			p := newProblem(pw.status, pw.body.Bytes())
+			p.Instance, p.RequestID = r.URL.Path, id
+
+			w.Header().Set("Content-Type", models.ProblemContentType)
+			w.Header().Del("Content-Length")
+			w.WriteHeader(p.Status)
+			_ = json.NewEncoder(w).Encode(p)
+		})
+	}
+}
+
+// newProblem describes the first error of a gofr error body, a body that is not one only keeps its status
+func newProblem(status int, body []byte) models.Problem {
+	p := models.Problem{Type: "about:blank", Title: http.StatusText(status), Status: status,
+		Code: problem.Code(status)}
+
+	var b errorBody
+	if err := json.Unmarshal(body, &b); err != nil || len(b.Errors) == 0 {
+		return p
+	}
+
+	e := b.Errors[0]
+	p.Detail, p.ResourceID, p.Details = e.Reason, e.ResourceID, e.Detail
+
+	// the codes gofr gives its own errors are phrases like "Entity Not Found"
+	if e.Code != "" && !strings.Contains(e.Code, " ") {
+		p.Code = e.Code
+	}
+
+	return p
+}
+
+// problemWriter holds back json error responses until they are rewritten, anything else is written through.
+// It flushes and lets websocket handlers take the connection over like the writer it wraps.
+type problemWriter struct {
+	http.ResponseWriter
+	// status is the status of a held back response
+	status int
+	body   bytes.Buffer
+}
+
+func (w *problemWriter) WriteHeader(status int) {
+	if status >= http.StatusBadRequest && strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		w.status = status
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *problemWriter) Write(b []byte) (int, error) {
+	if w.status != 0 {
+		return w.body.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *problemWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok && w.status == 0 {
+		f.Flush()
+	}
+}
+
+func (w *problemWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.Error("response writer does not support hijacking")
+	}
+
+	return h.Hijack()
+}
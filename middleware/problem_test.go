@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"Project/CarDealearship/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblems(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/car/1":
+			responder.NewContextualResponder(w, r).Respond(nil, &errors.Response{StatusCode: http.StatusConflict,
+				Code: "DUPLICATE_CAR", Reason: "a car with the VIN exists", ResourceID: "2", Detail: "vin"})
+		case "/unknown":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"code":"Route Not Found","reason":"route not found"}]}`))
+		case "/text":
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte("bad gateway"))
+		default:
+			responder.NewContextualResponder(w, r).Respond("car", nil)
+		}
+	})
+
+	handler := Problems()(inner)
+
+	testCases := []struct {
+		desc      string
+		path      string
+		requestID string
+		problem   *models.Problem
+		status    int
+		body      string
+	}{
+		{desc: "error", path: "/car/1", requestID: "r1", status: http.StatusConflict, problem: &models.Problem{
+			Type: "about:blank", Title: "Conflict", Status: http.StatusConflict, Detail: "a car with the VIN exists",
+			Instance: "/car/1", Code: "DUPLICATE_CAR", RequestID: "r1", ResourceID: "2", Details: "vin"}},
+		{desc: "gofr error", path: "/unknown", requestID: "r2", status: http.StatusNotFound, problem: &models.Problem{
+			Type: "about:blank", Title: "Not Found", Status: http.StatusNotFound, Detail: "route not found",
+			Instance: "/unknown", Code: "NOT_FOUND", RequestID: "r2"}},
+		{desc: "not json", path: "/text", status: http.StatusBadGateway, body: "bad gateway"},
+		{desc: "success", path: "/car/2", status: http.StatusOK, body: "{\"data\":\"car\"}\n"},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.requestID != "" {
+			r.Header.Set(RequestIDHeader, tc.requestID)
+		}
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.NotEmpty(t, w.Header().Get(RequestIDHeader), "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.problem == nil {
+			assert.Equal(t, tc.body, w.Body.String(), "TEST[%d], failed.\n%s", i, tc.desc)
+			continue
+		}
+
+		var p models.Problem
+
+		assert.Equal(t, models.ProblemContentType, w.Header().Get("Content-Type"), "TEST[%d], failed.\n%s", i,
+			tc.desc)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, *tc.problem, p, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.requestID, w.Header().Get(RequestIDHeader), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
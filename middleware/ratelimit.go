@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"Project/CarDealearship/auth"
+	"Project/CarDealearship/ratelimit"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"developer.zopsmart.com/go/gofr/pkg/gofr/responder"
+)
+
+// RateLimitConfig is the limit of every route, requests matching a rule are limited by the first such rule
+// instead. A zero Default leaves the other routes unlimited.
+type RateLimitConfig struct {
+	Default ratelimit.Limit
+	Rules   []ratelimit.Rule
+	// TrustProxy takes the ip of anonymous clients from X-Forwarded-For, set it only behind a proxy that sets it
+	TrustProxy bool
+	// Scope prefixes the keys of the buckets, so that the buckets of several RateLimit middlewares stay apart
+	Scope string
+}
+
+// RateLimit limits the requests of every client with a token bucket per client and route. Clients are told
+// apart by their principal when it is registered after Authenticate, and anonymous clients by their ip. Registered
+// before Authenticate it limits every client by its ip, failed authentications included. The requests are let
+// through when the backend fails, an unavailable redis should not take the api down.
+func RateLimit(k *gofr.Gofr, b ratelimit.Backend, config RateLimitConfig) gofr.Middleware {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit, route := config.Default, "*"
+
+			for _, rule := range config.Rules {
+				if rule.Matches(r.Method, r.URL.Path) {
+					limit, route = rule.Limit, rule.Method+" "+rule.Path
+					break
+				}
+			}
+
+			if limit.Rate <= 0 {
+				inner.ServeHTTP(w, r)
+				return
+			}
+
+			res, err := b.Take(r.Context(), config.Scope+clientKey(r, config.TrustProxy)+"|"+route, limit)
+			if err != nil {
+				k.Logger.Errorf("error in rate limiting %v %v: %v", r.Method, r.URL.Path, err)
+				inner.ServeHTTP(w, r)
+
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+			if !res.Allowed {
+				retry := ceilSeconds(res.RetryAfter)
+				w.Header().Set("Retry-After", strconv.Itoa(retry))
+
+				responder.NewContextualResponder(w, r).Respond(nil, &errors.Response{
+					StatusCode: http.StatusTooManyRequests, Code: "RATE_LIMITED",
+					Reason: "too many requests, retry after " + strconv.Itoa(retry) + "s",
+				})
+
+				return
+			}
+
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientKey is the principal of the request, or the ip of an anonymous client. Behind a trusted proxy the ip is
+// the last entry of X-Forwarded-For, the one the proxy appended, as the client can send any entries before it.
+func clientKey(r *http.Request, trustProxy bool) string {
+	if p, ok := auth.FromContext(r.Context()); ok {
+		return p.Method + ":" + p.Subject
+	}
+
+	if trustProxy {
+		if fwd := r.Header.Values("X-Forwarded-For"); len(fwd) > 0 {
+			entries := strings.Split(fwd[len(fwd)-1], ",")
+			if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+				return "ip:" + ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
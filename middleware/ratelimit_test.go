@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"Project/CarDealearship/auth"
+	"Project/CarDealearship/ratelimit"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBackend struct {
+	keys []string
+	err  error
+}
+
+// Take allows the first two takes of a key
+func (b *fakeBackend) Take(_ context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	if b.err != nil {
+		return ratelimit.Result{}, b.err
+	}
+
+	n := 0
+
+	for _, k := range b.keys {
+		if k == key {
+			n++
+		}
+	}
+
+	b.keys = append(b.keys, key)
+
+	if n >= 2 {
+		return ratelimit.Result{RetryAfter: 1500 * time.Millisecond, Reset: 2 * time.Second}, nil
+	}
+
+	return ratelimit.Result{Allowed: true, Remaining: 1 - n, Reset: time.Duration(n+1) * time.Second}, nil
+}
+
+func TestRateLimit(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	b := &fakeBackend{}
+	h := RateLimit(gofr.New(), b, RateLimitConfig{
+		Default: ratelimit.Limit{Rate: 1, Burst: 2},
+		Rules:   []ratelimit.Rule{{Method: http.MethodGet, Path: "/car/{id}", Limit: ratelimit.Limit{Rate: 1, Burst: 5}}},
+	})(inner)
+
+	alice := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", Method: auth.MethodJWT})
+
+	testCases := []struct {
+		desc      string
+		target    string
+		ctx       context.Context
+		status    int
+		limit     string
+		remaining string
+		reset     string
+	}{
+		{desc: "first request", target: "/cars", ctx: alice, status: http.StatusOK, limit: "2", remaining: "1",
+			reset: "1"},
+		{desc: "second request", target: "/cars", ctx: alice, status: http.StatusOK, limit: "2", remaining: "0",
+			reset: "2"},
+		{desc: "limited", target: "/cars", ctx: alice, status: http.StatusTooManyRequests, limit: "2",
+			remaining: "0", reset: "2"},
+		{desc: "other route rule", target: "/car/1", ctx: alice, status: http.StatusOK, limit: "5", remaining: "1",
+			reset: "1"},
+		{desc: "anonymous client", target: "/cars", ctx: context.Background(), status: http.StatusOK, limit: "2",
+			remaining: "1", reset: "1"},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest(http.MethodGet, tc.target, nil).WithContext(tc.ctx)
+		w := httptest.NewRecorder()
+
+		h.ServeHTTP(w, r)
+
+		assert.Equal(t, tc.status, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.limit, w.Header().Get("X-RateLimit-Limit"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.remaining, w.Header().Get("X-RateLimit-Remaining"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.reset, w.Header().Get("X-RateLimit-Reset"), "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.status == http.StatusTooManyRequests {
+			assert.Equal(t, "2", w.Header().Get("Retry-After"), "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Contains(t, w.Body.String(), "RATE_LIMITED", "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+
+	assert.Equal(t, []string{"jwt:alice|*", "jwt:alice|*", "jwt:alice|*", "jwt:alice|GET /car/{id}",
+		"ip:192.0.2.1|*"}, b.keys)
+}
+
+// TestRateLimitBeforeAuthentication tests that a limit registered before Authenticate limits the requests by ip
+// in buckets of their own, whatever credentials they carry
+func TestRateLimitBeforeAuthentication(t *testing.T) {
+	b := &fakeBackend{}
+	limited := RateLimit(gofr.New(), b, RateLimitConfig{Default: ratelimit.Limit{Rate: 1, Burst: 2}, Scope: "pre:"})
+
+	// the requests reach Authenticate, which rejects them
+	h := limited(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	statuses := make([]int, 0, 3)
+
+	for _, key := range []string{"guess-1", "guess-2", "guess-3"} {
+		r := httptest.NewRequest(http.MethodGet, "/cars", nil)
+		r.Header.Set(auth.APIKeyHeader, key)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		statuses = append(statuses, w.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, statuses)
+	assert.Equal(t, []string{"pre:ip:192.0.2.1|*", "pre:ip:192.0.2.1|*", "pre:ip:192.0.2.1|*"}, b.keys)
+}
+
+func TestRateLimitUnlimited(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	testCases := []struct {
+		desc    string
+		backend *fakeBackend
+		config  RateLimitConfig
+	}{
+		{desc: "no limit", backend: &fakeBackend{}},
+		{desc: "backend error", backend: &fakeBackend{err: errors.Error("connection refused")},
+			config: RateLimitConfig{Default: ratelimit.Limit{Rate: 1, Burst: 1}}},
+	}
+
+	for i, tc := range testCases {
+		w := httptest.NewRecorder()
+
+		RateLimit(gofr.New(), tc.backend, tc.config)(inner).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cars", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Empty(t, w.Header().Get("X-RateLimit-Limit"), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Empty(t, tc.backend.keys, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		forwarded  string
+		trustProxy bool
+		key        string
+	}{
+		{desc: "remote address", key: "ip:192.0.2.1"},
+		{desc: "untrusted forwarded for", forwarded: "203.0.113.9", key: "ip:192.0.2.1"},
+		{desc: "trusted forwarded for", forwarded: "203.0.113.9", trustProxy: true, key: "ip:203.0.113.9"},
+		{desc: "entries sent by the client", forwarded: "198.51.100.7, 10.0.0.1, 203.0.113.9", trustProxy: true,
+			key: "ip:203.0.113.9"},
+		{desc: "empty forwarded for", forwarded: " ", trustProxy: true, key: "ip:192.0.2.1"},
+	}
+
+	for i, tc := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "/cars", nil)
+		r.Header.Set("X-Forwarded-For", tc.forwarded)
+
+		assert.Equal(t, tc.key, clientKey(r, tc.trustProxy), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,166 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Migration is a forward-only schema change applied once, in Version order.
+type Migration struct {
+	Version     int
+	Description string
+	Statements  []string
+}
+
+// DB is the subset of *sql.DB used to apply migrations
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// All returns every known migration in the order it must be applied
+func All() []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "create car and engine tables",
+			Statements: []string{
+				"CREATE TABLE IF NOT EXISTS Engine (id VARCHAR(36) PRIMARY KEY, displacement INT, " +
+					"cylinders INT, `range` INT)",
+				"CREATE TABLE IF NOT EXISTS Car (id VARCHAR(36) PRIMARY KEY, engine_id VARCHAR(36), " +
+					"name VARCHAR(255), year INT, brand VARCHAR(50), fuel_type VARCHAR(20))",
+			},
+		},
+		{
+			Version:     2,
+			Description: "create media table",
+			Statements: []string{
+				"CREATE TABLE IF NOT EXISTS Media (id VARCHAR(36) PRIMARY KEY, car_id VARCHAR(36) NOT NULL, " +
+					"kind VARCHAR(20), content_type VARCHAR(100), file_name VARCHAR(255), size BIGINT, " +
+					"position INT, is_cover BOOLEAN, storage_key VARCHAR(255), url VARCHAR(1024), " +
+					"thumbnail_key VARCHAR(255), thumbnail_url VARCHAR(1024), created_at DATETIME, " +
+					"INDEX idx_media_car (car_id))",
+			},
+		},
+		{
+			Version:     3,
+			Description: "create api key table",
+			Statements: []string{
+				"CREATE TABLE IF NOT EXISTS APIKey (id VARCHAR(36) PRIMARY KEY, name VARCHAR(255), " +
+					"key_hash CHAR(64) NOT NULL, roles VARCHAR(255), created_at DATETIME, revoked_at DATETIME NULL, " +
+					"UNIQUE INDEX idx_api_key_hash (key_hash))",
+			},
+		},
+		{
+			Version:     4,
+			Description: "add car cost price",
+			Statements:  []string{"ALTER TABLE Car ADD COLUMN cost_price INT NULL"},
+		},
+		{
+			Version:     5,
+			Description: "create dealership table",
+			Statements: []string{
+				"CREATE TABLE IF NOT EXISTS Dealership (id VARCHAR(36) PRIMARY KEY, name VARCHAR(255), city VARCHAR(100))",
+				"ALTER TABLE Car ADD COLUMN dealership_id VARCHAR(36) NULL, ADD INDEX idx_car_dealership (dealership_id)",
+			},
+		},
+		{
+			Version:     6,
+			Description: "add car status and event outbox",
+			Statements: []string{
+				"ALTER TABLE Car ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'available'",
+				"CREATE TABLE IF NOT EXISTS Outbox (id VARCHAR(36) PRIMARY KEY, type VARCHAR(50) NOT NULL, " +
+					"car_id VARCHAR(36) NOT NULL, payload JSON NOT NULL, occurred_at DATETIME(6) NOT NULL, " +
+					"published_at DATETIME(6) NULL, seq BIGINT AUTO_INCREMENT UNIQUE, " +
+					"INDEX idx_outbox_pending (published_at, seq))",
+			},
+		},
+		{
+			Version:     7,
+			Description: "add webhooks and their deliveries",
+			Statements: []string{
+				"CREATE TABLE IF NOT EXISTS Webhook (id VARCHAR(36) PRIMARY KEY, url VARCHAR(2048) NOT NULL, " +
+					"events VARCHAR(255) NOT NULL DEFAULT '', active BOOLEAN NOT NULL DEFAULT TRUE, " +
+					"secret VARCHAR(64) NOT NULL, created_at DATETIME(6) NOT NULL)",
+				"CREATE TABLE IF NOT EXISTS WebhookDelivery (id VARCHAR(36) PRIMARY KEY, " +
+					"webhook_id VARCHAR(36) NOT NULL, event_id VARCHAR(36) NOT NULL, event_type VARCHAR(50) NOT NULL, " +
+					"payload JSON NOT NULL, status VARCHAR(20) NOT NULL, attempts INT NOT NULL DEFAULT 0, " +
+					"response_code INT NOT NULL DEFAULT 0, last_error VARCHAR(1024) NOT NULL DEFAULT '', " +
+					"next_attempt_at DATETIME(6) NULL, created_at DATETIME(6) NOT NULL, delivered_at DATETIME(6) NULL, " +
+					"UNIQUE KEY uq_delivery_event (webhook_id, event_id), INDEX idx_delivery_due (status, next_attempt_at), " +
+					"INDEX idx_delivery_webhook (webhook_id, created_at), " +
+					"FOREIGN KEY (webhook_id) REFERENCES Webhook(id) ON DELETE CASCADE)",
+			},
+		},
+		{
+			Version:     8,
+			Description: "track when cars and engines change",
+			Statements: []string{
+				"ALTER TABLE Car ADD COLUMN updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) " +
+					"ON UPDATE CURRENT_TIMESTAMP(6)",
+				"ALTER TABLE Engine ADD COLUMN updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) " +
+					"ON UPDATE CURRENT_TIMESTAMP(6)",
+			},
+		},
+		{
+			Version:     9,
+			Description: "add the vin of cars and index the attributes duplicates are found by",
+			Statements: []string{
+				"ALTER TABLE Car ADD COLUMN vin VARCHAR(17) NULL, ADD INDEX idx_car_vin (vin), " +
+					"ADD INDEX idx_car_duplicate (brand, year, dealership_id)",
+			},
+		},
+		{
+			Version:     10,
+			Description: "make the vin of cars unique",
+			Statements: []string{
+				"ALTER TABLE Car DROP INDEX idx_car_vin, ADD UNIQUE INDEX uq_car_vin (vin)",
+			},
+		},
+	}
+}
+
+// Version returns the latest applied migration version, 0 when none has been applied.
+func Version(ctx context.Context, db DB) (int, error) {
+	if _, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations "+
+		"(version INT PRIMARY KEY, description VARCHAR(255), applied_at DATETIME)"); err != nil {
+		return 0, err
+	}
+
+	var version int
+
+	err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version),0) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// Run applies every migration newer than the current schema version.
+func Run(ctx context.Context, db DB, migrations []Migration) error {
+	current, err := Version(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+
+		for _, stmt := range m.Statements {
+			if _, err := db.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+
+		_, err := db.ExecContext(ctx, "INSERT INTO schema_migrations (version,description,applied_at) VALUES(?,?,NOW())",
+			m.Version, m.Description)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	createVersionTable = "CREATE TABLE IF NOT EXISTS schema_migrations " +
+		"(version INT PRIMARY KEY, description VARCHAR(255), applied_at DATETIME)"
+	selectVersion   = "SELECT COALESCE(MAX(version),0) FROM schema_migrations"
+	insertMigration = "INSERT INTO schema_migrations (version,description,applied_at) VALUES(?,?,NOW())"
+)
+
+// TestRun tests that only migrations newer than the applied version are executed
+func TestRun(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	migrations := []Migration{
+		{Version: 1, Description: "first", Statements: []string{"CREATE TABLE a (id INT)"}},
+		{Version: 2, Description: "second", Statements: []string{"CREATE TABLE b (id INT)", "CREATE TABLE c (id INT)"}},
+	}
+
+	mock.ExpectExec(createVersionTable).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectVersion).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+	mock.ExpectExec("CREATE TABLE b (id INT)").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE c (id INT)").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(insertMigration).WithArgs(2, "second").WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = Run(context.TODO(), db, migrations)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, mock.ExpectationsWereMet())
+}
+
+// TestRunError tests that a failing statement stops the run without recording the version
+func TestRunError(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	migrations := []Migration{{Version: 1, Description: "first", Statements: []string{"CREATE TABLE a (id INT)"}}}
+
+	mock.ExpectExec(createVersionTable).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(selectVersion).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(0))
+	mock.ExpectExec("CREATE TABLE a (id INT)").WillReturnError(errors.Error("syntax error"))
+
+	err = Run(context.TODO(), db, migrations)
+
+	assert.Equal(t, errors.Error("syntax error"), err)
+	assert.Equal(t, nil, mock.ExpectationsWereMet())
+}
+
+// TestAllOrdered tests that the migration versions are strictly increasing
+func TestAllOrdered(t *testing.T) {
+	all := All()
+
+	for i := 1; i < len(all); i++ {
+		assert.True(t, all[i].Version > all[i-1].Version, "migration %d is out of order", all[i].Version)
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey is a partner credential, only the SHA-256 hash of the key itself is stored
+type APIKey struct {
+	ID        uuid.UUID  `json:"ID"`
+	Name      string     `json:"Name"`
+	KeyHash   string     `json:"-"`
+	Roles     []string   `json:"Roles"`
+	CreatedAt time.Time  `json:"CreatedAt"`
+	RevokedAt *time.Time `json:"RevokedAt,omitempty"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "encoding/json"
+
+// BatchGetRequest is the body of POST /cars:batchGet
+type BatchGetRequest struct {
+	IDs []string `json:"IDs"`
+}
+
+// BatchUpdateRequest is the body of POST /cars:batchUpdate
+type BatchUpdateRequest struct {
+	Updates []CarPatch `json:"Updates"`
+}
+
+// CarPatch is an update of a batch, the fields present in Patch replace the ones of the car and the others are
+// kept. Engine fields are merged the same way.
+type CarPatch struct {
+	ID    string          `json:"ID"`
+	Patch json.RawMessage `json:"Patch"`
+}
+
+// BatchResult is the outcome of an item of a batch, Status is the HTTP status the item would have had on its own
+type BatchResult struct {
+	ID     string `json:"ID"`
+	Status int    `json:"Status"`
+	Car    *Car   `json:"Car,omitempty"`
+	Code   string `json:"Code,omitempty"`
+	Error  string `json:"Error,omitempty"`
+}
+
+// BatchResponse holds the results of a batch in the order of its items
+type BatchResponse struct {
+	Results []BatchResult `json:"Results"`
+}
@@ -1,12 +1,37 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Car statuses, a car is available until it is reserved or sold
+const (
+	CarAvailable = "available"
+	CarReserved  = "reserved"
+	CarSold      = "sold"
+)
 
 type Car struct {
-	ID       uuid.UUID `json:"ID,omitempty"`
-	Engine   Engine    `json:"Engine,omitempty"`
-	Name     string    `json:"Name"`
-	Year     int       `json:"Year"`
-	Brand    string    `json:"Brand"`
-	FuelType string    `json:"FuelType"`
+	ID           uuid.UUID  `json:"ID,omitempty"`
+	Engine       Engine     `json:"Engine,omitempty"`
+	Name         string     `json:"Name"`
+	Year         int        `json:"Year"`
+	Brand        string     `json:"Brand"`
+	FuelType     string     `json:"FuelType"`
+	Status       string     `json:"Status,omitempty"`
+	CostPrice    *int       `json:"CostPrice,omitempty"`
+	DealershipID *uuid.UUID `json:"DealershipID,omitempty"`
+	Media        []Media    `json:"Media,omitempty"`
+	VIN          string     `json:"VIN,omitempty"`
+	UpdatedAt    *time.Time `json:"UpdatedAt,omitempty"`
+}
+
+// InventoryCount is the number of cars with a status at a dealership, DealershipID is nil for the cars that are
+// not at one
+type InventoryCount struct {
+	Status       string
+	DealershipID *uuid.UUID
+	Count        int
 }
@@ -0,0 +1,14 @@
+package models
+
+// Comparison lines up the attributes of a few cars side by side. Values of every attribute are in
+// the same order as Cars, Differs is set when they are not all equal.
+type Comparison struct {
+	Cars       []Car               `json:"Cars"`
+	Attributes []ComparedAttribute `json:"Attributes"`
+}
+
+type ComparedAttribute struct {
+	Name    string        `json:"Name"`
+	Values  []interface{} `json:"Values"`
+	Differs bool          `json:"Differs"`
+}
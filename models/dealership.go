@@ -0,0 +1,10 @@
+package models
+
+import "github.com/google/uuid"
+
+// Dealership is a showroom cars are sold from
+type Dealership struct {
+	ID   uuid.UUID `json:"ID"`
+	Name string    `json:"Name"`
+	City string    `json:"City"`
+}
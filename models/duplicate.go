@@ -0,0 +1,14 @@
+package models
+
+// Duplicate reasons, why cars are taken for the same physical car
+const (
+	DuplicateVIN  = "vin"
+	DuplicateSpec = "spec"
+)
+
+// DuplicateGroup is a set of cars likely entered for the same physical car, either with the same VIN or with
+// the same brand, name, year and engine within a dealership
+type DuplicateGroup struct {
+	Reason string `json:"Reason"`
+	Cars   []Car  `json:"Cars"`
+}
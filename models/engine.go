@@ -1,10 +1,15 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Engine struct {
-	EngineID     uuid.UUID `json:"id,omitempty"`
-	Displacement int       `json:"displacement,omitempty"`
-	Cylinders    int       `json:"cylinders,omitempty"`
-	Range        int       `json:"range,omitempty"`
+	EngineID     uuid.UUID  `json:"id,omitempty"`
+	Displacement int        `json:"displacement,omitempty"`
+	Cylinders    int        `json:"cylinders,omitempty"`
+	Range        int        `json:"range,omitempty"`
+	UpdatedAt    *time.Time `json:"updatedAt,omitempty"`
 }
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types of the car lifecycle
+const (
+	EventCarCreated       = "CarCreated"
+	EventCarUpdated       = "CarUpdated"
+	EventCarDeleted       = "CarDeleted"
+	EventCarStatusChanged = "CarStatusChanged"
+)
+
+// Event is a change of a car published to downstream systems. Data is the car as written, or a StatusChange
+// for CarStatusChanged. An event can be delivered more than once, consumers deduplicate by ID.
+type Event struct {
+	// Seq is the position of the event in the outbox, only set on events read back to be streamed
+	Seq        int64           `json:"-"`
+	ID         uuid.UUID       `json:"ID"`
+	Type       string          `json:"Type"`
+	CarID      uuid.UUID       `json:"CarID"`
+	OccurredAt time.Time       `json:"OccurredAt"`
+	Data       json.RawMessage `json:"Data"`
+}
+
+// StatusChange is the data of a CarStatusChanged event, along with the brand and dealership of the car so that
+// consumers can route it without looking the car up
+type StatusChange struct {
+	From         string     `json:"From"`
+	To           string     `json:"To"`
+	Brand        string     `json:"Brand"`
+	DealershipID *uuid.UUID `json:"DealershipID,omitempty"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// HealthReport is the body of the health endpoints, Status is UP or DOWN
+type HealthReport struct {
+	Status string        `json:"Status"`
+	Checks []HealthCheck `json:"Checks"`
+}
+
+// HealthCheck is the result of a check of a dependency, LatencyMs is how long it took in milliseconds
+type HealthCheck struct {
+	Name      string  `json:"Name"`
+	Status    string  `json:"Status"`
+	LatencyMs float64 `json:"LatencyMs"`
+	Error     string  `json:"Error,omitempty"`
+}
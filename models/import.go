@@ -0,0 +1,32 @@
+package models
+
+// Import row statuses
+const (
+	ImportCreated = "created"
+	ImportValid   = "valid"
+	ImportFailed  = "failed"
+)
+
+// ImportRow is a car read from an import file. Error is set when the row could not be parsed.
+type ImportRow struct {
+	Row   int
+	Car   Car
+	Error string
+}
+
+// ImportResult is the outcome of a single row of an import
+type ImportResult struct {
+	Row    int    `json:"Row"`
+	Status string `json:"Status"`
+	ID     string `json:"ID,omitempty"`
+	Error  string `json:"Error,omitempty"`
+}
+
+// ImportReport summarises an import, Results are in the order of the rows in the file
+type ImportReport struct {
+	DryRun    bool           `json:"DryRun"`
+	Total     int            `json:"Total"`
+	Succeeded int            `json:"Succeeded"`
+	Failed    int            `json:"Failed"`
+	Results   []ImportResult `json:"Results"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Media kinds
+const (
+	MediaImage    = "image"
+	MediaDocument = "document"
+)
+
+type Media struct {
+	ID           uuid.UUID `json:"ID"`
+	CarID        uuid.UUID `json:"CarID"`
+	Kind         string    `json:"Kind"`
+	ContentType  string    `json:"ContentType"`
+	FileName     string    `json:"FileName"`
+	Size         int64     `json:"Size"`
+	Position     int       `json:"Position"`
+	IsCover      bool      `json:"IsCover"`
+	URL          string    `json:"URL"`
+	ThumbnailURL string    `json:"ThumbnailURL,omitempty"`
+	CreatedAt    time.Time `json:"CreatedAt"`
+
+	StorageKey   string `json:"-"`
+	ThumbnailKey string `json:"-"`
+}
+
+// Upload is a single file received for a car before it is stored
+type Upload struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
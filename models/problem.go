@@ -0,0 +1,18 @@
+package models
+
+// ProblemContentType is the media type of error responses
+const ProblemContentType = "application/problem+json"
+
+// Problem is an error response in the problem details format of RFC 7807. Code identifies the error and is
+// stable, unlike Title and Detail which are meant for people.
+type Problem struct {
+	Type       string      `json:"type"`
+	Title      string      `json:"title"`
+	Status     int         `json:"status"`
+	Detail     string      `json:"detail,omitempty"`
+	Instance   string      `json:"instance,omitempty"`
+	Code       string      `json:"code"`
+	RequestID  string      `json:"requestId,omitempty"`
+	ResourceID string      `json:"resourceId,omitempty"`
+	Details    interface{} `json:"details,omitempty"`
+}
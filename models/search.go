@@ -0,0 +1,15 @@
+package models
+
+import "github.com/google/uuid"
+
+// SearchHit is a car matched by the search index with its relevance score
+type SearchHit struct {
+	ID    uuid.UUID
+	Score float64
+}
+
+// SearchResult is a car returned by a full-text search, ordered by Score
+type SearchResult struct {
+	Car   Car     `json:"Car"`
+	Score float64 `json:"Score"`
+}
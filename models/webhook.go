@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Delivery statuses, a delivery is retried until it is delivered or runs out of attempts and is dead
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// Webhook is a partner endpoint the car events are posted to. Events lists the event types it receives, every
+// type when empty. The secret signing the payloads is only returned when the webhook is created.
+type Webhook struct {
+	ID        uuid.UUID `json:"ID"`
+	URL       string    `json:"URL"`
+	Events    []string  `json:"Events"`
+	Active    bool      `json:"Active"`
+	Secret    string    `json:"Secret,omitempty"`
+	CreatedAt time.Time `json:"CreatedAt"`
+}
+
+// WebhookDelivery is an event posted, or to be posted, to a webhook along with the outcome of its last attempt
+type WebhookDelivery struct {
+	ID            uuid.UUID       `json:"ID"`
+	WebhookID     uuid.UUID       `json:"WebhookID"`
+	EventID       uuid.UUID       `json:"EventID"`
+	EventType     string          `json:"EventType"`
+	Payload       json.RawMessage `json:"Payload"`
+	Status        string          `json:"Status"`
+	Attempts      int             `json:"Attempts"`
+	ResponseCode  int             `json:"ResponseCode,omitempty"`
+	LastError     string          `json:"LastError,omitempty"`
+	NextAttemptAt *time.Time      `json:"NextAttemptAt,omitempty"`
+	CreatedAt     time.Time       `json:"CreatedAt"`
+	DeliveredAt   *time.Time      `json:"DeliveredAt,omitempty"`
+}
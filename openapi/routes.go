@@ -0,0 +1,277 @@
+package openapi
+
+import (
+	"Project/CarDealearship/auth"
+	v2 "Project/CarDealearship/handlers/v2"
+	"Project/CarDealearship/models"
+	"net/http"
+)
+
+// Route describes an operation registered in main. Request and Response are values of the types of the json
+// bodies, a nil Response is an empty response. Routes with another content type set the Content fields.
+type Route struct {
+	Method string
+	Path   string
+	// ID is the operationId, the name of the operation in generated clients
+	ID         string
+	Summary    string
+	Tag        string
+	Permission auth.Permission
+	Query      []Parameter
+	Request    interface{}
+	// RequestContent lists the media types of a body that is not json
+	RequestContent []string
+	Response       interface{}
+	// ResponseContent lists the media types of a response that is not json
+	ResponseContent []string
+	// Public routes are served without credentials
+	Public bool
+	// Deprecated routes have a v2 successor
+	Deprecated bool
+	// Conditional routes are validated by middleware.Conditional, by their ETag and, when LastModified is set,
+	// by their Last-Modified
+	Conditional  bool
+	LastModified bool
+}
+
+// carList is the body of GET /cars
+type carList struct {
+	Customers []models.Car
+}
+
+type mediaOrder struct {
+	IDs []string `json:"IDs"`
+}
+
+// graphqlRequest is the body of POST /graphql, the response is the GraphQL result as is
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+func query(name, description string, schema *Schema, required bool) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema, Required: required}
+}
+
+func str() *Schema {
+	return &Schema{Type: "string"}
+}
+
+// streamQuery are the parameters of the car event streams
+var streamQuery = []Parameter{
+	query("brand", "brand of the cars, every car when empty", str(), false),
+	query("dealershipID", "dealership of the cars, every car when empty", &Schema{Type: "string", Format: "uuid"},
+		false),
+	query("lastEventId", "position of the last event received, the events after it are sent first. "+
+		"Server-Sent Events clients send it in the Last-Event-ID header.", &Schema{Type: "integer", Format: "int64"},
+		false),
+}
+
+var allowDuplicate = query("allowDuplicate", "create the car even though it is likely a duplicate of a car, "+
+	"which is otherwise rejected with 409. A car with the VIN of another car is always rejected",
+	&Schema{Type: "boolean"}, false)
+
+// Routes are the operations of the api, TestRoutes keeps them in line with main
+var Routes = []Route{
+	{
+		Method: http.MethodGet, Path: "/car/{id}", ID: "getCar", Summary: "Get a car by its id", Tag: "cars",
+		Permission: auth.ReadCars, Response: models.Car{},
+		Deprecated: true, Conditional: true, LastModified: true,
+	},
+	{
+		Method: http.MethodGet, Path: "/cars", ID: "listCars", Summary: "List the cars of a brand", Tag: "cars",
+		Permission: auth.ReadCars, Response: carList{}, Query: []Parameter{
+			query("brand", "brand of the cars", str(), false),
+			query("isEngine", "include the engine of every car", &Schema{Type: "boolean"}, true),
+		},
+		Deprecated: true, Conditional: true,
+	},
+	{
+		Method: http.MethodGet, Path: "/cars/search", ID: "searchCars", Summary: "Search the cars, most relevant first",
+		Tag: "cars", Permission: auth.ReadCars, Response: []models.SearchResult{}, Query: []Parameter{
+			query("q", "free text query", str(), true),
+			query("limit", "maximum number of results, 1 to 100", &Schema{Type: "integer", Format: "int32"}, false),
+		},
+		Deprecated: true,
+	},
+	{
+		Method: http.MethodGet, Path: "/cars/compare", ID: "compareCars", Summary: "Compare cars side by side",
+		Tag: "cars", Permission: auth.ReadCars, Response: models.Comparison{}, Query: []Parameter{
+			query("ids", "comma separated ids of the cars", str(), true),
+		},
+		Deprecated: true,
+	},
+	{
+		Method: http.MethodGet, Path: "/cars/export", ID: "exportCars", Summary: "Export the cars with their engines",
+		Tag: "cars", Permission: auth.ExportCars, ResponseContent: []string{"text/csv", "application/x-ndjson",
+			"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"}, Query: []Parameter{
+			query("brand", "brand of the cars, every car when empty", str(), false),
+			query("format", "format of the export", &Schema{Type: "string", Enum: []string{"csv", "ndjson", "xlsx"}},
+				false),
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/cars/stream", ID: "streamCarEvents",
+		Summary: "Stream the car events as Server-Sent Events, the id of an event is its position", Tag: "cars",
+		Permission: auth.ReadCars, ResponseContent: []string{"text/event-stream"}, Query: streamQuery,
+	},
+	{
+		Method: http.MethodGet, Path: "/cars/stream/ws", ID: "streamCarEventsWebSocket",
+		Summary: "Upgrade to a websocket receiving the car events, each one with its position as ID", Tag: "cars",
+		Permission: auth.ReadCars, ResponseContent: []string{"application/json"}, Query: streamQuery,
+	},
+	{
+		Method: http.MethodPost, Path: "/car", ID: "createCar", Summary: "Create a car", Tag: "cars",
+		Permission: auth.WriteCars, Request: models.Car{}, Response: models.Car{},
+		Query: []Parameter{allowDuplicate}, Deprecated: true,
+	},
+	{
+		Method: http.MethodGet, Path: "/cars/duplicates", ID: "listDuplicateCars",
+		Summary: "Report the cars likely entered more than once, by spec", Tag: "cars",
+		Permission: auth.ReadCars, Response: []models.DuplicateGroup{},
+	},
+	{
+		Method: http.MethodPost, Path: "/cars/import", ID: "importCars",
+		Summary: "Create cars in bulk from a csv or ndjson file", Tag: "cars", Permission: auth.ImportCars,
+		RequestContent: []string{"text/csv", "application/x-ndjson"}, Response: models.ImportReport{},
+		Query: []Parameter{query("dryRun", "only validate the rows", &Schema{Type: "boolean"}, false)},
+	},
+	{
+		Method: http.MethodPost, Path: "/cars:batchGet", ID: "batchGetCars",
+		Summary: "Get up to 100 cars by their ids, with a status for every id", Tag: "cars",
+		Permission: auth.ReadCars, Request: models.BatchGetRequest{}, Response: models.BatchResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/cars:batchUpdate", ID: "batchUpdateCars",
+		Summary: "Patch up to 100 cars in a single transaction, with a status for every patch", Tag: "cars",
+		Permission: auth.WriteCars, Request: models.BatchUpdateRequest{}, Response: models.BatchResponse{},
+	},
+	{
+		Method: http.MethodPut, Path: "/car/{id}", ID: "updateCar", Summary: "Update a car", Tag: "cars",
+		Permission: auth.WriteCars, Request: models.Car{}, Response: models.Car{},
+		Deprecated: true,
+	},
+	{
+		Method: http.MethodDelete, Path: "/car/{id}", ID: "deleteCar", Summary: "Delete a car", Tag: "cars",
+		Permission: auth.DeleteCars,
+		Deprecated: true,
+	},
+	{
+		Method: http.MethodGet, Path: "/v2/cars/search", ID: "searchCarsV2",
+		Summary: "Search the cars, most relevant first", Tag: "cars v2", Permission: auth.ReadCars,
+		Response: v2.SearchResults{}, Query: []Parameter{
+			query("q", "free text query", str(), true),
+			query("limit", "maximum number of results, 1 to 100", &Schema{Type: "integer", Format: "int32"}, false),
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/v2/cars/compare", ID: "compareCarsV2", Summary: "Compare cars side by side",
+		Tag: "cars v2", Permission: auth.ReadCars, Response: v2.Comparison{}, Query: []Parameter{
+			query("ids", "comma separated ids of the cars", str(), true),
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/v2/cars/{id}", ID: "getCarV2",
+		Summary: "Get a car by its id along with its engine and media", Tag: "cars v2", Permission: auth.ReadCars,
+		Response: v2.Car{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v2/cars", ID: "listCarsV2", Summary: "List the cars of a brand",
+		Tag: "cars v2", Permission: auth.ReadCars, Response: v2.CarList{}, Query: []Parameter{
+			query("brand", "brand of the cars", str(), true),
+			query("includeEngine", "include the engine of every car", &Schema{Type: "boolean"}, false),
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/v2/cars", ID: "createCarV2", Summary: "Create a car", Tag: "cars v2",
+		Permission: auth.WriteCars, Request: v2.CarRequest{}, Response: v2.Car{}, Query: []Parameter{allowDuplicate},
+	},
+	{
+		Method: http.MethodPut, Path: "/v2/cars/{id}", ID: "updateCarV2", Summary: "Update a car", Tag: "cars v2",
+		Permission: auth.WriteCars, Request: v2.CarRequest{}, Response: v2.Car{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v2/cars/{id}", ID: "deleteCarV2", Summary: "Delete a car", Tag: "cars v2",
+		Permission: auth.DeleteCars,
+	},
+	{
+		Method: http.MethodPost, Path: "/graphql", ID: "graphql", Summary: "Run a GraphQL query or mutation",
+		Tag: "graphql", Request: graphqlRequest{}, ResponseContent: []string{"application/json"},
+	},
+	{
+		Method: http.MethodGet, Path: "/car/{id}/media", ID: "listMedia", Summary: "List the media of a car",
+		Tag: "media", Permission: auth.ReadCars, Response: []models.Media{},
+	},
+	{
+		Method: http.MethodPost, Path: "/car/{id}/media", ID: "uploadMedia", Summary: "Upload media files for a car",
+		Tag: "media", Permission: auth.WriteMedia, RequestContent: []string{"multipart/form-data"},
+		Response: []models.Media{},
+	},
+	{
+		Method: http.MethodPut, Path: "/car/{id}/media/order", ID: "reorderMedia",
+		Summary: "Change the display order of the media", Tag: "media", Permission: auth.WriteMedia,
+		Request: mediaOrder{}, Response: []models.Media{},
+	},
+	{
+		Method: http.MethodPut, Path: "/car/{id}/media/{mediaID}/cover", ID: "setCover",
+		Summary: "Select the cover photo", Tag: "media", Permission: auth.WriteMedia, Response: []models.Media{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/car/{id}/media/{mediaID}", ID: "deleteMedia",
+		Summary: "Delete a media item", Tag: "media", Permission: auth.WriteMedia,
+	},
+	{
+		Method: http.MethodGet, Path: "/media/{key}", ID: "getMediaFile", Summary: "Get the content of a media file",
+		Tag: "media", Public: true, ResponseContent: []string{"application/octet-stream"},
+	},
+	{
+		Method: http.MethodGet, Path: "/webhooks", ID: "listWebhooks", Summary: "List the webhooks", Tag: "webhooks",
+		Permission: auth.ReadWebhooks, Response: []models.Webhook{},
+	},
+	{
+		Method: http.MethodPost, Path: "/webhooks", ID: "createWebhook",
+		Summary: "Subscribe a URL to car events, the response holds the signing secret", Tag: "webhooks",
+		Permission: auth.WriteWebhooks, Request: models.Webhook{}, Response: models.Webhook{},
+	},
+	{
+		Method: http.MethodGet, Path: "/webhooks/{id}", ID: "getWebhook", Summary: "Get a webhook by its id",
+		Tag: "webhooks", Permission: auth.ReadWebhooks, Response: models.Webhook{},
+	},
+	{
+		Method: http.MethodPut, Path: "/webhooks/{id}", ID: "updateWebhook", Summary: "Update a webhook",
+		Tag: "webhooks", Permission: auth.WriteWebhooks, Request: models.Webhook{}, Response: models.Webhook{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/webhooks/{id}", ID: "deleteWebhook",
+		Summary: "Delete a webhook along with its deliveries", Tag: "webhooks", Permission: auth.WriteWebhooks,
+	},
+	{
+		Method: http.MethodGet, Path: "/webhooks/{id}/deliveries", ID: "listWebhookDeliveries",
+		Summary: "List the latest deliveries of a webhook", Tag: "webhooks", Permission: auth.ReadWebhooks,
+		Response: []models.WebhookDelivery{}, Query: []Parameter{
+			query("status", "status of the deliveries", &Schema{Type: "string",
+				Enum: []string{models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead}}, false),
+			query("limit", "maximum number of deliveries, 1 to 100", &Schema{Type: "integer", Format: "int32"}, false),
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/webhooks/{id}/deliveries/{deliveryID}/retry", ID: "retryWebhookDelivery",
+		Summary: "Send a dead delivery again", Tag: "webhooks", Permission: auth.WriteWebhooks,
+		Response: models.WebhookDelivery{},
+	},
+	{
+		Method: http.MethodGet, Path: "/openapi.json", ID: "getOpenAPI", Summary: "Get this document", Tag: "meta",
+		Public: true, ResponseContent: []string{"application/json"},
+	},
+	{
+		Method: http.MethodGet, Path: "/health/live", ID: "getLiveness",
+		Summary: "Tell that the process is alive, the report is always UP", Tag: "meta", Public: true,
+		ResponseContent: []string{"application/health+json"},
+	},
+	{
+		Method: http.MethodGet, Path: "/health/ready", ID: "getReadiness",
+		Summary: "Check the dependencies, 503 while starting, shutting down or when a check fails", Tag: "meta",
+		Public: true, ResponseContent: []string{"application/health+json"},
+	},
+}
@@ -0,0 +1,193 @@
+package openapi
+
+import (
+	"Project/CarDealearship/auth"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var permissions = map[string]auth.Permission{
+	"ReadCars": auth.ReadCars, "WriteCars": auth.WriteCars, "DeleteCars": auth.DeleteCars,
+	"ImportCars": auth.ImportCars, "ExportCars": auth.ExportCars, "ReadCost": auth.ReadCost,
+	"WriteCost": auth.WriteCost, "WriteMedia": auth.WriteMedia,
+	"ReadWebhooks": auth.ReadWebhooks, "WriteWebhooks": auth.WriteWebhooks,
+}
+
+type registered struct {
+	permission auth.Permission
+	public     bool
+	deprecated bool
+}
+
+// mainRoutes reads the routes registered in main.go by k.GET and the like and by middleware.Mount, along with
+// the permission they require, whether they are public and whether they are listed as deprecated
+func mainRoutes(t *testing.T) map[string]registered {
+	f, err := parser.ParseFile(token.NewFileSet(), "../main.go", nil, 0)
+	if err != nil {
+		t.Fatalf("error in parsing main.go: %v", err)
+	}
+
+	var public, deprecated []string
+
+	routes := make(map[string]registered)
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.CompositeLit); ok {
+			if arr, ok := lit.Type.(*ast.ArrayType); ok && selector(arr.Elt) == "middleware.DeprecatedRoute" {
+				deprecated = append(deprecated, deprecatedRoutes(lit)...)
+			}
+
+			return true
+		}
+
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		var method, path string
+
+		var handler ast.Expr
+
+		switch name := selector(call.Fun); {
+		case name == "k.GET" || name == "k.POST" || name == "k.PUT" || name == "k.DELETE":
+			method, path, handler = strings.TrimPrefix(name, "k."), literal(call.Args[0]), call.Args[1]
+		case name == "middleware.Mount":
+			method = strings.ToUpper(strings.TrimPrefix(selector(call.Args[1]), "http.Method"))
+			path, handler = literal(call.Args[2]), call.Args[3]
+		case name == "middleware.Authenticate":
+			for _, arg := range call.Args[2:] {
+				public = append(public, literal(arg))
+			}
+
+			return true
+		default:
+			return true
+		}
+
+		var r registered
+
+		if h, ok := handler.(*ast.CallExpr); ok {
+			// gofr handlers are registered through handle of main.go, which requires the permission
+			if name := callee(h.Fun); name == "handle" || name == "middleware.RequireStream" {
+				r.permission = permissions[strings.TrimPrefix(selector(h.Args[0]), "auth.")]
+			}
+		}
+
+		routes[method+" "+path] = r
+
+		return true
+	})
+
+	for key, r := range routes {
+		for _, prefix := range public {
+			if strings.HasPrefix(strings.SplitN(key, " ", 2)[1], prefix) {
+				r.public = true
+			}
+		}
+
+		for _, d := range deprecated {
+			r.deprecated = r.deprecated || d == key
+		}
+
+		routes[key] = r
+	}
+
+	return routes
+}
+
+// deprecatedRoutes returns the method and path of the elements of a []middleware.DeprecatedRoute literal
+func deprecatedRoutes(lit *ast.CompositeLit) []string {
+	var routes []string
+
+	for _, elt := range lit.Elts {
+		route, ok := elt.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+
+		var method, path string
+
+		for _, field := range route.Elts {
+			kv, ok := field.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			switch key, _ := kv.Key.(*ast.Ident); key.Name {
+			case "Method":
+				method = strings.ToUpper(strings.TrimPrefix(selector(kv.Value), "http.Method"))
+			case "Path":
+				path = literal(kv.Value)
+			}
+		}
+
+		routes = append(routes, method+" "+path)
+	}
+
+	return routes
+}
+
+func selector(e ast.Expr) string {
+	s, ok := e.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+
+	x, ok := s.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+
+	return x.Name + "." + s.Sel.Name
+}
+
+// callee is the name of a called function, along with its package when it is not one of main.go
+func callee(e ast.Expr) string {
+	if id, ok := e.(*ast.Ident); ok {
+		return id.Name
+	}
+
+	return selector(e)
+}
+
+func literal(e ast.Expr) string {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok {
+		return ""
+	}
+
+	s, _ := strconv.Unquote(lit.Value)
+
+	return s
+}
+
+func TestRoutes(t *testing.T) {
+	registeredRoutes := mainRoutes(t)
+	documented := make(map[string]registered)
+
+	for _, r := range Routes {
+		documented[r.Method+" "+r.Path] = registered{permission: r.Permission, public: r.Public,
+			deprecated: r.Deprecated}
+	}
+
+	assert.NotEmpty(t, registeredRoutes)
+	assert.Equal(t, registeredRoutes, documented, "the routes of main.go are not the documented ones")
+}
+
+func TestRouteIDs(t *testing.T) {
+	ids := make(map[string]bool)
+
+	for _, r := range Routes {
+		assert.NotEmpty(t, r.ID, "%v %v has no operation id", r.Method, r.Path)
+		assert.False(t, ids[r.ID], "operation id %v is not unique", r.ID)
+
+		ids[r.ID] = true
+	}
+}
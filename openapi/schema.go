@@ -0,0 +1,138 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	uuidType          = reflect.TypeOf(uuid.UUID{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemas builds the schemas of go types the way encoding/json marshals them. Named structs are added to
+// the components and referenced, so that a model is described once.
+type schemas map[string]*Schema
+
+func (s schemas) of(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case t == rawMessageType:
+		// raw messages are marshaled as the json they hold
+		return &Schema{}
+	case t.Kind() != reflect.Ptr && t.Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := s.of(t.Elem())
+		if schema.Ref != "" {
+			return schema
+		}
+
+		schema.Nullable = true
+
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+
+		name := componentName(t)
+
+		if _, ok := s[name]; !ok {
+			// the placeholder ends the recursion of types referring to themselves
+			s[name] = &Schema{}
+			*s[name] = *s.object(t)
+		}
+
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		// interface{} values can be anything
+		return &Schema{}
+	}
+}
+
+// object describes the exported fields of a struct under their json names, fields without omitempty are
+// always present and so required. encoding/json never omits a struct, omitempty or not.
+func (s schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name, omitEmpty := f.Name, false
+
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+
+			if parts[0] != "" {
+				name = parts[0]
+			}
+
+			for _, opt := range parts[1:] {
+				omitEmpty = omitEmpty || opt == "omitempty"
+			}
+		}
+
+		schema.Properties[name] = s.of(f.Type)
+
+		if (!omitEmpty || f.Type.Kind() == reflect.Struct) && f.Type.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	sort.Strings(schema.Required)
+
+	return schema
+}
+
+// componentName is the capitalised name of a type, prefixed with the version of versioned packages like
+// handlers/v2 so that their types do not clash with the models of the same name
+func componentName(t reflect.Type) string {
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+
+	pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+	if len(pkg) > 1 && pkg[0] == 'v' && strings.Trim(pkg[1:], "0123456789") == "" {
+		return strings.ToUpper(pkg) + name
+	}
+
+	return name
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	v2 "Project/CarDealearship/handlers/v2"
+	"Project/CarDealearship/models"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type node struct {
+	ID       uuid.UUID         `json:"id"`
+	Name     string            `json:"name,omitempty"`
+	Weight   *float64          `json:"weight"`
+	Children []node            `json:"children"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Created  time.Time
+	Data     []byte          `json:"data"`
+	Raw      json.RawMessage `json:"raw"`
+	Extra    interface{}     `json:"extra"`
+	Secret   string          `json:"-"`
+	hidden   int             // nolint:unused,structcheck // unexported fields are not marshaled
+	Inline   struct{ N int } `json:"inline,omitempty"`
+}
+
+func TestSchemas(t *testing.T) {
+	s := make(schemas)
+
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/Node"}, s.of(reflect.TypeOf(&node{})))
+	assert.Equal(t, &Schema{Type: "object", Required: []string{"Created", "children", "data", "extra", "id",
+		"inline", "raw"}, Properties: map[string]*Schema{
+		"id":       {Type: "string", Format: "uuid"},
+		"name":     {Type: "string"},
+		"weight":   {Type: "number", Nullable: true},
+		"children": {Type: "array", Items: &Schema{Ref: "#/components/schemas/Node"}},
+		"labels":   {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		"Created":  {Type: "string", Format: "date-time"},
+		"data":     {Type: "string", Format: "byte"},
+		"raw":      {},
+		"extra":    {},
+		"inline": {Type: "object", Required: []string{"N"},
+			Properties: map[string]*Schema{"N": {Type: "integer", Format: "int32"}}},
+	}}, s["Node"])
+}
+
+func TestComponentName(t *testing.T) {
+	testCases := []struct {
+		value interface{}
+		name  string
+	}{
+		{models.Car{}, "Car"},
+		{v2.Car{}, "V2Car"},
+		{node{}, "Node"},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.name, componentName(reflect.TypeOf(tc.value)), "TEST[%d], failed.\n%s", i, tc.name)
+	}
+}
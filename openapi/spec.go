@@ -0,0 +1,248 @@
+package openapi
+
+import (
+	"Project/CarDealearship/models"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+const (
+	bearerAuth = "bearerAuth"
+	apiKeyAuth = "apiKeyAuth"
+)
+
+// Build returns the document describing Routes. Json bodies are wrapped in the data key like gofr responds
+// them, errors are problem details described by the shared responses of the components.
+func Build(title, version string) Document {
+	doc := Document{
+		OpenAPI: "3.0.3",
+		Info: Info{Title: title, Version: version, Description: "Cars of the dealership along with their " +
+			"engines and media. Callers authenticate with a bearer JWT or an api key, every operation states the " +
+			"permission it needs in x-permission."},
+		Paths: make(map[string]PathItem),
+		Components: Components{
+			Responses: errorResponses(),
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				apiKeyAuth: {Type: "apiKey", In: "header", Name: "X-API-Key"},
+			},
+		},
+	}
+
+	s := schemas{}
+	s.of(reflect.TypeOf(models.Problem{}))
+
+	for i := range Routes {
+		r := &Routes[i]
+
+		if doc.Paths[r.Path] == nil {
+			doc.Paths[r.Path] = make(PathItem)
+		}
+
+		doc.Paths[r.Path][strings.ToLower(r.Method)] = operation(s, r)
+	}
+
+	doc.Components.Schemas = s
+
+	return doc
+}
+
+func operation(s schemas, r *Route) *Operation {
+	op := &Operation{
+		OperationID: r.ID,
+		Summary:     r.Summary,
+		Tags:        []string{r.Tag},
+		Parameters:  append(pathParameters(r.Path), r.Query...),
+		Responses:   map[string]*Response{"429": ref("TooManyRequests"), "500": ref("InternalServerError")},
+		Security:    []map[string][]string{},
+		Permission:  string(r.Permission),
+		Deprecated:  r.Deprecated,
+	}
+
+	if !r.Public {
+		op.Security = []map[string][]string{{bearerAuth: {}}, {apiKeyAuth: {}}}
+		op.Responses["401"] = ref("Unauthorized")
+		op.Responses["403"] = ref("Forbidden")
+	}
+
+	if strings.Contains(r.Path, "{") {
+		op.Responses["404"] = ref("NotFound")
+	}
+
+	if len(op.Parameters) > 0 || r.Request != nil || len(r.RequestContent) > 0 {
+		op.Responses["400"] = ref("BadRequest")
+	}
+
+	switch {
+	case r.Request != nil:
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"application/json": {Schema: s.of(reflect.TypeOf(r.Request))},
+		}}
+	case len(r.RequestContent) > 0:
+		op.RequestBody = &RequestBody{Required: true, Content: raw(r.RequestContent)}
+	}
+
+	success := &Response{Description: r.Summary}
+
+	switch {
+	case r.Response != nil:
+		success.Content = map[string]MediaType{"application/json": {Schema: &Schema{
+			Type: "object", Required: []string{"data"},
+			Properties: map[string]*Schema{"data": s.of(reflect.TypeOf(r.Response))},
+		}}}
+	case len(r.ResponseContent) > 0:
+		success.Content = raw(r.ResponseContent)
+	}
+
+	if r.Conditional {
+		conditional(op, success, r.LastModified)
+	}
+
+	if r.Method != http.MethodGet {
+		idempotent(op, success)
+	}
+
+	op.Responses[strconv.Itoa(successStatus(r))] = success
+
+	return op
+}
+
+// conditional describes the validators of the response of a route served through middleware.Conditional
+func conditional(op *Operation, success *Response, lastModified bool) {
+	date := &Schema{Type: "string", Description: "http date"}
+
+	op.Parameters = append(op.Parameters, Parameter{Name: "If-None-Match", In: "header", Description: "ETags of " +
+		"the representations the caller has, the response is 304 when one is current", Schema: str()})
+
+	success.Headers = map[string]Header{
+		"ETag":          {Description: "hash of the representation", Schema: str()},
+		"Cache-Control": {Schema: str()},
+	}
+
+	if lastModified {
+		op.Parameters = append(op.Parameters, Parameter{Name: "If-Modified-Since", In: "header", Description: "the " +
+			"response is 304 when nothing changed since, only used without If-None-Match", Schema: date})
+		success.Headers["Last-Modified"] = Header{Description: "when the car or its engine last changed", Schema: date}
+	}
+
+	op.Responses["304"] = &Response{Description: "the representation of the caller is current",
+		Headers: success.Headers}
+}
+
+// idempotent describes the Idempotency-Key of a mutation, handled by middleware.Idempotency
+func idempotent(op *Operation, success *Response) {
+	op.Parameters = append(op.Parameters, Parameter{Name: "Idempotency-Key", In: "header", Description: "unique " +
+		"key of the request, a retry with it gets the response of the first request instead of being run again",
+		Schema: &Schema{Type: "string", MaxLength: 255}})
+
+	if success.Headers == nil {
+		success.Headers = make(map[string]Header)
+	}
+
+	success.Headers["Idempotent-Replayed"] = Header{Description: "true when the response is the one of an " +
+		"earlier request with the Idempotency-Key", Schema: &Schema{Type: "boolean"}}
+
+	op.Responses["400"] = ref("BadRequest")
+	op.Responses["409"] = ref("Conflict")
+	op.Responses["413"] = ref("PayloadTooLarge")
+	op.Responses["422"] = ref("IdempotencyKeyReused")
+}
+
+// successStatus is the status gofr responds a handler without error with
+func successStatus(r *Route) int {
+	switch {
+	case r.Method == http.MethodDelete:
+		return http.StatusNoContent
+	case r.Method == http.MethodPost && r.Response != nil:
+		return http.StatusCreated
+	default:
+		return http.StatusOK
+	}
+}
+
+func pathParameters(path string) []Parameter {
+	var params []Parameter
+
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name := strings.Trim(segment, "{}")
+
+			schema := str()
+			if strings.HasSuffix(name, "id") || strings.HasSuffix(name, "ID") {
+				schema.Format = "uuid"
+			}
+
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+		}
+	}
+
+	return params
+}
+
+func raw(contentTypes []string) map[string]MediaType {
+	content := make(map[string]MediaType, len(contentTypes))
+
+	for _, t := range contentTypes {
+		if t == "application/json" || t == "application/x-ndjson" {
+			content[t] = MediaType{Schema: &Schema{}}
+			continue
+		}
+
+		content[t] = MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+	}
+
+	return content
+}
+
+func ref(name string) *Response {
+	return &Response{Ref: "#/components/responses/" + name}
+}
+
+func errorResponses() map[string]*Response {
+	body := map[string]MediaType{models.ProblemContentType: {Schema: &Schema{Ref: "#/components/schemas/Problem"}}}
+	integer := &Schema{Type: "integer", Format: "int32"}
+
+	return map[string]*Response{
+		"BadRequest": {Description: "a parameter or the body is missing or invalid", Content: body},
+		"Unauthorized": {Description: "the credentials are missing or invalid", Content: body,
+			Headers: map[string]Header{"WWW-Authenticate": {Schema: str()}}},
+		"Forbidden": {Description: "the caller lacks the permission of the operation", Content: body},
+		"NotFound":  {Description: "the entity does not exist", Content: body},
+		"TooManyRequests": {Description: "the rate limit of the caller is exhausted", Content: body,
+			Headers: map[string]Header{
+				"Retry-After":           {Description: "seconds until a request is allowed", Schema: integer},
+				"X-RateLimit-Limit":     {Description: "burst of the limit", Schema: integer},
+				"X-RateLimit-Remaining": {Description: "requests left", Schema: integer},
+				"X-RateLimit-Reset":     {Description: "seconds until the limit is fully reset", Schema: integer},
+			}},
+		"Conflict": {Description: "a request with the Idempotency-Key is running, IDEMPOTENCY_KEY_IN_USE, or the " +
+			"entity conflicts with an existing one, like a likely duplicate car, DUPLICATE_CAR", Content: body,
+			Headers: map[string]Header{"Retry-After": {Description: "seconds to wait before retrying a request " +
+				"with the Idempotency-Key", Schema: integer}}},
+		"IdempotencyKeyReused": {Description: "the Idempotency-Key was used for another request", Content: body},
+		"PayloadTooLarge": {Description: "the body is too large, like the body of a request with an " +
+			"Idempotency-Key larger than the limit, PAYLOAD_TOO_LARGE", Content: body},
+		"InternalServerError": {Description: "an unexpected error", Content: body},
+	}
+}
+
+// Handler serves doc as is, without the data key gofr wraps responses in
+func Handler(doc Document) func(ctx *gofr.Context, w http.ResponseWriter) error {
+	body, marshalErr := json.MarshalIndent(doc, "", "  ")
+
+	return func(ctx *gofr.Context, w http.ResponseWriter) error {
+		if marshalErr != nil {
+			return marshalErr
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write(body)
+
+		return err
+	}
+}
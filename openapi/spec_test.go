@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+)
+
+const golden = "testdata/openapi.json"
+
+var update = flag.Bool("update", false, "rewrite "+golden+" with the current document")
+
+// TestBuild fails when a model or a route changes the document, review the change and run
+// go test ./openapi -update to accept it
+func TestBuild(t *testing.T) {
+	got, err := json.MarshalIndent(Build("carDealership", "0.1"), "", "  ")
+	if err != nil {
+		t.Fatalf("error in marshaling the document: %v", err)
+	}
+
+	got = append(got, '\n')
+
+	if *update {
+		if err = os.WriteFile(golden, got, 0o600); err != nil {
+			t.Fatalf("error in writing %v: %v", golden, err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("error in reading %v: %v", golden, err)
+	}
+
+	assert.Equal(t, string(want), string(got), "the document drifted from %v", golden)
+}
+
+func TestBuildOperation(t *testing.T) {
+	doc := Build("carDealership", "0.1")
+
+	get := doc.Paths["/car/{id}"]["get"]
+
+	assert.Equal(t, "getCar", get.OperationID)
+	assert.Equal(t, "cars:read", get.Permission)
+	assert.Equal(t, Parameter{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string",
+		Format: "uuid"}}, get.Parameters[0])
+	assert.Equal(t, []string{"If-None-Match", "If-Modified-Since"},
+		[]string{get.Parameters[1].Name, get.Parameters[2].Name}, "conditional requests")
+	assert.Contains(t, get.Responses["200"].Headers, "ETag")
+	assert.Contains(t, get.Responses, "304")
+
+	list := doc.Paths["/cars"]["get"]
+	assert.Contains(t, list.Responses["200"].Headers, "ETag")
+	assert.NotContains(t, list.Responses["200"].Headers, "Last-Modified", "lists are only validated by their ETag")
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/Car"},
+		get.Responses["200"].Content["application/json"].Schema.Properties["data"])
+	assert.Len(t, get.Security, 2)
+
+	for _, status := range []string{"401", "403", "404", "429", "500"} {
+		assert.Contains(t, get.Responses, status)
+	}
+
+	create := doc.Paths["/car"]["post"]
+	assert.Equal(t, []string{"allowDuplicate", "Idempotency-Key"},
+		[]string{create.Parameters[0].Name, create.Parameters[1].Name})
+	assert.Contains(t, create.Responses["201"].Headers, "Idempotent-Replayed")
+
+	for _, status := range []string{"400", "409", "413", "422"} {
+		assert.Contains(t, create.Responses, status)
+	}
+
+	assert.NotContains(t, get.Responses, "409", "safe methods take no Idempotency-Key")
+	assert.Contains(t, doc.Paths["/car/{id}"]["delete"].Responses, "204")
+	assert.Empty(t, doc.Paths["/media/{key}"]["get"].Security, "public operations need no credentials")
+
+	engine := doc.Components.Schemas["Engine"]
+	assert.Contains(t, engine.Properties, "displacement")
+	assert.Equal(t, &Schema{Type: "integer", Format: "int32", Nullable: true},
+		doc.Components.Schemas["Car"].Properties["CostPrice"])
+}
+
+func TestHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := Handler(Build("carDealership", "0.1"))(gofr.NewContext(nil, nil, gofr.New()), w)
+
+	var doc Document
+
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Contains(t, doc.Paths, "/cars")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,1073 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.19.1
+// source: pb/cars.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Car struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Year     int32  `protobuf:"varint,3,opt,name=year,proto3" json:"year,omitempty"`
+	Brand    string `protobuf:"bytes,4,opt,name=brand,proto3" json:"brand,omitempty"`
+	FuelType string `protobuf:"bytes,5,opt,name=fuel_type,json=fuelType,proto3" json:"fuel_type,omitempty"`
+	// cost_price is only set for callers allowed to read it.
+	CostPrice *int64  `protobuf:"varint,6,opt,name=cost_price,json=costPrice,proto3,oneof" json:"cost_price,omitempty"`
+	Engine    *Engine `protobuf:"bytes,7,opt,name=engine,proto3" json:"engine,omitempty"`
+	// dealership_id is empty for cars not assigned to a dealership.
+	DealershipId string `protobuf:"bytes,8,opt,name=dealership_id,json=dealershipId,proto3" json:"dealership_id,omitempty"`
+}
+
+func (x *Car) Reset() {
+	*x = Car{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Car) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Car) ProtoMessage() {}
+
+func (x *Car) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Car.ProtoReflect.Descriptor instead.
+func (*Car) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Car) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Car) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Car) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Car) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *Car) GetFuelType() string {
+	if x != nil {
+		return x.FuelType
+	}
+	return ""
+}
+
+func (x *Car) GetCostPrice() int64 {
+	if x != nil && x.CostPrice != nil {
+		return *x.CostPrice
+	}
+	return 0
+}
+
+func (x *Car) GetEngine() *Engine {
+	if x != nil {
+		return x.Engine
+	}
+	return nil
+}
+
+func (x *Car) GetDealershipId() string {
+	if x != nil {
+		return x.DealershipId
+	}
+	return ""
+}
+
+type Engine struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Displacement int32  `protobuf:"varint,2,opt,name=displacement,proto3" json:"displacement,omitempty"`
+	Cylinders    int32  `protobuf:"varint,3,opt,name=cylinders,proto3" json:"cylinders,omitempty"`
+	Range        int32  `protobuf:"varint,4,opt,name=range,proto3" json:"range,omitempty"`
+}
+
+func (x *Engine) Reset() {
+	*x = Engine{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Engine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Engine) ProtoMessage() {}
+
+func (x *Engine) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Engine.ProtoReflect.Descriptor instead.
+func (*Engine) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Engine) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Engine) GetDisplacement() int32 {
+	if x != nil {
+		return x.Displacement
+	}
+	return 0
+}
+
+func (x *Engine) GetCylinders() int32 {
+	if x != nil {
+		return x.Cylinders
+	}
+	return 0
+}
+
+func (x *Engine) GetRange() int32 {
+	if x != nil {
+		return x.Range
+	}
+	return 0
+}
+
+type GetCarRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetCarRequest) Reset() {
+	*x = GetCarRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCarRequest) ProtoMessage() {}
+
+func (x *GetCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCarRequest.ProtoReflect.Descriptor instead.
+func (*GetCarRequest) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetCarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListCarsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Brand         string `protobuf:"bytes,1,opt,name=brand,proto3" json:"brand,omitempty"`
+	IncludeEngine bool   `protobuf:"varint,2,opt,name=include_engine,json=includeEngine,proto3" json:"include_engine,omitempty"`
+}
+
+func (x *ListCarsRequest) Reset() {
+	*x = ListCarsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCarsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarsRequest) ProtoMessage() {}
+
+func (x *ListCarsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarsRequest.ProtoReflect.Descriptor instead.
+func (*ListCarsRequest) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListCarsRequest) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *ListCarsRequest) GetIncludeEngine() bool {
+	if x != nil {
+		return x.IncludeEngine
+	}
+	return false
+}
+
+type ListCarsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cars []*Car `protobuf:"bytes,1,rep,name=cars,proto3" json:"cars,omitempty"`
+}
+
+func (x *ListCarsResponse) Reset() {
+	*x = ListCarsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCarsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarsResponse) ProtoMessage() {}
+
+func (x *ListCarsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarsResponse.ProtoReflect.Descriptor instead.
+func (*ListCarsResponse) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListCarsResponse) GetCars() []*Car {
+	if x != nil {
+		return x.Cars
+	}
+	return nil
+}
+
+type StreamCarsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// brand is empty to stream the whole inventory.
+	Brand string `protobuf:"bytes,1,opt,name=brand,proto3" json:"brand,omitempty"`
+}
+
+func (x *StreamCarsRequest) Reset() {
+	*x = StreamCarsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamCarsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCarsRequest) ProtoMessage() {}
+
+func (x *StreamCarsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCarsRequest.ProtoReflect.Descriptor instead.
+func (*StreamCarsRequest) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamCarsRequest) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+type CreateCarRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Car *Car `protobuf:"bytes,1,opt,name=car,proto3" json:"car,omitempty"`
+}
+
+func (x *CreateCarRequest) Reset() {
+	*x = CreateCarRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCarRequest) ProtoMessage() {}
+
+func (x *CreateCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCarRequest.ProtoReflect.Descriptor instead.
+func (*CreateCarRequest) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateCarRequest) GetCar() *Car {
+	if x != nil {
+		return x.Car
+	}
+	return nil
+}
+
+type UpdateCarRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id  string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Car *Car   `protobuf:"bytes,2,opt,name=car,proto3" json:"car,omitempty"`
+}
+
+func (x *UpdateCarRequest) Reset() {
+	*x = UpdateCarRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCarRequest) ProtoMessage() {}
+
+func (x *UpdateCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCarRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCarRequest) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateCarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateCarRequest) GetCar() *Car {
+	if x != nil {
+		return x.Car
+	}
+	return nil
+}
+
+type DeleteCarRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteCarRequest) Reset() {
+	*x = DeleteCarRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCarRequest) ProtoMessage() {}
+
+func (x *DeleteCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCarRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCarRequest) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteCarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteCarResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteCarResponse) Reset() {
+	*x = DeleteCarResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteCarResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCarResponse) ProtoMessage() {}
+
+func (x *DeleteCarResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCarResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCarResponse) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{9}
+}
+
+type GetEngineRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetEngineRequest) Reset() {
+	*x = GetEngineRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEngineRequest) ProtoMessage() {}
+
+func (x *GetEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEngineRequest.ProtoReflect.Descriptor instead.
+func (*GetEngineRequest) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetEngineRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type BatchGetEnginesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ids []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+}
+
+func (x *BatchGetEnginesRequest) Reset() {
+	*x = BatchGetEnginesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchGetEnginesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetEnginesRequest) ProtoMessage() {}
+
+func (x *BatchGetEnginesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetEnginesRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetEnginesRequest) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchGetEnginesRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type BatchGetEnginesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Engines []*Engine `protobuf:"bytes,1,rep,name=engines,proto3" json:"engines,omitempty"`
+}
+
+func (x *BatchGetEnginesResponse) Reset() {
+	*x = BatchGetEnginesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_cars_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchGetEnginesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetEnginesResponse) ProtoMessage() {}
+
+func (x *BatchGetEnginesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_cars_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetEnginesResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetEnginesResponse) Descriptor() ([]byte, []int) {
+	return file_pb_cars_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchGetEnginesResponse) GetEngines() []*Engine {
+	if x != nil {
+		return x.Engines
+	}
+	return nil
+}
+
+var File_pb_cars_proto protoreflect.FileDescriptor
+
+var file_pb_cars_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x70, 0x62, 0x2f, 0x63, 0x61, 0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x10, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76,
+	0x31, 0x22, 0xfa, 0x01, 0x0a, 0x03, 0x43, 0x61, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65, 0x61,
+	0x72, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x75, 0x65, 0x6c, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x75, 0x65, 0x6c,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x0a, 0x63, 0x6f, 0x73, 0x74, 0x5f, 0x70, 0x72, 0x69,
+	0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x09, 0x63, 0x6f, 0x73, 0x74,
+	0x50, 0x72, 0x69, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x30, 0x0a, 0x06, 0x65, 0x6e, 0x67, 0x69,
+	0x6e, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x61, 0x72, 0x64, 0x65,
+	0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x67, 0x69,
+	0x6e, 0x65, 0x52, 0x06, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65,
+	0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x49, 0x64, 0x42,
+	0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x6f, 0x73, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x22, 0x70,
+	0x0a, 0x06, 0x45, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70,
+	0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c,
+	0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x63, 0x79, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x63, 0x79, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x61,
+	0x6e, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65,
+	0x22, 0x1f, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x22, 0x4e, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x61, 0x72, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e,
+	0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x45, 0x6e, 0x67, 0x69, 0x6e,
+	0x65, 0x22, 0x3d, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x61, 0x72, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x04, 0x63, 0x61, 0x72, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73,
+	0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x72, 0x52, 0x04, 0x63, 0x61, 0x72, 0x73,
+	0x22, 0x29, 0x0a, 0x11, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x61, 0x72, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x22, 0x3b, 0x0a, 0x10, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x27, 0x0a, 0x03, 0x63, 0x61, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63,
+	0x61, 0x72, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x61, 0x72, 0x52, 0x03, 0x63, 0x61, 0x72, 0x22, 0x4b, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x43, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x27, 0x0a, 0x03,
+	0x63, 0x61, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x61, 0x72, 0x64,
+	0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x72,
+	0x52, 0x03, 0x63, 0x61, 0x72, 0x22, 0x22, 0x0a, 0x10, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43,
+	0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x13, 0x0a, 0x11, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x43, 0x61, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x22,
+	0x0a, 0x10, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x2a, 0x0a, 0x16, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x45, 0x6e,
+	0x67, 0x69, 0x6e, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03,
+	0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73, 0x22, 0x4d,
+	0x0a, 0x17, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x67, 0x69, 0x6e, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x07, 0x65, 0x6e, 0x67,
+	0x69, 0x6e, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x61, 0x72,
+	0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e,
+	0x67, 0x69, 0x6e, 0x65, 0x52, 0x07, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x73, 0x32, 0xd3, 0x03,
+	0x0a, 0x0a, 0x43, 0x61, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x06,
+	0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x12, 0x1f, 0x2e, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c,
+	0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61,
+	0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x72, 0x12, 0x51,
+	0x0a, 0x08, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x61, 0x72, 0x73, 0x12, 0x21, 0x2e, 0x63, 0x61, 0x72,
+	0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x43, 0x61, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e,
+	0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x61, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x4a, 0x0a, 0x0a, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x61, 0x72, 0x73, 0x12,
+	0x23, 0x2e, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x61, 0x72, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72,
+	0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x72, 0x30, 0x01, 0x12, 0x46, 0x0a,
+	0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x61, 0x72, 0x12, 0x22, 0x2e, 0x63, 0x61, 0x72,
+	0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x43, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15,
+	0x2e, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x61, 0x72, 0x12, 0x46, 0x0a, 0x09, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43,
+	0x61, 0x72, 0x12, 0x22, 0x2e, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68,
+	0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x61, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c,
+	0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x72, 0x12, 0x54, 0x0a,
+	0x09, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x61, 0x72, 0x12, 0x22, 0x2e, 0x63, 0x61, 0x72,
+	0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x43, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23,
+	0x2e, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x61, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x32, 0xc2, 0x01, 0x0a, 0x0d, 0x45, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x49, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x67, 0x69,
+	0x6e, 0x65, 0x12, 0x22, 0x2e, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68,
+	0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c,
+	0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x67, 0x69, 0x6e, 0x65,
+	0x12, 0x66, 0x0a, 0x0f, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x67, 0x69,
+	0x6e, 0x65, 0x73, 0x12, 0x28, 0x2e, 0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73,
+	0x68, 0x69, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x45,
+	0x6e, 0x67, 0x69, 0x6e, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e,
+	0x63, 0x61, 0x72, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x1b, 0x5a, 0x19, 0x50, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x2f, 0x43, 0x61, 0x72, 0x44, 0x65, 0x61, 0x6c, 0x65, 0x61, 0x72, 0x73, 0x68,
+	0x69, 0x70, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pb_cars_proto_rawDescOnce sync.Once
+	file_pb_cars_proto_rawDescData = file_pb_cars_proto_rawDesc
+)
+
+func file_pb_cars_proto_rawDescGZIP() []byte {
+	file_pb_cars_proto_rawDescOnce.Do(func() {
+		file_pb_cars_proto_rawDescData = protoimpl.X.CompressGZIP(file_pb_cars_proto_rawDescData)
+	})
+	return file_pb_cars_proto_rawDescData
+}
+
+var file_pb_cars_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_pb_cars_proto_goTypes = []interface{}{
+	(*Car)(nil),                     // 0: cardealership.v1.Car
+	(*Engine)(nil),                  // 1: cardealership.v1.Engine
+	(*GetCarRequest)(nil),           // 2: cardealership.v1.GetCarRequest
+	(*ListCarsRequest)(nil),         // 3: cardealership.v1.ListCarsRequest
+	(*ListCarsResponse)(nil),        // 4: cardealership.v1.ListCarsResponse
+	(*StreamCarsRequest)(nil),       // 5: cardealership.v1.StreamCarsRequest
+	(*CreateCarRequest)(nil),        // 6: cardealership.v1.CreateCarRequest
+	(*UpdateCarRequest)(nil),        // 7: cardealership.v1.UpdateCarRequest
+	(*DeleteCarRequest)(nil),        // 8: cardealership.v1.DeleteCarRequest
+	(*DeleteCarResponse)(nil),       // 9: cardealership.v1.DeleteCarResponse
+	(*GetEngineRequest)(nil),        // 10: cardealership.v1.GetEngineRequest
+	(*BatchGetEnginesRequest)(nil),  // 11: cardealership.v1.BatchGetEnginesRequest
+	(*BatchGetEnginesResponse)(nil), // 12: cardealership.v1.BatchGetEnginesResponse
+}
+var file_pb_cars_proto_depIdxs = []int32{
+	1,  // 0: cardealership.v1.Car.engine:type_name -> cardealership.v1.Engine
+	0,  // 1: cardealership.v1.ListCarsResponse.cars:type_name -> cardealership.v1.Car
+	0,  // 2: cardealership.v1.CreateCarRequest.car:type_name -> cardealership.v1.Car
+	0,  // 3: cardealership.v1.UpdateCarRequest.car:type_name -> cardealership.v1.Car
+	1,  // 4: cardealership.v1.BatchGetEnginesResponse.engines:type_name -> cardealership.v1.Engine
+	2,  // 5: cardealership.v1.CarService.GetCar:input_type -> cardealership.v1.GetCarRequest
+	3,  // 6: cardealership.v1.CarService.ListCars:input_type -> cardealership.v1.ListCarsRequest
+	5,  // 7: cardealership.v1.CarService.StreamCars:input_type -> cardealership.v1.StreamCarsRequest
+	6,  // 8: cardealership.v1.CarService.CreateCar:input_type -> cardealership.v1.CreateCarRequest
+	7,  // 9: cardealership.v1.CarService.UpdateCar:input_type -> cardealership.v1.UpdateCarRequest
+	8,  // 10: cardealership.v1.CarService.DeleteCar:input_type -> cardealership.v1.DeleteCarRequest
+	10, // 11: cardealership.v1.EngineService.GetEngine:input_type -> cardealership.v1.GetEngineRequest
+	11, // 12: cardealership.v1.EngineService.BatchGetEngines:input_type -> cardealership.v1.BatchGetEnginesRequest
+	0,  // 13: cardealership.v1.CarService.GetCar:output_type -> cardealership.v1.Car
+	4,  // 14: cardealership.v1.CarService.ListCars:output_type -> cardealership.v1.ListCarsResponse
+	0,  // 15: cardealership.v1.CarService.StreamCars:output_type -> cardealership.v1.Car
+	0,  // 16: cardealership.v1.CarService.CreateCar:output_type -> cardealership.v1.Car
+	0,  // 17: cardealership.v1.CarService.UpdateCar:output_type -> cardealership.v1.Car
+	9,  // 18: cardealership.v1.CarService.DeleteCar:output_type -> cardealership.v1.DeleteCarResponse
+	1,  // 19: cardealership.v1.EngineService.GetEngine:output_type -> cardealership.v1.Engine
+	12, // 20: cardealership.v1.EngineService.BatchGetEngines:output_type -> cardealership.v1.BatchGetEnginesResponse
+	13, // [13:21] is the sub-list for method output_type
+	5,  // [5:13] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_pb_cars_proto_init() }
+func file_pb_cars_proto_init() {
+	if File_pb_cars_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pb_cars_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Car); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_cars_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Engine); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_cars_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCarRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_cars_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCarsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_cars_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCarsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_cars_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamCarsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_cars_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateCarRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_cars_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateCarRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_cars_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteCarRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_cars_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteCarResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_cars_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetEngineRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_cars_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchGetEnginesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_cars_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchGetEnginesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pb_cars_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pb_cars_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_pb_cars_proto_goTypes,
+		DependencyIndexes: file_pb_cars_proto_depIdxs,
+		MessageInfos:      file_pb_cars_proto_msgTypes,
+	}.Build()
+	File_pb_cars_proto = out.File
+	file_pb_cars_proto_rawDesc = nil
+	file_pb_cars_proto_goTypes = nil
+	file_pb_cars_proto_depIdxs = nil
+}
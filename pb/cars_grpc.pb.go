@@ -0,0 +1,453 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.19.1
+// source: pb/cars.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// CarServiceClient is the client API for CarService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CarServiceClient interface {
+	// GetCar returns a car with its engine.
+	GetCar(ctx context.Context, in *GetCarRequest, opts ...grpc.CallOption) (*Car, error)
+	// ListCars returns the cars of a brand, with their engines when include_engine is set.
+	ListCars(ctx context.Context, in *ListCarsRequest, opts ...grpc.CallOption) (*ListCarsResponse, error)
+	// StreamCars streams the cars of a brand, or of the whole inventory, with their engines. The cars are
+	// read from the database as they are sent.
+	StreamCars(ctx context.Context, in *StreamCarsRequest, opts ...grpc.CallOption) (CarService_StreamCarsClient, error)
+	// CreateCar creates a car along with its engine.
+	CreateCar(ctx context.Context, in *CreateCarRequest, opts ...grpc.CallOption) (*Car, error)
+	// UpdateCar replaces a car along with its engine.
+	UpdateCar(ctx context.Context, in *UpdateCarRequest, opts ...grpc.CallOption) (*Car, error)
+	// DeleteCar deletes a car along with its engine.
+	DeleteCar(ctx context.Context, in *DeleteCarRequest, opts ...grpc.CallOption) (*DeleteCarResponse, error)
+}
+
+type carServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCarServiceClient(cc grpc.ClientConnInterface) CarServiceClient {
+	return &carServiceClient{cc}
+}
+
+func (c *carServiceClient) GetCar(ctx context.Context, in *GetCarRequest, opts ...grpc.CallOption) (*Car, error) {
+	out := new(Car)
+	err := c.cc.Invoke(ctx, "/cardealership.v1.CarService/GetCar", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carServiceClient) ListCars(ctx context.Context, in *ListCarsRequest, opts ...grpc.CallOption) (*ListCarsResponse, error) {
+	out := new(ListCarsResponse)
+	err := c.cc.Invoke(ctx, "/cardealership.v1.CarService/ListCars", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carServiceClient) StreamCars(ctx context.Context, in *StreamCarsRequest, opts ...grpc.CallOption) (CarService_StreamCarsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CarService_ServiceDesc.Streams[0], "/cardealership.v1.CarService/StreamCars", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &carServiceStreamCarsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CarService_StreamCarsClient interface {
+	Recv() (*Car, error)
+	grpc.ClientStream
+}
+
+type carServiceStreamCarsClient struct {
+	grpc.ClientStream
+}
+
+func (x *carServiceStreamCarsClient) Recv() (*Car, error) {
+	m := new(Car)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *carServiceClient) CreateCar(ctx context.Context, in *CreateCarRequest, opts ...grpc.CallOption) (*Car, error) {
+	out := new(Car)
+	err := c.cc.Invoke(ctx, "/cardealership.v1.CarService/CreateCar", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carServiceClient) UpdateCar(ctx context.Context, in *UpdateCarRequest, opts ...grpc.CallOption) (*Car, error) {
+	out := new(Car)
+	err := c.cc.Invoke(ctx, "/cardealership.v1.CarService/UpdateCar", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carServiceClient) DeleteCar(ctx context.Context, in *DeleteCarRequest, opts ...grpc.CallOption) (*DeleteCarResponse, error) {
+	out := new(DeleteCarResponse)
+	err := c.cc.Invoke(ctx, "/cardealership.v1.CarService/DeleteCar", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CarServiceServer is the server API for CarService service.
+// All implementations must embed UnimplementedCarServiceServer
+// for forward compatibility
+type CarServiceServer interface {
+	// GetCar returns a car with its engine.
+	GetCar(context.Context, *GetCarRequest) (*Car, error)
+	// ListCars returns the cars of a brand, with their engines when include_engine is set.
+	ListCars(context.Context, *ListCarsRequest) (*ListCarsResponse, error)
+	// StreamCars streams the cars of a brand, or of the whole inventory, with their engines. The cars are
+	// read from the database as they are sent.
+	StreamCars(*StreamCarsRequest, CarService_StreamCarsServer) error
+	// CreateCar creates a car along with its engine.
+	CreateCar(context.Context, *CreateCarRequest) (*Car, error)
+	// UpdateCar replaces a car along with its engine.
+	UpdateCar(context.Context, *UpdateCarRequest) (*Car, error)
+	// DeleteCar deletes a car along with its engine.
+	DeleteCar(context.Context, *DeleteCarRequest) (*DeleteCarResponse, error)
+	mustEmbedUnimplementedCarServiceServer()
+}
+
+// UnimplementedCarServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedCarServiceServer struct {
+}
+
+func (UnimplementedCarServiceServer) GetCar(context.Context, *GetCarRequest) (*Car, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCar not implemented")
+}
+func (UnimplementedCarServiceServer) ListCars(context.Context, *ListCarsRequest) (*ListCarsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCars not implemented")
+}
+func (UnimplementedCarServiceServer) StreamCars(*StreamCarsRequest, CarService_StreamCarsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCars not implemented")
+}
+func (UnimplementedCarServiceServer) CreateCar(context.Context, *CreateCarRequest) (*Car, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCar not implemented")
+}
+func (UnimplementedCarServiceServer) UpdateCar(context.Context, *UpdateCarRequest) (*Car, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCar not implemented")
+}
+func (UnimplementedCarServiceServer) DeleteCar(context.Context, *DeleteCarRequest) (*DeleteCarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCar not implemented")
+}
+func (UnimplementedCarServiceServer) mustEmbedUnimplementedCarServiceServer() {}
+
+// UnsafeCarServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CarServiceServer will
+// result in compilation errors.
+type UnsafeCarServiceServer interface {
+	mustEmbedUnimplementedCarServiceServer()
+}
+
+func RegisterCarServiceServer(s grpc.ServiceRegistrar, srv CarServiceServer) {
+	s.RegisterService(&CarService_ServiceDesc, srv)
+}
+
+func _CarService_GetCar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarServiceServer).GetCar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cardealership.v1.CarService/GetCar",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarServiceServer).GetCar(ctx, req.(*GetCarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarService_ListCars_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCarsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarServiceServer).ListCars(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cardealership.v1.CarService/ListCars",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarServiceServer).ListCars(ctx, req.(*ListCarsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarService_StreamCars_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamCarsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CarServiceServer).StreamCars(m, &carServiceStreamCarsServer{stream})
+}
+
+type CarService_StreamCarsServer interface {
+	Send(*Car) error
+	grpc.ServerStream
+}
+
+type carServiceStreamCarsServer struct {
+	grpc.ServerStream
+}
+
+func (x *carServiceStreamCarsServer) Send(m *Car) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CarService_CreateCar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarServiceServer).CreateCar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cardealership.v1.CarService/CreateCar",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarServiceServer).CreateCar(ctx, req.(*CreateCarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarService_UpdateCar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarServiceServer).UpdateCar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cardealership.v1.CarService/UpdateCar",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarServiceServer).UpdateCar(ctx, req.(*UpdateCarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarService_DeleteCar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarServiceServer).DeleteCar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cardealership.v1.CarService/DeleteCar",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarServiceServer).DeleteCar(ctx, req.(*DeleteCarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CarService_ServiceDesc is the grpc.ServiceDesc for CarService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CarService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cardealership.v1.CarService",
+	HandlerType: (*CarServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCar",
+			Handler:    _CarService_GetCar_Handler,
+		},
+		{
+			MethodName: "ListCars",
+			Handler:    _CarService_ListCars_Handler,
+		},
+		{
+			MethodName: "CreateCar",
+			Handler:    _CarService_CreateCar_Handler,
+		},
+		{
+			MethodName: "UpdateCar",
+			Handler:    _CarService_UpdateCar_Handler,
+		},
+		{
+			MethodName: "DeleteCar",
+			Handler:    _CarService_DeleteCar_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamCars",
+			Handler:       _CarService_StreamCars_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pb/cars.proto",
+}
+
+// EngineServiceClient is the client API for EngineService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EngineServiceClient interface {
+	// GetEngine returns an engine.
+	GetEngine(ctx context.Context, in *GetEngineRequest, opts ...grpc.CallOption) (*Engine, error)
+	// BatchGetEngines returns the engines with up to 100 given ids, ids that do not exist are skipped.
+	BatchGetEngines(ctx context.Context, in *BatchGetEnginesRequest, opts ...grpc.CallOption) (*BatchGetEnginesResponse, error)
+}
+
+type engineServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEngineServiceClient(cc grpc.ClientConnInterface) EngineServiceClient {
+	return &engineServiceClient{cc}
+}
+
+func (c *engineServiceClient) GetEngine(ctx context.Context, in *GetEngineRequest, opts ...grpc.CallOption) (*Engine, error) {
+	out := new(Engine)
+	err := c.cc.Invoke(ctx, "/cardealership.v1.EngineService/GetEngine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *engineServiceClient) BatchGetEngines(ctx context.Context, in *BatchGetEnginesRequest, opts ...grpc.CallOption) (*BatchGetEnginesResponse, error) {
+	out := new(BatchGetEnginesResponse)
+	err := c.cc.Invoke(ctx, "/cardealership.v1.EngineService/BatchGetEngines", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EngineServiceServer is the server API for EngineService service.
+// All implementations must embed UnimplementedEngineServiceServer
+// for forward compatibility
+type EngineServiceServer interface {
+	// GetEngine returns an engine.
+	GetEngine(context.Context, *GetEngineRequest) (*Engine, error)
+	// BatchGetEngines returns the engines with up to 100 given ids, ids that do not exist are skipped.
+	BatchGetEngines(context.Context, *BatchGetEnginesRequest) (*BatchGetEnginesResponse, error)
+	mustEmbedUnimplementedEngineServiceServer()
+}
+
+// UnimplementedEngineServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedEngineServiceServer struct {
+}
+
+func (UnimplementedEngineServiceServer) GetEngine(context.Context, *GetEngineRequest) (*Engine, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEngine not implemented")
+}
+func (UnimplementedEngineServiceServer) BatchGetEngines(context.Context, *BatchGetEnginesRequest) (*BatchGetEnginesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetEngines not implemented")
+}
+func (UnimplementedEngineServiceServer) mustEmbedUnimplementedEngineServiceServer() {}
+
+// UnsafeEngineServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EngineServiceServer will
+// result in compilation errors.
+type UnsafeEngineServiceServer interface {
+	mustEmbedUnimplementedEngineServiceServer()
+}
+
+func RegisterEngineServiceServer(s grpc.ServiceRegistrar, srv EngineServiceServer) {
+	s.RegisterService(&EngineService_ServiceDesc, srv)
+}
+
+func _EngineService_GetEngine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEngineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServiceServer).GetEngine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cardealership.v1.EngineService/GetEngine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServiceServer).GetEngine(ctx, req.(*GetEngineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EngineService_BatchGetEngines_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetEnginesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EngineServiceServer).BatchGetEngines(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cardealership.v1.EngineService/BatchGetEngines",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EngineServiceServer).BatchGetEngines(ctx, req.(*BatchGetEnginesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EngineService_ServiceDesc is the grpc.ServiceDesc for EngineService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EngineService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cardealership.v1.EngineService",
+	HandlerType: (*EngineServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetEngine",
+			Handler:    _EngineService_GetEngine_Handler,
+		},
+		{
+			MethodName: "BatchGetEngines",
+			Handler:    _EngineService_BatchGetEngines_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pb/cars.proto",
+}
@@ -0,0 +1,4 @@
+// Package pb holds the messages and gRPC services of the inventory, generated from cars.proto
+package pb
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative ../pb/cars.proto
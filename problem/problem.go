@@ -0,0 +1,103 @@
+// Package problem maps the errors of the stores, services and handlers to the status and stable code they are
+// responded with, the body is written as problem details (RFC 7807) by middleware.Problems.
+package problem
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// Stable codes of the errors that do not carry their own
+const (
+	CodeInvalidParam = "INVALID_PARAM"
+	CodeMissingParam = "MISSING_PARAM"
+	CodeInvalidBody  = "INVALID_BODY"
+	CodeNotFound     = "NOT_FOUND"
+	CodeConflict     = "CONFLICT"
+	CodeInternal     = "INTERNAL"
+)
+
+// Map returns the response err is answered with. Errors that already are a response are kept, the client
+// mistakes that surface as raw errors, like a parameter that does not parse, become 400 and rows that do not
+// exist 404. Anything else is an internal error whose details are not disclosed.
+func Map(err error) *errors.Response {
+	switch e := err.(type) {
+	case *errors.Response:
+		if e.Code != "" {
+			return e
+		}
+
+		resp := *e
+		resp.Code = Code(e.StatusCode)
+
+		return &resp
+	case errors.EntityNotFound:
+		return &errors.Response{StatusCode: http.StatusNotFound, Code: CodeNotFound, Reason: e.Error(),
+			ResourceID: e.ID}
+	case errors.InvalidParam:
+		return params(CodeInvalidParam, e.Error(), e.Param)
+	case errors.MissingParam:
+		return params(CodeMissingParam, e.Error(), e.Param)
+	case errors.EntityAlreadyExists:
+		return &errors.Response{StatusCode: http.StatusConflict, Code: CodeConflict, Reason: e.Error()}
+	case *strconv.NumError:
+		return &errors.Response{StatusCode: http.StatusBadRequest, Code: CodeInvalidParam,
+			Reason: "invalid value " + strconv.Quote(e.Num)}
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		return &errors.Response{StatusCode: http.StatusBadRequest, Code: CodeInvalidBody, Reason: e.Error()}
+	case errors.DB:
+		if e.Err == sql.ErrNoRows {
+			return Map(e.Err)
+		}
+	}
+
+	if err == sql.ErrNoRows {
+		return &errors.Response{StatusCode: http.StatusNotFound, Code: CodeNotFound, Reason: "entity not found"}
+	}
+
+	return &errors.Response{StatusCode: http.StatusInternalServerError, Code: CodeInternal,
+		Reason: "internal error"}
+}
+
+// Handler wraps a gofr handler so that its errors are mapped
+func Handler(h gofr.Handler) gofr.Handler {
+	return func(ctx *gofr.Context) (interface{}, error) {
+		data, err := h(ctx)
+		if err != nil {
+			return nil, Of(ctx, err)
+		}
+
+		return data, nil
+	}
+}
+
+// Of maps err like Map and logs it when it is an internal error, as the response does not tell what it was
+func Of(ctx *gofr.Context, err error) *errors.Response {
+	resp := Map(err)
+
+	if _, ok := err.(*errors.Response); !ok && resp.StatusCode == http.StatusInternalServerError {
+		ctx.Logger.Errorf("internal error: %v", err)
+	}
+
+	return resp
+}
+
+// Code is the stable code of a status for errors that have none, like NOT_FOUND for 404
+func Code(status int) string {
+	if status == 0 || status == http.StatusInternalServerError {
+		return CodeInternal
+	}
+
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
+func params(code, reason string, names []string) *errors.Response {
+	return &errors.Response{StatusCode: http.StatusBadRequest, Code: code, Reason: reason,
+		Detail: map[string][]string{"params": names}}
+}
@@ -0,0 +1,82 @@
+package problem
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMap to test the errors are mapped to the status and the stable code they are responded with
+func TestMap(t *testing.T) {
+	_, parseErr := strconv.ParseBool("")
+	syntaxErr := json.Unmarshal([]byte("{"), &struct{}{})
+	conflict := &errors.Response{StatusCode: http.StatusConflict, Code: "DUPLICATE_CAR", Reason: "duplicate"}
+
+	testCases := []struct {
+		desc string
+		err  error
+		want *errors.Response
+	}{
+		{desc: "response", err: conflict, want: conflict},
+		{desc: "response without code", err: &errors.Response{StatusCode: http.StatusUnprocessableEntity},
+			want: &errors.Response{StatusCode: http.StatusUnprocessableEntity, Code: "UNPROCESSABLE_ENTITY"}},
+		{desc: "entity not found", err: errors.EntityNotFound{Entity: "Car", ID: "1"},
+			want: &errors.Response{StatusCode: http.StatusNotFound, Code: CodeNotFound,
+				Reason: "No 'Car' found for Id: '1'", ResourceID: "1"}},
+		{desc: "no rows", err: sql.ErrNoRows, want: &errors.Response{StatusCode: http.StatusNotFound,
+			Code: CodeNotFound, Reason: "entity not found"}},
+		{desc: "no rows of the db", err: errors.DB{Err: sql.ErrNoRows}, want: &errors.Response{
+			StatusCode: http.StatusNotFound, Code: CodeNotFound, Reason: "entity not found"}},
+		{desc: "invalid param", err: errors.InvalidParam{Param: []string{"limit"}},
+			want: &errors.Response{StatusCode: http.StatusBadRequest, Code: CodeInvalidParam,
+				Reason: "Incorrect value for parameter: [limit]", Detail: map[string][]string{"params": {"limit"}}}},
+		{desc: "missing param", err: errors.MissingParam{Param: []string{"brand"}},
+			want: &errors.Response{StatusCode: http.StatusBadRequest, Code: CodeMissingParam,
+				Reason: "Parameter [brand] is required", Detail: map[string][]string{"params": {"brand"}}}},
+		{desc: "already exists", err: errors.EntityAlreadyExists{}, want: &errors.Response{
+			StatusCode: http.StatusConflict, Code: CodeConflict, Reason: "entity already exists"}},
+		{desc: "unparsed param", err: parseErr, want: &errors.Response{StatusCode: http.StatusBadRequest,
+			Code: CodeInvalidParam, Reason: `invalid value ""`}},
+		{desc: "invalid json", err: syntaxErr, want: &errors.Response{StatusCode: http.StatusBadRequest,
+			Code: CodeInvalidBody, Reason: syntaxErr.Error()}},
+		{desc: "db error", err: errors.DB{Err: errors.Error("connection refused")},
+			want: &errors.Response{StatusCode: http.StatusInternalServerError, Code: CodeInternal,
+				Reason: "internal error"}},
+		{desc: "unexpected", err: errors.Error("boom"), want: &errors.Response{
+			StatusCode: http.StatusInternalServerError, Code: CodeInternal, Reason: "internal error"}},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.want, Map(tc.err), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestHandler to test the errors of a wrapped handler are mapped and its data is kept
+func TestHandler(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	data, err := Handler(func(ctx *gofr.Context) (interface{}, error) { return "car", nil })(ctx)
+
+	assert.Equal(t, "car", data)
+	assert.Equal(t, nil, err)
+
+	data, err = Handler(func(ctx *gofr.Context) (interface{}, error) { return "car", sql.ErrNoRows })(ctx)
+
+	assert.Equal(t, nil, data)
+	assert.Equal(t, &errors.Response{StatusCode: http.StatusNotFound, Code: CodeNotFound,
+		Reason: "entity not found"}, err)
+}
+
+// TestCode to test the codes derived from statuses
+func TestCode(t *testing.T) {
+	assert.Equal(t, "NOT_FOUND", Code(http.StatusNotFound))
+	assert.Equal(t, "TOO_MANY_REQUESTS", Code(http.StatusTooManyRequests))
+	assert.Equal(t, CodeInternal, Code(http.StatusInternalServerError))
+	assert.Equal(t, CodeInternal, Code(0))
+}
@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"Project/CarDealearship/route"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+)
+
+// Limit is a token bucket refilled with Rate tokens per second up to Burst tokens, every request takes one
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until a token is available, zero when one was taken
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// Backend keeps the buckets, one per key
+type Backend interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Rule applies a limit to the requests of a route, Path can hold {param} segments like a gofr route
+type Rule struct {
+	Method string
+	Path   string
+	Limit  Limit
+}
+
+var units = map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour}
+
+// ParseLimit reads a limit written as count/unit with an optional :burst, for example 120/m:20. The unit is
+// s, m or h and the burst defaults to the count.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	invalid := errors.Error("invalid rate limit " + strconv.Quote(s))
+
+	parts := strings.SplitN(s, ":", 2)
+
+	rate := strings.SplitN(parts[0], "/", 2)
+	if len(rate) != 2 {
+		return Limit{}, invalid
+	}
+
+	count, err := strconv.Atoi(rate[0])
+	if err != nil || count <= 0 {
+		return Limit{}, invalid
+	}
+
+	unit, ok := units[rate[1]]
+	if !ok {
+		return Limit{}, invalid
+	}
+
+	burst := count
+
+	if len(parts) == 2 {
+		if burst, err = strconv.Atoi(parts[1]); err != nil || burst <= 0 {
+			return Limit{}, invalid
+		}
+	}
+
+	return Limit{Rate: float64(count) / unit.Seconds(), Burst: burst}, nil
+}
+
+// ParseRules reads route limits like "GET /cars=60/m:20, POST /cars/import=10/h" separated by commas or new
+// lines, so that they can be kept in a file too. Lines starting with # are skipped.
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+
+	for _, r := range strings.FieldsFunc(s, func(c rune) bool { return c == ',' || c == '\n' }) {
+		r = strings.TrimSpace(r)
+		if r == "" || strings.HasPrefix(r, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(r, "=", 2)
+		route := strings.Fields(parts[0])
+
+		if len(parts) != 2 || len(route) != 2 {
+			return nil, errors.Error("invalid rate limit rule " + strconv.Quote(r))
+		}
+
+		limit, err := ParseLimit(parts[1])
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, Rule{Method: strings.ToUpper(route[0]), Path: route[1], Limit: limit})
+	}
+
+	return rules, nil
+}
+
+// Matches reports whether the rule applies to a request
+func (r Rule) Matches(method, path string) bool {
+	if r.Method != method {
+		return false
+	}
+
+	_, ok := route.Match(r.Path, path)
+
+	return ok
+}
+
+// take refills a bucket holding tokens as of last up to now and takes a token from it. It is the algorithm
+// of every backend, the redis one runs the same steps in a script.
+func take(tokens float64, last, now time.Time, limit Limit) (float64, Result) {
+	if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+		tokens = math.Min(float64(limit.Burst), tokens+elapsed*limit.Rate)
+	}
+
+	res := Result{Allowed: tokens >= 1}
+
+	if res.Allowed {
+		tokens--
+	} else {
+		res.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	res.Remaining = int(tokens)
+	res.Reset = seconds((float64(limit.Burst) - tokens) / limit.Rate)
+
+	return tokens, res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input string
+		limit Limit
+		isErr bool
+	}{
+		{desc: "per second", input: "5/s", limit: Limit{Rate: 5, Burst: 5}},
+		{desc: "per minute with burst", input: " 120/m:20 ", limit: Limit{Rate: 2, Burst: 20}},
+		{desc: "per hour", input: "3600/h:1", limit: Limit{Rate: 1, Burst: 1}},
+		{desc: "unknown unit", input: "5/d", isErr: true},
+		{desc: "no unit", input: "5", isErr: true},
+		{desc: "zero count", input: "0/s", isErr: true},
+		{desc: "invalid burst", input: "5/s:x", isErr: true},
+	}
+
+	for i, tc := range testCases {
+		limit, err := ParseLimit(tc.input)
+
+		assert.Equal(t, tc.isErr, err != nil, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.limit, limit, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("get /cars=60/m:20, POST /car/{id}/media=10/h\n# imports\nPOST /cars/import=1/s\n")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Method: "GET", Path: "/cars", Limit: Limit{Rate: 1, Burst: 20}},
+		{Method: "POST", Path: "/car/{id}/media", Limit: Limit{Rate: 10.0 / 3600, Burst: 10}},
+		{Method: "POST", Path: "/cars/import", Limit: Limit{Rate: 1, Burst: 1}},
+	}, rules)
+
+	for _, s := range []string{"/cars=1/s", "GET /cars", "GET /cars=1"} {
+		_, err = ParseRules(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	r := Rule{Method: "POST", Path: "/car/{id}/media"}
+
+	testCases := []struct {
+		method string
+		path   string
+		match  bool
+	}{
+		{"POST", "/car/123/media", true},
+		{"POST", "/car/123/media/", true},
+		{"GET", "/car/123/media", false},
+		{"POST", "/car/123", false},
+		{"POST", "/cars/123/media", false},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.match, r.Matches(tc.method, tc.path), "TEST[%d], failed.\n%s %s", i, tc.method, tc.path)
+	}
+}
+
+func TestTake(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 4}
+	start := time.Unix(0, 0)
+
+	testCases := []struct {
+		desc   string
+		tokens float64
+		now    time.Time
+		left   float64
+		result Result
+	}{
+		{desc: "full bucket", tokens: 4, now: start, left: 3,
+			result: Result{Allowed: true, Remaining: 3, Reset: 500 * time.Millisecond}},
+		{desc: "refilled", tokens: 0, now: start.Add(time.Second), left: 1,
+			result: Result{Allowed: true, Remaining: 1, Reset: 1500 * time.Millisecond}},
+		{desc: "refill is capped at burst", tokens: 1, now: start.Add(time.Hour), left: 3,
+			result: Result{Allowed: true, Remaining: 3, Reset: 500 * time.Millisecond}},
+		{desc: "empty bucket", tokens: 0.5, now: start, left: 0.5,
+			result: Result{Remaining: 0, RetryAfter: 250 * time.Millisecond, Reset: 1750 * time.Millisecond}},
+	}
+
+	for i, tc := range testCases {
+		left, res := take(tc.tokens, start, tc.now, limit)
+
+		assert.Equal(t, tc.left, left, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.result, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
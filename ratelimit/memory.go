@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is the number of takes between two sweeps of the buckets that are full again
+const sweepEvery = 1024
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+type memory struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+	now     func() time.Time
+}
+
+// nolint:revive // need not be exported
+// NewMemory factory function, the buckets are kept in process and so are not shared between instances
+func NewMemory() *memory {
+	return &memory{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Take takes a token from the bucket of key, a key seen for the first time starts with a full bucket
+func (m *memory) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	m.takes++
+	if m.takes%sweepEvery == 0 {
+		m.sweep(now)
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		m.buckets[key] = b
+	}
+
+	var res Result
+
+	b.tokens, res = take(b.tokens, b.last, now, limit)
+	b.last = now
+	b.full = now.Add(res.Reset)
+
+	return res, nil
+}
+
+// sweep drops the buckets that have refilled, they are the same as a new one
+func (m *memory) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryTake(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 1, Burst: 2}
+
+	for i, allowed := range []bool{true, true, false} {
+		res, err := m.Take(context.Background(), "a", limit)
+
+		assert.NoError(t, err)
+		assert.Equal(t, allowed, res.Allowed, "TEST[%d], failed.\ntake %d", i, i+1)
+	}
+
+	res, _ := m.Take(context.Background(), "b", limit)
+	assert.True(t, res.Allowed, "buckets are kept per key")
+
+	now = now.Add(time.Second)
+
+	res, _ = m.Take(context.Background(), "a", limit)
+	assert.True(t, res.Allowed, "a token is refilled after a second")
+}
+
+func TestMemorySweep(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	_, _ = m.Take(context.Background(), "a", Limit{Rate: 1, Burst: 2})
+	_, _ = m.Take(context.Background(), "b", Limit{Rate: 1, Burst: 2})
+
+	now = now.Add(time.Second)
+	_, _ = m.Take(context.Background(), "b", Limit{Rate: 1, Burst: 2})
+
+	m.sweep(now)
+
+	assert.NotContains(t, m.buckets, "a", "a full bucket is dropped")
+	assert.Contains(t, m.buckets, "b", "a bucket that is refilling is kept")
+}
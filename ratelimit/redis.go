@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/go-redis/redis/v8"
+)
+
+// takeScript is take run atomically on a hash holding the tokens and the time in milliseconds they were
+// counted at. The tokens are returned as a string as redis truncates numbers returned by scripts.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+if now > ts then
+  tokens = math.min(burst, tokens + (now - ts) * rate)
+  ts = now
+end
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', ts)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+const redisPrefix = "ratelimit:"
+
+type redisBackend struct {
+	client redis.Scripter
+	now    func() time.Time
+}
+
+// nolint:revive // need not be exported
+// NewRedis factory function, the buckets are shared by every instance using the same redis
+func NewRedis(client redis.Scripter) redisBackend {
+	return redisBackend{client: client, now: time.Now}
+}
+
+// Take takes a token from the bucket of key, buckets expire once they have refilled
+func (r redisBackend) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	perMilli := limit.Rate / 1000
+
+	v, err := takeScript.Run(ctx, r.client, []string{redisPrefix + key},
+		strconv.FormatFloat(perMilli, 'g', -1, 64), limit.Burst, r.now().UnixNano()/int64(time.Millisecond)).Result()
+	if err != nil {
+		return Result{}, err
+	}
+
+	reply, ok := v.([]interface{})
+	if !ok || len(reply) != 2 {
+		return Result{}, errors.Error("unexpected rate limit script reply")
+	}
+
+	allowed, _ := reply[0].(int64)
+	s, _ := reply[1].(string)
+
+	tokens, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{Allowed: allowed == 1, Remaining: int(math.Max(tokens, 0)),
+		Reset: seconds((float64(limit.Burst) - tokens) / limit.Rate)}
+
+	if !res.Allowed {
+		res.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisTake(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+
+	defer client.Close()
+
+	now := time.Unix(1000, 0)
+	r := NewRedis(client)
+	r.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 2, Burst: 2}
+
+	testCases := []struct {
+		desc    string
+		advance time.Duration
+		result  Result
+	}{
+		{desc: "full bucket", result: Result{Allowed: true, Remaining: 1, Reset: 500 * time.Millisecond}},
+		{desc: "last token", result: Result{Allowed: true, Remaining: 0, Reset: time.Second}},
+		{desc: "empty bucket", result: Result{RetryAfter: 500 * time.Millisecond, Reset: time.Second}},
+		{desc: "half refilled", advance: 250 * time.Millisecond,
+			result: Result{RetryAfter: 250 * time.Millisecond, Reset: 750 * time.Millisecond}},
+		{desc: "refilled", advance: 250 * time.Millisecond,
+			result: Result{Allowed: true, Remaining: 0, Reset: time.Second}},
+	}
+
+	for i, tc := range testCases {
+		now = now.Add(tc.advance)
+
+		res, err := r.Take(context.Background(), "a", limit)
+
+		assert.NoError(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.result, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.True(t, s.Exists(redisPrefix+"a"))
+	assert.True(t, s.TTL(redisPrefix+"a") > 0, "the bucket expires")
+
+	s.FastForward(time.Minute)
+	assert.False(t, s.Exists(redisPrefix+"a"), "the bucket expires once refilled")
+}
+
+func TestRedisTakeError(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr(), MaxRetries: -1})
+
+	defer client.Close()
+
+	s.Close()
+
+	_, err := NewRedis(client).Take(context.Background(), "a", Limit{Rate: 1, Burst: 1})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,29 @@
+package route
+
+import "strings"
+
+// Match matches path against a route pattern holding {param} segments like a gofr route, and returns the
+// values of its params
+func Match(pattern, path string) (map[string]string, bool) {
+	want := strings.Split(strings.Trim(pattern, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(want) != len(got) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+
+	for i := range want {
+		if strings.HasPrefix(want[i], "{") && strings.HasSuffix(want[i], "}") {
+			params[strings.Trim(want[i], "{}")] = got[i]
+			continue
+		}
+
+		if want[i] != got[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
@@ -0,0 +1,31 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		path    string
+		params  map[string]string
+		match   bool
+	}{
+		{"/car/{id}/media", "/car/123/media", map[string]string{"id": "123"}, true},
+		{"/car/{id}/media", "/car/123/media/", map[string]string{"id": "123"}, true},
+		{"/car/{id}/media", "/car/123", nil, false},
+		{"/car/{id}/media", "/cars/123/media", nil, false},
+		{"/cars", "/cars", map[string]string{}, true},
+		{"/dealership/{dealershipId}/car/{id}", "/dealership/1/car/2", map[string]string{"dealershipId": "1", "id": "2"},
+			true},
+	}
+
+	for i, tc := range testCases {
+		params, ok := Match(tc.pattern, tc.path)
+
+		assert.Equal(t, tc.match, ok, "TEST[%d], failed.\n%s %s", i, tc.pattern, tc.path)
+		assert.Equal(t, tc.params, params, "TEST[%d], failed.\n%s %s", i, tc.pattern, tc.path)
+	}
+}
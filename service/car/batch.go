@@ -0,0 +1,187 @@
+package car
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/problem"
+	"encoding/json"
+	"net/http"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+)
+
+// BatchGet is a service layer function to get the cars with the given ids along with their engines in a single
+// query. There is a result for every id, in the same order, with the status it would have had on its own.
+func (service service) BatchGet(ctx *gofr.Context, ids []string) ([]models.BatchResult, error) {
+	results := make([]models.BatchResult, len(ids))
+	valid := make([]string, 0, len(ids))
+
+	for i, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			results[i] = batchError(ctx, id, errors.InvalidParam{Param: []string{"ID"}})
+			continue
+		}
+
+		valid = append(valid, id)
+	}
+
+	cars, err := service.carStore.GetCarsByIDs(ctx, valid)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]models.Car, len(cars))
+	for i := range cars {
+		byID[cars[i].ID.String()] = cars[i]
+	}
+
+	for i := range results {
+		if results[i].Status != 0 {
+			continue
+		}
+
+		c, ok := byID[ids[i]]
+		if !ok {
+			results[i] = batchError(ctx, ids[i], errors.EntityNotFound{Entity: "Car", ID: ids[i]})
+			continue
+		}
+
+		redact(ctx, &c)
+		results[i] = models.BatchResult{ID: ids[i], Status: http.StatusOK, Car: &c}
+	}
+
+	return results, nil
+}
+
+// BatchUpdate is a service layer function to apply patches to many cars in a single transaction. The cars are
+// read with a single query and every patch is checked like an update of its own, patches failing the checks get
+// their error while the others are written. When a write fails the transaction is rolled back, the failed patch
+// gets its error and the other valid ones 424 Failed Dependency.
+func (service service) BatchUpdate(ctx *gofr.Context, patches []models.CarPatch) ([]models.BatchResult, error) {
+	results := make([]models.BatchResult, len(patches))
+	ids := make([]string, 0, len(patches))
+	seen := make(map[string]bool, len(patches))
+
+	for i := range patches {
+		id := patches[i].ID
+
+		switch _, err := uuid.Parse(id); {
+		case err != nil:
+			results[i] = batchError(ctx, id, errors.InvalidParam{Param: []string{"ID"}})
+		case seen[id]:
+			results[i] = batchError(ctx, id, &errors.Response{StatusCode: http.StatusBadRequest,
+				Code: "DUPLICATE_ITEM", Reason: "the car is updated by an earlier item of the batch"})
+		default:
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	var (
+		updated []int
+		failed  = -1
+	)
+
+	err := service.tx.WithTransaction(ctx, func(ctx *gofr.Context) error {
+		cars, err := service.carStore.GetCarsByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		byID := make(map[string]models.Car, len(cars))
+		for i := range cars {
+			byID[cars[i].ID.String()] = cars[i]
+		}
+
+		for i := range patches {
+			if results[i].Status != 0 {
+				continue
+			}
+
+			prev, ok := byID[patches[i].ID]
+			if !ok {
+				results[i] = batchError(ctx, patches[i].ID, errors.EntityNotFound{Entity: "Car", ID: patches[i].ID})
+				continue
+			}
+
+			car, err := patch(ctx, &prev, patches[i].Patch)
+			if err != nil {
+				results[i] = batchError(ctx, patches[i].ID, err)
+				continue
+			}
+
+			c, err := service.update(ctx, patches[i].ID, &car, &prev)
+			if err != nil {
+				failed = i
+				return err
+			}
+
+			results[i] = models.BatchResult{ID: patches[i].ID, Status: http.StatusOK, Car: &c}
+			updated = append(updated, i)
+		}
+
+		return nil
+	})
+
+	switch {
+	case err != nil && failed == -1:
+		return nil, err
+	case err != nil:
+		// the updates written before the failed one are rolled back and the ones after it were not run
+		for i := range results {
+			if i != failed && (results[i].Status == 0 || results[i].Status == http.StatusOK) {
+				results[i] = models.BatchResult{ID: patches[i].ID, Status: http.StatusFailedDependency,
+					Code: "ROLLED_BACK", Error: "not updated, another update of the batch failed"}
+			}
+		}
+
+		results[failed] = batchError(ctx, patches[failed].ID, err)
+
+		return results, nil
+	}
+
+	for _, i := range updated {
+		service.indexCar(ctx, *results[i].Car)
+		redact(ctx, results[i].Car)
+	}
+
+	return results, nil
+}
+
+// patch applies the fields present in p to a copy of the car prev and checks the result like an update
+func patch(ctx *gofr.Context, prev *models.Car, p json.RawMessage) (models.Car, error) {
+	// only the fields of the patch are checked for permissions, the car keeps the cost price it has
+	var fields models.Car
+	if err := json.Unmarshal(p, &fields); err != nil {
+		return models.Car{}, errors.InvalidParam{Param: []string{"Patch"}}
+	}
+
+	if err := checkCostWrite(ctx, &fields); err != nil {
+		return models.Car{}, err
+	}
+
+	car := *prev
+	_ = json.Unmarshal(p, &car)
+
+	car.ID, car.Engine.EngineID = prev.ID, prev.Engine.EngineID
+	car.UpdatedAt, car.Engine.UpdatedAt, car.Media = nil, nil, nil
+
+	if err := checkStatus(&car); err != nil {
+		return models.Car{}, err
+	}
+
+	if err := checkVIN(&car); err != nil {
+		return models.Car{}, err
+	}
+
+	return car, nil
+}
+
+// batchError is the result of an item of a batch that failed with err, with the status and code err would have
+// been responded with on its own
+func batchError(ctx *gofr.Context, id string, err error) models.BatchResult {
+	resp := problem.Of(ctx, err)
+
+	return models.BatchResult{ID: id, Status: resp.StatusCode, Code: resp.Code, Error: resp.Reason}
+}
@@ -0,0 +1,187 @@
+package car
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/problem"
+	"Project/CarDealearship/stores"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBatchGet to test the cars of a batch are read with a single query and every id gets its own result
+func TestBatchGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	carService := New(mockCar, stores.NewMockEngine(ctrl), stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		stores.NewMockSearchIndex(ctrl), stores.NewMockTransaction(ctrl), stores.NewMockOutbox(ctrl), nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	cost := 30000
+	found, missing := uuid.New(), uuid.New().String()
+	car := models.Car{ID: found, Name: "Roma", Year: 2021, Brand: "Ferrari", CostPrice: &cost}
+	ids := []string{found.String(), "bad", missing}
+
+	mockCar.EXPECT().GetCarsByIDs(ctx, []string{found.String(), missing}).Return([]models.Car{car}, nil)
+	mockCar.EXPECT().GetCarsByIDs(ctx, gomock.Any()).Return(nil, errors.Error("db down"))
+
+	// the principal of ctx cannot read the cost price
+	car.CostPrice = nil
+
+	res, err := carService.BatchGet(ctx, ids)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.BatchResult{
+		{ID: found.String(), Status: http.StatusOK, Car: &car},
+		{ID: "bad", Status: http.StatusBadRequest, Code: problem.CodeInvalidParam,
+			Error: errors.InvalidParam{Param: []string{"ID"}}.Error()},
+		{ID: missing, Status: http.StatusNotFound, Code: "NOT_FOUND",
+			Error: errors.EntityNotFound{Entity: "Car", ID: missing}.Error()},
+	}, res)
+
+	res, err = carService.BatchGet(ctx, ids)
+
+	assert.Equal(t, errors.Error("db down"), err)
+	assert.Equal(t, []models.BatchResult(nil), res)
+}
+
+// TestBatchUpdate to test the patches of a batch are applied in a transaction with a result for every patch
+func TestBatchUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockIndex := stores.NewMockSearchIndex(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	carService := New(mockCar, mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl), mockIndex, mockTx,
+		mockOutbox, nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	a, b, missing := uuid.New(), uuid.New(), uuid.New().String()
+	carA := models.Car{ID: a, Name: "X5", Year: 2019, Brand: "BMW", Status: models.CarAvailable,
+		Engine: models.Engine{EngineID: a, Displacement: 3000, Cylinders: 6}}
+	carB := models.Car{ID: b, Name: "Model 3", Year: 2020, Brand: "Tesla", Status: models.CarAvailable,
+		Engine: models.Engine{EngineID: b, Range: 500}}
+	soldA := carA
+	soldA.Status = models.CarSold
+
+	patches := []models.CarPatch{
+		{ID: a.String(), Patch: json.RawMessage(`{"Status":"sold","ID":"` + b.String() + `"}`)},
+		{ID: missing, Patch: json.RawMessage(`{}`)},
+		{ID: "bad", Patch: json.RawMessage(`{}`)},
+		{ID: a.String(), Patch: json.RawMessage(`{"Year":2020}`)},
+		{ID: b.String(), Patch: json.RawMessage(`{"CostPrice":1}`)},
+	}
+
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+	mockCar.EXPECT().GetCarsByIDs(ctx, []string{a.String(), missing, b.String()}).
+		Return([]models.Car{carA, carB}, nil)
+	mockCar.EXPECT().UpdateCar(ctx, a.String(), &soldA).Return(soldA, nil)
+	mockEngine.EXPECT().EngineUpdate(ctx, a.String(), &soldA.Engine).Return(carA.Engine, nil)
+	mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarUpdated, soldA)).Return(nil)
+	mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarStatusChanged, nil)).Return(nil)
+	mockIndex.EXPECT().Index(ctx, soldA).Return(nil)
+
+	res, err := carService.BatchUpdate(ctx, patches)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.BatchResult{
+		{ID: a.String(), Status: http.StatusOK, Car: &soldA},
+		{ID: missing, Status: http.StatusNotFound, Code: "NOT_FOUND",
+			Error: errors.EntityNotFound{Entity: "Car", ID: missing}.Error()},
+		{ID: "bad", Status: http.StatusBadRequest, Code: problem.CodeInvalidParam,
+			Error: errors.InvalidParam{Param: []string{"ID"}}.Error()},
+		{ID: a.String(), Status: http.StatusBadRequest, Code: "DUPLICATE_ITEM",
+			Error: "the car is updated by an earlier item of the batch"},
+		{ID: b.String(), Status: http.StatusForbidden, Code: "FORBIDDEN",
+			Error: "missing permission cars:cost:write"},
+	}, res)
+}
+
+// TestBatchUpdateRollback to test a failed write rolls back the batch and the other patches are reported
+func TestBatchUpdateRollback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	carService := New(mockCar, mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		stores.NewMockSearchIndex(ctrl), mockTx, mockOutbox, nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	cars := []models.Car{{ID: a, Name: "F8", Brand: "Ferrari"}, {ID: b, Name: "SF90", Brand: "Ferrari"},
+		{ID: c, Name: "Roma", Brand: "Ferrari"}}
+	patches := []models.CarPatch{{ID: a.String(), Patch: json.RawMessage(`{"Year":2021}`)},
+		{ID: b.String(), Patch: json.RawMessage(`{"Year":2022}`)},
+		{ID: c.String(), Patch: json.RawMessage(`{"Year":2023}`)}}
+
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction).Times(2)
+	mockCar.EXPECT().GetCarsByIDs(ctx, []string{a.String(), b.String(), c.String()}).Return(cars, nil)
+	mockCar.EXPECT().UpdateCar(ctx, a.String(), gomock.Any()).
+		DoAndReturn(func(_ *gofr.Context, _ string, c *models.Car) (models.Car, error) { return *c, nil })
+	mockEngine.EXPECT().EngineUpdate(ctx, a.String(), gomock.Any()).Return(models.Engine{EngineID: a}, nil)
+	mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarUpdated, nil)).Return(nil)
+	mockCar.EXPECT().UpdateCar(ctx, b.String(), gomock.Any()).Return(models.Car{}, errors.Error("db down"))
+	mockCar.EXPECT().GetCarsByIDs(ctx, gomock.Any()).Return(nil, errors.Error("db down"))
+
+	res, err := carService.BatchUpdate(ctx, patches)
+
+	rolledBack := "not updated, another update of the batch failed"
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.BatchResult{
+		{ID: a.String(), Status: http.StatusFailedDependency, Code: "ROLLED_BACK", Error: rolledBack},
+		{ID: b.String(), Status: http.StatusInternalServerError, Code: "INTERNAL", Error: "internal error"},
+		{ID: c.String(), Status: http.StatusFailedDependency, Code: "ROLLED_BACK", Error: rolledBack},
+	}, res)
+
+	res, err = carService.BatchUpdate(ctx, patches)
+
+	assert.Equal(t, errors.Error("db down"), err)
+	assert.Equal(t, []models.BatchResult(nil), res)
+}
+
+// TestPatch to test a patch only changes the fields it has and is checked like an update
+func TestPatch(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	id := uuid.New()
+	prev := models.Car{ID: id, Name: "Cayenne", Year: 2019, Brand: "Porsche", VIN: "WP1ZZZ9YZKDA00000",
+		Engine: models.Engine{EngineID: id, Displacement: 3000, Cylinders: 6}}
+
+	testCases := []struct {
+		desc  string
+		patch string
+		want  models.Car
+		err   error
+	}{
+		{desc: "year and engine", patch: `{"Year":2020,"Engine":{"cylinders":8}}`,
+			want: models.Car{ID: id, Name: "Cayenne", Year: 2020, Brand: "Porsche", VIN: "WP1ZZZ9YZKDA00000",
+				Engine: models.Engine{EngineID: id, Displacement: 3000, Cylinders: 8}}},
+		{desc: "invalid json", patch: `[]`, err: errors.InvalidParam{Param: []string{"Patch"}}},
+		{desc: "invalid status", patch: `{"Status":"leased"}`, err: errors.InvalidParam{Param: []string{"Status"}}},
+		{desc: "invalid vin", patch: `{"VIN":"WP1"}`, err: errors.InvalidParam{Param: []string{"VIN"}}},
+	}
+
+	for i, tc := range testCases {
+		c := prev
+
+		res, err := patch(ctx, &c, json.RawMessage(tc.patch))
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.want, res, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, prev, c, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
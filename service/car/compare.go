@@ -0,0 +1,131 @@
+package car
+
+import (
+	"Project/CarDealearship/models"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+)
+
+const (
+	minCompare = 2
+	maxCompare = 3
+)
+
+// attribute extracts one row of the comparison matrix from a car, nil marks a value that does not apply
+type attribute struct {
+	name  string
+	value func(c models.Car, year int) interface{}
+}
+
+var attributes = []attribute{
+	{"Name", func(c models.Car, _ int) interface{} { return c.Name }},
+	{"Brand", func(c models.Car, _ int) interface{} { return c.Brand }},
+	{"Year", func(c models.Car, _ int) interface{} { return c.Year }},
+	{"Age", func(c models.Car, year int) interface{} { return year - c.Year }},
+	{"FuelType", func(c models.Car, _ int) interface{} { return c.FuelType }},
+	{"Displacement", func(c models.Car, _ int) interface{} { return c.Engine.Displacement }},
+	{"Cylinders", func(c models.Car, _ int) interface{} { return c.Engine.Cylinders }},
+	{"Range", func(c models.Car, _ int) interface{} { return c.Engine.Range }},
+	{"DisplacementPerCylinder", displacementPerCylinder},
+}
+
+// Compare is a service layer function to load a few cars with their engines in one query and line up
+// their attributes. Every id that does not exist is reported in the EntityNotFound error.
+func (service service) Compare(ctx *gofr.Context, ids []string) (models.Comparison, error) {
+	ids = unique(ids)
+	if len(ids) < minCompare || len(ids) > maxCompare {
+		return models.Comparison{}, errors.InvalidParam{Param: []string{"ids"}}
+	}
+
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return models.Comparison{}, errors.InvalidParam{Param: []string{"ids"}}
+		}
+	}
+
+	cars, err := service.carStore.GetCarsByIDs(ctx, ids)
+	if err != nil {
+		return models.Comparison{}, err
+	}
+
+	byID := make(map[string]models.Car, len(cars))
+	for i := range cars {
+		byID[cars[i].ID.String()] = cars[i]
+	}
+
+	ordered := make([]models.Car, 0, len(ids))
+
+	var missing []string
+
+	for _, id := range ids {
+		c, ok := byID[uuid.MustParse(id).String()]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+
+		redact(ctx, &c)
+		ordered = append(ordered, c)
+	}
+
+	if len(missing) > 0 {
+		return models.Comparison{}, errors.EntityNotFound{Entity: "Car", ID: strings.Join(missing, ",")}
+	}
+
+	return compare(ordered, time.Now().Year()), nil
+}
+
+// compare builds the comparison matrix of the cars as of the given year
+func compare(cars []models.Car, year int) models.Comparison {
+	rows := make([]models.ComparedAttribute, 0, len(attributes))
+
+	for _, a := range attributes {
+		row := models.ComparedAttribute{Name: a.name, Values: make([]interface{}, len(cars))}
+
+		for i := range cars {
+			row.Values[i] = a.value(cars[i], year)
+
+			if i > 0 && !reflect.DeepEqual(row.Values[i], row.Values[0]) {
+				row.Differs = true
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return models.Comparison{Cars: cars, Attributes: rows}
+}
+
+// displacementPerCylinder is rounded to two decimals, it does not apply to engines without cylinders
+func displacementPerCylinder(c models.Car, _ int) interface{} {
+	if c.Engine.Cylinders == 0 {
+		return nil
+	}
+
+	return math.Round(float64(c.Engine.Displacement)/float64(c.Engine.Cylinders)*100) / 100
+}
+
+// unique drops empty and repeated ids keeping the order of their first occurrence
+func unique(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	res := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+
+		seen[id] = true
+
+		res = append(res, id)
+	}
+
+	return res
+}
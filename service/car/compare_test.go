@@ -0,0 +1,91 @@
+package car
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompare to test the Compare service
+func TestCompare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	carService := New(mockCar, stores.NewMockEngine(ctrl), stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		stores.NewMockSearchIndex(ctrl), stores.NewMockTransaction(ctrl), stores.NewMockOutbox(ctrl), nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	id1 := uuid.New()
+	id2 := uuid.New()
+	missing := uuid.New()
+
+	c1 := models.Car{ID: id1, Name: "X5", Year: 2019, Brand: "BMW", FuelType: "Diesel",
+		Engine: models.Engine{EngineID: id1, Displacement: 3000, Cylinders: 6}}
+	c2 := models.Car{ID: id2, Name: "Cayenne", Year: 2019, Brand: "Porsche", FuelType: "Petrol",
+		Engine: models.Engine{EngineID: id2, Displacement: 2900, Cylinders: 6}}
+
+	mockCar.EXPECT().GetCarsByIDs(ctx, []string{id2.String(), id1.String()}).Return([]models.Car{c1, c2}, nil)
+	mockCar.EXPECT().GetCarsByIDs(ctx, []string{id1.String(), missing.String()}).Return([]models.Car{c1}, nil)
+	mockCar.EXPECT().GetCarsByIDs(ctx, []string{id1.String(), id2.String()}).Return(nil, errors.Error("db error"))
+
+	testCases := []struct {
+		desc string
+		ids  []string
+		cars []models.Car
+		err  error
+	}{
+		{desc: "success keeps requested order", ids: []string{id2.String(), id1.String(), id2.String()},
+			cars: []models.Car{c2, c1}},
+		{desc: "missing cars are listed", ids: []string{id1.String(), missing.String()},
+			err: errors.EntityNotFound{Entity: "Car", ID: missing.String()}},
+		{desc: "store error", ids: []string{id1.String(), id2.String()}, err: errors.Error("db error")},
+		{desc: "single car", ids: []string{id1.String()}, err: errors.InvalidParam{Param: []string{"ids"}}},
+		{desc: "too many cars", ids: []string{uuid.NewString(), uuid.NewString(), uuid.NewString(), uuid.NewString()},
+			err: errors.InvalidParam{Param: []string{"ids"}}},
+		{desc: "invalid id", ids: []string{id1.String(), "abc"}, err: errors.InvalidParam{Param: []string{"ids"}}},
+	}
+
+	for i, tc := range testCases {
+		res, err := carService.Compare(ctx, tc.ids)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.cars, res.Cars, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestCompareMatrix to test the attribute matrix and the derived metrics
+func TestCompareMatrix(t *testing.T) {
+	tesla := models.Car{Name: "Model 3", Year: 2020, Brand: "Tesla", FuelType: "Electric",
+		Engine: models.Engine{Range: 500}}
+	bmw := models.Car{Name: "M3", Year: 2018, Brand: "BMW", FuelType: "Petrol",
+		Engine: models.Engine{Displacement: 2993, Cylinders: 6}}
+
+	res := compare([]models.Car{tesla, bmw}, 2022)
+
+	expected := []models.ComparedAttribute{
+		{Name: "Name", Values: []interface{}{"Model 3", "M3"}, Differs: true},
+		{Name: "Brand", Values: []interface{}{"Tesla", "BMW"}, Differs: true},
+		{Name: "Year", Values: []interface{}{2020, 2018}, Differs: true},
+		{Name: "Age", Values: []interface{}{2, 4}, Differs: true},
+		{Name: "FuelType", Values: []interface{}{"Electric", "Petrol"}, Differs: true},
+		{Name: "Displacement", Values: []interface{}{0, 2993}, Differs: true},
+		{Name: "Cylinders", Values: []interface{}{0, 6}, Differs: true},
+		{Name: "Range", Values: []interface{}{500, 0}, Differs: true},
+		{Name: "DisplacementPerCylinder", Values: []interface{}{nil, 498.83}, Differs: true},
+	}
+
+	assert.Equal(t, expected, res.Attributes)
+
+	same := compare([]models.Car{bmw, bmw}, time.Now().Year())
+	for _, a := range same.Attributes {
+		assert.Equal(t, false, a.Differs, a.Name)
+	}
+}
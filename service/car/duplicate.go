@@ -0,0 +1,183 @@
+package car
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+)
+
+// vinLength is the length of a vehicle identification number, which has no I, O or Q to tell it from 1 and 0
+const vinLength = 17
+
+// Duplicates is a service layer function to report the cars likely entered more than once. VINs are unique, so
+// the cars are grouped on their spec. The cars are streamed from the store by brand, year and dealership, which
+// every car of a group shares, so that only the cars sharing them are held at once.
+func (service service) Duplicates(ctx *gofr.Context) ([]models.DuplicateGroup, error) {
+	var (
+		res    = make([]models.DuplicateGroup, 0)
+		bucket string
+		cars   []models.Car
+	)
+
+	err := service.carStore.StreamCarsBySpec(ctx, func(c models.Car) error {
+		if key := bucketKey(&c); key != bucket {
+			res = append(res, specGroups(cars)...)
+			bucket, cars = key, nil
+		}
+
+		redact(ctx, &c)
+		cars = append(cars, c)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(res, specGroups(cars)...), nil
+}
+
+// specGroups groups cars sharing a brand, year and dealership on their spec, keeping the groups of more than one car
+func specGroups(cars []models.Car) []models.DuplicateGroup {
+	var keys []string
+
+	groups := make(map[string][]models.Car)
+
+	for i := range cars {
+		key := specKey(&cars[i])
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		groups[key] = append(groups[key], cars[i])
+	}
+
+	var res []models.DuplicateGroup
+
+	for _, key := range keys {
+		if len(groups[key]) > 1 {
+			res = append(res, models.DuplicateGroup{Reason: models.DuplicateSpec, Cars: groups[key]})
+		}
+	}
+
+	return res
+}
+
+// checkDuplicate rejects a car that is likely a car entered before, naming the first one found
+func (service service) checkDuplicate(ctx *gofr.Context, c *models.Car) error {
+	candidates, err := service.carStore.GetDuplicateCandidates(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	for i := range candidates {
+		if reason := duplicateReason(c, &candidates[i]); reason != "" {
+			return duplicateError(candidates[i].ID, reason)
+		}
+	}
+
+	return nil
+}
+
+// duplicateReason tells why two cars are taken for the same physical car, or returns an empty string when they
+// are not
+func duplicateReason(c, other *models.Car) string {
+	switch {
+	case c.VIN != "" && c.VIN == other.VIN:
+		return models.DuplicateVIN
+	case specKey(c) == specKey(other):
+		return models.DuplicateSpec
+	default:
+		return ""
+	}
+}
+
+// bucketKey identifies the brand, year and dealership of a car, which the cars of a spec group share
+func bucketKey(c *models.Car) string {
+	dealership := ""
+	if c.DealershipID != nil {
+		dealership = c.DealershipID.String()
+	}
+
+	return fmt.Sprintf("%v|%v|%v", dealership, strings.ToLower(c.Brand), c.Year)
+}
+
+// specKey identifies a car by its dealership, brand, year, name and engine. Names are compared on their letters
+// and digits only, so that "Model 3", "model-3" and "Model3" are the same name.
+func specKey(c *models.Car) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, c.Name)
+
+	return fmt.Sprintf("%v|%v|%v|%v|%v", bucketKey(c), name, c.Engine.Displacement, c.Engine.Cylinders,
+		c.Engine.Range)
+}
+
+// vinConflict turns the error of a car written with the VIN of another car into the error a duplicate is
+// rejected with, naming the other car when it is found. id is the car written, empty for a new car.
+func (service service) vinConflict(ctx *gofr.Context, id, vin string, err error) error {
+	if err != stores.ErrDuplicateVIN {
+		return err
+	}
+
+	candidates, _ := service.carStore.GetDuplicateCandidates(ctx, &models.Car{VIN: vin})
+
+	for i := range candidates {
+		if candidates[i].VIN == vin && candidates[i].ID.String() != id {
+			return duplicateError(candidates[i].ID, models.DuplicateVIN)
+		}
+	}
+
+	return &errors.Response{
+		StatusCode: http.StatusConflict,
+		Code:       "DUPLICATE_CAR",
+		Reason:     "another car has the same VIN, the car is likely a duplicate of it",
+		Detail:     models.DuplicateVIN,
+	}
+}
+
+func duplicateError(id uuid.UUID, reason string) error {
+	what := "the same VIN"
+	if reason == models.DuplicateSpec {
+		what = "the same brand, name, year and engine in the same dealership"
+	}
+
+	return &errors.Response{
+		StatusCode: http.StatusConflict,
+		Code:       "DUPLICATE_CAR",
+		Reason:     fmt.Sprintf("car %v has %v, the car is likely a duplicate of it", id, what),
+		ResourceID: id.String(),
+		Detail:     reason,
+	}
+}
+
+// checkVIN normalizes the VIN of a car to upper case and checks it is well formed, a car need not have one
+func checkVIN(c *models.Car) error {
+	c.VIN = strings.ToUpper(strings.TrimSpace(c.VIN))
+	if c.VIN == "" {
+		return nil
+	}
+
+	if len(c.VIN) != vinLength || strings.ContainsAny(c.VIN, "IOQ") {
+		return errors.InvalidParam{Param: []string{"VIN"}}
+	}
+
+	for _, r := range c.VIN {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return errors.InvalidParam{Param: []string{"VIN"}}
+		}
+	}
+
+	return nil
+}
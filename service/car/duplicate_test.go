@@ -0,0 +1,231 @@
+package car
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"net/http"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateDuplicate to test that Create rejects likely duplicates unless they are allowed
+func TestCreateDuplicate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockIndex := stores.NewMockSearchIndex(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	carService := New(mockCar, mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl), mockIndex, mockTx,
+		mockOutbox, nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	dealer := uuid.New()
+	existing := uuid.New()
+	engine := models.Engine{Displacement: 3000, Cylinders: 6}
+	input := models.Car{Name: "X5 xDrive", Year: 2020, Brand: "BMW", FuelType: "Petrol", DealershipID: &dealer,
+		VIN: "wbakS4100k0000000", Engine: engine}
+
+	testCases := []struct {
+		desc           string
+		allowDuplicate bool
+		candidates     []models.Car
+		candidatesErr  error
+		err            error
+	}{
+		{desc: "same vin", candidates: []models.Car{{ID: existing, Name: "X6", Year: 2021, Brand: "BMW",
+			VIN: "WBAKS4100K0000000"}}, err: duplicateError(existing, models.DuplicateVIN)},
+		{desc: "same spec with another name format", candidates: []models.Car{{ID: existing, Name: "x5-XDRIVE",
+			Year: 2020, Brand: "BMW", DealershipID: &dealer, Engine: engine}},
+			err: duplicateError(existing, models.DuplicateSpec)},
+		{desc: "another engine", candidates: []models.Car{{ID: existing, Name: "X5 xDrive", Year: 2020,
+			Brand: "BMW", DealershipID: &dealer, Engine: models.Engine{Displacement: 4400, Cylinders: 8}}}},
+		{desc: "duplicate allowed", allowDuplicate: true},
+		{desc: "candidates error", candidatesErr: errors.Error("db down"), err: errors.Error("db down")},
+	}
+
+	for i, tc := range testCases {
+		if !tc.allowDuplicate {
+			mockCar.EXPECT().GetDuplicateCandidates(ctx, gomock.Any()).Return(tc.candidates, tc.candidatesErr)
+		}
+
+		if tc.err == nil {
+			id := uuid.New()
+
+			mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+			mockEngine.EXPECT().EngineCreate(ctx, gomock.Any()).Return(models.Engine{EngineID: id}, nil)
+			mockCar.EXPECT().CreateCar(ctx, gomock.Any()).
+				DoAndReturn(func(_ *gofr.Context, c *models.Car) (models.Car, error) { return *c, nil })
+			mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarCreated, nil)).Return(nil)
+			mockIndex.EXPECT().Index(ctx, gomock.Any()).Return(nil)
+		}
+
+		car := input
+		res, err := carService.Create(ctx, &car, tc.allowDuplicate)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.err == nil {
+			assert.Equal(t, "WBAKS4100K0000000", res.VIN, "TEST[%d], failed.\n%s", i, tc.desc)
+		}
+	}
+}
+
+// TestDuplicateError to test the error a duplicate is rejected with refers to the existing car
+func TestDuplicateError(t *testing.T) {
+	id := uuid.New()
+
+	err, ok := duplicateError(id, models.DuplicateVIN).(*errors.Response)
+
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, err.StatusCode)
+	assert.Equal(t, "DUPLICATE_CAR", err.Code)
+	assert.Equal(t, id.String(), err.ResourceID)
+	assert.Equal(t, models.DuplicateVIN, err.Detail)
+}
+
+// TestVINConflict to test that a car written with the VIN of another car is rejected as a duplicate of it, even
+// when duplicates are allowed
+func TestVINConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	carService := New(mockCar, mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		stores.NewMockSearchIndex(ctrl), mockTx, stores.NewMockOutbox(ctrl), nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	existing := uuid.New()
+	vin := "WBAKS4100K0000000"
+	car := models.Car{Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Petrol", VIN: vin}
+
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+	mockEngine.EXPECT().EngineCreate(ctx, gomock.Any()).Return(models.Engine{EngineID: uuid.New()}, nil)
+	mockCar.EXPECT().CreateCar(ctx, gomock.Any()).Return(models.Car{}, stores.ErrDuplicateVIN)
+	mockCar.EXPECT().GetDuplicateCandidates(ctx, &models.Car{VIN: vin}).
+		Return([]models.Car{{ID: existing, VIN: vin}}, nil)
+
+	_, err := carService.Create(ctx, &car, true)
+
+	assert.Equal(t, duplicateError(existing, models.DuplicateVIN), err)
+
+	testCases := []struct {
+		desc       string
+		id         string
+		candidates []models.Car
+		err        error
+		expected   error
+	}{
+		{desc: "another error", err: errors.Error("db down"), expected: errors.Error("db down")},
+		{desc: "other car found", id: uuid.NewString(), candidates: []models.Car{{ID: existing, VIN: vin}},
+			err: stores.ErrDuplicateVIN, expected: duplicateError(existing, models.DuplicateVIN)},
+		{desc: "only the car written found", id: existing.String(), candidates: []models.Car{{ID: existing, VIN: vin}},
+			err: stores.ErrDuplicateVIN, expected: &errors.Response{StatusCode: http.StatusConflict,
+				Code: "DUPLICATE_CAR", Reason: "another car has the same VIN, the car is likely a duplicate of it",
+				Detail: models.DuplicateVIN}},
+	}
+
+	for i, tc := range testCases {
+		if tc.err == stores.ErrDuplicateVIN {
+			mockCar.EXPECT().GetDuplicateCandidates(ctx, &models.Car{VIN: vin}).Return(tc.candidates, nil)
+		}
+
+		err := carService.vinConflict(ctx, tc.id, vin, tc.err)
+
+		assert.Equal(t, tc.expected, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestDuplicates to test the report of the cars likely entered more than once
+func TestDuplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	carService := New(mockCar, stores.NewMockEngine(ctrl), stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		stores.NewMockSearchIndex(ctrl), stores.NewMockTransaction(ctrl), stores.NewMockOutbox(ctrl), nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	cost := 50000
+	dealer := uuid.New()
+	engine := models.Engine{Displacement: 3000, Cylinders: 6}
+	c1 := models.Car{ID: uuid.New(), Name: "911 Carrera", Year: 2018, Brand: "Porsche", VIN: "WP0ZZZ99ZJS100000",
+		Engine: engine, CostPrice: &cost}
+	c2 := models.Car{ID: uuid.New(), Name: "Cayenne", Year: 2018, Brand: "Porsche"}
+	c3 := models.Car{ID: uuid.New(), Name: "911-carrera", Year: 2018, Brand: "PORSCHE", Engine: engine}
+	c4 := models.Car{ID: uuid.New(), Name: "911 Carrera", Year: 2018, Brand: "Porsche", Engine: engine,
+		DealershipID: &dealer}
+	c5 := models.Car{ID: uuid.New(), Name: "Taycan", Year: 2021, Brand: "Porsche"}
+	c6 := models.Car{ID: uuid.New(), Name: "Taycan", Year: 2021, Brand: "Porsche"}
+
+	stream := func(cars ...models.Car) func(*gofr.Context, func(models.Car) error) error {
+		return func(_ *gofr.Context, fn func(models.Car) error) error {
+			for i := range cars {
+				if err := fn(cars[i]); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	}
+
+	mockCar.EXPECT().StreamCarsBySpec(ctx, gomock.Any()).DoAndReturn(stream(c1, c2, c3, c4, c5, c6))
+	mockCar.EXPECT().StreamCarsBySpec(ctx, gomock.Any()).DoAndReturn(stream())
+	mockCar.EXPECT().StreamCarsBySpec(ctx, gomock.Any()).Return(errors.Error("db down"))
+
+	// the principal of ctx cannot read the cost price
+	c1.CostPrice = nil
+
+	res, err := carService.Duplicates(ctx)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.DuplicateGroup{
+		{Reason: models.DuplicateSpec, Cars: []models.Car{c1, c3}},
+		{Reason: models.DuplicateSpec, Cars: []models.Car{c5, c6}},
+	}, res)
+
+	res, err = carService.Duplicates(ctx)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.DuplicateGroup{}, res)
+
+	res, err = carService.Duplicates(ctx)
+
+	assert.Equal(t, errors.Error("db down"), err)
+	assert.Equal(t, []models.DuplicateGroup(nil), res)
+}
+
+// TestCheckVIN to test the VIN of a car is normalized and checked
+func TestCheckVIN(t *testing.T) {
+	testCases := []struct {
+		desc string
+		vin  string
+		want string
+		err  error
+	}{
+		{desc: "no vin"},
+		{desc: "lower case", vin: " 5yj3e1ea7kf317000 ", want: "5YJ3E1EA7KF317000"},
+		{desc: "too short", vin: "5YJ3E1EA7", want: "5YJ3E1EA7", err: errors.InvalidParam{Param: []string{"VIN"}}},
+		{desc: "letter O", vin: "5YJ3E1EA7KF3170O0", want: "5YJ3E1EA7KF3170O0",
+			err: errors.InvalidParam{Param: []string{"VIN"}}},
+		{desc: "symbol", vin: "5YJ3E1EA7KF3170-0", want: "5YJ3E1EA7KF3170-0",
+			err: errors.InvalidParam{Param: []string{"VIN"}}},
+	}
+
+	for i, tc := range testCases {
+		c := models.Car{VIN: tc.vin}
+
+		assert.Equal(t, tc.err, checkVIN(&c), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.want, c.VIN, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
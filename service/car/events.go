@@ -0,0 +1,40 @@
+package car
+
+import (
+	"Project/CarDealearship/metrics"
+	"Project/CarDealearship/models"
+	"encoding/json"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+)
+
+// emit records an event in the outbox. It must run inside the transaction of the change it describes, the
+// relay publishes it once that transaction commits. Events carry the whole car, cost price included, since
+// they are read by back office systems rather than by the principal of ctx.
+func (service service) emit(ctx *gofr.Context, eventType string, carID uuid.UUID, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return service.outbox.AddEvent(ctx, &models.Event{ID: uuid.New(), Type: eventType, CarID: carID,
+		OccurredAt: time.Now().UTC(), Data: payload})
+}
+
+// emitUpdate records a CarUpdated event, followed by a CarStatusChanged one when the update changed the status
+func (service service) emitUpdate(ctx *gofr.Context, prev, c *models.Car) error {
+	if err := service.emit(ctx, models.EventCarUpdated, c.ID, c); err != nil {
+		return err
+	}
+
+	service.count(ctx, metrics.CarsUpdated, c)
+
+	if prev.Status == c.Status {
+		return nil
+	}
+
+	return service.emit(ctx, models.EventCarStatusChanged, c.ID, models.StatusChange{From: prev.Status, To: c.Status,
+		Brand: c.Brand, DealershipID: c.DealershipID})
+}
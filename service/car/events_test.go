@@ -0,0 +1,80 @@
+package car
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// eventMatcher matches an event of the given type, with the given data unless it is nil
+type eventMatcher struct {
+	eventType string
+	data      interface{}
+}
+
+func eventOf(eventType string, data interface{}) gomock.Matcher {
+	return eventMatcher{eventType: eventType, data: data}
+}
+
+func (m eventMatcher) Matches(x interface{}) bool {
+	e, ok := x.(*models.Event)
+	if !ok || e.Type != m.eventType || e.ID == uuid.Nil || e.OccurredAt.IsZero() {
+		return false
+	}
+
+	if m.data == nil {
+		return true
+	}
+
+	data, _ := json.Marshal(m.data)
+
+	return string(data) == string(e.Data)
+}
+
+func (m eventMatcher) String() string {
+	return fmt.Sprintf("is a %v event of %+v", m.eventType, m.data)
+}
+
+// TestEmit to test that emit records an event of the car in the outbox
+func TestEmit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	carService := New(stores.NewMockCar(ctrl), stores.NewMockEngine(ctrl), stores.NewMockMedia(ctrl),
+		stores.NewMockBlob(ctrl), stores.NewMockSearchIndex(ctrl), stores.NewMockTransaction(ctrl), mockOutbox, nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	cost := 18000
+	car := models.Car{ID: uuid.New(), Name: "X5", Brand: "BMW", Status: models.CarReserved, CostPrice: &cost}
+
+	var recorded *models.Event
+
+	mockOutbox.EXPECT().AddEvent(ctx, gomock.Any()).DoAndReturn(func(_ *gofr.Context, e *models.Event) error {
+		recorded = e
+		return nil
+	})
+	mockOutbox.EXPECT().AddEvent(ctx, gomock.Any()).Return(errors.Error("db down"))
+
+	err := carService.emit(ctx, models.EventCarCreated, car.ID, car)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, models.EventCarCreated, recorded.Type)
+	assert.Equal(t, car.ID, recorded.CarID)
+	assert.NotEqual(t, uuid.Nil, recorded.ID)
+	assert.JSONEq(t, `{"ID":"`+car.ID.String()+`","Engine":{"id":"00000000-0000-0000-0000-000000000000"},`+
+		`"Name":"X5","Year":0,"Brand":"BMW","FuelType":"","Status":"reserved","CostPrice":18000}`,
+		string(recorded.Data), "events are not redacted")
+
+	err = carService.emit(ctx, models.EventCarCreated, car.ID, car)
+	assert.Equal(t, errors.Error("db down"), err)
+}
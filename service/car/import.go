@@ -0,0 +1,137 @@
+package car
+
+import (
+	"Project/CarDealearship/metrics"
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+const importBatchSize = 100
+
+// Import is a service layer function to create many cars at once. Every row is validated with the same
+// rules as Create and the valid ones are created in transactions of importBatchSize rows; when a row of a
+// batch fails the whole batch is rolled back along with the events of its cars. In dry run mode rows are only
+// validated.
+func (service service) Import(ctx *gofr.Context, rows []models.ImportRow, dryRun bool) (models.ImportReport, error) {
+	report := models.ImportReport{DryRun: dryRun, Total: len(rows), Results: make([]models.ImportResult, len(rows))}
+
+	var batch []int
+
+	for i := range rows {
+		report.Results[i] = models.ImportResult{Row: rows[i].Row, Status: models.ImportValid}
+
+		if rows[i].Error == "" {
+			if err := checkVIN(&rows[i].Car); err != nil {
+				rows[i].Error = err.Error()
+			}
+		}
+
+		if rows[i].Error == "" {
+			car := rows[i].Car
+			if _, reason := validateCreateCar(&car); reason != "" {
+				service.reject(reason)
+				rows[i].Error = "invalid car: check brand, fuel type and year"
+			} else if err := checkCostWrite(ctx, &car); err != nil {
+				rows[i].Error = err.Error()
+			} else if err := checkStatus(&car); err != nil {
+				rows[i].Error = err.Error()
+			}
+		}
+
+		if rows[i].Error != "" {
+			report.Results[i].Status = models.ImportFailed
+			report.Results[i].Error = rows[i].Error
+
+			continue
+		}
+
+		if dryRun {
+			continue
+		}
+
+		batch = append(batch, i)
+
+		if len(batch) == importBatchSize {
+			service.importBatch(ctx, rows, batch, report.Results)
+			batch = nil
+		}
+	}
+
+	if len(batch) > 0 {
+		service.importBatch(ctx, rows, batch, report.Results)
+	}
+
+	for i := range report.Results {
+		if report.Results[i].Status == models.ImportFailed {
+			report.Failed++
+		} else {
+			report.Succeeded++
+		}
+	}
+
+	return report, nil
+}
+
+// importBatch creates the cars of the rows at the given indexes in one transaction and records the outcome
+func (service service) importBatch(ctx *gofr.Context, rows []models.ImportRow, batch []int,
+	results []models.ImportResult) {
+	created := make([]models.Car, 0, len(batch))
+	failed := -1
+
+	err := service.tx.WithTransaction(ctx, func(ctx *gofr.Context) error {
+		for _, i := range batch {
+			c := rows[i].Car
+			if c.Status == "" {
+				c.Status = models.CarAvailable
+			}
+
+			engine, err := service.engineStore.EngineCreate(ctx, &c.Engine)
+			if err != nil {
+				failed = i
+				return err
+			}
+
+			c.Engine = engine
+			c.ID = engine.EngineID
+
+			if c, err = service.carStore.CreateCar(ctx, &c); err != nil {
+				failed = i
+				return err
+			}
+
+			if err = service.emit(ctx, models.EventCarCreated, c.ID, c); err != nil {
+				failed = i
+				return err
+			}
+
+			service.count(ctx, metrics.CarsCreated, &c)
+
+			created = append(created, c)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		ctx.Logger.Errorf("error in importing batch: %v", err)
+
+		for _, i := range batch {
+			results[i].Status = models.ImportFailed
+			results[i].Error = "not imported, another row of its batch failed"
+
+			if i == failed {
+				results[i].Error = err.Error()
+			}
+		}
+
+		return
+	}
+
+	for n, i := range batch {
+		results[i].Status = models.ImportCreated
+		results[i].ID = created[n].ID.String()
+
+		service.indexCar(ctx, created[n])
+	}
+}
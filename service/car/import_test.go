@@ -0,0 +1,122 @@
+package car
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func runInTransaction(ctx *gofr.Context, fn func(ctx *gofr.Context) error) error {
+	return fn(ctx)
+}
+
+// TestImport to test the Import service
+func TestImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockIndex := stores.NewMockSearchIndex(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	carService := New(mockCar, mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl), mockIndex, mockTx,
+		mockOutbox, nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	id1 := uuid.New()
+	id2 := uuid.New()
+
+	rows := []models.ImportRow{
+		{Row: 1, Car: models.Car{Name: "X5", Year: 2019, Brand: "BMW", FuelType: "Diesel",
+			Engine: models.Engine{Displacement: 3000, Cylinders: 6}}},
+		{Row: 2, Car: models.Car{Name: "A4", Year: 2019, Brand: "Audi", FuelType: "Diesel"}},
+		{Row: 3, Error: "year: invalid syntax"},
+		{Row: 4, Car: models.Car{Name: "Model 3", Year: 2020, Brand: "Tesla", FuelType: "Electric",
+			Engine: models.Engine{Range: 500}}},
+	}
+
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+	mockEngine.EXPECT().EngineCreate(ctx, gomock.Any()).
+		Return(models.Engine{EngineID: id1, Displacement: 3000, Cylinders: 6}, nil)
+	mockCar.EXPECT().CreateCar(ctx, gomock.Any()).
+		DoAndReturn(func(_ *gofr.Context, c *models.Car) (models.Car, error) { return *c, nil }).Times(2)
+	mockEngine.EXPECT().EngineCreate(ctx, gomock.Any()).Return(models.Engine{EngineID: id2, Range: 500}, nil)
+	mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarCreated, nil)).Return(nil).Times(2)
+	mockIndex.EXPECT().Index(ctx, gomock.Any()).Return(nil).Times(2)
+
+	report, err := carService.Import(ctx, rows, false)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, models.ImportReport{Total: 4, Succeeded: 2, Failed: 2, Results: []models.ImportResult{
+		{Row: 1, Status: models.ImportCreated, ID: id1.String()},
+		{Row: 2, Status: models.ImportFailed, Error: "invalid car: check brand, fuel type and year"},
+		{Row: 3, Status: models.ImportFailed, Error: "year: invalid syntax"},
+		{Row: 4, Status: models.ImportCreated, ID: id2.String()},
+	}}, report)
+}
+
+// TestImportRollback to test that a failing row fails its whole batch
+func TestImportRollback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	carService := New(mockCar, mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		stores.NewMockSearchIndex(ctrl), mockTx, mockOutbox, nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	rows := []models.ImportRow{
+		{Row: 1, Car: models.Car{Name: "X5", Year: 2019, Brand: "BMW", FuelType: "Diesel"}},
+		{Row: 2, Car: models.Car{Name: "X6", Year: 2020, Brand: "BMW", FuelType: "Petrol"}},
+	}
+
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+	mockEngine.EXPECT().EngineCreate(ctx, gomock.Any()).Return(models.Engine{EngineID: uuid.New()}, nil)
+	mockCar.EXPECT().CreateCar(ctx, gomock.Any()).Return(models.Car{ID: uuid.New()}, nil)
+	mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarCreated, nil)).Return(nil)
+	mockEngine.EXPECT().EngineCreate(ctx, gomock.Any()).Return(models.Engine{}, errors.Error("deadlock"))
+
+	report, err := carService.Import(ctx, rows, false)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, models.ImportReport{Total: 2, Failed: 2, Results: []models.ImportResult{
+		{Row: 1, Status: models.ImportFailed, Error: "not imported, another row of its batch failed"},
+		{Row: 2, Status: models.ImportFailed, Error: "deadlock"},
+	}}, report)
+}
+
+// TestImportDryRun to test that a dry run only validates the rows
+func TestImportDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	carService := New(stores.NewMockCar(ctrl), stores.NewMockEngine(ctrl), stores.NewMockMedia(ctrl),
+		stores.NewMockBlob(ctrl), stores.NewMockSearchIndex(ctrl), stores.NewMockTransaction(ctrl),
+		stores.NewMockOutbox(ctrl), nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	rows := []models.ImportRow{
+		{Row: 1, Car: models.Car{Name: "X5", Year: 2019, Brand: "BMW", FuelType: "Diesel"}},
+		{Row: 2, Car: models.Car{Name: "X5", Year: 1800, Brand: "BMW", FuelType: "Diesel"}},
+		{Row: 3, Car: models.Car{Name: "X5", Year: 2019, Brand: "BMW", FuelType: "Diesel", VIN: "WBA0123"}},
+	}
+
+	report, err := carService.Import(ctx, rows, true)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, models.ImportReport{DryRun: true, Total: 3, Succeeded: 1, Failed: 2, Results: []models.ImportResult{
+		{Row: 1, Status: models.ImportValid},
+		{Row: 2, Status: models.ImportFailed, Error: "invalid car: check brand, fuel type and year"},
+		{Row: 3, Status: models.ImportFailed, Error: errors.InvalidParam{Param: []string{"VIN"}}.Error()},
+	}}, report)
+}
@@ -0,0 +1,64 @@
+package car
+
+import (
+	"Project/CarDealearship/metrics"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores/transaction"
+	"sync"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// inventory keeps the labels of the inventory gauges last set, so that the ones no longer counted are reset
+type inventory struct {
+	mu     sync.Mutex
+	labels map[[2]string]bool
+}
+
+// ReportInventory sets the inventory gauges to the number of cars by status and dealership. The gauges of a
+// status and dealership that no longer has cars are set to 0.
+func (service service) ReportInventory(ctx *gofr.Context) error {
+	counts, err := service.carStore.CountCars(ctx)
+	if err != nil {
+		return err
+	}
+
+	service.inventory.mu.Lock()
+	defer service.inventory.mu.Unlock()
+
+	labels := make(map[[2]string]bool, len(counts))
+
+	for _, c := range counts {
+		l := [2]string{c.Status, metrics.NoDealership}
+		if c.DealershipID != nil {
+			l[1] = c.DealershipID.String()
+		}
+
+		labels[l] = true
+		_ = service.metrics.SetGauge(metrics.Inventory, float64(c.Count), l[0], l[1])
+	}
+
+	for l := range service.inventory.labels {
+		if !labels[l] {
+			_ = service.metrics.SetGauge(metrics.Inventory, 0, l[0], l[1])
+		}
+	}
+
+	service.inventory.labels = labels
+
+	return nil
+}
+
+// count increments the counter metric of a change to the car once the transaction of ctx commits
+func (service service) count(ctx *gofr.Context, metric string, c *models.Car) {
+	brand, fuel := c.Brand, c.FuelType
+
+	transaction.AfterCommit(ctx, func() {
+		_ = service.metrics.IncCounter(metric, brand, fuel)
+	})
+}
+
+// reject counts a car rejected on create because of the invalid attribute reason
+func (service service) reject(reason string) {
+	_ = service.metrics.IncCounter(metrics.ValidationFailures, reason)
+}
@@ -0,0 +1,193 @@
+package car
+
+import (
+	"context"
+	"testing"
+
+	"Project/CarDealearship/metrics"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"Project/CarDealearship/stores/transaction"
+
+	"developer.zopsmart.com/go/gofr/pkg/datastore"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder keeps the counters and gauges set, keyed by name and labels
+type recorder struct {
+	counters map[string]int
+	gauges   map[string]float64
+}
+
+func newRecorder() *recorder {
+	return &recorder{counters: make(map[string]int), gauges: make(map[string]float64)}
+}
+
+func (r *recorder) IncCounter(name string, labels ...string) error {
+	r.counters[key(name, labels)]++
+	return nil
+}
+
+func (r *recorder) SetGauge(name string, value float64, labels ...string) error {
+	r.gauges[key(name, labels)] = value
+	return nil
+}
+
+func (r *recorder) ObserveHistogram(string, float64, ...string) error {
+	return nil
+}
+
+func key(name string, labels []string) string {
+	for _, l := range labels {
+		name += " " + l
+	}
+
+	return name
+}
+
+func TestCountChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockIndex := stores.NewMockSearchIndex(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	mockMedia := stores.NewMockMedia(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	m := newRecorder()
+	carService := New(mockCar, mockEngine, mockMedia, stores.NewMockBlob(ctrl), mockIndex, mockTx, mockOutbox, m)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	id := uuid.New()
+	c := models.Car{ID: id, Name: "Roma", Year: 2021, Brand: "Ferrari", FuelType: "Petrol",
+		Status: models.CarAvailable, Engine: models.Engine{EngineID: id}}
+	input := models.Car{Name: "Roma", Year: 2021, Brand: "Ferrari", FuelType: "Petrol"}
+
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction).Times(3)
+	mockCar.EXPECT().GetDuplicateCandidates(ctx, gomock.Any()).Return(nil, nil)
+	mockEngine.EXPECT().EngineCreate(ctx, gomock.Any()).Return(models.Engine{EngineID: id}, nil)
+	mockCar.EXPECT().CreateCar(ctx, gomock.Any()).Return(c, nil)
+	mockCar.EXPECT().GetCarByID(ctx, id.String()).Return(c, nil).Times(2)
+	mockCar.EXPECT().UpdateCar(ctx, id.String(), gomock.Any()).Return(c, nil)
+	mockEngine.EXPECT().EngineUpdate(ctx, id.String(), gomock.Any()).Return(c.Engine, nil)
+	mockMedia.EXPECT().GetMediaByCarID(ctx, id.String()).Return(nil, nil)
+	mockMedia.EXPECT().DeleteMediaByCarID(ctx, id.String()).Return(nil)
+	mockCar.EXPECT().DeleteCar(ctx, id.String()).Return(nil)
+	mockEngine.EXPECT().EngineDelete(ctx, id.String()).Return(nil)
+	mockOutbox.EXPECT().AddEvent(ctx, gomock.Any()).Return(nil).Times(3)
+	mockIndex.EXPECT().Index(ctx, gomock.Any()).Return(nil).Times(2)
+	mockIndex.EXPECT().Remove(ctx, id.String()).Return(nil)
+
+	_, err := carService.Create(ctx, &input, false)
+	assert.Equal(t, nil, err)
+
+	_, err = carService.Update(ctx, id.String(), &input)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, nil, carService.Delete(ctx, id.String()))
+
+	assert.Equal(t, map[string]int{"cars_created_total Ferrari Petrol": 1, "cars_updated_total Ferrari Petrol": 1,
+		"cars_deleted_total Ferrari Petrol": 1}, m.counters)
+}
+
+func TestCountRolledBack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	m := newRecorder()
+	carService := New(mockCar, mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		stores.NewMockSearchIndex(ctrl), transaction.New(), mockOutbox, m)
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	id := uuid.New()
+	c := models.Car{ID: id, Name: "Roma", Year: 2021, Brand: "Ferrari", FuelType: "Petrol",
+		Status: models.CarAvailable, Engine: models.Engine{EngineID: id}}
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mockEngine.EXPECT().EngineCreate(gomock.Any(), gomock.Any()).Return(models.Engine{EngineID: id}, nil)
+	mockCar.EXPECT().CreateCar(gomock.Any(), gomock.Any()).Return(c, nil)
+	mockOutbox.EXPECT().AddEvent(gomock.Any(), gomock.Any()).Return(errors.Error("db down"))
+
+	_, err := carService.Create(ctx, &models.Car{Name: "Roma", Year: 2021, Brand: "Ferrari", FuelType: "Petrol"},
+		true)
+
+	assert.Equal(t, errors.Error("db down"), err)
+	assert.Empty(t, m.counters, "a car is counted once its transaction commits")
+}
+
+func TestCountValidationFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := newRecorder()
+	carService := New(stores.NewMockCar(ctrl), stores.NewMockEngine(ctrl), stores.NewMockMedia(ctrl),
+		stores.NewMockBlob(ctrl), stores.NewMockSearchIndex(ctrl), stores.NewMockTransaction(ctrl),
+		stores.NewMockOutbox(ctrl), m)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	inputs := []models.Car{
+		{Name: "Roma", Year: 1800, Brand: "Ferrari", FuelType: "Petrol"},
+		{Name: "Roma", Year: 2021, Brand: "Fiat", FuelType: "Petrol"},
+		{Name: "Roma", Year: 2021, Brand: "Ferrari", FuelType: "Solar"},
+		{Name: "Roma", Year: 2021, Brand: "Fiat", FuelType: "Solar"},
+	}
+
+	for i := range inputs {
+		_, err := carService.Create(ctx, &inputs[i], false)
+
+		assert.Equal(t, errors.InvalidParam{}, err, "TEST[%d], failed.\n", i)
+	}
+
+	report, _ := carService.Import(ctx, []models.ImportRow{{Row: 2, Car: models.Car{Name: "Roma", Year: 2021,
+		Brand: "Ferrari"}}}, true)
+
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, map[string]int{"car_validation_failures_total year": 1, "car_validation_failures_total brand": 2,
+		"car_validation_failures_total fuel_type": 2}, m.counters)
+}
+
+func TestReportInventory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	m := newRecorder()
+	carService := New(mockCar, stores.NewMockEngine(ctrl), stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		stores.NewMockSearchIndex(ctrl), stores.NewMockTransaction(ctrl), stores.NewMockOutbox(ctrl), m)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	dealer := uuid.New()
+	available := "cars_inventory available " + dealer.String()
+
+	gomock.InOrder(
+		mockCar.EXPECT().CountCars(ctx).Return([]models.InventoryCount{
+			{Status: models.CarAvailable, DealershipID: &dealer, Count: 3}, {Status: models.CarSold, Count: 1}}, nil),
+		mockCar.EXPECT().CountCars(ctx).Return([]models.InventoryCount{
+			{Status: models.CarSold, Count: 4}}, nil),
+		mockCar.EXPECT().CountCars(ctx).Return(nil, errors.Error("db down")),
+	)
+
+	assert.Equal(t, nil, carService.ReportInventory(ctx))
+	assert.Equal(t, map[string]float64{available: 3, "cars_inventory sold " + metrics.NoDealership: 1}, m.gauges)
+
+	assert.Equal(t, nil, carService.ReportInventory(ctx))
+	assert.Equal(t, map[string]float64{available: 0, "cars_inventory sold none": 4}, m.gauges,
+		"the gauges of the cars no longer counted are reset")
+
+	assert.Equal(t, errors.Error("db down"), carService.ReportInventory(ctx))
+	assert.Equal(t, map[string]float64{available: 0, "cars_inventory sold none": 4}, m.gauges)
+}
@@ -1,9 +1,13 @@
 package car
 
 import (
+	"Project/CarDealearship/auth"
+	"Project/CarDealearship/metrics"
 	"Project/CarDealearship/models"
 	"Project/CarDealearship/stores"
+	"database/sql"
 	"reflect"
+	"strings"
 	"time"
 
 	"developer.zopsmart.com/go/gofr/pkg/errors"
@@ -14,12 +18,21 @@ import (
 type service struct {
 	carStore    stores.Car
 	engineStore stores.Engine
+	mediaStore  stores.Media
+	blobStore   stores.Blob
+	index       stores.SearchIndex
+	tx          stores.Transaction
+	outbox      stores.Outbox
+	metrics     metrics.Recorder
+	inventory   *inventory
 }
 
 // nolint:revive // need not be exported
-// New factory function
-func New(c stores.Car, e stores.Engine) service {
-	return service{carStore: c, engineStore: e}
+// New factory function, r records the business metrics and can be nil
+func New(c stores.Car, e stores.Engine, m stores.Media, b stores.Blob, idx stores.SearchIndex, tx stores.Transaction,
+	o stores.Outbox, r metrics.Recorder) service {
+	return service{carStore: c, engineStore: e, mediaStore: m, blobStore: b, index: idx, tx: tx, outbox: o,
+		metrics: metrics.Or(r), inventory: &inventory{}}
 }
 
 // GetByID function is the service function to get a car by its id
@@ -40,6 +53,14 @@ func (service service) GetByID(ctx *gofr.Context, id string) (models.Car, error)
 
 	c.Engine = engine
 
+	media, err := service.mediaStore.GetMediaByCarID(ctx, id)
+	if err != nil {
+		return models.Car{}, err
+	}
+
+	c.Media = media
+	redact(ctx, &c)
+
 	return c, nil
 }
 
@@ -56,35 +77,112 @@ func (service service) GetByBrand(ctx *gofr.Context, brand string, isEngine bool
 		return nil, err
 	}
 
+	for i := range res {
+		redact(ctx, &res[i])
+	}
+
 	return res, nil
 }
 
-// Create is the service layer function to create a model of a car
-func (service service) Create(ctx *gofr.Context, car *models.Car) (models.Car, error) {
-	c := validateCreateCar(car)
+// Create is the service layer function to create a model of a car, a new car is available unless told otherwise.
+// A car likely entered before is rejected unless allowDuplicate is set, a car with the VIN of another car always is.
+func (service service) Create(ctx *gofr.Context, car *models.Car, allowDuplicate bool) (models.Car, error) {
+	if err := checkCostWrite(ctx, car); err != nil {
+		return models.Car{}, err
+	}
 
-	if reflect.DeepEqual(c, models.Car{}) {
+	if err := checkStatus(car); err != nil {
+		return models.Car{}, err
+	}
+
+	if err := checkVIN(car); err != nil {
+		return models.Car{}, err
+	}
+
+	c, reason := validateCreateCar(car)
+	if reason != "" {
+		service.reject(reason)
 		return models.Car{}, errors.InvalidParam{}
 	}
 
-	engine, err := service.engineStore.EngineCreate(ctx, &c.Engine)
+	if c.Status == "" {
+		c.Status = models.CarAvailable
+	}
+
+	if !allowDuplicate {
+		if err := service.checkDuplicate(ctx, &c); err != nil {
+			return models.Car{}, err
+		}
+	}
+
+	err := service.tx.WithTransaction(ctx, func(ctx *gofr.Context) error {
+		engine, err := service.engineStore.EngineCreate(ctx, &c.Engine)
+		if err != nil {
+			return err
+		}
+
+		c.Engine = engine
+		c.ID = c.Engine.EngineID
+
+		c, err = service.carStore.CreateCar(ctx, &c)
+		if err != nil {
+			return err
+		}
+
+		service.count(ctx, metrics.CarsCreated, &c)
+
+		return service.emit(ctx, models.EventCarCreated, c.ID, c)
+	})
 	if err != nil {
+		return models.Car{}, service.vinConflict(ctx, "", car.VIN, err)
+	}
+
+	service.indexCar(ctx, c)
+	redact(ctx, &c)
+
+	return c, nil
+}
+
+// Update is a service layer function to update a car record in database, the status, the cost price, the
+// dealership and the VIN of the car are kept when the update has none
+func (service service) Update(ctx *gofr.Context, id string, car *models.Car) (models.Car, error) {
+	if err := checkCostWrite(ctx, car); err != nil {
 		return models.Car{}, err
 	}
 
-	c.Engine = engine
-	c.ID = c.Engine.EngineID
+	if err := checkStatus(car); err != nil {
+		return models.Car{}, err
+	}
 
-	c, err = service.carStore.CreateCar(ctx, &c)
-	if err != nil {
+	if err := checkVIN(car); err != nil {
 		return models.Car{}, err
 	}
 
+	var c models.Car
+
+	err := service.tx.WithTransaction(ctx, func(ctx *gofr.Context) error {
+		prev, err := service.carStore.GetCarByID(ctx, id)
+		if err != nil {
+			return carError(id, err)
+		}
+
+		c, err = service.update(ctx, id, car, &prev)
+
+		return err
+	})
+	if err != nil {
+		return models.Car{}, service.vinConflict(ctx, id, car.VIN, err)
+	}
+
+	service.indexCar(ctx, c)
+	redact(ctx, &c)
+
 	return c, nil
 }
 
-// Update is a service layer function to update a car record in database
-func (service service) Update(ctx *gofr.Context, id string, car *models.Car) (models.Car, error) {
+// update writes a checked update of the car prev along with its engine and records its events, it must run
+// inside a transaction
+func (service service) update(ctx *gofr.Context, id string, car, prev *models.Car) (models.Car, error) {
 	c, err := service.carStore.UpdateCar(ctx, id, car)
 	if err != nil {
 		return models.Car{}, err
@@ -97,29 +195,179 @@ func (service service) Update(ctx *gofr.Context, id string, car *models.Car) (mo
 
 	c.ID = uuid.MustParse(id)
 	c.Engine = engine
+	keep(&c, prev)
 
-	return c, nil
+	return c, service.emitUpdate(ctx, prev, &c)
 }
 
-// Delete to service layer function to delete the car from database
+// Delete to service layer function to delete the car from database along with its engine and its media. The
+// files of the media are removed once the car is gone.
 func (service service) Delete(ctx *gofr.Context, id string) error {
 	if id == uuid.Nil.String() {
-		return errors.EntityNotFound{ID: id}
+		return errors.EntityNotFound{Entity: "Car", ID: id}
 	}
 
-	err := service.carStore.DeleteCar(ctx, id)
+	var media []models.Media
+
+	err := service.tx.WithTransaction(ctx, func(ctx *gofr.Context) error {
+		c, err := service.carStore.GetCarByID(ctx, id)
+		if err != nil {
+			return carError(id, err)
+		}
+
+		if media, err = service.mediaStore.GetMediaByCarID(ctx, id); err != nil {
+			return err
+		}
+
+		if err = service.mediaStore.DeleteMediaByCarID(ctx, id); err != nil {
+			return err
+		}
+
+		if err = service.carStore.DeleteCar(ctx, id); err != nil {
+			return err
+		}
+
+		if err = service.engineStore.EngineDelete(ctx, id); err != nil {
+			return err
+		}
+
+		service.count(ctx, metrics.CarsDeleted, &c)
+
+		// the event carries the car as it was, consumers no longer can look it up
+		return service.emit(ctx, models.EventCarDeleted, c.ID, c)
+	})
 	if err != nil {
 		return err
 	}
 
-	err = service.engineStore.EngineDelete(ctx, id)
+	if err = service.index.Remove(ctx, id); err != nil {
+		ctx.Logger.Errorf("error in removing car %v from the search index: %v", id, err)
+	}
+
+	service.removeFiles(ctx, media)
+
+	return nil
+}
+
+// carError turns the error of reading the car with the id into the error it is answered with, only a car that
+// does not exist is not found
+func carError(id string, err error) error {
+	if err == sql.ErrNoRows {
+		return errors.EntityNotFound{Entity: "Car", ID: id}
+	}
+
+	return err
+}
+
+// removeFiles deletes the files of the media of a deleted car. A failure is only logged since the metadata,
+// which is what makes the files visible, is already gone.
+func (service service) removeFiles(ctx *gofr.Context, media []models.Media) {
+	for i := range media {
+		for _, key := range []string{media[i].StorageKey, media[i].ThumbnailKey} {
+			if key == "" {
+				continue
+			}
+
+			if err := service.blobStore.Delete(ctx, key); err != nil {
+				ctx.Logger.Errorf("error in deleting blob %v: %v", key, err)
+			}
+		}
+	}
+}
+
+// Search is a service layer function to find the cars matching a free text query, most relevant first
+func (service service) Search(ctx *gofr.Context, query string, limit int) ([]models.SearchResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.MissingParam{Param: []string{"q"}}
+	}
+
+	hits, err := service.index.Search(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(hits))
+	for i := range hits {
+		ids[i] = hits[i].ID.String()
+	}
+
+	cars, err := service.carStore.GetCarsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]models.Car, len(cars))
+	for i := range cars {
+		byID[cars[i].ID] = cars[i]
+	}
+
+	res := make([]models.SearchResult, 0, len(hits))
+
+	for _, h := range hits {
+		// the index can briefly hold cars that were deleted by another instance
+		if c, ok := byID[h.ID]; ok {
+			redact(ctx, &c)
+			res = append(res, models.SearchResult{Car: c, Score: h.Score})
+		}
+	}
+
+	return res, nil
+}
+
+// Reindex loads every car into the search index, it is run on startup to fill the in-process index
+func (service service) Reindex(ctx *gofr.Context) error {
+	cars, err := service.carStore.GetAllCars(ctx)
 	if err != nil {
 		return err
 	}
 
+	for i := range cars {
+		if err = service.index.Index(ctx, cars[i]); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// Export is a service layer function to pass every car with the given brand, or every car when brand is empty,
+// along with its engine to fn. The cars are streamed from the store rather than loaded at once.
+func (service service) Export(ctx *gofr.Context, brand string, fn func(car models.Car) error) error {
+	return service.carStore.StreamCars(ctx, brand, func(car models.Car) error {
+		redact(ctx, &car)
+		return fn(car)
+	})
+}
+
+// GetByIDs is a service layer function to get the cars with the given ids along with their engines in a single
+// query, ids that do not exist are skipped
+func (service service) GetByIDs(ctx *gofr.Context, ids []string) ([]models.Car, error) {
+	cars, err := service.carStore.GetCarsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range cars {
+		redact(ctx, &cars[i])
+	}
+
+	return cars, nil
+}
+
+// GetEngines is a service layer function to get the engines with the given ids in a single query, ids that do
+// not exist are skipped
+func (service service) GetEngines(ctx *gofr.Context, ids []string) ([]models.Engine, error) {
+	return service.engineStore.GetEnginesByIDs(ctx, ids)
+}
+
+// indexCar keeps the search index in sync with a written car. The database is the source of truth, so
+// an indexing failure is logged rather than failing the write.
+func (service service) indexCar(ctx *gofr.Context, c models.Car) {
+	if err := service.index.Index(ctx, c); err != nil {
+		ctx.Logger.Errorf("error in indexing car %v: %v", c.ID, err)
+	}
+}
+
 // checkBrand check if the validity of brand
 func checkBrand(car *models.Car) *models.Car {
 	brands := [5]string{"Tesla", "Porsche", "Ferrari", "Mercedes", "BMW"}
@@ -170,11 +418,64 @@ func checkAge(car *models.Car) *models.Car {
 	return car
 }
 
-// validateCreateCar checks the validity of the car
-func validateCreateCar(car *models.Car) models.Car {
-	car = checkAge(car)
-	car = checkBrand(car)
-	car = checkFuel(car)
+// validateCreateCar checks the validity of the car, an invalid car is returned empty along with the reason of the
+// first check it failed
+func validateCreateCar(car *models.Car) (models.Car, string) {
+	checks := []struct {
+		reason string
+		check  func(car *models.Car) *models.Car
+	}{{"year", checkAge}, {"brand", checkBrand}, {"fuel_type", checkFuel}}
+
+	for _, c := range checks {
+		if car = c.check(car); reflect.DeepEqual(*car, models.Car{}) {
+			return *car, c.reason
+		}
+	}
+
+	return *car, ""
+}
+
+// checkStatus checks the status of a car is a known one, an empty status is left to the caller to fill
+func checkStatus(c *models.Car) error {
+	switch c.Status {
+	case "", models.CarAvailable, models.CarReserved, models.CarSold:
+		return nil
+	default:
+		return errors.InvalidParam{Param: []string{"Status"}}
+	}
+}
+
+// keep fills the fields an update left empty, and so the store did not change, from the car before the update
+func keep(c, prev *models.Car) {
+	if c.Status == "" {
+		c.Status = prev.Status
+	}
+
+	if c.CostPrice == nil {
+		c.CostPrice = prev.CostPrice
+	}
+
+	if c.DealershipID == nil {
+		c.DealershipID = prev.DealershipID
+	}
+
+	if c.VIN == "" {
+		c.VIN = prev.VIN
+	}
+}
+
+// redact hides the fields of a car the principal of ctx is not allowed to read
+func redact(ctx *gofr.Context, c *models.Car) {
+	if !auth.Can(ctx, auth.ReadCost) {
+		c.CostPrice = nil
+	}
+}
+
+// checkCostWrite only lets principals allowed to change the cost price set one
+func checkCostWrite(ctx *gofr.Context, c *models.Car) error {
+	if c.CostPrice == nil {
+		return nil
+	}
 
-	return *car
+	return auth.Check(ctx, auth.WriteCost)
 }
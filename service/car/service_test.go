@@ -1,9 +1,11 @@
 package car
 
 import (
+	"Project/CarDealearship/auth"
 	"Project/CarDealearship/models"
 	"Project/CarDealearship/stores"
 
+	"database/sql"
 	"testing"
 
 	"developer.zopsmart.com/go/gofr/pkg/errors"
@@ -20,12 +22,15 @@ func TestGetByID(t *testing.T) {
 
 	mockCar := stores.NewMockCar(ctrl)
 	mockEngine := stores.NewMockEngine(ctrl)
-	carService := New(mockCar, mockEngine)
+	mockMedia := stores.NewMockMedia(ctrl)
+	carService := New(mockCar, mockEngine, mockMedia, stores.NewMockBlob(ctrl), stores.NewMockSearchIndex(ctrl),
+		stores.NewMockTransaction(ctrl), stores.NewMockOutbox(ctrl), nil)
 	ctx := gofr.NewContext(nil, nil, gofr.New())
 
 	id := uuid.New()
 	id2 := uuid.New()
 	id3 := uuid.New()
+	id4 := uuid.New()
 
 	testCases := []struct {
 		desc     string
@@ -36,7 +41,9 @@ func TestGetByID(t *testing.T) {
 			desc: "success case",
 			id:   id,
 			expected: models.Car{ID: id, Name: "Model 3", Year: 2010, Brand: "Tesla", FuelType: "diesel",
-				Engine: models.Engine{EngineID: id, Displacement: 200, Cylinders: 1, Range: 0}},
+				Engine: models.Engine{EngineID: id, Displacement: 200, Cylinders: 1, Range: 0},
+				Media: []models.Media{{ID: id2, CarID: id, Kind: models.MediaImage, IsCover: true,
+					URL: "http://localhost:9000/media/front.png"}}},
 		},
 		{
 			desc:     "not found",
@@ -62,6 +69,11 @@ func TestGetByID(t *testing.T) {
 			id:       id2,
 			expected: models.Car{},
 		},
+		{
+			desc:     "GetMediaByCarID returns error",
+			id:       id4,
+			expected: models.Car{},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -71,9 +83,14 @@ func TestGetByID(t *testing.T) {
 			mockCar.EXPECT().GetCarByID(ctx, tc.id.String()).Return(tc.expected, nil)
 			mockEngine.EXPECT().EngineGetByID(ctx, tc.id.String()).
 				Return(tc.expected.Engine, errors.Error("err"))
+		} else if tc.id == id4 {
+			mockCar.EXPECT().GetCarByID(ctx, tc.id.String()).Return(tc.expected, nil)
+			mockEngine.EXPECT().EngineGetByID(ctx, tc.id.String()).Return(tc.expected.Engine, nil)
+			mockMedia.EXPECT().GetMediaByCarID(ctx, tc.id.String()).Return(nil, errors.Error("err"))
 		} else if tc.id != uuid.Nil {
 			mockCar.EXPECT().GetCarByID(ctx, tc.id.String()).Return(tc.expected, nil)
 			mockEngine.EXPECT().EngineGetByID(ctx, tc.id.String()).Return(tc.expected.Engine, nil)
+			mockMedia.EXPECT().GetMediaByCarID(ctx, tc.id.String()).Return(tc.expected.Media, nil)
 		}
 
 		res, _ := carService.GetByID(ctx, tc.id.String())
@@ -93,7 +110,8 @@ func TestGetByBrand(t *testing.T) {
 
 	mockCar := stores.NewMockCar(ctrl)
 	mockEngine := stores.NewMockEngine(ctrl)
-	carService := New(mockCar, mockEngine)
+	carService := New(mockCar, mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		stores.NewMockSearchIndex(ctrl), stores.NewMockTransaction(ctrl), stores.NewMockOutbox(ctrl), nil)
 	ctx := gofr.NewContext(nil, nil, gofr.New())
 
 	testCases := []struct {
@@ -140,14 +158,17 @@ func TestCreate(t *testing.T) {
 	var (
 		id = uuid.New()
 		c1 = models.Car{ID: id, Name: "Model 3", Year: 2020, Brand: "Tesla", FuelType: "Diesel",
-			Engine: models.Engine{EngineID: id, Displacement: 200, Cylinders: 6}}
+			Status: models.CarAvailable, Engine: models.Engine{EngineID: id, Displacement: 200, Cylinders: 6}}
 		c2 = models.Car{ID: id, Name: "Model 5", Year: 2021, Brand: "BMW", FuelType: "Diesel",
-			Engine: models.Engine{EngineID: id, Displacement: 400, Cylinders: 2}}
+			Status: models.CarAvailable, Engine: models.Engine{EngineID: id, Displacement: 400, Cylinders: 2}}
 		c3 = models.Car{}
 		c5 = models.Car{ID: id, Name: "Model 7", Year: 2020, Brand: "ABC", FuelType: "Diesel",
 			Engine: models.Engine{EngineID: id, Displacement: 250, Cylinders: 4}}
 		c4 = models.Car{ID: id, Name: "Mod 2", Year: 2020, Brand: "BMW", FuelType: "Diesel",
-			Engine: models.Engine{EngineID: id, Displacement: 250, Cylinders: 3}}
+			Status: models.CarAvailable, Engine: models.Engine{EngineID: id, Displacement: 250, Cylinders: 3}}
+		c6 = models.Car{ID: id, Name: "X5", Year: 2020, Brand: "BMW", FuelType: "Diesel", Status: "leased"}
+		c7 = models.Car{ID: id, Name: "X6", Year: 2020, Brand: "BMW", FuelType: "Petrol",
+			Status: models.CarAvailable, Engine: models.Engine{EngineID: id, Displacement: 300, Cylinders: 6}}
 	)
 
 	ctrl := gomock.NewController(t)
@@ -155,35 +176,84 @@ func TestCreate(t *testing.T) {
 
 	mockCar := stores.NewMockCar(ctrl)
 	mockEngine := stores.NewMockEngine(ctrl)
-	carService := New(mockCar, mockEngine)
+	mockIndex := stores.NewMockSearchIndex(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	carService := New(mockCar, mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl), mockIndex, mockTx,
+		mockOutbox, nil)
 	ctx := gofr.NewContext(nil, nil, gofr.New())
 
 	testCases := []struct {
 		desc   string
 		input  models.Car
 		output models.Car
+		err    error
 	}{
 		{desc: "success case", input: c1, output: c1},
-		{desc: "Create returns error", input: c2, output: c3},
-		{desc: "EngineCreate returns error", input: c4, output: c3},
-		{desc: "Brand not present", input: c5, output: c3},
+		{desc: "Create returns error", input: c2, output: c3, err: errors.InvalidParam{}},
+		{desc: "EngineCreate returns error", input: c4, output: c3, err: errors.InvalidParam{}},
+		{desc: "Brand not present", input: c5, output: c3, err: errors.InvalidParam{}},
+		{desc: "unknown status", input: c6, output: c3, err: errors.InvalidParam{Param: []string{"Status"}}},
+		{desc: "event not recorded", input: c7, output: c3, err: errors.Error("db down")},
 	}
 
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction).Times(4)
+	mockCar.EXPECT().GetDuplicateCandidates(ctx, gomock.Any()).Return(nil, nil).Times(4)
+
 	mockCar.EXPECT().CreateCar(ctx, &c1).Return(c1, nil)
+	mockIndex.EXPECT().Index(ctx, c1).Return(nil)
 	mockEngine.EXPECT().EngineCreate(ctx, &c1.Engine).Return(c1.Engine, nil)
+	mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarCreated, c1)).Return(nil)
 
 	mockCar.EXPECT().CreateCar(ctx, &c2).Return(c2, errors.InvalidParam{})
 	mockEngine.EXPECT().EngineCreate(ctx, &c2.Engine).Return(c2.Engine, nil)
 
 	mockEngine.EXPECT().EngineCreate(ctx, &c4.Engine).Return(c4.Engine, errors.InvalidParam{})
 
+	mockEngine.EXPECT().EngineCreate(ctx, &c7.Engine).Return(c7.Engine, nil)
+	mockCar.EXPECT().CreateCar(ctx, &c7).Return(c7, nil)
+	mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarCreated, c7)).Return(errors.Error("db down"))
+
 	for i := range testCases {
-		res, _ := carService.Create(ctx, &testCases[i].input)
+		res, err := carService.Create(ctx, &testCases[i].input, false)
+		assert.Equal(t, testCases[i].err, err, "[TEST%d]Failed. %s", i+1, testCases[i].desc)
 		assert.Equal(t, res, testCases[i].output,
 			" [TEST%d]Failed. Got %v\tExpected %v\n", i+1, res, testCases[i].output)
 	}
 }
 
+// TestCreateDefaultStatus to test that a car is created available when it has no status
+func TestCreateDefaultStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockIndex := stores.NewMockSearchIndex(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	carService := New(mockCar, mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl), mockIndex, mockTx,
+		mockOutbox, nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	id := uuid.New()
+	input := models.Car{Name: "Roma", Year: 2021, Brand: "Ferrari", FuelType: "Petrol"}
+	created := models.Car{ID: id, Name: "Roma", Year: 2021, Brand: "Ferrari", FuelType: "Petrol",
+		Status: models.CarAvailable, Engine: models.Engine{EngineID: id}}
+
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+	mockCar.EXPECT().GetDuplicateCandidates(ctx, gomock.Any()).Return(nil, nil)
+	mockEngine.EXPECT().EngineCreate(ctx, gomock.Any()).Return(models.Engine{EngineID: id}, nil)
+	mockCar.EXPECT().CreateCar(ctx, &created).Return(created, nil)
+	mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarCreated, created)).Return(nil)
+	mockIndex.EXPECT().Index(ctx, created).Return(nil)
+
+	res, err := carService.Create(ctx, &input, false)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, created, res)
+}
+
 // TestUpdate to test the Update service
 func TestUpdate(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -191,17 +261,24 @@ func TestUpdate(t *testing.T) {
 
 	mockCar := stores.NewMockCar(ctrl)
 	mockEngine := stores.NewMockEngine(ctrl)
-	carService := New(mockCar, mockEngine)
+	mockIndex := stores.NewMockSearchIndex(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	carService := New(mockCar, mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl), mockIndex, mockTx,
+		mockOutbox, nil)
 	ctx := gofr.NewContext(nil, nil, gofr.New())
 	var (
 		id = uuid.New()
 		c1 = models.Car{ID: id, Name: "Cayenne", Year: 2020, Brand: "Porsche", FuelType: "diesel",
-			Engine: models.Engine{EngineID: id, Displacement: 100, Cylinders: 6, Range: 120}}
+			Status: models.CarAvailable, Engine: models.Engine{EngineID: id, Displacement: 100, Cylinders: 6, Range: 120}}
 		c2 = models.Car{ID: id, Name: "Cayenne", Year: 2020, Brand: "Porsche", FuelType: "diesel",
 			Engine: models.Engine{EngineID: id, Displacement: 100, Cylinders: 6, Range: 120}}
 		c3 = models.Car{}
 		c4 = models.Car{ID: id, Name: "Cayenne", Year: 2020, Brand: "Porsche", FuelType: "diesel",
 			Engine: models.Engine{EngineID: id, Displacement: 100, Cylinders: 6, Range: 120}}
+		c5   = models.Car{ID: id, Name: "Cayenne", Status: "leased"}
+		prev = models.Car{ID: id, Name: "Cayenne", Year: 2019, Brand: "Porsche", FuelType: "diesel",
+			Status: models.CarAvailable}
 	)
 
 	testCases := []struct {
@@ -209,34 +286,97 @@ func TestUpdate(t *testing.T) {
 		id     uuid.UUID
 		input  models.Car
 		output models.Car
+		err    error
 	}{
 		{desc: "success case", id: id, input: c1, output: c1},
-		{desc: "Error in updateCar", id: id, input: c2, output: c3},
-		{desc: "error in UpdateEngine", id: id, input: c4, output: c3},
+		{desc: "Error in updateCar", id: id, input: c2, output: c3, err: errors.InvalidParam{}},
+		{desc: "error in UpdateEngine", id: id, input: c4, output: c3, err: errors.InvalidParam{}},
+		{desc: "car not found", id: id, input: c2, output: c3, err: errors.EntityNotFound{Entity: "Car", ID: id.String()}},
+		{desc: "unknown status", id: id, input: c5, output: c3, err: errors.InvalidParam{Param: []string{"Status"}}},
+		{desc: "error in GetCarByID", id: id, input: c2, output: c3, err: errors.Error("db down")},
 	}
 
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction).Times(5)
+
+	mockCar.EXPECT().GetCarByID(ctx, id.String()).Return(prev, nil)
 	mockCar.EXPECT().UpdateCar(ctx, c1.ID.String(), &c1).Return(c1, nil)
 	mockEngine.EXPECT().EngineUpdate(ctx, c1.ID.String(), &c1.Engine).Return(c1.Engine, nil)
+	mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarUpdated, c1)).Return(nil)
+	mockIndex.EXPECT().Index(ctx, c1).Return(errors.Error("index error"))
 
+	mockCar.EXPECT().GetCarByID(ctx, id.String()).Return(prev, nil)
 	mockCar.EXPECT().UpdateCar(ctx, c2.ID.String(), &c2).Return(c3, errors.InvalidParam{})
 
+	mockCar.EXPECT().GetCarByID(ctx, id.String()).Return(prev, nil)
 	mockCar.EXPECT().UpdateCar(ctx, c4.ID.String(), &c4).Return(c3, nil)
 	mockEngine.EXPECT().EngineUpdate(ctx, c4.ID.String(), &c4.Engine).Return(c3.Engine, errors.InvalidParam{})
 
+	mockCar.EXPECT().GetCarByID(ctx, id.String()).Return(models.Car{}, sql.ErrNoRows)
+	mockCar.EXPECT().GetCarByID(ctx, id.String()).Return(models.Car{}, errors.Error("db down"))
+
 	for i, tc := range testCases {
-		car, _ := carService.Update(ctx, tc.id.String(), &tc.input)
+		car, err := carService.Update(ctx, tc.id.String(), &tc.input)
+		assert.Equal(t, tc.err, err, "[TEST%d]Failed. %s", i+1, tc.desc)
 		assert.Equal(t, car, tc.output, "[TEST%d]Failed. Got %v\tExpected %v\n", i+1, car, tc.output)
 	}
 }
 
+// TestUpdateStatus to test that an update keeps the fields it has none for and reports a status change
+func TestUpdateStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockIndex := stores.NewMockSearchIndex(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	carService := New(mockCar, mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl), mockIndex, mockTx,
+		mockOutbox, nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = auth.WithPrincipal(ctx.Context, auth.Principal{Subject: "u1", Roles: []string{auth.RoleManager}})
+
+	id, dealer := uuid.New(), uuid.New()
+	cost := 52000
+	prev := models.Car{ID: id, Name: "X5", Year: 2019, Brand: "BMW", FuelType: "Diesel", Status: models.CarAvailable,
+		CostPrice: &cost, DealershipID: &dealer, Engine: models.Engine{EngineID: id}}
+	input := models.Car{Name: "X5", Year: 2019, Brand: "BMW", FuelType: "Diesel", Status: models.CarSold}
+	updated := prev
+	updated.Status = models.CarSold
+
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+	mockCar.EXPECT().GetCarByID(ctx, id.String()).Return(prev, nil)
+	mockCar.EXPECT().UpdateCar(ctx, id.String(), &input).Return(input, nil)
+	mockEngine.EXPECT().EngineUpdate(ctx, id.String(), &input.Engine).Return(prev.Engine, nil)
+	gomock.InOrder(
+		mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarUpdated, updated)).Return(nil),
+		mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarStatusChanged,
+			models.StatusChange{From: models.CarAvailable, To: models.CarSold, Brand: "BMW", DealershipID: &dealer})).
+			Return(nil),
+	)
+	mockIndex.EXPECT().Index(ctx, updated).Return(nil)
+
+	res, err := carService.Update(ctx, id.String(), &input)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, updated, res)
+}
+
 // TestDelete to test the Delete handler
 func TestDelete(t *testing.T) {
 	var (
 		id  = uuid.New()
 		id2 = uuid.New()
 		id3 = uuid.New()
+		id4 = uuid.New()
+		id5 = uuid.New()
+		id6 = uuid.New()
+		c1  = models.Car{ID: id, Name: "Roma", Brand: "Ferrari", Status: models.CarSold}
 	)
 
+	media := []models.Media{{ID: uuid.New(), CarID: id, StorageKey: "1.jpg", ThumbnailKey: "1_thumb.jpg"},
+		{ID: uuid.New(), CarID: id, StorageKey: "2.pdf"}}
+
 	tests := []struct {
 		desc   string
 		id     uuid.UUID
@@ -244,9 +384,12 @@ func TestDelete(t *testing.T) {
 		err    error
 	}{
 		{"success case", id, models.Car{}, nil},
-		{"Nil UUID", uuid.Nil, models.Car{}, errors.EntityNotFound{ID: uuid.Nil.String()}},
+		{"Nil UUID", uuid.Nil, models.Car{}, errors.EntityNotFound{Entity: "Car", ID: uuid.Nil.String()}},
 		{"error in Delete", id2, models.Car{}, errors.InvalidParam{}},
 		{"error in DeleteEngine", id3, models.Car{}, errors.InvalidParam{}},
+		{"car not found", id4, models.Car{}, errors.EntityNotFound{Entity: "Car", ID: id4.String()}},
+		{"error in DeleteMediaByCarID", id5, models.Car{}, errors.Error("db down")},
+		{"error in GetCarByID", id6, models.Car{}, errors.Error("db down")},
 	}
 
 	ctrl := gomock.NewController(t)
@@ -254,18 +397,44 @@ func TestDelete(t *testing.T) {
 
 	mockCar := stores.NewMockCar(ctrl)
 	mockEngine := stores.NewMockEngine(ctrl)
-	carService := New(mockCar, mockEngine)
+	mockMedia := stores.NewMockMedia(ctrl)
+	mockBlob := stores.NewMockBlob(ctrl)
+	mockIndex := stores.NewMockSearchIndex(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	carService := New(mockCar, mockEngine, mockMedia, mockBlob, mockIndex, mockTx, mockOutbox, nil)
 	ctx := gofr.NewContext(nil, nil, gofr.New())
 
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction).Times(6)
+	mockCar.EXPECT().GetCarByID(ctx, id.String()).Return(c1, nil)
+	mockMedia.EXPECT().GetMediaByCarID(ctx, id.String()).Return(media, nil)
+	mockMedia.EXPECT().DeleteMediaByCarID(ctx, id.String()).Return(nil)
 	mockCar.EXPECT().DeleteCar(ctx, id.String()).Return(nil)
 	mockEngine.EXPECT().EngineDelete(ctx, id.String()).Return(nil)
+	mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarDeleted, c1)).Return(nil)
+	mockIndex.EXPECT().Remove(ctx, id.String()).Return(nil)
+	// the files are removed once the car is gone, a failure is only logged
+	mockBlob.EXPECT().Delete(ctx, "1.jpg").Return(nil)
+	mockBlob.EXPECT().Delete(ctx, "1_thumb.jpg").Return(errors.Error("blob store down"))
+	mockBlob.EXPECT().Delete(ctx, "2.pdf").Return(nil)
+	mockCar.EXPECT().GetCarByID(ctx, id2.String()).Return(models.Car{ID: id2}, nil)
+	mockMedia.EXPECT().GetMediaByCarID(ctx, id2.String()).Return(nil, nil)
+	mockMedia.EXPECT().DeleteMediaByCarID(ctx, id2.String()).Return(nil)
 	mockCar.EXPECT().DeleteCar(ctx, id2.String()).Return(errors.InvalidParam{})
+	mockCar.EXPECT().GetCarByID(ctx, id3.String()).Return(models.Car{ID: id3}, nil)
+	mockMedia.EXPECT().GetMediaByCarID(ctx, id3.String()).Return(media, nil)
+	mockMedia.EXPECT().DeleteMediaByCarID(ctx, id3.String()).Return(nil)
 	mockCar.EXPECT().DeleteCar(ctx, id3.String()).Return(nil)
 	mockEngine.EXPECT().EngineDelete(ctx, id3.String()).Return(errors.InvalidParam{})
+	mockCar.EXPECT().GetCarByID(ctx, id4.String()).Return(models.Car{}, sql.ErrNoRows)
+	mockCar.EXPECT().GetCarByID(ctx, id6.String()).Return(models.Car{}, errors.Error("db down"))
+	mockCar.EXPECT().GetCarByID(ctx, id5.String()).Return(models.Car{ID: id5}, nil)
+	mockMedia.EXPECT().GetMediaByCarID(ctx, id5.String()).Return(media, nil)
+	mockMedia.EXPECT().DeleteMediaByCarID(ctx, id5.String()).Return(errors.Error("db down"))
 
-	for _, tc := range tests {
+	for i, tc := range tests {
 		err := carService.Delete(ctx, tc.id.String())
-		assert.Equal(t, err, tc.err)
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
 	}
 }
 
@@ -289,17 +458,248 @@ func TestValidateCreate(t *testing.T) {
 		desc   string
 		input  models.Car
 		output models.Car
+		reason string
 	}{
 		{desc: "Success case", input: c1, output: c1},
-		{desc: "Wrong brand", input: c2, output: c3},
-		{desc: "Wrong Fuel Type", input: c4, output: c3},
-		{desc: "Wrong year", input: c5, output: c3},
+		{desc: "Wrong brand", input: c2, output: c3, reason: "brand"},
+		{desc: "Wrong Fuel Type", input: c4, output: c3, reason: "fuel_type"},
+		{desc: "Wrong year", input: c5, output: c3, reason: "year"},
 	}
 
 	for i := range testCases {
-		c := validateCreateCar(&testCases[i].input)
+		c, reason := validateCreateCar(&testCases[i].input)
 
 		assert.Equal(t, testCases[i].output, c,
 			" [TEST%d]Failed. Got %v\tExpected %v\n", i+1, c, testCases[i].output)
+		assert.Equal(t, testCases[i].reason, reason, " [TEST%d]Failed.", i+1)
+	}
+}
+
+// TestSearch to test the Search service
+func TestSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockIndex := stores.NewMockSearchIndex(ctrl)
+	carService := New(mockCar, stores.NewMockEngine(ctrl), stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		mockIndex, stores.NewMockTransaction(ctrl), stores.NewMockOutbox(ctrl), nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	id1 := uuid.New()
+	id2 := uuid.New()
+	deleted := uuid.New()
+
+	c1 := models.Car{ID: id1, Name: "Model 3", Year: 2020, Brand: "Tesla", FuelType: "Electric",
+		Engine: models.Engine{EngineID: id1, Range: 500}}
+	c2 := models.Car{ID: id2, Name: "Model S", Year: 2018, Brand: "Tesla", FuelType: "Electric",
+		Engine: models.Engine{EngineID: id2, Range: 600}}
+
+	mockIndex.EXPECT().Search(ctx, "tesla 2020", 10).
+		Return([]models.SearchHit{{ID: id1, Score: 4}, {ID: deleted, Score: 3}, {ID: id2, Score: 2}}, nil)
+	mockCar.EXPECT().GetCarsByIDs(ctx, []string{id1.String(), deleted.String(), id2.String()}).
+		Return([]models.Car{c2, c1}, nil)
+
+	mockIndex.EXPECT().Search(ctx, "bmw", 10).Return([]models.SearchHit{{ID: id1, Score: 1}}, nil)
+	mockCar.EXPECT().GetCarsByIDs(ctx, []string{id1.String()}).Return(nil, errors.Error("db error"))
+
+	mockIndex.EXPECT().Search(ctx, "audi", 10).Return(nil, errors.Error("index error"))
+
+	testCases := []struct {
+		desc   string
+		query  string
+		output []models.SearchResult
+		err    error
+	}{
+		{desc: "success keeps index order and skips stale hits", query: "tesla 2020",
+			output: []models.SearchResult{{Car: c1, Score: 4}, {Car: c2, Score: 2}}},
+		{desc: "empty query", query: "  ", err: errors.MissingParam{Param: []string{"q"}}},
+		{desc: "store error", query: "bmw", err: errors.Error("db error")},
+		{desc: "index error", query: "audi", err: errors.Error("index error")},
+	}
+
+	for i, tc := range testCases {
+		res, err := carService.Search(ctx, tc.query, 10)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.output, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestReindex to test that every car is loaded into the search index
+func TestReindex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockIndex := stores.NewMockSearchIndex(ctrl)
+	carService := New(mockCar, stores.NewMockEngine(ctrl), stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		mockIndex, stores.NewMockTransaction(ctrl), stores.NewMockOutbox(ctrl), nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	c1 := models.Car{ID: uuid.New(), Name: "X5", Brand: "BMW"}
+	c2 := models.Car{ID: uuid.New(), Name: "Roma", Brand: "Ferrari"}
+
+	mockCar.EXPECT().GetAllCars(ctx).Return([]models.Car{c1, c2}, nil)
+	mockIndex.EXPECT().Index(ctx, c1).Return(nil)
+	mockIndex.EXPECT().Index(ctx, c2).Return(nil)
+	mockCar.EXPECT().GetAllCars(ctx).Return(nil, errors.Error("db error"))
+
+	assert.Equal(t, nil, carService.Reindex(ctx))
+	assert.Equal(t, errors.Error("db error"), carService.Reindex(ctx))
+}
+
+func TestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	carService := New(mockCar, stores.NewMockEngine(ctrl), stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		stores.NewMockSearchIndex(ctrl), stores.NewMockTransaction(ctrl), stores.NewMockOutbox(ctrl), nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	c1 := models.Car{ID: uuid.New(), Name: "X5", Brand: "BMW"}
+	c2 := models.Car{ID: uuid.New(), Name: "X7", Brand: "BMW"}
+
+	mockCar.EXPECT().StreamCars(ctx, "BMW", gomock.Any()).
+		DoAndReturn(func(_ *gofr.Context, _ string, fn func(models.Car) error) error {
+			for _, c := range []models.Car{c1, c2} {
+				if err := fn(c); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}).Times(2)
+
+	var got []models.Car
+
+	err := carService.Export(ctx, "BMW", func(c models.Car) error {
+		got = append(got, c)
+		return nil
+	})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.Car{c1, c2}, got)
+
+	err = carService.Export(ctx, "BMW", func(c models.Car) error {
+		return errors.Error("write error")
+	})
+
+	assert.Equal(t, errors.Error("write error"), err)
+}
+
+// TestGetByIDs to test the batched loading of cars
+func TestGetByIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	carService := New(mockCar, stores.NewMockEngine(ctrl), stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		stores.NewMockSearchIndex(ctrl), stores.NewMockTransaction(ctrl), stores.NewMockOutbox(ctrl), nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	cost := 21000
+	id := uuid.New()
+	car := models.Car{ID: id, Name: "X5", Brand: "BMW", CostPrice: &cost}
+	redacted := car
+	redacted.CostPrice = nil
+
+	testCases := []struct {
+		desc   string
+		cars   []models.Car
+		err    error
+		output []models.Car
+	}{
+		{desc: "cost price redacted", cars: []models.Car{car}, output: []models.Car{redacted}},
+		{desc: "store error", err: errors.Error("connection refused")},
+	}
+
+	for i, tc := range testCases {
+		mockCar.EXPECT().GetCarsByIDs(ctx, []string{id.String()}).Return(tc.cars, tc.err)
+
+		res, err := carService.GetByIDs(ctx, []string{id.String()})
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.output, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestGetEngines to test the batched loading of engines
+func TestGetEngines(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEngine := stores.NewMockEngine(ctrl)
+	carService := New(stores.NewMockCar(ctrl), mockEngine, stores.NewMockMedia(ctrl), stores.NewMockBlob(ctrl),
+		stores.NewMockSearchIndex(ctrl), stores.NewMockTransaction(ctrl), stores.NewMockOutbox(ctrl), nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	engine := models.Engine{EngineID: uuid.New(), Displacement: 3000, Cylinders: 6}
+	ids := []string{engine.EngineID.String()}
+
+	mockEngine.EXPECT().GetEnginesByIDs(ctx, ids).Return([]models.Engine{engine}, nil)
+
+	res, err := carService.GetEngines(ctx, ids)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.Engine{engine}, res)
+}
+
+// TestCostPrice to test that the cost price is only shown to and changed by roles allowed to
+func TestCostPrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockEngine := stores.NewMockEngine(ctrl)
+	mockMedia := stores.NewMockMedia(ctrl)
+	mockIndex := stores.NewMockSearchIndex(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	mockOutbox := stores.NewMockOutbox(ctrl)
+	carService := New(mockCar, mockEngine, mockMedia, stores.NewMockBlob(ctrl), mockIndex, mockTx, mockOutbox, nil)
+
+	withRole := func(role string) *gofr.Context {
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+		ctx.Context = auth.WithPrincipal(ctx.Context, auth.Principal{Subject: "u1", Roles: []string{role}})
+
+		return ctx
 	}
+
+	cost := 21000
+	id := uuid.New()
+	car := models.Car{ID: id, Name: "X5", Year: 2019, Brand: "BMW", FuelType: "Diesel", CostPrice: &cost,
+		Engine: models.Engine{EngineID: id, Displacement: 3000, Cylinders: 6}}
+	redacted := car
+	redacted.CostPrice = nil
+
+	mockCar.EXPECT().GetCarByID(gomock.Any(), id.String()).Return(car, nil).Times(3)
+	mockEngine.EXPECT().EngineGetByID(gomock.Any(), id.String()).Return(car.Engine, nil).Times(2)
+	mockMedia.EXPECT().GetMediaByCarID(gomock.Any(), id.String()).Return(nil, nil).Times(2)
+
+	res, err := carService.GetByID(withRole(auth.RoleManager), id.String())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, car, res)
+
+	res, err = carService.GetByID(withRole(auth.RoleSales), id.String())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, redacted, res)
+
+	input := car
+	_, err = carService.Update(withRole(auth.RoleSales), id.String(), &input)
+	assert.Equal(t, auth.Check(withRole(auth.RoleSales), auth.WriteCost), err)
+
+	ctx := withRole(auth.RoleManager)
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+	mockCar.EXPECT().UpdateCar(ctx, id.String(), &input).Return(car, nil)
+	mockOutbox.EXPECT().AddEvent(ctx, eventOf(models.EventCarUpdated, car)).Return(nil)
+	mockEngine.EXPECT().EngineUpdate(ctx, id.String(), &input.Engine).Return(car.Engine, nil)
+	mockIndex.EXPECT().Index(ctx, car).Return(nil)
+
+	res, err = carService.Update(ctx, id.String(), &input)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, car, res)
+
+	_, err = carService.Create(withRole(auth.RoleViewer), &input, false)
+	assert.Equal(t, auth.Check(withRole(auth.RoleViewer), auth.WriteCost), err)
 }
@@ -0,0 +1,24 @@
+package dealership
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+type service struct {
+	store stores.Dealership
+}
+
+// nolint:revive // need not be exported
+// New factory function
+func New(d stores.Dealership) service {
+	return service{store: d}
+}
+
+// GetByIDs is a service layer function to get the dealerships with the given ids in a single query, ids that do
+// not exist are skipped
+func (s service) GetByIDs(ctx *gofr.Context, ids []string) ([]models.Dealership, error) {
+	return s.store.GetDealershipsByIDs(ctx, ids)
+}
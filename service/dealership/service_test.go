@@ -0,0 +1,45 @@
+package dealership
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetByIDs tests the GetByIDs service
+func TestGetByIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := stores.NewMockDealership(ctrl)
+	s := New(mockStore)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	d := models.Dealership{ID: uuid.New(), Name: "Downtown Motors", City: "Bangalore"}
+	missing := uuid.NewString()
+
+	testCases := []struct {
+		desc   string
+		ids    []string
+		output []models.Dealership
+		err    error
+	}{
+		{desc: "found", ids: []string{d.ID.String(), missing}, output: []models.Dealership{d}},
+		{desc: "store error", ids: []string{missing}, err: errors.Error("connection refused")},
+	}
+
+	for i, tc := range testCases {
+		mockStore.EXPECT().GetDealershipsByIDs(ctx, tc.ids).Return(tc.output, tc.err)
+
+		res, err := s.GetByIDs(ctx, tc.ids)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.output, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -3,12 +3,46 @@ package service
 import (
 	"Project/CarDealearship/models"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"io"
 )
 
 type Cars interface {
 	GetByID(ctx *gofr.Context, id string) (models.Car, error)
 	GetByBrand(ctx *gofr.Context, brand string, isEngine bool) ([]models.Car, error)
-	Create(ctx *gofr.Context, car *models.Car) (models.Car, error)
+	Create(ctx *gofr.Context, car *models.Car, allowDuplicate bool) (models.Car, error)
 	Delete(ctx *gofr.Context, id string) error
 	Update(ctx *gofr.Context, id string, car *models.Car) (models.Car, error)
+	Duplicates(ctx *gofr.Context) ([]models.DuplicateGroup, error)
+	BatchGet(ctx *gofr.Context, ids []string) ([]models.BatchResult, error)
+	BatchUpdate(ctx *gofr.Context, patches []models.CarPatch) ([]models.BatchResult, error)
+	Search(ctx *gofr.Context, query string, limit int) ([]models.SearchResult, error)
+	Compare(ctx *gofr.Context, ids []string) (models.Comparison, error)
+	Import(ctx *gofr.Context, rows []models.ImportRow, dryRun bool) (models.ImportReport, error)
+	Export(ctx *gofr.Context, brand string, fn func(car models.Car) error) error
+	GetByIDs(ctx *gofr.Context, ids []string) ([]models.Car, error)
+	GetEngines(ctx *gofr.Context, ids []string) ([]models.Engine, error)
+	ReportInventory(ctx *gofr.Context) error
+}
+
+type Dealerships interface {
+	GetByIDs(ctx *gofr.Context, ids []string) ([]models.Dealership, error)
+}
+
+type Media interface {
+	Upload(ctx *gofr.Context, carID string, files []models.Upload) ([]models.Media, error)
+	GetByCarID(ctx *gofr.Context, carID string) ([]models.Media, error)
+	Reorder(ctx *gofr.Context, carID string, ids []string) ([]models.Media, error)
+	SetCover(ctx *gofr.Context, carID, id string) ([]models.Media, error)
+	Delete(ctx *gofr.Context, carID, id string) error
+	Open(ctx *gofr.Context, key string) (io.ReadCloser, string, error)
+}
+
+type Webhooks interface {
+	GetAll(ctx *gofr.Context) ([]models.Webhook, error)
+	GetByID(ctx *gofr.Context, id string) (models.Webhook, error)
+	Create(ctx *gofr.Context, webhook *models.Webhook) (models.Webhook, error)
+	Update(ctx *gofr.Context, id string, webhook *models.Webhook) (models.Webhook, error)
+	Delete(ctx *gofr.Context, id string) error
+	GetDeliveries(ctx *gofr.Context, id, status string, limit int) ([]models.WebhookDelivery, error)
+	Retry(ctx *gofr.Context, id, deliveryID string) (models.WebhookDelivery, error)
 }
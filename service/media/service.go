@@ -0,0 +1,301 @@
+package media
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+)
+
+// extensions maps every accepted upload content type to the extension of its storage key
+var extensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"application/pdf": ".pdf",
+}
+
+type service struct {
+	carStore   stores.Car
+	mediaStore stores.Media
+	blobStore  stores.Blob
+	tx         stores.Transaction
+}
+
+// nolint:revive // need not be exported
+// New factory function
+func New(c stores.Car, m stores.Media, b stores.Blob, t stores.Transaction) service {
+	return service{carStore: c, mediaStore: m, blobStore: b, tx: t}
+}
+
+// Upload stores the files of a car, generating a thumbnail for every image. The first image of a car
+// without media becomes its cover. Either every file is added or none is: the media are created in a single
+// transaction and the files already written are deleted when a file or the transaction fails.
+func (s service) Upload(ctx *gofr.Context, carID string, files []models.Upload) ([]models.Media, error) {
+	id, err := s.checkCar(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, errors.MissingParam{Param: []string{"files"}}
+	}
+
+	for i := range files {
+		files[i].ContentType = http.DetectContentType(files[i].Content)
+		if _, ok := extensions[files[i].ContentType]; !ok {
+			return nil, errors.InvalidParam{Param: []string{files[i].FileName}}
+		}
+	}
+
+	existing, err := s.mediaStore.GetMediaByCarID(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+
+	hasCover := false
+
+	for i := range existing {
+		hasCover = hasCover || existing[i].IsCover
+	}
+
+	media := make([]models.Media, len(files))
+	written := make([]string, 0, 2*len(files))
+
+	for i := range files {
+		m := models.Media{ID: uuid.New(), CarID: id, ContentType: files[i].ContentType, FileName: files[i].FileName,
+			Size: int64(len(files[i].Content)), Position: len(existing) + i, Kind: models.MediaDocument,
+			CreatedAt: time.Now().UTC()}
+
+		if m.ContentType != "application/pdf" {
+			m.Kind = models.MediaImage
+			m.IsCover = !hasCover
+			hasCover = true
+		}
+
+		written, err = s.store(ctx, &m, files[i].Content, written)
+		if err != nil {
+			s.discard(ctx, written)
+			return nil, err
+		}
+
+		media[i] = m
+	}
+
+	err = s.tx.WithTransaction(ctx, func(ctx *gofr.Context) error {
+		for i := range media {
+			if media[i], err = s.mediaStore.CreateMedia(ctx, &media[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.discard(ctx, written)
+		return nil, err
+	}
+
+	s.touch(ctx, carID)
+
+	return media, nil
+}
+
+// store writes the file and, for images, its thumbnail to the blob store, appending the keys it wrote to
+// written. Images are decoded before anything is written, so that an invalid one leaves nothing behind.
+func (s service) store(ctx *gofr.Context, m *models.Media, content []byte, written []string) ([]string, error) {
+	var thumb []byte
+
+	if m.Kind == models.MediaImage {
+		var err error
+
+		if thumb, err = thumbnail(content); err != nil {
+			ctx.Logger.Errorf("error in generating thumbnail for %v: %v", m.FileName, err)
+			return written, errors.InvalidParam{Param: []string{m.FileName}}
+		}
+	}
+
+	m.StorageKey = m.ID.String() + extensions[m.ContentType]
+
+	err := s.blobStore.Put(ctx, m.StorageKey, m.ContentType, bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return written, err
+	}
+
+	written = append(written, m.StorageKey)
+	m.URL = s.blobStore.URL(m.StorageKey)
+
+	if thumb == nil {
+		return written, nil
+	}
+
+	m.ThumbnailKey = m.ID.String() + "_thumb.jpg"
+
+	err = s.blobStore.Put(ctx, m.ThumbnailKey, "image/jpeg", bytes.NewReader(thumb), int64(len(thumb)))
+	if err != nil {
+		return written, err
+	}
+
+	m.ThumbnailURL = s.blobStore.URL(m.ThumbnailKey)
+
+	return append(written, m.ThumbnailKey), nil
+}
+
+// discard deletes the files of an upload that failed, a failure is only logged since nothing refers to them
+func (s service) discard(ctx *gofr.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.blobStore.Delete(ctx, key); err != nil {
+			ctx.Logger.Errorf("error in deleting blob %v: %v", key, err)
+		}
+	}
+}
+
+// GetByCarID returns the media of a car, cover first and then in display order
+func (s service) GetByCarID(ctx *gofr.Context, carID string) ([]models.Media, error) {
+	if _, err := s.checkCar(ctx, carID); err != nil {
+		return nil, err
+	}
+
+	return s.mediaStore.GetMediaByCarID(ctx, carID)
+}
+
+// Reorder sets the display order of a car's media, ids must list every media item of the car exactly once. The
+// positions are written in a single transaction so that a failure leaves the order as it was.
+func (s service) Reorder(ctx *gofr.Context, carID string, ids []string) ([]models.Media, error) {
+	existing, err := s.GetByCarID(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(existing))
+	for i := range existing {
+		known[existing[i].ID.String()] = true
+	}
+
+	if len(ids) != len(existing) {
+		return nil, errors.InvalidParam{Param: []string{"ids"}}
+	}
+
+	for _, id := range ids {
+		if !known[id] {
+			return nil, errors.InvalidParam{Param: []string{"ids"}}
+		}
+
+		delete(known, id)
+	}
+
+	err = s.tx.WithTransaction(ctx, func(ctx *gofr.Context) error {
+		for i, id := range ids {
+			if err := s.mediaStore.UpdateMediaPosition(ctx, id, i); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.touch(ctx, carID)
+
+	return s.mediaStore.GetMediaByCarID(ctx, carID)
+}
+
+// SetCover makes the given image the cover photo of the car
+func (s service) SetCover(ctx *gofr.Context, carID, id string) ([]models.Media, error) {
+	m, err := s.get(ctx, carID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Kind != models.MediaImage {
+		return nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+
+	if err = s.mediaStore.SetCoverMedia(ctx, carID, id); err != nil {
+		return nil, err
+	}
+
+	s.touch(ctx, carID)
+
+	return s.mediaStore.GetMediaByCarID(ctx, carID)
+}
+
+// Delete removes a media item and its files. Failing to remove a file is only logged since the
+// metadata, which is what makes the file visible, is already gone.
+func (s service) Delete(ctx *gofr.Context, carID, id string) error {
+	m, err := s.get(ctx, carID, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.mediaStore.DeleteMedia(ctx, id); err != nil {
+		return err
+	}
+
+	s.touch(ctx, carID)
+
+	for _, key := range []string{m.StorageKey, m.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+
+		if err = s.blobStore.Delete(ctx, key); err != nil {
+			ctx.Logger.Errorf("error in deleting blob %v: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+// Open returns the content of a stored file and its content type
+func (s service) Open(ctx *gofr.Context, key string) (io.ReadCloser, string, error) {
+	return s.blobStore.Get(ctx, key)
+}
+
+// touch marks the car as changed, its media are part of it. A failure is only logged since the media are
+// already changed.
+func (s service) touch(ctx *gofr.Context, carID string) {
+	if err := s.carStore.TouchCar(ctx, carID); err != nil {
+		ctx.Logger.Errorf("error in marking car %v as changed: %v", carID, err)
+	}
+}
+
+// get returns the media item, making sure it belongs to the car
+func (s service) get(ctx *gofr.Context, carID, id string) (models.Media, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return models.Media{}, errors.InvalidParam{Param: []string{"id"}}
+	}
+
+	m, err := s.mediaStore.GetMediaByID(ctx, id)
+	if err != nil {
+		return models.Media{}, errors.EntityNotFound{Entity: "Media", ID: id}
+	}
+
+	if m.CarID.String() != carID {
+		return models.Media{}, errors.EntityNotFound{Entity: "Media", ID: id}
+	}
+
+	return m, nil
+}
+
+// checkCar validates the car id and makes sure the car exists
+func (s service) checkCar(ctx *gofr.Context, carID string) (uuid.UUID, error) {
+	id, err := uuid.Parse(carID)
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, errors.InvalidParam{Param: []string{"id"}}
+	}
+
+	if _, err = s.carStore.GetCarByID(ctx, carID); err != nil {
+		return uuid.Nil, errors.EntityNotFound{Entity: "Car", ID: carID}
+	}
+
+	return id, nil
+}
@@ -0,0 +1,369 @@
+package media
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func pngImage(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// TestThumbnail tests that images are scaled down to the thumbnail width keeping their aspect ratio
+func TestThumbnail(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		width, height int
+		outW, outH    int
+	}{
+		{desc: "wide image", width: 1280, height: 720, outW: 320, outH: 180},
+		{desc: "small image", width: 100, height: 50, outW: 100, outH: 50},
+		{desc: "very wide image", width: 4000, height: 2, outW: 320, outH: 1},
+	}
+
+	for i, tc := range testCases {
+		b, err := thumbnail(pngImage(t, tc.width, tc.height))
+		assert.Equal(t, nil, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		img, err := jpeg.Decode(bytes.NewReader(b))
+		assert.Equal(t, nil, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, image.Rect(0, 0, tc.outW, tc.outH), img.Bounds(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	_, err := thumbnail([]byte("not an image"))
+	assert.Equal(t, image.ErrFormat, err)
+
+	_, err = thumbnail(bombImage(t))
+	assert.Equal(t, errTooLarge, err, "an image declaring huge dimensions is not decoded")
+}
+
+// bombImage returns a small PNG declaring dimensions of 100000x100000 pixels
+func bombImage(t *testing.T) []byte {
+	b := pngImage(t, 1, 1)
+
+	// the IHDR chunk follows the 8 bytes of the signature: length, type, width, height, ..., crc
+	binary.BigEndian.PutUint32(b[16:], 100000)
+	binary.BigEndian.PutUint32(b[20:], 100000)
+	binary.BigEndian.PutUint32(b[29:], crc32.ChecksumIEEE(b[12:29]))
+
+	return b
+}
+
+// TestUpload tests the Upload service
+func TestUpload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockMedia := stores.NewMockMedia(ctrl)
+	mockBlob := stores.NewMockBlob(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	s := New(mockCar, mockMedia, mockBlob, mockTx)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	carID := uuid.New()
+	img := pngImage(t, 640, 480)
+	pdf := []byte("%PDF-1.4 brochure")
+
+	mockCar.EXPECT().GetCarByID(ctx, carID.String()).Return(models.Car{ID: carID}, nil)
+	mockMedia.EXPECT().GetMediaByCarID(ctx, carID.String()).Return([]models.Media{}, nil)
+	mockBlob.EXPECT().Put(ctx, gomock.Any(), "image/png", gomock.Any(), int64(len(img))).Return(nil)
+	mockBlob.EXPECT().Put(ctx, gomock.Any(), "image/jpeg", gomock.Any(), gomock.Any()).Return(nil)
+	mockBlob.EXPECT().Put(ctx, gomock.Any(), "application/pdf", gomock.Any(), int64(len(pdf))).Return(nil)
+	mockBlob.EXPECT().URL(gomock.Any()).DoAndReturn(func(key string) string { return "http://m/" + key }).Times(3)
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+	mockMedia.EXPECT().CreateMedia(ctx, gomock.Any()).
+		DoAndReturn(func(_ *gofr.Context, m *models.Media) (models.Media, error) { return *m, nil }).Times(2)
+	mockCar.EXPECT().TouchCar(ctx, carID.String()).Return(nil)
+
+	res, err := s.Upload(ctx, carID.String(), []models.Upload{{FileName: "front.png", Content: img},
+		{FileName: "brochure.pdf", Content: pdf}})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(res))
+	assert.Equal(t, models.MediaImage, res[0].Kind)
+	assert.Equal(t, true, res[0].IsCover)
+	assert.Equal(t, 0, res[0].Position)
+	assert.Equal(t, "http://m/"+res[0].ID.String()+".png", res[0].URL)
+	assert.Equal(t, "http://m/"+res[0].ID.String()+"_thumb.jpg", res[0].ThumbnailURL)
+	assert.Equal(t, models.MediaDocument, res[1].Kind)
+	assert.Equal(t, false, res[1].IsCover)
+	assert.Equal(t, 1, res[1].Position)
+	assert.Equal(t, "", res[1].ThumbnailURL)
+}
+
+// TestUploadErrors tests the validation done by the Upload service
+func TestUploadErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockMedia := stores.NewMockMedia(ctrl)
+	s := New(mockCar, mockMedia, stores.NewMockBlob(ctrl), nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	carID := uuid.New()
+	missing := uuid.New()
+
+	mockCar.EXPECT().GetCarByID(ctx, missing.String()).Return(models.Car{}, errors.Error("no rows"))
+	mockCar.EXPECT().GetCarByID(ctx, carID.String()).Return(models.Car{ID: carID}, nil).Times(2)
+
+	testCases := []struct {
+		desc  string
+		carID string
+		files []models.Upload
+		err   error
+	}{
+		{desc: "invalid id", carID: "abc", err: errors.InvalidParam{Param: []string{"id"}}},
+		{desc: "car not found", carID: missing.String(), err: errors.EntityNotFound{Entity: "Car", ID: missing.String()}},
+		{desc: "no files", carID: carID.String(), err: errors.MissingParam{Param: []string{"files"}}},
+		{desc: "unsupported type", carID: carID.String(), files: []models.Upload{{FileName: "a.txt",
+			Content: []byte("plain text")}}, err: errors.InvalidParam{Param: []string{"a.txt"}}},
+	}
+
+	for i, tc := range testCases {
+		res, err := s.Upload(ctx, tc.carID, tc.files)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, []models.Media(nil), res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestUploadCleanup tests that the files of a failed upload are deleted, whichever file or step failed
+func TestUploadCleanup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockMedia := stores.NewMockMedia(ctrl)
+	mockBlob := stores.NewMockBlob(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	s := New(mockCar, mockMedia, mockBlob, mockTx)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	carID := uuid.New()
+	img := pngImage(t, 64, 48)
+	pdf := []byte("%PDF-1.4 brochure")
+
+	var written []string
+
+	put := func(_ *gofr.Context, key, _ string, _ io.Reader, _ int64) error {
+		written = append(written, key)
+		return nil
+	}
+
+	var deleted []string
+
+	del := func(_ *gofr.Context, key string) error {
+		deleted = append(deleted, key)
+		return nil
+	}
+
+	mockCar.EXPECT().GetCarByID(ctx, carID.String()).Return(models.Car{ID: carID}, nil).Times(3)
+	mockMedia.EXPECT().GetMediaByCarID(ctx, carID.String()).Return(nil, nil).Times(3)
+	mockBlob.EXPECT().URL(gomock.Any()).Return("http://m/key").AnyTimes()
+	mockBlob.EXPECT().Delete(ctx, gomock.Any()).DoAndReturn(del).AnyTimes()
+
+	testCases := []struct {
+		desc  string
+		files []models.Upload
+		setup func()
+		err   error
+	}{
+		{desc: "image too large", files: []models.Upload{{FileName: "brochure.pdf", Content: pdf},
+			{FileName: "bomb.png", Content: bombImage(t)}}, setup: func() {
+			mockBlob.EXPECT().Put(ctx, gomock.Any(), "application/pdf", gomock.Any(), gomock.Any()).DoAndReturn(put)
+		}, err: errors.InvalidParam{Param: []string{"bomb.png"}}},
+		{desc: "thumbnail not written", files: []models.Upload{{FileName: "front.png", Content: img}}, setup: func() {
+			mockBlob.EXPECT().Put(ctx, gomock.Any(), "image/png", gomock.Any(), gomock.Any()).DoAndReturn(put)
+			mockBlob.EXPECT().Put(ctx, gomock.Any(), "image/jpeg", gomock.Any(), gomock.Any()).
+				Return(errors.Error("disk full"))
+		}, err: errors.Error("disk full")},
+		{desc: "media not created", files: []models.Upload{{FileName: "front.png", Content: img},
+			{FileName: "brochure.pdf", Content: pdf}}, setup: func() {
+			mockBlob.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(put).Times(3)
+			mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+			mockMedia.EXPECT().CreateMedia(ctx, gomock.Any()).
+				DoAndReturn(func(_ *gofr.Context, m *models.Media) (models.Media, error) { return *m, nil })
+			mockMedia.EXPECT().CreateMedia(ctx, gomock.Any()).Return(models.Media{}, errors.Error("db down"))
+		}, err: errors.Error("db down")},
+	}
+
+	for i, tc := range testCases {
+		written, deleted = nil, nil
+
+		tc.setup()
+
+		res, err := s.Upload(ctx, carID.String(), tc.files)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, []models.Media(nil), res, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.NotEmpty(t, written, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, written, deleted, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// runInTransaction runs fn the way the mock transaction is expected to
+func runInTransaction(ctx *gofr.Context, fn func(ctx *gofr.Context) error) error {
+	return fn(ctx)
+}
+
+// TestReorder tests the Reorder service
+func TestReorder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockMedia := stores.NewMockMedia(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	s := New(mockCar, mockMedia, stores.NewMockBlob(ctrl), mockTx)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	carID := uuid.New()
+	m1 := models.Media{ID: uuid.New(), CarID: carID, Position: 0}
+	m2 := models.Media{ID: uuid.New(), CarID: carID, Position: 1}
+	reordered := []models.Media{{ID: m2.ID, CarID: carID}, {ID: m1.ID, CarID: carID, Position: 1}}
+
+	mockCar.EXPECT().GetCarByID(ctx, carID.String()).Return(models.Car{ID: carID}, nil).Times(4)
+	mockMedia.EXPECT().GetMediaByCarID(ctx, carID.String()).Return([]models.Media{m1, m2}, nil).Times(4)
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction).Times(2)
+	mockMedia.EXPECT().UpdateMediaPosition(ctx, m2.ID.String(), 0).Return(nil).Times(2)
+	mockMedia.EXPECT().UpdateMediaPosition(ctx, m1.ID.String(), 1).Return(errors.Error("db down"))
+	mockMedia.EXPECT().UpdateMediaPosition(ctx, m1.ID.String(), 1).Return(nil)
+	mockCar.EXPECT().TouchCar(ctx, carID.String()).Return(nil)
+	mockMedia.EXPECT().GetMediaByCarID(ctx, carID.String()).Return(reordered, nil)
+
+	testCases := []struct {
+		desc   string
+		ids    []string
+		output []models.Media
+		err    error
+	}{
+		{desc: "missing id", ids: []string{m2.ID.String()}, err: errors.InvalidParam{Param: []string{"ids"}}},
+		{desc: "duplicate id", ids: []string{m2.ID.String(), m2.ID.String()},
+			err: errors.InvalidParam{Param: []string{"ids"}}},
+		{desc: "failed update", ids: []string{m2.ID.String(), m1.ID.String()}, err: errors.Error("db down")},
+		{desc: "success", ids: []string{m2.ID.String(), m1.ID.String()}, output: reordered},
+	}
+
+	for i, tc := range testCases {
+		res, err := s.Reorder(ctx, carID.String(), tc.ids)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.output, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestSetCover tests the SetCover service
+func TestSetCover(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockMedia := stores.NewMockMedia(ctrl)
+	s := New(mockCar, mockMedia, stores.NewMockBlob(ctrl), nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	carID := uuid.New()
+	img := models.Media{ID: uuid.New(), CarID: carID, Kind: models.MediaImage}
+	doc := models.Media{ID: uuid.New(), CarID: carID, Kind: models.MediaDocument}
+	other := models.Media{ID: uuid.New(), CarID: uuid.New(), Kind: models.MediaImage}
+
+	mockMedia.EXPECT().GetMediaByID(ctx, img.ID.String()).Return(img, nil)
+	mockMedia.EXPECT().GetMediaByID(ctx, doc.ID.String()).Return(doc, nil)
+	mockMedia.EXPECT().GetMediaByID(ctx, other.ID.String()).Return(other, nil)
+	mockMedia.EXPECT().SetCoverMedia(ctx, carID.String(), img.ID.String()).Return(nil)
+	mockCar.EXPECT().TouchCar(ctx, carID.String()).Return(nil)
+	mockMedia.EXPECT().GetMediaByCarID(ctx, carID.String()).Return([]models.Media{img}, nil)
+
+	testCases := []struct {
+		desc   string
+		id     string
+		output []models.Media
+		err    error
+	}{
+		{desc: "success", id: img.ID.String(), output: []models.Media{img}},
+		{desc: "document cannot be cover", id: doc.ID.String(), err: errors.InvalidParam{Param: []string{"id"}}},
+		{desc: "media of another car", id: other.ID.String(),
+			err: errors.EntityNotFound{Entity: "Media", ID: other.ID.String()}},
+		{desc: "invalid id", id: "abc", err: errors.InvalidParam{Param: []string{"id"}}},
+	}
+
+	for i, tc := range testCases {
+		res, err := s.SetCover(ctx, carID.String(), tc.id)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.output, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestDelete tests the Delete service
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	mockMedia := stores.NewMockMedia(ctrl)
+	mockBlob := stores.NewMockBlob(ctrl)
+	s := New(mockCar, mockMedia, mockBlob, nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	carID := uuid.New()
+	m := models.Media{ID: uuid.New(), CarID: carID, StorageKey: "a.png", ThumbnailKey: "a_thumb.jpg"}
+	m2 := models.Media{ID: uuid.New(), CarID: carID, StorageKey: "b.pdf"}
+
+	mockMedia.EXPECT().GetMediaByID(ctx, m.ID.String()).Return(m, nil)
+	mockMedia.EXPECT().DeleteMedia(ctx, m.ID.String()).Return(nil)
+	mockCar.EXPECT().TouchCar(ctx, carID.String()).Return(errors.Error("db error"))
+	mockBlob.EXPECT().Delete(ctx, "a.png").Return(nil)
+	mockBlob.EXPECT().Delete(ctx, "a_thumb.jpg").Return(errors.Error("io error"))
+
+	mockMedia.EXPECT().GetMediaByID(ctx, m2.ID.String()).Return(m2, nil)
+	mockMedia.EXPECT().DeleteMedia(ctx, m2.ID.String()).Return(errors.Error("db error"))
+
+	assert.Equal(t, nil, s.Delete(ctx, carID.String(), m.ID.String()))
+	assert.Equal(t, errors.Error("db error"), s.Delete(ctx, carID.String(), m2.ID.String()))
+}
+
+// TestOpen tests that files are read from the blob store
+func TestOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBlob := stores.NewMockBlob(ctrl)
+	s := New(stores.NewMockCar(ctrl), stores.NewMockMedia(ctrl), mockBlob, nil)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	mockBlob.EXPECT().Get(ctx, "a.png").Return(io.NopCloser(bytes.NewReader([]byte("x"))), "image/png", nil)
+
+	r, contentType, err := s.Open(ctx, "a.png")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "image/png", contentType)
+	assert.NotNil(t, r)
+}
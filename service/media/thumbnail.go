@@ -0,0 +1,98 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+
+	// registered so image.Decode understands every accepted upload format
+	_ "image/gif"
+	_ "image/png"
+)
+
+const (
+	thumbnailWidth   = 320
+	thumbnailQuality = 80
+	// maxPixels bounds the images that are decoded, a small file may declare dimensions that take gigabytes
+	maxPixels = 50_000_000
+)
+
+// errTooLarge is returned for images whose dimensions exceed maxPixels
+const errTooLarge = errors.Error("image dimensions too large")
+
+// thumbnail decodes an image and returns a JPEG scaled down to thumbnailWidth, keeping the aspect ratio.
+// Images narrower than thumbnailWidth are only re-encoded. The dimensions are checked before the image is decoded.
+func thumbnail(content []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Width > maxPixels || cfg.Height > maxPixels || cfg.Width*cfg.Height > maxPixels {
+		return nil, errTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	if w > thumbnailWidth {
+		h = h * thumbnailWidth / w
+		w = thumbnailWidth
+	}
+
+	if h < 1 {
+		h = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	// every destination pixel is the average of the block of source pixels it covers
+	for y := 0; y < h; y++ {
+		sy0 := b.Min.Y + y*b.Dy()/h
+		sy1 := b.Min.Y + (y+1)*b.Dy()/h
+
+		for x := 0; x < w; x++ {
+			sx0 := b.Min.X + x*b.Dx()/w
+			sx1 := b.Min.X + (x+1)*b.Dx()/w
+
+			dst.Set(x, y, average(src, sx0, sy0, sx1, sy1))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func average(src image.Image, x0, y0, x1, y1 int) color.Color {
+	if x1 <= x0 {
+		x1 = x0 + 1
+	}
+
+	if y1 <= y0 {
+		y1 = y0 + 1
+	}
+
+	var r, g, b, a, n uint64
+
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			cr, cg, cb, ca := src.At(x, y).RGBA()
+			r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+			n++
+		}
+	}
+
+	return color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)}
+}
@@ -6,6 +6,7 @@ package service
 
 import (
 	models "Project/CarDealearship/models"
+	io "io"
 	reflect "reflect"
 
 	gofr "developer.zopsmart.com/go/gofr/pkg/gofr"
@@ -35,19 +36,64 @@ func (m *MockCars) EXPECT() *MockCarsMockRecorder {
 	return m.recorder
 }
 
+// BatchGet mocks base method.
+func (m *MockCars) BatchGet(ctx *gofr.Context, ids []string) ([]models.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchGet", ctx, ids)
+	ret0, _ := ret[0].([]models.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGet indicates an expected call of BatchGet.
+func (mr *MockCarsMockRecorder) BatchGet(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGet", reflect.TypeOf((*MockCars)(nil).BatchGet), ctx, ids)
+}
+
+// BatchUpdate mocks base method.
+func (m *MockCars) BatchUpdate(ctx *gofr.Context, patches []models.CarPatch) ([]models.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchUpdate", ctx, patches)
+	ret0, _ := ret[0].([]models.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchUpdate indicates an expected call of BatchUpdate.
+func (mr *MockCarsMockRecorder) BatchUpdate(ctx, patches interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchUpdate", reflect.TypeOf((*MockCars)(nil).BatchUpdate), ctx, patches)
+}
+
+// Compare mocks base method.
+func (m *MockCars) Compare(ctx *gofr.Context, ids []string) (models.Comparison, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compare", ctx, ids)
+	ret0, _ := ret[0].(models.Comparison)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Compare indicates an expected call of Compare.
+func (mr *MockCarsMockRecorder) Compare(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compare", reflect.TypeOf((*MockCars)(nil).Compare), ctx, ids)
+}
+
 // Create mocks base method.
-func (m *MockCars) Create(ctx *gofr.Context, car *models.Car) (models.Car, error) {
+func (m *MockCars) Create(ctx *gofr.Context, car *models.Car, allowDuplicate bool) (models.Car, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, car)
+	ret := m.ctrl.Call(m, "Create", ctx, car, allowDuplicate)
 	ret0, _ := ret[0].(models.Car)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockCarsMockRecorder) Create(ctx, car interface{}) *gomock.Call {
+func (mr *MockCarsMockRecorder) Create(ctx, car, allowDuplicate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCars)(nil).Create), ctx, car)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCars)(nil).Create), ctx, car, allowDuplicate)
 }
 
 // Delete mocks base method.
@@ -64,6 +110,35 @@ func (mr *MockCarsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCars)(nil).Delete), ctx, id)
 }
 
+// Duplicates mocks base method.
+func (m *MockCars) Duplicates(ctx *gofr.Context) ([]models.DuplicateGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Duplicates", ctx)
+	ret0, _ := ret[0].([]models.DuplicateGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Duplicates indicates an expected call of Duplicates.
+func (mr *MockCarsMockRecorder) Duplicates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Duplicates", reflect.TypeOf((*MockCars)(nil).Duplicates), ctx)
+}
+
+// Export mocks base method.
+func (m *MockCars) Export(ctx *gofr.Context, brand string, fn func(models.Car) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, brand, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockCarsMockRecorder) Export(ctx, brand, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockCars)(nil).Export), ctx, brand, fn)
+}
+
 // GetByBrand mocks base method.
 func (m *MockCars) GetByBrand(ctx *gofr.Context, brand string, isEngine bool) ([]models.Car, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +169,80 @@ func (mr *MockCarsMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCars)(nil).GetByID), ctx, id)
 }
 
+// GetByIDs mocks base method.
+func (m *MockCars) GetByIDs(ctx *gofr.Context, ids []string) ([]models.Car, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.Car)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockCarsMockRecorder) GetByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockCars)(nil).GetByIDs), ctx, ids)
+}
+
+// GetEngines mocks base method.
+func (m *MockCars) GetEngines(ctx *gofr.Context, ids []string) ([]models.Engine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEngines", ctx, ids)
+	ret0, _ := ret[0].([]models.Engine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEngines indicates an expected call of GetEngines.
+func (mr *MockCarsMockRecorder) GetEngines(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEngines", reflect.TypeOf((*MockCars)(nil).GetEngines), ctx, ids)
+}
+
+// Import mocks base method.
+func (m *MockCars) Import(ctx *gofr.Context, rows []models.ImportRow, dryRun bool) (models.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, rows, dryRun)
+	ret0, _ := ret[0].(models.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockCarsMockRecorder) Import(ctx, rows, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockCars)(nil).Import), ctx, rows, dryRun)
+}
+
+// ReportInventory mocks base method.
+func (m *MockCars) ReportInventory(ctx *gofr.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportInventory", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportInventory indicates an expected call of ReportInventory.
+func (mr *MockCarsMockRecorder) ReportInventory(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportInventory", reflect.TypeOf((*MockCars)(nil).ReportInventory), ctx)
+}
+
+// Search mocks base method.
+func (m *MockCars) Search(ctx *gofr.Context, query string, limit int) ([]models.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, limit)
+	ret0, _ := ret[0].([]models.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockCarsMockRecorder) Search(ctx, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockCars)(nil).Search), ctx, query, limit)
+}
+
 // Update mocks base method.
 func (m *MockCars) Update(ctx *gofr.Context, id string, car *models.Car) (models.Car, error) {
 	m.ctrl.T.Helper()
@@ -108,3 +257,281 @@ func (mr *MockCarsMockRecorder) Update(ctx, id, car interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCars)(nil).Update), ctx, id, car)
 }
+
+// MockDealerships is a mock of Dealerships interface.
+type MockDealerships struct {
+	ctrl     *gomock.Controller
+	recorder *MockDealershipsMockRecorder
+}
+
+// MockDealershipsMockRecorder is the mock recorder for MockDealerships.
+type MockDealershipsMockRecorder struct {
+	mock *MockDealerships
+}
+
+// NewMockDealerships creates a new mock instance.
+func NewMockDealerships(ctrl *gomock.Controller) *MockDealerships {
+	mock := &MockDealerships{ctrl: ctrl}
+	mock.recorder = &MockDealershipsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDealerships) EXPECT() *MockDealershipsMockRecorder {
+	return m.recorder
+}
+
+// GetByIDs mocks base method.
+func (m *MockDealerships) GetByIDs(ctx *gofr.Context, ids []string) ([]models.Dealership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.Dealership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockDealershipsMockRecorder) GetByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockDealerships)(nil).GetByIDs), ctx, ids)
+}
+
+// MockMedia is a mock of Media interface.
+type MockMedia struct {
+	ctrl     *gomock.Controller
+	recorder *MockMediaMockRecorder
+}
+
+// MockMediaMockRecorder is the mock recorder for MockMedia.
+type MockMediaMockRecorder struct {
+	mock *MockMedia
+}
+
+// NewMockMedia creates a new mock instance.
+func NewMockMedia(ctrl *gomock.Controller) *MockMedia {
+	mock := &MockMedia{ctrl: ctrl}
+	mock.recorder = &MockMediaMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMedia) EXPECT() *MockMediaMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockMedia) Delete(ctx *gofr.Context, carID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, carID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockMediaMockRecorder) Delete(ctx, carID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMedia)(nil).Delete), ctx, carID, id)
+}
+
+// GetByCarID mocks base method.
+func (m *MockMedia) GetByCarID(ctx *gofr.Context, carID string) ([]models.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCarID", ctx, carID)
+	ret0, _ := ret[0].([]models.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCarID indicates an expected call of GetByCarID.
+func (mr *MockMediaMockRecorder) GetByCarID(ctx, carID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCarID", reflect.TypeOf((*MockMedia)(nil).GetByCarID), ctx, carID)
+}
+
+// Open mocks base method.
+func (m *MockMedia) Open(ctx *gofr.Context, key string) (io.ReadCloser, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, key)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockMediaMockRecorder) Open(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockMedia)(nil).Open), ctx, key)
+}
+
+// Reorder mocks base method.
+func (m *MockMedia) Reorder(ctx *gofr.Context, carID string, ids []string) ([]models.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, carID, ids)
+	ret0, _ := ret[0].([]models.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockMediaMockRecorder) Reorder(ctx, carID, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockMedia)(nil).Reorder), ctx, carID, ids)
+}
+
+// SetCover mocks base method.
+func (m *MockMedia) SetCover(ctx *gofr.Context, carID, id string) ([]models.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCover", ctx, carID, id)
+	ret0, _ := ret[0].([]models.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCover indicates an expected call of SetCover.
+func (mr *MockMediaMockRecorder) SetCover(ctx, carID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCover", reflect.TypeOf((*MockMedia)(nil).SetCover), ctx, carID, id)
+}
+
+// Upload mocks base method.
+func (m *MockMedia) Upload(ctx *gofr.Context, carID string, files []models.Upload) ([]models.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", ctx, carID, files)
+	ret0, _ := ret[0].([]models.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockMediaMockRecorder) Upload(ctx, carID, files interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockMedia)(nil).Upload), ctx, carID, files)
+}
+
+// MockWebhooks is a mock of Webhooks interface.
+type MockWebhooks struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhooksMockRecorder
+}
+
+// MockWebhooksMockRecorder is the mock recorder for MockWebhooks.
+type MockWebhooksMockRecorder struct {
+	mock *MockWebhooks
+}
+
+// NewMockWebhooks creates a new mock instance.
+func NewMockWebhooks(ctrl *gomock.Controller) *MockWebhooks {
+	mock := &MockWebhooks{ctrl: ctrl}
+	mock.recorder = &MockWebhooksMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhooks) EXPECT() *MockWebhooksMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhooks) Create(ctx *gofr.Context, webhook *models.Webhook) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, webhook)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhooksMockRecorder) Create(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhooks)(nil).Create), ctx, webhook)
+}
+
+// Delete mocks base method.
+func (m *MockWebhooks) Delete(ctx *gofr.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhooksMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhooks)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockWebhooks) GetAll(ctx *gofr.Context) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWebhooksMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWebhooks)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockWebhooks) GetByID(ctx *gofr.Context, id string) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockWebhooksMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWebhooks)(nil).GetByID), ctx, id)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockWebhooks) GetDeliveries(ctx *gofr.Context, id, status string, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, id, status, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockWebhooksMockRecorder) GetDeliveries(ctx, id, status, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockWebhooks)(nil).GetDeliveries), ctx, id, status, limit)
+}
+
+// Retry mocks base method.
+func (m *MockWebhooks) Retry(ctx *gofr.Context, id, deliveryID string) (models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", ctx, id, deliveryID)
+	ret0, _ := ret[0].(models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retry indicates an expected call of Retry.
+func (mr *MockWebhooksMockRecorder) Retry(ctx, id, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockWebhooks)(nil).Retry), ctx, id, deliveryID)
+}
+
+// Update mocks base method.
+func (m *MockWebhooks) Update(ctx *gofr.Context, id string, webhook *models.Webhook) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, webhook)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhooksMockRecorder) Update(ctx, id, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhooks)(nil).Update), ctx, id, webhook)
+}
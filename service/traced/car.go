@@ -0,0 +1,174 @@
+// Package traced wraps the services to trace every method in a span of its own, the spans of the stores they
+// call are its children.
+package traced
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/service"
+	"Project/CarDealearship/tracing"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CarIDKey is the attribute of the car a span is about
+const CarIDKey = attribute.Key("car.id")
+
+type cars struct {
+	service service.Cars
+}
+
+// nolint:revive // need not be exported
+// NewCars factory function
+func NewCars(s service.Cars) cars {
+	return cars{service: s}
+}
+
+// GetByID gets the car
+func (s cars) GetByID(ctx *gofr.Context, id string) (models.Car, error) {
+	ctx, span := start(ctx, "GetByID", CarIDKey.String(id))
+	res, err := s.service.GetByID(ctx, id)
+	span.End(err)
+
+	return res, err
+}
+
+// GetByBrand gets the cars of the brand
+func (s cars) GetByBrand(ctx *gofr.Context, brand string, isEngine bool) ([]models.Car, error) {
+	ctx, span := start(ctx, "GetByBrand", attribute.String("car.brand", brand))
+	res, err := s.service.GetByBrand(ctx, brand, isEngine)
+	endRows(span, len(res), err)
+
+	return res, err
+}
+
+// Create creates the car
+func (s cars) Create(ctx *gofr.Context, car *models.Car, allowDuplicate bool) (models.Car, error) {
+	ctx, span := start(ctx, "Create")
+	res, err := s.service.Create(ctx, car, allowDuplicate)
+	span.End(err)
+
+	return res, err
+}
+
+// Delete deletes the car
+func (s cars) Delete(ctx *gofr.Context, id string) error {
+	ctx, span := start(ctx, "Delete", CarIDKey.String(id))
+	err := s.service.Delete(ctx, id)
+	span.End(err)
+
+	return err
+}
+
+// Update updates the car
+func (s cars) Update(ctx *gofr.Context, id string, car *models.Car) (models.Car, error) {
+	ctx, span := start(ctx, "Update", CarIDKey.String(id))
+	res, err := s.service.Update(ctx, id, car)
+	span.End(err)
+
+	return res, err
+}
+
+// Duplicates gets the groups of likely duplicate cars
+func (s cars) Duplicates(ctx *gofr.Context) ([]models.DuplicateGroup, error) {
+	ctx, span := start(ctx, "Duplicates")
+	res, err := s.service.Duplicates(ctx)
+	endRows(span, len(res), err)
+
+	return res, err
+}
+
+// BatchGet gets the cars
+func (s cars) BatchGet(ctx *gofr.Context, ids []string) ([]models.BatchResult, error) {
+	ctx, span := start(ctx, "BatchGet")
+	res, err := s.service.BatchGet(ctx, ids)
+	endRows(span, len(res), err)
+
+	return res, err
+}
+
+// BatchUpdate patches the cars
+func (s cars) BatchUpdate(ctx *gofr.Context, patches []models.CarPatch) ([]models.BatchResult, error) {
+	ctx, span := start(ctx, "BatchUpdate")
+	res, err := s.service.BatchUpdate(ctx, patches)
+	endRows(span, len(res), err)
+
+	return res, err
+}
+
+// Search finds the cars matching the query, the query is not recorded as it is free text of the user
+func (s cars) Search(ctx *gofr.Context, query string, limit int) ([]models.SearchResult, error) {
+	ctx, span := start(ctx, "Search")
+	res, err := s.service.Search(ctx, query, limit)
+	endRows(span, len(res), err)
+
+	return res, err
+}
+
+// Compare compares the cars
+func (s cars) Compare(ctx *gofr.Context, ids []string) (models.Comparison, error) {
+	ctx, span := start(ctx, "Compare")
+	res, err := s.service.Compare(ctx, ids)
+	span.End(err)
+
+	return res, err
+}
+
+// Import creates the cars of the rows
+func (s cars) Import(ctx *gofr.Context, rows []models.ImportRow, dryRun bool) (models.ImportReport, error) {
+	ctx, span := start(ctx, "Import", attribute.Int("import.rows", len(rows)), attribute.Bool("import.dry_run", dryRun))
+	res, err := s.service.Import(ctx, rows, dryRun)
+	span.End(err)
+
+	return res, err
+}
+
+// Export passes the cars of the brand to fn, the span includes the time spent in fn
+func (s cars) Export(ctx *gofr.Context, brand string, fn func(car models.Car) error) error {
+	ctx, span := start(ctx, "Export", attribute.String("car.brand", brand))
+	n := 0
+
+	err := s.service.Export(ctx, brand, func(car models.Car) error {
+		n++
+		return fn(car)
+	})
+	endRows(span, n, err)
+
+	return err
+}
+
+// GetByIDs gets the cars
+func (s cars) GetByIDs(ctx *gofr.Context, ids []string) ([]models.Car, error) {
+	ctx, span := start(ctx, "GetByIDs")
+	res, err := s.service.GetByIDs(ctx, ids)
+	endRows(span, len(res), err)
+
+	return res, err
+}
+
+// GetEngines gets the engines
+func (s cars) GetEngines(ctx *gofr.Context, ids []string) ([]models.Engine, error) {
+	ctx, span := start(ctx, "GetEngines")
+	res, err := s.service.GetEngines(ctx, ids)
+	endRows(span, len(res), err)
+
+	return res, err
+}
+
+// ReportInventory sets the inventory gauges
+func (s cars) ReportInventory(ctx *gofr.Context) error {
+	ctx, span := start(ctx, "ReportInventory")
+	err := s.service.ReportInventory(ctx)
+	span.End(err)
+
+	return err
+}
+
+func start(ctx *gofr.Context, method string, attrs ...attribute.KeyValue) (*gofr.Context, *tracing.Span) {
+	return tracing.Start(ctx, "service.car."+method, attrs...)
+}
+
+func endRows(span *tracing.Span, n int, err error) {
+	span.SetRows(n)
+	span.End(err)
+}
@@ -0,0 +1,227 @@
+package traced
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/service"
+	carService "Project/CarDealearship/service/car"
+	"Project/CarDealearship/stores/car"
+	"Project/CarDealearship/stores/engine"
+	"Project/CarDealearship/stores/instrumented"
+	"Project/CarDealearship/stores/media"
+	"Project/CarDealearship/stores/outbox"
+	"Project/CarDealearship/stores/search"
+	"Project/CarDealearship/stores/transaction"
+	"Project/CarDealearship/tracing"
+
+	"developer.zopsmart.com/go/gofr/pkg/datastore"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newExporter() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	return exporter
+}
+
+// spanned matches the copies of ctx that carry a span, which the calls are made with
+type spanned struct {
+	ctx *gofr.Context
+}
+
+func (m spanned) Matches(x interface{}) bool {
+	c, ok := x.(*gofr.Context)
+
+	return ok && c != m.ctx && c.Gofr == m.ctx.Gofr && trace.SpanFromContext(c).SpanContext().IsValid()
+}
+
+func (m spanned) String() string {
+	return "a copy of the context carrying a span"
+}
+
+func TestCars(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	exporter := newExporter()
+	mockService := service.NewMockCars(ctrl)
+	s := NewCars(mockService)
+
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = context.TODO()
+
+	c := models.Car{ID: uuid.New(), Name: "M3", Brand: "BMW"}
+	id := c.ID.String()
+	rows := []models.ImportRow{{Row: 2, Car: c}}
+	fn := func(models.Car) error { return nil }
+
+	gomock.InOrder(
+		mockService.EXPECT().GetByID(spanned{ctx}, id).Return(c, nil),
+		mockService.EXPECT().GetByBrand(spanned{ctx}, "BMW", true).Return([]models.Car{c, c}, nil),
+		mockService.EXPECT().Create(spanned{ctx}, &c, false).Return(c, nil),
+		mockService.EXPECT().Update(spanned{ctx}, id, &c).Return(models.Car{}, errors.EntityNotFound{Entity: "Car", ID: id}),
+		mockService.EXPECT().Delete(spanned{ctx}, id).Return(nil),
+		mockService.EXPECT().Duplicates(spanned{ctx}).Return(nil, nil),
+		mockService.EXPECT().BatchGet(spanned{ctx}, []string{id}).Return([]models.BatchResult{{ID: id}}, nil),
+		mockService.EXPECT().BatchUpdate(spanned{ctx}, nil).Return(nil, errors.Error("db down")),
+		mockService.EXPECT().Search(spanned{ctx}, "bmw", 10).Return(nil, nil),
+		mockService.EXPECT().Compare(spanned{ctx}, []string{id}).Return(models.Comparison{}, nil),
+		mockService.EXPECT().Import(spanned{ctx}, rows, true).Return(models.ImportReport{}, nil),
+		mockService.EXPECT().Export(spanned{ctx}, "BMW", gomock.Any()).
+			DoAndReturn(func(_ *gofr.Context, _ string, fn func(models.Car) error) error {
+				_ = fn(c)
+				return fn(c)
+			}),
+		mockService.EXPECT().GetByIDs(spanned{ctx}, []string{id}).Return([]models.Car{c}, nil),
+		mockService.EXPECT().GetEngines(spanned{ctx}, []string{id}).Return(nil, nil),
+		mockService.EXPECT().ReportInventory(spanned{ctx}).Return(nil),
+	)
+
+	res, _ := s.GetByID(ctx, id)
+	assert.Equal(t, c, res)
+
+	cars, _ := s.GetByBrand(ctx, "BMW", true)
+	assert.Equal(t, []models.Car{c, c}, cars)
+
+	res, _ = s.Create(ctx, &c, false)
+	assert.Equal(t, c, res)
+
+	_, err := s.Update(ctx, id, &c)
+	assert.Equal(t, errors.EntityNotFound{Entity: "Car", ID: id}, err, "errors are returned as they are")
+
+	assert.Equal(t, nil, s.Delete(ctx, id))
+
+	_, _ = s.Duplicates(ctx)
+	_, _ = s.BatchGet(ctx, []string{id})
+
+	_, err = s.BatchUpdate(ctx, nil)
+	assert.Equal(t, errors.Error("db down"), err)
+
+	_, _ = s.Search(ctx, "bmw", 10)
+	_, _ = s.Compare(ctx, []string{id})
+	_, _ = s.Import(ctx, rows, true)
+	assert.Equal(t, nil, s.Export(ctx, "BMW", fn))
+	_, _ = s.GetByIDs(ctx, []string{id})
+	_, _ = s.GetEngines(ctx, []string{id})
+	assert.Equal(t, nil, s.ReportInventory(ctx))
+
+	assert.Equal(t, context.TODO(), ctx.Context, "ctx is left as it is")
+
+	spans := exporter.GetSpans()
+	if !assert.Len(t, spans, 15) {
+		return
+	}
+
+	tests := []struct {
+		index  int
+		name   string
+		attrs  []attribute.KeyValue
+		status codes.Code
+	}{
+		{0, "service.car.GetByID", []attribute.KeyValue{CarIDKey.String(id)}, codes.Unset},
+		{1, "service.car.GetByBrand", []attribute.KeyValue{attribute.String("car.brand", "BMW"), tracing.RowsKey.Int(2)},
+			codes.Unset},
+		{2, "service.car.Create", nil, codes.Unset},
+		{3, "service.car.Update", []attribute.KeyValue{CarIDKey.String(id)}, codes.Error},
+		{7, "service.car.BatchUpdate", []attribute.KeyValue{tracing.RowsKey.Int(0)}, codes.Error},
+		{8, "service.car.Search", []attribute.KeyValue{tracing.RowsKey.Int(0)}, codes.Unset},
+		{10, "service.car.Import", []attribute.KeyValue{attribute.Int("import.rows", 1),
+			attribute.Bool("import.dry_run", true)}, codes.Unset},
+		{11, "service.car.Export", []attribute.KeyValue{attribute.String("car.brand", "BMW"), tracing.RowsKey.Int(2)},
+			codes.Unset},
+		{14, "service.car.ReportInventory", nil, codes.Unset},
+	}
+
+	for i, tc := range tests {
+		span := spans[tc.index]
+
+		assert.Equal(t, tc.name, span.Name, "TEST[%d], failed.\n%s", i, tc.name)
+		assert.Equal(t, tc.attrs, span.Attributes, "TEST[%d], failed.\n%s", i, tc.name)
+		assert.Equal(t, tc.status, span.Status.Code, "TEST[%d], failed.\n%s", i, tc.name)
+	}
+}
+
+// TestPropagation tests that the spans of the service, of the stores and of their statements form a single trace
+// when a car is deleted, the statements of the transaction included
+func TestPropagation(t *testing.T) {
+	exporter := newExporter()
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	s := NewCars(carService.New(instrumented.NewCar(car.New(), nil), instrumented.NewEngine(engine.New(), nil),
+		media.New(), nil, search.New(), transaction.New(), outbox.New(), nil))
+
+	id := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM Car WHERE ID=?").WithArgs(id.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "engine_id", "name", "year", "brand", "fuel_type", "status",
+			"cost_price", "dealership_id", "vin", "updated_at"}).
+			AddRow(id, id, "M3", 2020, "BMW", "Petrol", models.CarAvailable, nil, nil, nil, time.Now()))
+	mock.ExpectQuery("SELECT .* FROM Media WHERE car_id=?").WithArgs(id.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("DELETE FROM Media").WithArgs(id.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM Car").WithArgs(id.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("delete from Engine").WithArgs(id.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO Outbox").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.Equal(t, nil, s.Delete(ctx, id.String()))
+	assert.Equal(t, nil, mock.ExpectationsWereMet())
+
+	// spans are exported as they end, the statements of the transaction are children of the store calls
+	// running them
+	tests := []struct {
+		name   string
+		parent int
+	}{
+		{"SQL SELECT", 1},
+		{"store.car.GetCarByID", 9},
+		{"SQL SELECT", 9},
+		{"SQL DELETE", 9},
+		{"SQL DELETE", 5},
+		{"store.car.DeleteCar", 9},
+		{"SQL DELETE", 7},
+		{"store.engine.EngineDelete", 9},
+		{"SQL INSERT", 9},
+		{"service.car.Delete", -1},
+	}
+
+	spans := exporter.GetSpans()
+	if !assert.Len(t, spans, len(tests)) {
+		return
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.name, spans[i].Name, "TEST[%d], failed.\n%s", i, tc.name)
+		assert.Equal(t, spans[9].SpanContext.TraceID(), spans[i].SpanContext.TraceID(), "TEST[%d], failed.\n%s", i,
+			tc.name)
+
+		if tc.parent < 0 {
+			assert.False(t, spans[i].Parent.IsValid(), "TEST[%d], failed.\n%s", i, tc.name)
+			continue
+		}
+
+		assert.Equal(t, spans[tc.parent].SpanContext.SpanID(), spans[i].Parent.SpanID(), "TEST[%d], failed.\n%s", i,
+			tc.name)
+	}
+}
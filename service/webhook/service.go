@@ -0,0 +1,192 @@
+package webhook
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+)
+
+const secretSize = 32
+
+var eventTypes = map[string]bool{
+	models.EventCarCreated:       true,
+	models.EventCarUpdated:       true,
+	models.EventCarDeleted:       true,
+	models.EventCarStatusChanged: true,
+}
+
+var deliveryStatuses = map[string]bool{
+	models.DeliveryPending:   true,
+	models.DeliveryDelivered: true,
+	models.DeliveryDead:      true,
+}
+
+type service struct {
+	store stores.Webhook
+}
+
+// nolint:revive // need not be exported
+// New factory function
+func New(s stores.Webhook) service {
+	return service{store: s}
+}
+
+// GetAll returns every webhook, without their secrets
+func (s service) GetAll(ctx *gofr.Context) ([]models.Webhook, error) {
+	webhooks, err := s.store.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// GetByID returns a webhook without its secret
+func (s service) GetByID(ctx *gofr.Context, id string) (models.Webhook, error) {
+	w, err := s.find(ctx, id)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	w.Secret = ""
+
+	return w, nil
+}
+
+// Create subscribes an endpoint to car events. The webhook is active and gets a generated secret, returned
+// only here, that signs its deliveries.
+func (s service) Create(ctx *gofr.Context, webhook *models.Webhook) (models.Webhook, error) {
+	if err := validate(webhook); err != nil {
+		return models.Webhook{}, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	w := models.Webhook{ID: uuid.New(), URL: webhook.URL, Events: webhook.Events, Active: true, Secret: secret,
+		CreatedAt: time.Now().UTC()}
+
+	return s.store.CreateWebhook(ctx, &w)
+}
+
+// Update changes the url, the events and the state of a webhook, its secret is kept
+func (s service) Update(ctx *gofr.Context, id string, webhook *models.Webhook) (models.Webhook, error) {
+	if err := validate(webhook); err != nil {
+		return models.Webhook{}, err
+	}
+
+	prev, err := s.find(ctx, id)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	w := models.Webhook{ID: prev.ID, URL: webhook.URL, Events: webhook.Events, Active: webhook.Active,
+		CreatedAt: prev.CreatedAt}
+
+	return s.store.UpdateWebhook(ctx, id, &w)
+}
+
+// Delete removes a webhook along with its deliveries
+func (s service) Delete(ctx *gofr.Context, id string) error {
+	if _, err := s.find(ctx, id); err != nil {
+		return err
+	}
+
+	return s.store.DeleteWebhook(ctx, id)
+}
+
+// GetDeliveries returns the latest limit deliveries of a webhook, newest first, only the ones with the given
+// status unless it is empty
+func (s service) GetDeliveries(ctx *gofr.Context, id, status string, limit int) ([]models.WebhookDelivery, error) {
+	if status != "" && !deliveryStatuses[status] {
+		return nil, errors.InvalidParam{Param: []string{"status"}}
+	}
+
+	if _, err := s.find(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.store.GetDeliveries(ctx, id, status, limit)
+}
+
+// Retry sends a dead delivery again, with as many attempts as a new one
+func (s service) Retry(ctx *gofr.Context, id, deliveryID string) (models.WebhookDelivery, error) {
+	if _, err := s.find(ctx, id); err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	d, err := s.store.GetDeliveryByID(ctx, deliveryID)
+	if err != nil || d.WebhookID.String() != id {
+		return models.WebhookDelivery{}, errors.EntityNotFound{Entity: "WebhookDelivery", ID: deliveryID}
+	}
+
+	if d.Status != models.DeliveryDead {
+		return models.WebhookDelivery{}, &errors.Response{
+			StatusCode: http.StatusConflict,
+			Code:       "CONFLICT",
+			Reason:     "only dead deliveries can be retried, the delivery is " + d.Status,
+		}
+	}
+
+	now := time.Now().UTC()
+	d.Status, d.Attempts, d.NextAttemptAt = models.DeliveryPending, 0, &now
+
+	if err = s.store.UpdateDelivery(ctx, &d); err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	return d, nil
+}
+
+func (s service) find(ctx *gofr.Context, id string) (models.Webhook, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return models.Webhook{}, errors.InvalidParam{Param: []string{"id"}}
+	}
+
+	w, err := s.store.GetWebhookByID(ctx, id)
+	if err != nil {
+		return models.Webhook{}, errors.EntityNotFound{Entity: "Webhook", ID: id}
+	}
+
+	return w, nil
+}
+
+// validate checks the url of a webhook is an absolute http one and its events are known types
+func validate(w *models.Webhook) error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.InvalidParam{Param: []string{"URL"}}
+	}
+
+	for _, e := range w.Events {
+		if !eventTypes[e] {
+			return errors.InvalidParam{Param: []string{"Events"}}
+		}
+	}
+
+	return nil
+}
+
+// newSecret returns a random hex secret
+func newSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
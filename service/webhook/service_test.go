@@ -0,0 +1,198 @@
+package webhook
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := stores.NewMockWebhook(ctrl)
+	s := New(mockStore)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	w := models.Webhook{ID: uuid.New(), URL: "https://crm.example.com/hooks", Active: true, Secret: "s1"}
+	public := w
+	public.Secret = ""
+
+	mockStore.EXPECT().GetWebhooks(ctx).Return([]models.Webhook{w}, nil)
+	mockStore.EXPECT().GetWebhooks(ctx).Return(nil, errors.Error("db down"))
+	mockStore.EXPECT().GetWebhookByID(ctx, w.ID.String()).Return(w, nil)
+	mockStore.EXPECT().GetWebhookByID(ctx, gomock.Any()).Return(models.Webhook{}, errors.Error("no rows"))
+
+	all, err := s.GetAll(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.Webhook{public}, all, "secrets are not listed")
+
+	all, err = s.GetAll(ctx)
+	assert.Equal(t, errors.Error("db down"), err)
+	assert.Equal(t, []models.Webhook(nil), all)
+
+	res, err := s.GetByID(ctx, w.ID.String())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, public, res)
+
+	missing := uuid.NewString()
+
+	_, err = s.GetByID(ctx, missing)
+	assert.Equal(t, errors.EntityNotFound{Entity: "Webhook", ID: missing}, err)
+
+	_, err = s.GetByID(ctx, "not-an-id")
+	assert.Equal(t, errors.InvalidParam{Param: []string{"id"}}, err)
+}
+
+func TestCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := stores.NewMockWebhook(ctrl)
+	s := New(mockStore)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	testCases := []struct {
+		desc  string
+		input models.Webhook
+		err   error
+	}{
+		{desc: "created", input: models.Webhook{URL: "https://crm.example.com/hooks",
+			Events: []string{models.EventCarCreated, models.EventCarStatusChanged}}},
+		{desc: "every event", input: models.Webhook{URL: "http://localhost:8080/hooks"}},
+		{desc: "relative url", input: models.Webhook{URL: "/hooks"}, err: errors.InvalidParam{Param: []string{"URL"}}},
+		{desc: "not http", input: models.Webhook{URL: "ftp://crm.example.com"},
+			err: errors.InvalidParam{Param: []string{"URL"}}},
+		{desc: "unknown event", input: models.Webhook{URL: "https://crm.example.com/hooks",
+			Events: []string{"CarPainted"}}, err: errors.InvalidParam{Param: []string{"Events"}}},
+	}
+
+	mockStore.EXPECT().CreateWebhook(ctx, gomock.Any()).
+		DoAndReturn(func(_ *gofr.Context, w *models.Webhook) (models.Webhook, error) { return *w, nil }).Times(2)
+
+	for i, tc := range testCases {
+		res, err := s.Create(ctx, &tc.input)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.err != nil {
+			continue
+		}
+
+		assert.NotEqual(t, uuid.Nil, res.ID, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Len(t, res.Secret, 64, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.False(t, res.CreatedAt.IsZero(), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, models.Webhook{ID: res.ID, URL: tc.input.URL, Events: tc.input.Events, Active: true,
+			Secret: res.Secret, CreatedAt: res.CreatedAt}, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestUpdateAndDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := stores.NewMockWebhook(ctrl)
+	s := New(mockStore)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	created := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	prev := models.Webhook{ID: uuid.New(), URL: "https://crm.example.com/hooks", Active: true, Secret: "s1",
+		CreatedAt: created}
+	input := models.Webhook{URL: "https://crm.example.com/v2/hooks", Events: []string{models.EventCarDeleted},
+		Secret: "chosen"}
+	updated := models.Webhook{ID: prev.ID, URL: input.URL, Events: input.Events, CreatedAt: created}
+	id := prev.ID.String()
+
+	mockStore.EXPECT().GetWebhookByID(ctx, id).Return(prev, nil).Times(2)
+	mockStore.EXPECT().UpdateWebhook(ctx, id, &updated).Return(updated, nil)
+	mockStore.EXPECT().DeleteWebhook(ctx, id).Return(nil)
+
+	res, err := s.Update(ctx, id, &input)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, updated, res, "the secret can not be changed")
+
+	_, err = s.Update(ctx, id, &models.Webhook{URL: "hooks"})
+	assert.Equal(t, errors.InvalidParam{Param: []string{"URL"}}, err)
+
+	assert.Equal(t, nil, s.Delete(ctx, id))
+
+	missing := uuid.NewString()
+
+	mockStore.EXPECT().GetWebhookByID(ctx, missing).Return(models.Webhook{}, errors.Error("no rows"))
+	assert.Equal(t, errors.EntityNotFound{Entity: "Webhook", ID: missing}, s.Delete(ctx, missing))
+}
+
+func TestGetDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := stores.NewMockWebhook(ctrl)
+	s := New(mockStore)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	id := uuid.New()
+	deliveries := []models.WebhookDelivery{{ID: uuid.New(), WebhookID: id, Status: models.DeliveryDead}}
+
+	mockStore.EXPECT().GetWebhookByID(ctx, id.String()).Return(models.Webhook{ID: id}, nil)
+	mockStore.EXPECT().GetDeliveries(ctx, id.String(), models.DeliveryDead, 20).Return(deliveries, nil)
+
+	res, err := s.GetDeliveries(ctx, id.String(), models.DeliveryDead, 20)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, deliveries, res)
+
+	_, err = s.GetDeliveries(ctx, id.String(), "lost", 20)
+	assert.Equal(t, errors.InvalidParam{Param: []string{"status"}}, err)
+}
+
+func TestRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := stores.NewMockWebhook(ctrl)
+	s := New(mockStore)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	id := uuid.New()
+	dead := models.WebhookDelivery{ID: uuid.New(), WebhookID: id, Status: models.DeliveryDead, Attempts: 8,
+		ResponseCode: 500, LastError: "unexpected response 500 Internal Server Error"}
+	delivered := models.WebhookDelivery{ID: uuid.New(), WebhookID: id, Status: models.DeliveryDelivered}
+	other := models.WebhookDelivery{ID: uuid.New(), WebhookID: uuid.New(), Status: models.DeliveryDead}
+
+	mockStore.EXPECT().GetWebhookByID(ctx, id.String()).Return(models.Webhook{ID: id}, nil).AnyTimes()
+	mockStore.EXPECT().GetDeliveryByID(ctx, dead.ID.String()).Return(dead, nil)
+	mockStore.EXPECT().GetDeliveryByID(ctx, delivered.ID.String()).Return(delivered, nil)
+	mockStore.EXPECT().GetDeliveryByID(ctx, other.ID.String()).Return(other, nil)
+
+	var updated models.WebhookDelivery
+
+	mockStore.EXPECT().UpdateDelivery(ctx, gomock.Any()).DoAndReturn(func(_ *gofr.Context,
+		d *models.WebhookDelivery) error {
+		updated = *d
+		return nil
+	})
+
+	res, err := s.Retry(ctx, id.String(), dead.ID.String())
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, models.DeliveryPending, res.Status)
+	assert.Equal(t, 0, res.Attempts)
+	assert.NotNil(t, res.NextAttemptAt)
+	assert.Equal(t, dead.LastError, res.LastError, "the last error is kept until the next attempt")
+	assert.Equal(t, res, updated)
+
+	_, err = s.Retry(ctx, id.String(), delivered.ID.String())
+	assert.Equal(t, &errors.Response{StatusCode: http.StatusConflict, Code: "CONFLICT",
+		Reason: "only dead deliveries can be retried, the delivery is delivered"}, err)
+
+	_, err = s.Retry(ctx, id.String(), other.ID.String())
+	assert.Equal(t, errors.EntityNotFound{Entity: "WebhookDelivery", ID: other.ID.String()}, err)
+}
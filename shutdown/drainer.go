@@ -0,0 +1,68 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+)
+
+// Drainer counts the requests in flight, once it drains new requests are turned away
+type Drainer struct {
+	mu       sync.Mutex
+	inFlight int
+	draining bool
+	idle     chan struct{}
+}
+
+// NewDrainer factory function
+func NewDrainer() *Drainer {
+	return &Drainer{idle: make(chan struct{})}
+}
+
+// Acquire counts a new request in flight, it returns false when the drainer drains and the request must be
+// turned away. Every acquired request is released.
+func (d *Drainer) Acquire() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.draining {
+		return false
+	}
+
+	d.inFlight++
+
+	return true
+}
+
+// Release counts a request out
+func (d *Drainer) Release() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.inFlight--
+
+	if d.draining && d.inFlight == 0 {
+		close(d.idle)
+	}
+}
+
+// Drain turns the new requests away and waits for the ones in flight to end, or for ctx to be done
+func (d *Drainer) Drain(ctx context.Context) error {
+	d.mu.Lock()
+
+	if !d.draining {
+		d.draining = true
+
+		if d.inFlight == 0 {
+			close(d.idle)
+		}
+	}
+
+	d.mu.Unlock()
+
+	select {
+	case <-d.idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
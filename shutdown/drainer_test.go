@@ -0,0 +1,57 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrain(t *testing.T) {
+	d := NewDrainer()
+
+	assert.True(t, d.Acquire())
+	assert.True(t, d.Acquire())
+	d.Release()
+
+	drained := make(chan error)
+
+	go func() {
+		drained <- d.Drain(context.Background())
+	}()
+
+	// Drain turns new requests away before it waits
+	for d.Acquire() {
+		d.Release()
+	}
+
+	select {
+	case <-drained:
+		t.Fatal("drained with a request in flight")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	d.Release()
+
+	assert.Equal(t, nil, <-drained)
+	assert.False(t, d.Acquire(), "requests stay turned away")
+	assert.Equal(t, nil, d.Drain(context.Background()), "a drained drainer drains again at once")
+}
+
+func TestDrainIdle(t *testing.T) {
+	d := NewDrainer()
+
+	assert.Equal(t, nil, d.Drain(context.Background()))
+	assert.False(t, d.Acquire())
+}
+
+func TestDrainTimeout(t *testing.T) {
+	d := NewDrainer()
+	assert.True(t, d.Acquire())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, d.Drain(ctx))
+}
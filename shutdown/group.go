@@ -0,0 +1,50 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs the background workers of the application until it is stopped
+type Group struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	stopped bool
+}
+
+// NewGroup factory function
+func NewGroup() *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Go runs fn in a goroutine of its own, fn returns once its ctx is done. fn is not run once the group stopped.
+func (g *Group) Go(fn func(ctx context.Context)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stopped {
+		return
+	}
+
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+		fn(g.ctx)
+	}()
+}
+
+// Stop tells the workers to return and waits for them, or for ctx to be done
+func (g *Group) Stop(ctx context.Context) error {
+	g.mu.Lock()
+	g.stopped = true
+	g.mu.Unlock()
+
+	g.cancel()
+
+	return Wait(ctx, g.wg.Wait)
+}
@@ -0,0 +1,49 @@
+package shutdown
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	g := NewGroup()
+
+	var finished int32
+
+	for i := 0; i < 3; i++ {
+		g.Go(func(ctx context.Context) {
+			<-ctx.Done()
+			atomic.AddInt32(&finished, 1)
+		})
+	}
+
+	assert.Equal(t, nil, g.Stop(context.Background()))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&finished), "Stop waits for the workers")
+
+	g.Go(func(ctx context.Context) {
+		atomic.AddInt32(&finished, 1)
+	})
+
+	assert.Equal(t, nil, g.Stop(context.Background()))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&finished), "workers are not run once the group stopped")
+}
+
+func TestGroupTimeout(t *testing.T) {
+	g := NewGroup()
+	block := make(chan struct{})
+
+	defer close(block)
+
+	g.Go(func(ctx context.Context) {
+		<-block
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, g.Stop(ctx))
+}
@@ -0,0 +1,70 @@
+// Package shutdown stops the application gracefully. Once it is told to stop, the requests in flight are drained
+// while new ones are turned away, the background workers finish what they are doing and the resources are
+// released, all within a deadline.
+package shutdown
+
+import (
+	"context"
+	"time"
+)
+
+// Logger logs the progress of the shutdown, gofr's logger satisfies it
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// Step is a stage of the shutdown, Run is given the context of the whole shutdown
+type Step struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Run runs the steps one after the other within timeout. A step that fails or runs out of time is logged and the
+// next ones still run, the last steps release resources that must be released anyway.
+func Run(logger Logger, timeout time.Duration, steps ...Step) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, s := range steps {
+		start := time.Now()
+
+		if err := s.Run(ctx); err != nil {
+			logger.Errorf("error in shutting down, %v: %v", s.Name, err)
+			continue
+		}
+
+		logger.Infof("shut down %v in %v", s.Name, time.Since(start))
+	}
+}
+
+// Delay waits d, or until ctx is done. Run first, it lets the load balancers see readiness fail and stop
+// routing requests to the application before the ones that still arrive are turned away.
+func Delay(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Wait runs fn, which blocks until something stopped, and returns once it did or once ctx is done
+func Wait(ctx context.Context, fn func()) error {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
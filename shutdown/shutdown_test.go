@@ -0,0 +1,78 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// logger keeps the messages logged
+type logger struct {
+	infos, errors []string
+}
+
+func (l *logger) Infof(format string, args ...interface{}) {
+	l.infos = append(l.infos, fmt.Sprintf(format, args...))
+}
+
+func (l *logger) Errorf(format string, args ...interface{}) {
+	l.errors = append(l.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRun(t *testing.T) {
+	var (
+		l     logger
+		order []string
+	)
+
+	step := func(name string, err error) Step {
+		return Step{Name: name, Run: func(ctx context.Context) error {
+			order = append(order, name)
+			return err
+		}}
+	}
+
+	slow := Step{Name: "slow", Run: func(ctx context.Context) error {
+		order = append(order, "slow")
+		<-ctx.Done()
+
+		return ctx.Err()
+	}}
+
+	Run(&l, 20*time.Millisecond, step("requests", nil), step("workers", errors.Error("relay stuck")), slow,
+		step("database", nil))
+
+	assert.Equal(t, []string{"requests", "workers", "slow", "database"}, order,
+		"the steps run in order, after a failure or a timeout too")
+	assert.Equal(t, []string{"error in shutting down, workers: relay stuck",
+		"error in shutting down, slow: context deadline exceeded"}, l.errors)
+	assert.Len(t, l.infos, 2)
+}
+
+func TestWait(t *testing.T) {
+	assert.Equal(t, nil, Wait(context.Background(), func() {}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	block := make(chan struct{})
+	defer close(block)
+
+	assert.Equal(t, context.DeadlineExceeded, Wait(ctx, func() { <-block }))
+}
+
+func TestDelay(t *testing.T) {
+	start := time.Now()
+
+	assert.Equal(t, nil, Delay(context.Background(), 10*time.Millisecond))
+	assert.True(t, time.Since(start) >= 10*time.Millisecond, "the delay is waited")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, Delay(ctx, time.Hour))
+}
@@ -0,0 +1,41 @@
+package apikey
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores/transaction"
+	"database/sql"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+type store struct{}
+
+// nolint:revive // need not be exported
+// New factory function
+func New() store {
+	return store{}
+}
+
+// GetAPIKeyByHash is the datastore layer function to get the api key with the given SHA-256 hash
+func (s store) GetAPIKeyByHash(ctx *gofr.Context, hash string) (models.APIKey, error) {
+	var (
+		k       models.APIKey
+		roles   string
+		revoked sql.NullTime
+	)
+
+	err := transaction.DB(ctx).QueryRowContext(ctx, "SELECT id,name,key_hash,roles,created_at,revoked_at FROM APIKey "+
+		"WHERE key_hash=?;", hash).Scan(&k.ID, &k.Name, &k.KeyHash, &roles, &k.CreatedAt, &revoked)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	k.Roles = strings.FieldsFunc(roles, func(r rune) bool { return r == ',' || r == ' ' })
+
+	if revoked.Valid {
+		k.RevokedAt = &revoked.Time
+	}
+
+	return k, nil
+}
@@ -0,0 +1,65 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/datastore"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAPIKeyByHash(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	s := New()
+
+	query := "SELECT id,name,key_hash,roles,created_at,revoked_at FROM APIKey WHERE key_hash=?;"
+	cols := []string{"id", "name", "key_hash", "roles", "created_at", "revoked_at"}
+	id := uuid.New()
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	revoked := created.Add(time.Hour)
+
+	testCases := []struct {
+		desc     string
+		hash     string
+		mock     *sqlmock.ExpectedQuery
+		expected models.APIKey
+		err      error
+	}{
+		{
+			desc: "active key", hash: "h1",
+			mock: mock.ExpectQuery(query).WithArgs("h1").
+				WillReturnRows(sqlmock.NewRows(cols).AddRow(id.String(), "crm", "h1", "sales, viewer", created, nil)),
+			expected: models.APIKey{ID: id, Name: "crm", KeyHash: "h1", Roles: []string{"sales", "viewer"},
+				CreatedAt: created},
+		},
+		{
+			desc: "revoked key", hash: "h2",
+			mock: mock.ExpectQuery(query).WithArgs("h2").
+				WillReturnRows(sqlmock.NewRows(cols).AddRow(id.String(), "old", "h2", "", created, revoked)),
+			expected: models.APIKey{ID: id, Name: "old", KeyHash: "h2", Roles: []string{}, CreatedAt: created,
+				RevokedAt: &revoked},
+		},
+		{
+			desc: "unknown key", hash: "h3", err: sql.ErrNoRows,
+			mock: mock.ExpectQuery(query).WithArgs("h3").WillReturnError(sql.ErrNoRows),
+		},
+	}
+
+	for i, tc := range testCases {
+		res, err := s.GetAPIKeyByHash(ctx, tc.hash)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.expected, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,106 @@
+package blob
+
+import (
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+type local struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocal returns a blob store keeping files under dir and serving them below baseURL
+func NewLocal(dir, baseURL string) local {
+	return local{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// path resolves a key inside the storage directory, rejecting keys that would escape it
+func (l local) path(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || strings.HasPrefix(key, ".") {
+		return "", errors.InvalidParam{Param: []string{"key"}}
+	}
+
+	return filepath.Join(l.dir, key), nil
+}
+
+// Put writes the body to a temporary file and renames it into place so readers never see partial files
+func (l local) Put(ctx *gofr.Context, key, contentType string, body io.Reader, size int64) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(l.dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(l.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = io.Copy(tmp, body); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
+
+// Get opens the stored file, the content type is derived from the key's extension
+func (l local) Get(ctx *gofr.Context, key string) (io.ReadCloser, string, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	f, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return nil, "", errors.EntityNotFound{Entity: "Media", ID: key}
+	}
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return f, contentType, nil
+}
+
+// Delete removes the stored file, deleting a missing file is not an error
+func (l local) Delete(ctx *gofr.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// URL returns the public URL of the key
+func (l local) URL(key string) string {
+	return l.baseURL + "/" + key
+}
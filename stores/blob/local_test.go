@@ -0,0 +1,47 @@
+package blob
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLocal tests storing, reading and deleting files on the local filesystem
+func TestLocal(t *testing.T) {
+	l := NewLocal(t.TempDir(), "http://localhost:9000/media/")
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	err := l.Put(ctx, "front.png", "image/png", strings.NewReader("png-bytes"), 9)
+	assert.Equal(t, nil, err)
+
+	r, contentType, err := l.Get(ctx, "front.png")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "image/png", contentType)
+
+	b, _ := io.ReadAll(r)
+	r.Close()
+
+	assert.Equal(t, "png-bytes", string(b))
+	assert.Equal(t, "http://localhost:9000/media/front.png", l.URL("front.png"))
+
+	assert.Equal(t, nil, l.Delete(ctx, "front.png"))
+	assert.Equal(t, nil, l.Delete(ctx, "front.png"))
+
+	_, _, err = l.Get(ctx, "front.png")
+	assert.Equal(t, errors.EntityNotFound{Entity: "Media", ID: "front.png"}, err)
+}
+
+// TestLocalInvalidKey tests that keys cannot point outside the storage directory
+func TestLocalInvalidKey(t *testing.T) {
+	l := NewLocal(t.TempDir(), "http://localhost:9000/media")
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	for _, key := range []string{"", "../secret", "a/b.png", ".hidden"} {
+		err := l.Put(ctx, key, "image/png", strings.NewReader("x"), 1)
+		assert.Equal(t, errors.InvalidParam{Param: []string{"key"}}, err, key)
+	}
+}
@@ -0,0 +1,212 @@
+package blob
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+const (
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	amzDateFormat   = "20060102T150405Z"
+)
+
+// S3Config holds the settings of an S3 compatible bucket. PublicURL is used to build media URLs and
+// defaults to the path style bucket URL on Endpoint.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+}
+
+type s3 struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3 returns a blob store talking to an S3 compatible service using path style requests
+func NewS3(cfg S3Config) s3 {
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = cfg.Endpoint + "/" + cfg.Bucket
+	}
+
+	cfg.PublicURL = strings.TrimSuffix(cfg.PublicURL, "/")
+
+	return s3{cfg: cfg, client: &http.Client{Timeout: time.Minute}, now: time.Now}
+}
+
+func (s s3) objectURL(key string) string {
+	return s.cfg.Endpoint + "/" + url.PathEscape(s.cfg.Bucket) + "/" + url.PathEscape(key)
+}
+
+func (s s3) do(ctx *gofr.Context, method, key string, body io.Reader, size int64,
+	header map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.ContentLength = size
+	}
+
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	sign(req, s.cfg.AccessKey, s.cfg.SecretKey, s.cfg.Region, unsignedPayload, s.now())
+
+	return s.client.Do(req)
+}
+
+// Put uploads the object, size must be the exact length of body
+func (s s3) Put(ctx *gofr.Context, key, contentType string, body io.Reader, size int64) error {
+	resp, err := s.do(ctx, http.MethodPut, key, body, size, map[string]string{"Content-Type": contentType})
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	return checkResponse(resp, key)
+}
+
+// Get downloads the object, the caller must close the returned reader
+func (s s3) Get(ctx *gofr.Context, key string) (io.ReadCloser, string, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, 0, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err = checkResponse(resp, key); err != nil {
+		resp.Body.Close()
+		return nil, "", err
+	}
+
+	return resp.Body, resp.Header.Get("Content-Type"), nil
+}
+
+// Delete removes the object, S3 treats deleting a missing object as success
+func (s s3) Delete(ctx *gofr.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	return checkResponse(resp, key)
+}
+
+// URL returns the public URL of the key
+func (s s3) URL(key string) string {
+	return s.cfg.PublicURL + "/" + url.PathEscape(key)
+}
+
+func checkResponse(resp *http.Response, key string) error {
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errors.EntityNotFound{Entity: "Media", ID: key}
+	case resp.StatusCode >= http.StatusBadRequest:
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Error(fmt.Sprintf("blob store returned %d: %s", resp.StatusCode, strings.TrimSpace(string(b))))
+	}
+
+	return nil
+}
+
+// sign adds an AWS signature version 4 Authorization header covering the host and every header already set
+func sign(req *http.Request, accessKey, secretKey, region, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format(amzDateFormat)
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+
+	for _, k := range keys {
+		vals := values[k]
+		sort.Strings(vals)
+
+		for _, v := range vals {
+			parts = append(parts, url.QueryEscape(k)+"="+url.QueryEscape(v))
+		}
+	}
+
+	return strings.ReplaceAll(strings.Join(parts, "&"), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
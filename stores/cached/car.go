@@ -0,0 +1,124 @@
+package cached
+
+import (
+	"Project/CarDealearship/cache"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"Project/CarDealearship/stores/transaction"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+// listVersionKey holds the version of the cached lists of cars, any change to a car replaces it
+const listVersionKey = "cars:version"
+
+type car struct {
+	stores.Car
+	cache   *cache.Cache
+	ttl     time.Duration
+	listTTL time.Duration
+}
+
+// nolint:revive // need not be exported
+// NewCar factory function, cars are read through the cache and kept for ttl, the cars of a brand for listTTL.
+// The other reads go to s.
+func NewCar(s stores.Car, c *cache.Cache, ttl, listTTL time.Duration) car {
+	return car{Car: s, cache: c, ttl: ttl, listTTL: listTTL}
+}
+
+// GetCarByID reads the car through the cache, inside a transaction it is read from the database
+func (s car) GetCarByID(ctx *gofr.Context, id string) (models.Car, error) {
+	if transaction.InTransaction(ctx) {
+		return s.Car.GetCarByID(ctx, id)
+	}
+
+	var c models.Car
+
+	err := s.cache.Fetch(ctx, "car", carKey(id), s.ttl, &c, func(ctx *gofr.Context) (interface{}, error) {
+		return s.Car.GetCarByID(ctx, id)
+	})
+
+	return c, err
+}
+
+// GetCarsByBrand reads the cars of the brand through the cache, inside a transaction they are read from the
+// database
+func (s car) GetCarsByBrand(ctx *gofr.Context, brand string) ([]models.Car, error) {
+	if transaction.InTransaction(ctx) {
+		return s.Car.GetCarsByBrand(ctx, brand)
+	}
+
+	var cars []models.Car
+
+	key := "cars:" + s.cache.Version(ctx, listVersionKey, s.listTTL) + ":brand:" + brand
+
+	err := s.cache.Fetch(ctx, "cars", key, s.listTTL, &cars, func(ctx *gofr.Context) (interface{}, error) {
+		return s.Car.GetCarsByBrand(ctx, brand)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cars, nil
+}
+
+// CreateCar creates the car and invalidates the lists of cars
+func (s car) CreateCar(ctx *gofr.Context, c *models.Car) (models.Car, error) {
+	res, err := s.Car.CreateCar(ctx, c)
+	if err != nil {
+		return res, err
+	}
+
+	invalidate(ctx, s.cache, carKey(res.ID.String()), listVersionKey)
+
+	return res, nil
+}
+
+// UpdateCar updates the car and invalidates it along with the lists of cars
+func (s car) UpdateCar(ctx *gofr.Context, id string, c *models.Car) (models.Car, error) {
+	res, err := s.Car.UpdateCar(ctx, id, c)
+	if err != nil {
+		return res, err
+	}
+
+	invalidate(ctx, s.cache, carKey(id), listVersionKey)
+
+	return res, nil
+}
+
+// DeleteCar deletes the car and invalidates it along with the lists of cars
+func (s car) DeleteCar(ctx *gofr.Context, id string) error {
+	if err := s.Car.DeleteCar(ctx, id); err != nil {
+		return err
+	}
+
+	invalidate(ctx, s.cache, carKey(id), listVersionKey)
+
+	return nil
+}
+
+// TouchCar marks the car as changed and invalidates it along with the lists of cars
+func (s car) TouchCar(ctx *gofr.Context, id string) error {
+	if err := s.Car.TouchCar(ctx, id); err != nil {
+		return err
+	}
+
+	invalidate(ctx, s.cache, carKey(id), listVersionKey)
+
+	return nil
+}
+
+func carKey(id string) string {
+	return "car:" + id
+}
+
+// invalidate deletes the keys. Until the transaction of ctx commits other requests can still read and cache the
+// rows as they were, so the keys are deleted again once it has.
+func invalidate(ctx *gofr.Context, c *cache.Cache, keys ...string) {
+	c.Delete(ctx, keys...)
+
+	if transaction.InTransaction(ctx) {
+		transaction.AfterCommit(ctx, func() { c.Delete(ctx, keys...) })
+	}
+}
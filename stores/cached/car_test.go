@@ -0,0 +1,208 @@
+package cached
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Project/CarDealearship/cache"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"Project/CarDealearship/stores/transaction"
+
+	"developer.zopsmart.com/go/gofr/pkg/datastore"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContext(t *testing.T) (*gofr.Context, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}, Logger: gofr.New().Logger})
+	ctx.Context = context.TODO()
+
+	return ctx, mock
+}
+
+// detached matches the copy of ctx the cache loads with
+type detached struct {
+	ctx *gofr.Context
+}
+
+func (m detached) Matches(x interface{}) bool {
+	c, ok := x.(*gofr.Context)
+	if !ok || c == m.ctx || c.Gofr != m.ctx.Gofr {
+		return false
+	}
+
+	_, deadline := c.Deadline()
+
+	return deadline
+}
+
+func (m detached) String() string {
+	return "a copy of the context ending after the load timeout"
+}
+
+func TestGetCarByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	ctx, mock := newContext(t)
+	s := NewCar(mockCar, cache.New(cache.NewLRU(100), nil), time.Minute, time.Minute)
+
+	id := uuid.New()
+	c := models.Car{ID: id, Name: "X5", Brand: "BMW", Status: models.CarAvailable}
+
+	mockCar.EXPECT().GetCarByID(detached{ctx}, id.String()).Return(c, nil)
+	mockCar.EXPECT().GetCarByID(detached{ctx}, "missing").Return(models.Car{}, errors.Error("sql: no rows in result set")).
+		Times(2)
+
+	for i := 0; i < 2; i++ {
+		res, err := s.GetCarByID(ctx, id.String())
+
+		assert.Equal(t, nil, err, "TEST[%d], failed.\n%s", i, "read through the cache")
+		assert.Equal(t, c, res, "TEST[%d], failed.\n%s", i, "read through the cache")
+
+		_, err = s.GetCarByID(ctx, "missing")
+		assert.Equal(t, errors.Error("sql: no rows in result set"), err, "TEST[%d], failed.\n%s", i,
+			"errors are not cached")
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	mockCar.EXPECT().GetCarByID(gomock.Any(), id.String()).Return(c, nil)
+
+	err := transaction.New().WithTransaction(ctx, func(ctx *gofr.Context) error {
+		res, err := s.GetCarByID(ctx, id.String())
+		assert.Equal(t, c, res, "inside a transaction the car is read from the database")
+
+		return err
+	})
+
+	assert.Equal(t, nil, err)
+}
+
+func TestCarInvalidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	ctx, mock := newContext(t)
+	s := NewCar(mockCar, cache.New(cache.NewLRU(100), nil), time.Minute, time.Minute)
+
+	id := uuid.New()
+	prev := models.Car{ID: id, Name: "X5", Brand: "BMW", Status: models.CarAvailable}
+	sold := prev
+	sold.Status = models.CarSold
+
+	mockCar.EXPECT().GetCarsByBrand(detached{ctx}, "BMW").Return([]models.Car{prev}, nil)
+
+	res, _ := s.GetCarsByBrand(ctx, "BMW")
+	assert.Equal(t, []models.Car{prev}, res)
+
+	res, _ = s.GetCarsByBrand(ctx, "BMW")
+	assert.Equal(t, []models.Car{prev}, res, "the list is read from the cache")
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	// a request reading the car while the update has not committed caches it as it was
+	reader, _ := newContext(t)
+
+	mockCar.EXPECT().UpdateCar(gomock.Any(), id.String(), &sold).Return(sold, nil)
+	mockCar.EXPECT().GetCarByID(detached{reader}, id.String()).Return(prev, nil)
+
+	err := transaction.New().WithTransaction(ctx, func(ctx *gofr.Context) error {
+		if _, err := s.UpdateCar(ctx, id.String(), &sold); err != nil {
+			return err
+		}
+
+		c, err := s.GetCarByID(reader, id.String())
+		assert.Equal(t, prev, c)
+
+		return err
+	})
+
+	assert.Equal(t, nil, err)
+
+	mockCar.EXPECT().GetCarByID(detached{ctx}, id.String()).Return(sold, nil)
+	mockCar.EXPECT().GetCarsByBrand(detached{ctx}, "BMW").Return([]models.Car{sold}, nil)
+
+	c, _ := s.GetCarByID(ctx, id.String())
+	assert.Equal(t, sold, c, "the car is deleted again once the update commits")
+
+	res, _ = s.GetCarsByBrand(ctx, "BMW")
+	assert.Equal(t, []models.Car{sold}, res, "the lists are invalidated")
+
+	created := models.Car{ID: uuid.New(), Name: "i4", Brand: "BMW"}
+
+	mockCar.EXPECT().CreateCar(ctx, &created).Return(created, nil)
+	mockCar.EXPECT().GetCarsByBrand(detached{ctx}, "BMW").Return([]models.Car{sold, created}, nil)
+
+	_, err = s.CreateCar(ctx, &created)
+	assert.Equal(t, nil, err)
+
+	res, _ = s.GetCarsByBrand(ctx, "BMW")
+	assert.Equal(t, []models.Car{sold, created}, res, "a new car invalidates the lists")
+
+	mockCar.EXPECT().TouchCar(ctx, id.String()).Return(nil)
+	mockCar.EXPECT().GetCarByID(detached{ctx}, id.String()).Return(sold, nil)
+
+	assert.Equal(t, nil, s.TouchCar(ctx, id.String()))
+
+	c, _ = s.GetCarByID(ctx, id.String())
+	assert.Equal(t, sold, c, "a touched car is invalidated")
+
+	mockCar.EXPECT().DeleteCar(ctx, id.String()).Return(nil)
+	mockCar.EXPECT().GetCarByID(detached{ctx}, id.String()).
+		Return(models.Car{}, errors.Error("sql: no rows in result set"))
+
+	assert.Equal(t, nil, s.DeleteCar(ctx, id.String()))
+
+	_, err = s.GetCarByID(ctx, id.String())
+	assert.Equal(t, errors.Error("sql: no rows in result set"), err, "a deleted car is not read from the cache")
+}
+
+func TestCarWriteErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	ctx, _ := newContext(t)
+	s := NewCar(mockCar, cache.New(cache.NewLRU(100), nil), time.Minute, time.Minute)
+
+	c := models.Car{ID: uuid.New(), Brand: "BMW"}
+	id := c.ID.String()
+
+	mockCar.EXPECT().GetCarByID(detached{ctx}, id).Return(c, nil)
+	mockCar.EXPECT().CreateCar(ctx, &c).Return(models.Car{}, errors.Error("duplicate"))
+	mockCar.EXPECT().UpdateCar(ctx, id, &c).Return(models.Car{}, errors.Error("db down"))
+	mockCar.EXPECT().DeleteCar(ctx, id).Return(errors.Error("db down"))
+	mockCar.EXPECT().TouchCar(ctx, id).Return(errors.Error("db down"))
+
+	_, _ = s.GetCarByID(ctx, id)
+
+	_, err := s.CreateCar(ctx, &c)
+	assert.Equal(t, errors.Error("duplicate"), err)
+
+	_, err = s.UpdateCar(ctx, id, &c)
+	assert.Equal(t, errors.Error("db down"), err)
+
+	assert.Equal(t, errors.Error("db down"), s.DeleteCar(ctx, id))
+	assert.Equal(t, errors.Error("db down"), s.TouchCar(ctx, id))
+
+	res, _ := s.GetCarByID(ctx, id)
+	assert.Equal(t, c, res, "a failed write keeps the cached car")
+}
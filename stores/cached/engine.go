@@ -0,0 +1,77 @@
+package cached
+
+import (
+	"Project/CarDealearship/cache"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"Project/CarDealearship/stores/transaction"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+type engine struct {
+	stores.Engine
+	cache *cache.Cache
+	ttl   time.Duration
+}
+
+// nolint:revive // need not be exported
+// NewEngine factory function, engines are read through the cache and kept for ttl. The other reads go to s.
+func NewEngine(s stores.Engine, c *cache.Cache, ttl time.Duration) engine {
+	return engine{Engine: s, cache: c, ttl: ttl}
+}
+
+// EngineGetByID reads the engine through the cache, inside a transaction it is read from the database
+func (s engine) EngineGetByID(ctx *gofr.Context, id string) (models.Engine, error) {
+	if transaction.InTransaction(ctx) {
+		return s.Engine.EngineGetByID(ctx, id)
+	}
+
+	var e models.Engine
+
+	err := s.cache.Fetch(ctx, "engine", engineKey(id), s.ttl, &e, func(ctx *gofr.Context) (interface{}, error) {
+		return s.Engine.EngineGetByID(ctx, id)
+	})
+
+	return e, err
+}
+
+// EngineCreate creates the engine
+func (s engine) EngineCreate(ctx *gofr.Context, e *models.Engine) (models.Engine, error) {
+	res, err := s.Engine.EngineCreate(ctx, e)
+	if err != nil {
+		return res, err
+	}
+
+	invalidate(ctx, s.cache, engineKey(res.EngineID.String()))
+
+	return res, nil
+}
+
+// EngineUpdate updates the engine and invalidates it
+func (s engine) EngineUpdate(ctx *gofr.Context, id string, e *models.Engine) (models.Engine, error) {
+	res, err := s.Engine.EngineUpdate(ctx, id, e)
+	if err != nil {
+		return res, err
+	}
+
+	invalidate(ctx, s.cache, engineKey(id))
+
+	return res, nil
+}
+
+// EngineDelete deletes the engine and invalidates it
+func (s engine) EngineDelete(ctx *gofr.Context, id string) error {
+	if err := s.Engine.EngineDelete(ctx, id); err != nil {
+		return err
+	}
+
+	invalidate(ctx, s.cache, engineKey(id))
+
+	return nil
+}
+
+func engineKey(id string) string {
+	return "engine:" + id
+}
@@ -0,0 +1,63 @@
+package cached
+
+import (
+	"testing"
+	"time"
+
+	"Project/CarDealearship/cache"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngine(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEngine := stores.NewMockEngine(ctrl)
+	ctx, _ := newContext(t)
+	s := NewEngine(mockEngine, cache.New(cache.NewLRU(100), nil), time.Minute)
+
+	e := models.Engine{EngineID: uuid.New(), Displacement: 2998, Cylinders: 6}
+	id := e.EngineID.String()
+	updated := e
+	updated.Displacement = 2993
+
+	gomock.InOrder(
+		mockEngine.EXPECT().EngineGetByID(detached{ctx}, id).Return(e, nil),
+		mockEngine.EXPECT().EngineUpdate(ctx, id, &updated).Return(updated, nil),
+		mockEngine.EXPECT().EngineGetByID(detached{ctx}, id).Return(updated, nil),
+		mockEngine.EXPECT().EngineDelete(ctx, id).Return(nil),
+		mockEngine.EXPECT().EngineGetByID(detached{ctx}, id).
+			Return(models.Engine{}, errors.Error("sql: no rows in result set")),
+		mockEngine.EXPECT().EngineCreate(ctx, &e).Return(e, nil),
+		mockEngine.EXPECT().EngineGetByID(detached{ctx}, id).Return(e, nil),
+	)
+
+	res, _ := s.EngineGetByID(ctx, id)
+	assert.Equal(t, e, res)
+
+	res, _ = s.EngineGetByID(ctx, id)
+	assert.Equal(t, e, res, "the engine is read from the cache")
+
+	_, err := s.EngineUpdate(ctx, id, &updated)
+	assert.Equal(t, nil, err)
+
+	res, _ = s.EngineGetByID(ctx, id)
+	assert.Equal(t, updated, res, "an update invalidates the engine")
+
+	assert.Equal(t, nil, s.EngineDelete(ctx, id))
+
+	_, err = s.EngineGetByID(ctx, id)
+	assert.Equal(t, errors.Error("sql: no rows in result set"), err, "a deleted engine is not read from the cache")
+
+	_, err = s.EngineCreate(ctx, &e)
+	assert.Equal(t, nil, err)
+
+	res, _ = s.EngineGetByID(ctx, id)
+	assert.Equal(t, e, res)
+}
@@ -2,12 +2,26 @@ package car
 
 import (
 	"Project/CarDealearship/models"
-	"context"
+	"Project/CarDealearship/stores"
+	"Project/CarDealearship/stores/transaction"
+	"database/sql"
+	"strings"
+	"time"
 
 	"developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
 )
 
+const carColumns = "id,engine_id,name,year,brand,fuel_type,status,cost_price,dealership_id"
+
+// selectCar selects the columns of a car along with when it last changed, which is kept by the database
+const selectCar = "SELECT " + carColumns + ",vin,updated_at FROM Car"
+
+// errDuplicateEntry is the number of the MySQL error for a row breaking a unique key
+const errDuplicateEntry = 1062
+
 type store struct{}
 
 func New() store {
@@ -16,16 +30,27 @@ func New() store {
 
 // GetCarByID function is the datastore layer function to get a car by its id
 func (s store) GetCarByID(ctx *gofr.Context, Id string) (models.Car, error) {
-	var c models.Car
+	var (
+		c          models.Car
+		cost       sql.NullInt64
+		dealership sql.NullString
+		vin        sql.NullString
+		updated    time.Time
+	)
 
-	query := "SELECT * FROM Car WHERE ID=?;"
-	err := ctx.DB().QueryRowContext(ctx, query, Id).
-		Scan(&c.ID, &c.Engine.EngineID, &c.Name, &c.Year, &c.Brand, &c.FuelType)
+	err := transaction.DB(ctx).QueryRowContext(ctx, selectCar+" WHERE ID=?;", Id).
+		Scan(&c.ID, &c.Engine.EngineID, &c.Name, &c.Year, &c.Brand, &c.FuelType, &c.Status, &cost, &dealership,
+			&vin, &updated)
 
 	if err != nil {
 		return models.Car{}, err
 	}
 
+	c.UpdatedAt = &updated
+	c.CostPrice = costPrice(cost)
+	c.DealershipID = dealershipID(dealership)
+	c.VIN = vin.String
+
 	return c, nil
 }
 
@@ -33,7 +58,7 @@ func (s store) GetCarByID(ctx *gofr.Context, Id string) (models.Car, error) {
 func (s store) GetCarsByBrand(ctx *gofr.Context, brand string) ([]models.Car, error) {
 	var car []models.Car
 
-	rows, err := ctx.DB().QueryContext(ctx, "select * from Car where brand=?;", brand)
+	rows, err := transaction.DB(ctx).QueryContext(ctx, selectCar+" WHERE brand=?;", brand)
 	if err != nil {
 		return nil, err
 	}
@@ -43,13 +68,24 @@ func (s store) GetCarsByBrand(ctx *gofr.Context, brand string) ([]models.Car, er
 	}()
 
 	for rows.Next() {
-		var c models.Car
+		var (
+			c          models.Car
+			cost       sql.NullInt64
+			dealership sql.NullString
+			vin        sql.NullString
+			updated    time.Time
+		)
 
-		err = rows.Scan(&c.ID, &c.Engine.EngineID, &c.Name, &c.Year, &c.Brand, &c.FuelType)
+		err = rows.Scan(&c.ID, &c.Engine.EngineID, &c.Name, &c.Year, &c.Brand, &c.FuelType, &c.Status, &cost,
+			&dealership, &vin, &updated)
 		if err != nil {
 			return nil, errors.Error("Scan Error")
 		}
 
+		c.UpdatedAt = &updated
+		c.CostPrice = costPrice(cost)
+		c.DealershipID = dealershipID(dealership)
+		c.VIN = vin.String
 		car = append(car, c)
 	}
 
@@ -61,12 +97,118 @@ func (s store) GetCarsByBrand(ctx *gofr.Context, brand string) ([]models.Car, er
 	return car, nil
 }
 
+// carWithEngine selects a car together with its engine
+const carWithEngine = "SELECT c.id,c.name,c.year,c.brand,c.fuel_type,c.status,c.cost_price,c.dealership_id," +
+	"c.vin,c.updated_at,e.id,e.displacement,e.cylinders,e.`range`,e.updated_at " +
+	"FROM Car c JOIN Engine e ON e.id=c.engine_id"
+
+// GetCarsByIDs is a datastore layer function to get the cars with the given ids, along with their engines,
+// in a single query. Ids that do not exist are skipped.
+func (s store) GetCarsByIDs(ctx *gofr.Context, ids []string) ([]models.Car, error) {
+	if len(ids) == 0 {
+		return []models.Car{}, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+
+	return s.getCarsWithEngine(ctx, carWithEngine+" WHERE c.id IN ("+placeholders+");", args...)
+}
+
+// GetDuplicateCandidates is a datastore layer function to get, along with their engines, the cars with the VIN
+// of car or with its brand and year within its dealership, among which its duplicates are
+func (s store) GetDuplicateCandidates(ctx *gofr.Context, car *models.Car) ([]models.Car, error) {
+	return s.getCarsWithEngine(ctx, carWithEngine+" WHERE c.vin=? OR (c.brand=? AND c.year=? AND c.dealership_id<=>?);",
+		nullable(car.VIN), car.Brand, car.Year, car.DealershipID)
+}
+
+// GetAllCars is a datastore layer function to get every car along with its engine
+func (s store) GetAllCars(ctx *gofr.Context) ([]models.Car, error) {
+	return s.getCarsWithEngine(ctx, carWithEngine+";")
+}
+
+// StreamCars is a datastore layer function to pass every car with the given brand, or every car when brand is
+// empty, along with its engine to fn one row at a time. It stops at the first error returned by fn.
+func (s store) StreamCars(ctx *gofr.Context, brand string, fn func(car models.Car) error) error {
+	if brand == "" {
+		return s.eachCarWithEngine(ctx, fn, carWithEngine+" ORDER BY c.brand,c.name,c.id;")
+	}
+
+	return s.eachCarWithEngine(ctx, fn, carWithEngine+" WHERE c.brand=? ORDER BY c.brand,c.name,c.id;", brand)
+}
+
+// StreamCarsBySpec is a datastore layer function to pass every car along with its engine to fn one row at a time,
+// ordered by brand, year and dealership so that the cars which may be duplicates of each other come together
+func (s store) StreamCarsBySpec(ctx *gofr.Context, fn func(car models.Car) error) error {
+	return s.eachCarWithEngine(ctx, fn, carWithEngine+" ORDER BY c.brand,c.year,c.dealership_id,c.id;")
+}
+
+func (s store) getCarsWithEngine(ctx *gofr.Context, query string, args ...interface{}) ([]models.Car, error) {
+	cars := make([]models.Car, 0)
+
+	err := s.eachCarWithEngine(ctx, func(c models.Car) error {
+		cars = append(cars, c)
+		return nil
+	}, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return cars, nil
+}
+
+func (s store) eachCarWithEngine(ctx *gofr.Context, fn func(car models.Car) error, query string,
+	args ...interface{}) error {
+	rows, err := transaction.DB(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var (
+			c          models.Car
+			cost       sql.NullInt64
+			dealership sql.NullString
+			vin        sql.NullString
+			updated    time.Time
+			engine     time.Time
+		)
+
+		err = rows.Scan(&c.ID, &c.Name, &c.Year, &c.Brand, &c.FuelType, &c.Status, &cost, &dealership, &vin,
+			&updated, &c.Engine.EngineID, &c.Engine.Displacement, &c.Engine.Cylinders, &c.Engine.Range, &engine)
+		if err != nil {
+			return errors.Error("Scan Error")
+		}
+
+		c.UpdatedAt = &updated
+		c.Engine.UpdatedAt = &engine
+		c.CostPrice = costPrice(cost)
+		c.DealershipID = dealershipID(dealership)
+		c.VIN = vin.String
+
+		if err = fn(c); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 // CreateCar is the datastore layer function to create a model of a car
 func (s store) CreateCar(ctx *gofr.Context, car *models.Car) (models.Car, error) {
-	_, err := ctx.DB().ExecContext(ctx, "INSERT INTO Car (id,engine_id,name,year,brand,fuel_type) VALUES(?,?,?,?,?,?)",
-		car.ID, car.Engine.EngineID, car.Name, car.Year, car.Brand, car.FuelType)
+	_, err := transaction.DB(ctx).ExecContext(ctx, "INSERT INTO Car ("+carColumns+",vin) VALUES(?,?,?,?,?,?,?,?,?,?)",
+		car.ID, car.Engine.EngineID, car.Name, car.Year, car.Brand, car.FuelType, car.Status, car.CostPrice,
+		car.DealershipID, nullable(car.VIN))
 	if err != nil {
-		return models.Car{}, err
+		return models.Car{}, vinError(err)
 	}
 
 	return *car, nil
@@ -74,8 +216,7 @@ func (s store) CreateCar(ctx *gofr.Context, car *models.Car) (models.Car, error)
 
 // DeleteCar to service layer function to delete the car from database
 func (s store) DeleteCar(ctx *gofr.Context, id string) error {
-	ctx.Context = context.TODO()
-	_, err := ctx.DB().ExecContext(ctx, "DELETE FROM Car WHERE ID=?", id)
+	_, err := transaction.DB(ctx).ExecContext(ctx, "DELETE FROM Car WHERE ID=?", id)
 	if err != nil {
 		return err
 	}
@@ -83,13 +224,92 @@ func (s store) DeleteCar(ctx *gofr.Context, id string) error {
 	return nil
 }
 
-// UpdateCar is a datastore layer function to update a car record in database
+// UpdateCar is a datastore layer function to update a car record in database, the status, the cost price, the
+// dealership and the vin are kept when the car has none
 func (s store) UpdateCar(ctx *gofr.Context, id string, car *models.Car) (models.Car, error) {
-	_, err := ctx.DB().ExecContext(ctx, "UPDATE Car SET name=?,year=?,brand=?,fuel_type=? WHERE id=?",
-		car.Name, car.Year, car.Brand, car.FuelType, id)
+	_, err := transaction.DB(ctx).ExecContext(ctx, "UPDATE Car SET name=?,year=?,brand=?,fuel_type=?,"+
+		"status=COALESCE(NULLIF(?,''),status),cost_price=COALESCE(?,cost_price),dealership_id=COALESCE(?,dealership_id),"+
+		"vin=COALESCE(?,vin) WHERE id=?", car.Name, car.Year, car.Brand, car.FuelType, car.Status, car.CostPrice,
+		car.DealershipID, nullable(car.VIN), id)
 	if err != nil {
-		return models.Car{}, err
+		return models.Car{}, vinError(err)
 	}
 
 	return *car, nil
 }
+
+// TouchCar is a datastore layer function to mark a car as changed by a change kept outside of its row, like
+// one of its media
+func (s store) TouchCar(ctx *gofr.Context, id string) error {
+	_, err := transaction.DB(ctx).ExecContext(ctx, "UPDATE Car SET updated_at=CURRENT_TIMESTAMP(6) WHERE id=?", id)
+
+	return err
+}
+
+// CountCars is a datastore layer function to count the cars by status and dealership
+func (s store) CountCars(ctx *gofr.Context) ([]models.InventoryCount, error) {
+	rows, err := transaction.DB(ctx).QueryContext(ctx,
+		"SELECT status,dealership_id,COUNT(*) FROM Car GROUP BY status,dealership_id;")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var counts []models.InventoryCount
+
+	for rows.Next() {
+		var (
+			c          models.InventoryCount
+			dealership sql.NullString
+		)
+
+		if err = rows.Scan(&c.Status, &dealership, &c.Count); err != nil {
+			return nil, err
+		}
+
+		c.DealershipID = dealershipID(dealership)
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+// vinError tells the error of a car written with the VIN of another car from the other errors of a write
+func vinError(err error) error {
+	if e, ok := err.(*mysql.MySQLError); ok && e.Number == errDuplicateEntry && strings.Contains(e.Message, "uq_car_vin") {
+		return stores.ErrDuplicateVIN
+	}
+
+	return err
+}
+
+// nullable stores an empty string as NULL
+func nullable(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func costPrice(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+
+	v := int(n.Int64)
+
+	return &v
+}
+
+func dealershipID(s sql.NullString) *uuid.UUID {
+	if !s.Valid {
+		return nil
+	}
+
+	id, err := uuid.Parse(s.String)
+	if err != nil {
+		return nil
+	}
+
+	return &id
+}
@@ -3,14 +3,18 @@ package car
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
 
 	"developer.zopsmart.com/go/gofr/pkg/datastore"
 	"developer.zopsmart.com/go/gofr/pkg/errors"
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -25,6 +29,11 @@ func TestGetCarByID(t *testing.T) {
 
 	id1 := uuid.New()
 	id2 := uuid.New()
+	dealer := uuid.New()
+	cost := 18000
+	updated := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	byID := "SELECT id,engine_id,name,year,brand,fuel_type,status,cost_price,dealership_id,vin,updated_at " +
+		"FROM Car WHERE ID=?;"
 
 	testCases := []struct {
 		desc string
@@ -37,18 +46,22 @@ func TestGetCarByID(t *testing.T) {
 			desc: "Success Case",
 			id:   id1.String(),
 			resp: models.Car{ID: id1, Engine: models.Engine{EngineID: id1, Displacement: 0, Cylinders: 0, Range: 0},
-				Name: "Model 2", Year: 2000, Brand: "Tesla", FuelType: "Petrol"},
+				Name: "Model 2", Year: 2000, Brand: "Tesla", FuelType: "Petrol", Status: models.CarSold, CostPrice: &cost,
+				DealershipID: &dealer, VIN: "5YJ3E1EA7KF317000", UpdatedAt: &updated},
 			err: nil,
-			mock: mock.ExpectQuery("SELECT * FROM Car WHERE ID=?;").WithArgs(id1).
-				WillReturnRows(sqlmock.NewRows([]string{"id", "engine_id", "name", "year", "brand", "fuelType"}).
-					AddRow(id1.String(), id1.String(), "Model 2", 2000, "Tesla", "Petrol")),
+			mock: mock.ExpectQuery(byID).
+				WithArgs(id1).WillReturnRows(sqlmock.NewRows([]string{"id", "engine_id", "name", "year", "brand",
+				"fuelType", "status", "cost_price", "dealership_id", "vin", "updated_at"}).
+				AddRow(id1.String(), id1.String(), "Model 2", 2000, "Tesla", "Petrol", "sold", cost, dealer.String(),
+					"5YJ3E1EA7KF317000", updated)),
 		},
 		{
 			desc: "ID not present",
 			id:   id2.String(),
 			resp: models.Car{},
 			err:  errors.EntityNotFound{Entity: "Car", ID: id2.String()},
-			mock: mock.ExpectQuery("SELECT * FROM Car WHERE ID=?;").WithArgs(id2).
+			mock: mock.ExpectQuery(byID).
+				WithArgs(id2).
 				WillReturnError(errors.EntityNotFound{Entity: "Car", ID: id2.String()}),
 		},
 	}
@@ -76,18 +89,23 @@ func TestGetCarsByBrand(t *testing.T) {
 		id2 = uuid.New()
 		id3 = uuid.New()
 
+		updated = time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
 		car = models.Car{ID: id1, Name: "GenX", Year: 2015, Brand: "Tesla",
-			FuelType: "electric", Engine: models.Engine{EngineID: id1}}
+			FuelType: "electric", Status: models.CarAvailable, Engine: models.Engine{EngineID: id1}, UpdatedAt: &updated}
 
 		car2 = models.Car{ID: id2, Name: "Model 3", Year: 2020, Brand: "Tesla",
-			FuelType: "electric", Engine: models.Engine{EngineID: id2}}
+			FuelType: "electric", Status: models.CarReserved, Engine: models.Engine{EngineID: id2}, UpdatedAt: &updated}
 
 		car3 = models.Car{ID: id3, Name: "Model 3", Year: 2020, Brand: "BMW",
 			FuelType: "electric", Engine: models.Engine{EngineID: id3}}
 
-		rows = sqlmock.NewRows([]string{"id", "engine_id", "name", "year", "brand", "fuel_type"}).
-			AddRow(id1.String(), id1.String(), car.Name, car.Year, car.Brand, car.FuelType).
-			AddRow(id2.String(), id2.String(), car2.Name, car2.Year, car2.Brand, car2.FuelType)
+		rows = sqlmock.NewRows([]string{"id", "engine_id", "name", "year", "brand", "fuel_type", "status",
+			"cost_price", "dealership_id", "vin", "updated_at"}).
+			AddRow(id1.String(), id1.String(), car.Name, car.Year, car.Brand, car.FuelType, car.Status, nil, nil,
+				nil, updated).
+			AddRow(id2.String(), id2.String(), car2.Name, car2.Year, car2.Brand, car2.FuelType, car2.Status, nil, nil,
+				nil, updated)
 
 		rwbmw = sqlmock.NewRows([]string{"id", "engine_id", "name", "year", "brand"}).
 			AddRow(id3.String(), id3.String(), car3.Name, car3.Year, car3.Brand)
@@ -96,10 +114,14 @@ func TestGetCarsByBrand(t *testing.T) {
 				AddRow(id3.String(), id3.String(), car3.Name, car3.Year, "Ferrari").
 				RowError(0, errors.Error("Row error"))
 
-		rowPorsche = sqlmock.NewRows([]string{"id", "engine_id", "name", "year", "brand", "fuel_type"}).
-				CloseError(fmt.Errorf("close error"))
+		rowPorsche = sqlmock.NewRows([]string{"id", "engine_id", "name", "year", "brand", "fuel_type", "status",
+			"cost_price", "dealership_id", "vin", "updated_at"}).
+			CloseError(fmt.Errorf("close error"))
 	)
 
+	brandQuery := "SELECT id,engine_id,name,year,brand,fuel_type,status,cost_price,dealership_id,vin,updated_at " +
+		"FROM Car WHERE brand=?;"
+
 	testCases := []struct {
 		desc   string
 		brand  string
@@ -114,13 +136,13 @@ func TestGetCarsByBrand(t *testing.T) {
 		{desc: "error in close row", brand: "Porsche", output: nil, err: nil},
 	}
 
-	mock.ExpectQuery("select * from Car where brand=?;").WithArgs("Tesla").WillReturnRows(rows)
-	mock.ExpectQuery("select * from Car where brand=?;").WithArgs("BMW").WillReturnRows(rwbmw)
-	mock.ExpectQuery("select * from Car where brand=?;").WithArgs("").
+	mock.ExpectQuery(brandQuery).WithArgs("Tesla").WillReturnRows(rows)
+	mock.ExpectQuery(brandQuery).WithArgs("BMW").WillReturnRows(rwbmw)
+	mock.ExpectQuery(brandQuery).WithArgs("").
 		WillReturnError(errors.MissingParam{})
-	mock.ExpectQuery("select * from Car where brand=?;").WithArgs("Ferrari").
+	mock.ExpectQuery(brandQuery).WithArgs("Ferrari").
 		WillReturnRows(rowFerrari)
-	mock.ExpectQuery("select * from Car where brand=?;").WithArgs("Porsche").WillReturnRows(rowPorsche)
+	mock.ExpectQuery(brandQuery).WithArgs("Porsche").WillReturnRows(rowPorsche)
 
 	for i, tc := range testCases {
 		car, err := a.GetCarsByBrand(ctx, tc.brand)
@@ -137,10 +159,12 @@ func TestGetCarsByBrand(t *testing.T) {
 func TestCreateCar(t *testing.T) {
 	id := uuid.New()
 
-	car := models.Car{ID: id, Name: "GenX", Year: 2015, Brand: "Tesla",
-		FuelType: "electric", Engine: models.Engine{EngineID: id}}
+	car := models.Car{ID: id, Name: "GenX", Year: 2015, Brand: "Tesla", VIN: "5YJ3E1EA7KF317000",
+		FuelType: "electric", Status: models.CarAvailable, Engine: models.Engine{EngineID: id}}
 	car2 := models.Car{ID: uuid.Nil, Name: "GenX", Year: 2015, Brand: "Tesla",
-		FuelType: "electric", Engine: models.Engine{EngineID: id}}
+		FuelType: "electric", Status: models.CarAvailable, Engine: models.Engine{EngineID: id}}
+	duplicateVIN := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '5YJ3E1EA7KF317000' for key 'uq_car_vin'"}
+	duplicateID := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '" + id.String() + "' for key 'PRIMARY'"}
 
 	testCases := []struct {
 		desc           string
@@ -150,6 +174,8 @@ func TestCreateCar(t *testing.T) {
 	}{
 		{"Car created successfully", car, car, nil},
 		{"failure", car2, models.Car{}, errors.Error("query error")},
+		{"vin of another car", car, models.Car{}, stores.ErrDuplicateVIN},
+		{"another unique key", car, models.Car{}, duplicateID},
 	}
 
 	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
@@ -163,18 +189,30 @@ func TestCreateCar(t *testing.T) {
 
 	defer db.Close()
 
-	mock.ExpectExec("INSERT INTO Car (id,engine_id,name,year,brand,fuel_type) VALUES(?,?,?,?,?,?)").
-		WithArgs(car.ID, car.Engine.EngineID, car.Name, car.Year, car.Brand, car.FuelType).
+	insert := "INSERT INTO Car (id,engine_id,name,year,brand,fuel_type,status,cost_price,dealership_id,vin) " +
+		"VALUES(?,?,?,?,?,?,?,?,?,?)"
+
+	mock.ExpectExec(insert).
+		WithArgs(car.ID, car.Engine.EngineID, car.Name, car.Year, car.Brand, car.FuelType, car.Status, nil, nil,
+			"5YJ3E1EA7KF317000").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	mock.ExpectExec("INSERT INTO Car (id,engine_id,name,year,brand,fuel_type) VALUES(?,?,?,?,?,?)").
-		WithArgs(uuid.Nil, car.Engine.EngineID, car.Name, car.Year, car.Brand, car.FuelType).
+	mock.ExpectExec(insert).
+		WithArgs(uuid.Nil, car.Engine.EngineID, car.Name, car.Year, car.Brand, car.FuelType, car.Status, nil, nil,
+			nil).
 		WillReturnError(errors.Error("query error"))
 
+	for _, err := range []error{duplicateVIN, duplicateID} {
+		mock.ExpectExec(insert).
+			WithArgs(car.ID, car.Engine.EngineID, car.Name, car.Year, car.Brand, car.FuelType, car.Status, nil, nil,
+				"5YJ3E1EA7KF317000").
+			WillReturnError(err)
+	}
+
 	for i, tc := range testCases {
 		res, err := a.CreateCar(ctx, &tc.input)
 
-		if res != tc.expectedOutput {
+		if !reflect.DeepEqual(res, tc.expectedOutput) {
 			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, res, tc.expectedOutput)
 		}
 
@@ -205,12 +243,22 @@ func TestUpdateCar(t *testing.T) {
 
 	defer db.Close()
 
-	mock.ExpectExec("UPDATE Car SET name=?,year=?,brand=?,fuel_type=? WHERE id=?").
-		WithArgs(car.Name, car.Year, car.Brand, car.FuelType, id).
+	update := "UPDATE Car SET name=?,year=?,brand=?,fuel_type=?,status=COALESCE(NULLIF(?,''),status)," +
+		"cost_price=COALESCE(?,cost_price),dealership_id=COALESCE(?,dealership_id),vin=COALESCE(?,vin) WHERE id=?"
+
+	mock.ExpectExec(update).
+		WithArgs(car.Name, car.Year, car.Brand, car.FuelType, "", nil, nil, nil, id).
 		WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec("UPDATE Car SET name=?,year=?,brand=?,fuel_type=? WHERE id=?").
-		WithArgs(car.Name, car.Year, car.Brand, car.FuelType, id).
+	mock.ExpectExec(update).
+		WithArgs(car.Name, car.Year, car.Brand, car.FuelType, "", nil, nil, nil, id).
 		WillReturnError(errors.Error("Update Failed"))
+	mock.ExpectExec(update).
+		WithArgs(car.Name, car.Year, car.Brand, car.FuelType, "", nil, nil, "5YJ3E1EA7KF317000", id).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '5YJ3E1EA7KF317000' " +
+			"for key 'uq_car_vin'"})
+
+	withVIN := car
+	withVIN.VIN = "5YJ3E1EA7KF317000"
 
 	cases := []struct {
 		desc  string
@@ -219,6 +267,7 @@ func TestUpdateCar(t *testing.T) {
 	}{
 		{"success", car, nil},
 		{"failure", car, updateFailed},
+		{"vin of another car", withVIN, stores.ErrDuplicateVIN},
 	}
 
 	for i, tc := range cases {
@@ -268,3 +317,293 @@ func TestDeleteCar(t *testing.T) {
 		}
 	}
 }
+
+// TestGetCarsByIDs tests the datastore function GetCarsByIDs
+func TestGetCarsByIDs(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	a := New()
+
+	id1 := uuid.New()
+	id2 := uuid.New()
+	cols := []string{"id", "name", "year", "brand", "fuel_type", "status", "cost_price", "dealership_id", "vin",
+		"updated_at", "engine_id", "displacement", "cylinders", "range", "engine_updated_at"}
+	updated := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	car1 := models.Car{ID: id1, Name: "Model 3", Year: 2020, Brand: "Tesla", FuelType: "Electric",
+		Status: models.CarAvailable, Engine: models.Engine{EngineID: id1, Range: 500, UpdatedAt: &updated},
+		UpdatedAt: &updated}
+	car2 := models.Car{ID: id2, Name: "X5", Year: 2019, Brand: "BMW", FuelType: "Diesel",
+		Status: models.CarSold, Engine: models.Engine{EngineID: id2, Displacement: 3000, Cylinders: 6,
+			UpdatedAt: &updated}, VIN: "WBAKS4100K0000000", UpdatedAt: &updated}
+
+	mock.ExpectQuery(carWithEngine+" WHERE c.id IN (?,?);").WithArgs(id1.String(), id2.String()).
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(id1.String(), "Model 3", 2020, "Tesla", "Electric", "available", nil, nil, nil, updated,
+				id1.String(), 0, 0, 500, updated).
+			AddRow(id2.String(), "X5", 2019, "BMW", "Diesel", "sold", nil, nil, "WBAKS4100K0000000", updated,
+				id2.String(), 3000, 6, 0, updated))
+	mock.ExpectQuery(carWithEngine + " WHERE c.id IN (?);").WithArgs("bad").
+		WillReturnError(errors.Error("query error"))
+	mock.ExpectQuery(carWithEngine + " WHERE c.id IN (?);").WithArgs("short").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id1.String()))
+
+	testCases := []struct {
+		desc   string
+		ids    []string
+		output []models.Car
+		err    error
+	}{
+		{desc: "success", ids: []string{id1.String(), id2.String()}, output: []models.Car{car1, car2}},
+		{desc: "no ids", ids: nil, output: []models.Car{}},
+		{desc: "query error", ids: []string{"bad"}, err: errors.Error("query error")},
+		{desc: "scan error", ids: []string{"short"}, err: errors.Error("Scan Error")},
+	}
+
+	for i, tc := range testCases {
+		res, err := a.GetCarsByIDs(ctx, tc.ids)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.output, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestGetDuplicateCandidates tests the datastore function GetDuplicateCandidates
+func TestGetDuplicateCandidates(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	a := New()
+
+	id := uuid.New()
+	dealer := uuid.New()
+	cols := []string{"id", "name", "year", "brand", "fuel_type", "status", "cost_price", "dealership_id", "vin",
+		"updated_at", "engine_id", "displacement", "cylinders", "range", "engine_updated_at"}
+	updated := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	query := carWithEngine + " WHERE c.vin=? OR (c.brand=? AND c.year=? AND c.dealership_id<=>?);"
+
+	mock.ExpectQuery(query).WithArgs("WP0ZZZ99ZJS100000", "Porsche", 2018, dealer.String()).
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(id.String(), "911", 2018, "Porsche", "Petrol", "available", nil, dealer.String(), nil, updated,
+				id.String(), 3000, 6, 0, updated))
+	mock.ExpectQuery(query).WithArgs(nil, "Porsche", 2018, nil).WillReturnError(errors.Error("db error"))
+
+	testCases := []struct {
+		desc   string
+		car    models.Car
+		output []models.Car
+		err    error
+	}{
+		{desc: "vin and dealership", car: models.Car{Brand: "Porsche", Year: 2018, VIN: "WP0ZZZ99ZJS100000",
+			DealershipID: &dealer}, output: []models.Car{{ID: id, Name: "911", Year: 2018, Brand: "Porsche",
+			FuelType: "Petrol", Status: models.CarAvailable, DealershipID: &dealer, Engine: models.Engine{EngineID: id,
+				Displacement: 3000, Cylinders: 6, UpdatedAt: &updated}, UpdatedAt: &updated}}},
+		{desc: "neither vin nor dealership", car: models.Car{Brand: "Porsche", Year: 2018},
+			err: errors.Error("db error")},
+	}
+
+	for i, tc := range testCases {
+		res, err := a.GetDuplicateCandidates(ctx, &tc.car)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.output, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestGetAllCars tests the datastore function GetAllCars
+func TestGetAllCars(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	a := New()
+
+	id := uuid.New()
+	cols := []string{"id", "name", "year", "brand", "fuel_type", "status", "cost_price", "dealership_id", "vin",
+		"updated_at", "engine_id", "displacement", "cylinders", "range", "engine_updated_at"}
+	updated := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(carWithEngine + ";").
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(id.String(), "911", 2018, "Porsche", "Petrol", "available", nil, nil, nil, updated, id.String(),
+				3000, 6, 0, updated))
+
+	res, err := a.GetAllCars(ctx)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.Car{{ID: id, Name: "911", Year: 2018, Brand: "Porsche", FuelType: "Petrol",
+		Status: models.CarAvailable, Engine: models.Engine{EngineID: id, Displacement: 3000, Cylinders: 6,
+			UpdatedAt: &updated}, UpdatedAt: &updated}}, res)
+}
+
+func TestStreamCars(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	a := New()
+
+	id1, id2 := uuid.New(), uuid.New()
+	cols := []string{"id", "name", "year", "brand", "fuel_type", "status", "cost_price", "dealership_id", "vin",
+		"updated_at", "engine_id", "displacement", "cylinders", "range", "engine_updated_at"}
+	updated := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(cols).
+			AddRow(id1.String(), "911", 2018, "Porsche", "Petrol", "available", 95000, nil, nil, updated,
+				id1.String(), 3000, 6, 0, updated).
+			AddRow(id2.String(), "Taycan", 2021, "Porsche", "Electric", "reserved", nil, nil, nil, updated,
+				id2.String(), 0, 0, 450, updated)
+	}
+	cost := 95000
+	car1 := models.Car{ID: id1, Name: "911", Year: 2018, Brand: "Porsche", FuelType: "Petrol", CostPrice: &cost,
+		Status: models.CarAvailable, Engine: models.Engine{EngineID: id1, Displacement: 3000, Cylinders: 6,
+			UpdatedAt: &updated}, UpdatedAt: &updated}
+	car2 := models.Car{ID: id2, Name: "Taycan", Year: 2021, Brand: "Porsche", FuelType: "Electric",
+		Status: models.CarReserved, Engine: models.Engine{EngineID: id2, Range: 450, UpdatedAt: &updated},
+		UpdatedAt: &updated}
+
+	testCases := []struct {
+		desc     string
+		brand    string
+		mock     *sqlmock.ExpectedQuery
+		fnErr    error
+		expected []models.Car
+		err      error
+	}{
+		{
+			desc: "all cars", brand: "", expected: []models.Car{car1, car2},
+			mock: mock.ExpectQuery(carWithEngine + " ORDER BY c.brand,c.name,c.id;").WillReturnRows(rows()),
+		},
+		{
+			desc: "filtered by brand", brand: "Porsche", expected: []models.Car{car1, car2},
+			mock: mock.ExpectQuery(carWithEngine + " WHERE c.brand=? ORDER BY c.brand,c.name,c.id;").
+				WithArgs("Porsche").WillReturnRows(rows()),
+		},
+		{
+			desc: "callback error stops the stream", brand: "", fnErr: errors.Error("write error"),
+			expected: []models.Car{car1}, err: errors.Error("write error"),
+			mock: mock.ExpectQuery(carWithEngine + " ORDER BY c.brand,c.name,c.id;").WillReturnRows(rows()),
+		},
+		{
+			desc: "query error", brand: "", err: errors.Error("db error"),
+			mock: mock.ExpectQuery(carWithEngine + " ORDER BY c.brand,c.name,c.id;").
+				WillReturnError(errors.Error("db error")),
+		},
+	}
+
+	for i, tc := range testCases {
+		var got []models.Car
+
+		err := a.StreamCars(ctx, tc.brand, func(c models.Car) error {
+			got = append(got, c)
+			return tc.fnErr
+		})
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.expected, got, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestStreamCarsBySpec tests that every car is streamed ordered by the columns duplicates share
+func TestStreamCarsBySpec(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	a := New()
+
+	id := uuid.New()
+	cols := []string{"id", "name", "year", "brand", "fuel_type", "status", "cost_price", "dealership_id", "vin",
+		"updated_at", "engine_id", "displacement", "cylinders", "range", "engine_updated_at"}
+	updated := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	query := carWithEngine + " ORDER BY c.brand,c.year,c.dealership_id,c.id;"
+
+	mock.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(id.String(), "911", 2018, "Porsche", "Petrol", "available", nil, nil, nil, updated, id.String(),
+				3000, 6, 0, updated))
+	mock.ExpectQuery(query).WillReturnError(errors.Error("db error"))
+
+	var got []models.Car
+
+	err := a.StreamCarsBySpec(ctx, func(c models.Car) error {
+		got = append(got, c)
+		return nil
+	})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.Car{{ID: id, Name: "911", Year: 2018, Brand: "Porsche", FuelType: "Petrol",
+		Status: models.CarAvailable, Engine: models.Engine{EngineID: id, Displacement: 3000, Cylinders: 6,
+			UpdatedAt: &updated}, UpdatedAt: &updated}}, got)
+
+	err = a.StreamCarsBySpec(ctx, func(models.Car) error { return nil })
+
+	assert.Equal(t, errors.Error("db error"), err)
+}
+
+// TestTouchCar test the TouchCar functionality of the datastore layer
+func TestTouchCar(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	a := New()
+
+	id := uuid.NewString()
+	touch := "UPDATE Car SET updated_at=CURRENT_TIMESTAMP(6) WHERE id=?"
+
+	mock.ExpectExec(touch).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(touch).WithArgs(id).WillReturnError(errors.Error("db down"))
+
+	assert.Equal(t, nil, a.TouchCar(ctx, id))
+	assert.Equal(t, errors.Error("db down"), a.TouchCar(ctx, id))
+}
+
+func TestCountCars(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	a := New()
+
+	dealership := uuid.New()
+	query := "SELECT status,dealership_id,COUNT(*) FROM Car GROUP BY status,dealership_id;"
+
+	tests := []struct {
+		desc   string
+		mock   *sqlmock.ExpectedQuery
+		output []models.InventoryCount
+		err    error
+	}{
+		{"success", mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"status", "dealership_id",
+			"count"}).AddRow("AVAILABLE", dealership.String(), 3).AddRow("SOLD", nil, 1)),
+			[]models.InventoryCount{{Status: "AVAILABLE", DealershipID: &dealership, Count: 3},
+				{Status: "SOLD", Count: 1}}, nil},
+		{"db error", mock.ExpectQuery(query).WillReturnError(errors.Error("db down")), nil, errors.Error("db down")},
+		{"scan error", mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"status", "dealership_id",
+			"count"}).AddRow("AVAILABLE", nil, "many")), nil, nil},
+	}
+
+	for i, tc := range tests {
+		output, err := a.CountCars(ctx)
+
+		assert.Equal(t, tc.output, output, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if tc.desc == "scan error" {
+			assert.Error(t, err, "TEST[%d], failed.\n%s", i, tc.desc)
+			continue
+		}
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
@@ -0,0 +1,53 @@
+package dealership
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores/transaction"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+type store struct{}
+
+// nolint:revive // need not be exported
+// New factory function
+func New() store {
+	return store{}
+}
+
+// GetDealershipsByIDs is the datastore layer function to get the dealerships with the given ids in a single
+// query, ids that do not exist are skipped
+func (s store) GetDealershipsByIDs(ctx *gofr.Context, ids []string) ([]models.Dealership, error) {
+	dealerships := make([]models.Dealership, 0, len(ids))
+	if len(ids) == 0 {
+		return dealerships, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+
+	rows, err := transaction.DB(ctx).QueryContext(ctx, "SELECT id,name,city FROM Dealership WHERE id IN ("+
+		strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")+");", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var d models.Dealership
+
+		if err = rows.Scan(&d.ID, &d.Name, &d.City); err != nil {
+			return nil, err
+		}
+
+		dealerships = append(dealerships, d)
+	}
+
+	return dealerships, rows.Err()
+}
@@ -0,0 +1,58 @@
+package dealership
+
+import (
+	"context"
+	"testing"
+
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/datastore"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDealershipsByIDs(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	s := New()
+
+	id1, id2 := uuid.New(), uuid.New()
+	cols := []string{"id", "name", "city"}
+
+	testCases := []struct {
+		desc     string
+		ids      []string
+		mock     *sqlmock.ExpectedQuery
+		expected []models.Dealership
+		err      error
+	}{
+		{
+			desc: "found", ids: []string{id1.String(), id2.String()},
+			mock: mock.ExpectQuery("SELECT id,name,city FROM Dealership WHERE id IN (?,?);").
+				WithArgs(id1.String(), id2.String()).
+				WillReturnRows(sqlmock.NewRows(cols).AddRow(id1.String(), "Downtown", "Bengaluru")),
+			expected: []models.Dealership{{ID: id1, Name: "Downtown", City: "Bengaluru"}},
+		},
+		{desc: "no ids", expected: []models.Dealership{}},
+		{
+			desc: "query error", ids: []string{id1.String()}, err: errors.Error("db down"),
+			mock: mock.ExpectQuery("SELECT id,name,city FROM Dealership WHERE id IN (?);").WithArgs(id1.String()).
+				WillReturnError(errors.Error("db down")),
+		},
+	}
+
+	for i, tc := range testCases {
+		res, err := s.GetDealershipsByIDs(ctx, tc.ids)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.expected, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,11 +2,17 @@ package engine
 
 import (
 	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores/transaction"
+	"strings"
+	"time"
 
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 	"github.com/google/uuid"
 )
 
+// selectEngine selects the columns of an engine along with when it last changed, which is kept by the database
+const selectEngine = "SELECT id,displacement,cylinders,`range`,updated_at FROM Engine"
+
 type engineStore struct {
 }
 
@@ -18,22 +24,70 @@ func New() engineStore {
 
 // EngineGetByID is the datastore layer function to get engine by its id
 func (s engineStore) EngineGetByID(ctx *gofr.Context, id string) (models.Engine, error) {
-	var e models.Engine
+	var (
+		e       models.Engine
+		updated time.Time
+	)
 
-	err := ctx.DB().QueryRowContext(ctx, "SELECT * from Engine where id=?;", id).
-		Scan(&e.EngineID, &e.Displacement, &e.Cylinders, &e.Range)
+	err := transaction.DB(ctx).QueryRowContext(ctx, selectEngine+" WHERE id=?;", id).
+		Scan(&e.EngineID, &e.Displacement, &e.Cylinders, &e.Range, &updated)
 	if err != nil {
 		return models.Engine{}, err
 	}
 
+	e.UpdatedAt = &updated
+
 	return e, nil
 }
 
+// GetEnginesByIDs is the datastore layer function to get the engines with the given ids in a single query,
+// ids that do not exist are skipped
+func (s engineStore) GetEnginesByIDs(ctx *gofr.Context, ids []string) ([]models.Engine, error) {
+	engines := make([]models.Engine, 0, len(ids))
+	if len(ids) == 0 {
+		return engines, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+
+	rows, err := transaction.DB(ctx).QueryContext(ctx, selectEngine+" WHERE id IN ("+placeholders+");", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var (
+			e       models.Engine
+			updated time.Time
+		)
+
+		if err = rows.Scan(&e.EngineID, &e.Displacement, &e.Cylinders, &e.Range, &updated); err != nil {
+			return nil, err
+		}
+
+		e.UpdatedAt = &updated
+
+		engines = append(engines, e)
+	}
+
+	return engines, rows.Err()
+}
+
 // EngineCreate is the datastore layer function to create a model of an engine
 func (s engineStore) EngineCreate(ctx *gofr.Context, engine *models.Engine) (models.Engine, error) {
 	engine.EngineID = uuid.New()
 
-	_, err := ctx.DB().ExecContext(ctx, "INSERT INTO Engine (id,displacement,cylinders,`range`) VALUES(?,?,?,?)",
+	_, err := transaction.DB(ctx).ExecContext(ctx,
+		"INSERT INTO Engine (id,displacement,cylinders,`range`) VALUES(?,?,?,?)",
 		engine.EngineID.String(), engine.Displacement, engine.Cylinders, engine.Range)
 	if err != nil {
 		return models.Engine{}, err
@@ -44,7 +98,7 @@ func (s engineStore) EngineCreate(ctx *gofr.Context, engine *models.Engine) (mod
 
 // EngineDelete to service layer function to delete the engine from database
 func (s engineStore) EngineDelete(ctx *gofr.Context, id string) error {
-	_, err := ctx.DB().ExecContext(ctx, "delete from Engine where id=?", id)
+	_, err := transaction.DB(ctx).ExecContext(ctx, "delete from Engine where id=?", id)
 	if err != nil {
 		return err
 	}
@@ -54,7 +108,7 @@ func (s engineStore) EngineDelete(ctx *gofr.Context, id string) error {
 
 // EngineUpdate is a datastore layer function to update a car record in database
 func (s engineStore) EngineUpdate(ctx *gofr.Context, id string, engine *models.Engine) (models.Engine, error) {
-	_, err := ctx.DB().ExecContext(ctx, "UPDATE Engine SET displacement=?,cylinders=?,`range`=? WHERE Id=?;",
+	_, err := transaction.DB(ctx).ExecContext(ctx, "UPDATE Engine SET displacement=?,cylinders=?,`range`=? WHERE Id=?;",
 		engine.Displacement, engine.Cylinders, engine.Range, id)
 	if err != nil {
 		return models.Engine{}, err
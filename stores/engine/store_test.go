@@ -5,7 +5,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"developer.zopsmart.com/go/gofr/pkg/datastore"
 	"developer.zopsmart.com/go/gofr/pkg/errors"
@@ -33,12 +35,14 @@ func TestEngineGetByID(t *testing.T) {
 		t.Errorf("cannot generate new id : %v", err)
 	}
 
-	engine := models.Engine{EngineID: id, Displacement: 1800, Cylinders: 7, Range: 0}
+	updated := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	engine := models.Engine{EngineID: id, Displacement: 1800, Cylinders: 7, Range: 0, UpdatedAt: &updated}
+	query := "SELECT id,displacement,cylinders,`range`,updated_at FROM Engine WHERE id=?;"
 
-	rows := sqlmock.NewRows([]string{"id", "displacement", "cylinders", "range"}).
-		AddRow(id.String(), 1800, 7, 0)
-	mock.ExpectQuery("SELECT * from Engine where id=?;").WithArgs(id.String()).WillReturnRows(rows)
-	mock.ExpectQuery("SELECT * from Engine where id=?;").WithArgs(uuid.Nil).WillReturnError(errors.EntityNotFound{})
+	rows := sqlmock.NewRows([]string{"id", "displacement", "cylinders", "range", "updated_at"}).
+		AddRow(id.String(), 1800, 7, 0, updated)
+	mock.ExpectQuery(query).WithArgs(id.String()).WillReturnRows(rows)
+	mock.ExpectQuery(query).WithArgs(uuid.Nil).WillReturnError(errors.EntityNotFound{})
 
 	cases := []struct {
 		desc   string
@@ -52,7 +56,55 @@ func TestEngineGetByID(t *testing.T) {
 	for i, tc := range cases {
 		resp, err := dbcheck.EngineGetByID(ctx, tc.input.String())
 
-		if resp != tc.output {
+		if !reflect.DeepEqual(resp, tc.output) {
+			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, resp, tc.output)
+		}
+
+		if err != tc.err {
+			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, err, tc.err)
+		}
+	}
+}
+
+// TestGetEnginesByIDs test the GetEnginesByIDs functionality of the datastore layer
+func TestGetEnginesByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Error(err)
+	}
+
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	dbcheck := New()
+
+	defer db.Close()
+
+	id1, id2 := uuid.New(), uuid.New()
+	updated := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	query := "SELECT id,displacement,cylinders,`range`,updated_at FROM Engine WHERE id IN (?,?);"
+
+	mock.ExpectQuery(query).WithArgs(id1.String(), id2.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "displacement", "cylinders", "range", "updated_at"}).
+			AddRow(id1.String(), 1800, 4, 0, updated).AddRow(id2.String(), 0, 0, 400, updated))
+	mock.ExpectQuery(query).WithArgs(id1.String(), id2.String()).WillReturnError(errors.Error("db down"))
+
+	cases := []struct {
+		desc   string
+		input  []string
+		output []models.Engine
+		err    error
+	}{
+		{"success", []string{id1.String(), id2.String()}, []models.Engine{
+			{EngineID: id1, Displacement: 1800, Cylinders: 4, UpdatedAt: &updated},
+			{EngineID: id2, Range: 400, UpdatedAt: &updated}}, nil},
+		{"failure", []string{id1.String(), id2.String()}, nil, errors.Error("db down")},
+		{"no ids", nil, []models.Engine{}, nil},
+	}
+	for i, tc := range cases {
+		resp, err := dbcheck.GetEnginesByIDs(ctx, tc.input)
+
+		if !reflect.DeepEqual(resp, tc.output) {
 			t.Errorf("\n[TEST %v] Failed \nDesc %v\nGot %v\n Expected %v", i, tc.desc, resp, tc.output)
 		}
 
@@ -0,0 +1,6 @@
+package stores
+
+import "developer.zopsmart.com/go/gofr/pkg/errors"
+
+// ErrDuplicateVIN is returned by the car store when a car is written with the VIN of another car
+const ErrDuplicateVIN = errors.Error("another car has the VIN")
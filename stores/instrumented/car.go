@@ -0,0 +1,169 @@
+// Package instrumented wraps the stores to record the latency of every method as a metrics.StoreQueryDuration
+// histogram, labelled by store and method, and to trace every method in a span of its own.
+package instrumented
+
+import (
+	"Project/CarDealearship/metrics"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"Project/CarDealearship/tracing"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+type car struct {
+	store   stores.Car
+	metrics metrics.Recorder
+}
+
+// nolint:revive // need not be exported
+// NewCar factory function, the latencies of s are recorded with m, which can be nil
+func NewCar(s stores.Car, m metrics.Recorder) car {
+	return car{store: s, metrics: metrics.Or(m)}
+}
+
+// GetCarByID reads the car
+func (s car) GetCarByID(ctx *gofr.Context, id string) (models.Car, error) {
+	ctx, o := begin(ctx, s.metrics, "car", "GetCarByID")
+	res, err := s.store.GetCarByID(ctx, id)
+	o.end(err)
+
+	return res, err
+}
+
+// GetCarsByBrand reads the cars of the brand
+func (s car) GetCarsByBrand(ctx *gofr.Context, brand string) ([]models.Car, error) {
+	ctx, o := begin(ctx, s.metrics, "car", "GetCarsByBrand")
+	res, err := s.store.GetCarsByBrand(ctx, brand)
+	o.endRows(len(res), err)
+
+	return res, err
+}
+
+// GetCarsByIDs reads the cars
+func (s car) GetCarsByIDs(ctx *gofr.Context, ids []string) ([]models.Car, error) {
+	ctx, o := begin(ctx, s.metrics, "car", "GetCarsByIDs")
+	res, err := s.store.GetCarsByIDs(ctx, ids)
+	o.endRows(len(res), err)
+
+	return res, err
+}
+
+// GetAllCars reads every car
+func (s car) GetAllCars(ctx *gofr.Context) ([]models.Car, error) {
+	ctx, o := begin(ctx, s.metrics, "car", "GetAllCars")
+	res, err := s.store.GetAllCars(ctx)
+	o.endRows(len(res), err)
+
+	return res, err
+}
+
+// GetDuplicateCandidates reads the cars that may be duplicates of c
+func (s car) GetDuplicateCandidates(ctx *gofr.Context, c *models.Car) ([]models.Car, error) {
+	ctx, o := begin(ctx, s.metrics, "car", "GetDuplicateCandidates")
+	res, err := s.store.GetDuplicateCandidates(ctx, c)
+	o.endRows(len(res), err)
+
+	return res, err
+}
+
+// StreamCars passes the cars of the brand to fn, the latency and the span include the time spent in fn
+func (s car) StreamCars(ctx *gofr.Context, brand string, fn func(car models.Car) error) error {
+	ctx, o := begin(ctx, s.metrics, "car", "StreamCars")
+	n := 0
+
+	err := s.store.StreamCars(ctx, brand, func(c models.Car) error {
+		n++
+		return fn(c)
+	})
+	o.endRows(n, err)
+
+	return err
+}
+
+// StreamCarsBySpec passes every car to fn in the order duplicates are found in, the latency and the span include
+// the time spent in fn
+func (s car) StreamCarsBySpec(ctx *gofr.Context, fn func(car models.Car) error) error {
+	ctx, o := begin(ctx, s.metrics, "car", "StreamCarsBySpec")
+	n := 0
+
+	err := s.store.StreamCarsBySpec(ctx, func(c models.Car) error {
+		n++
+		return fn(c)
+	})
+	o.endRows(n, err)
+
+	return err
+}
+
+// CreateCar creates the car
+func (s car) CreateCar(ctx *gofr.Context, c *models.Car) (models.Car, error) {
+	ctx, o := begin(ctx, s.metrics, "car", "CreateCar")
+	res, err := s.store.CreateCar(ctx, c)
+	o.end(err)
+
+	return res, err
+}
+
+// DeleteCar deletes the car
+func (s car) DeleteCar(ctx *gofr.Context, id string) error {
+	ctx, o := begin(ctx, s.metrics, "car", "DeleteCar")
+	err := s.store.DeleteCar(ctx, id)
+	o.end(err)
+
+	return err
+}
+
+// UpdateCar updates the car
+func (s car) UpdateCar(ctx *gofr.Context, id string, c *models.Car) (models.Car, error) {
+	ctx, o := begin(ctx, s.metrics, "car", "UpdateCar")
+	res, err := s.store.UpdateCar(ctx, id, c)
+	o.end(err)
+
+	return res, err
+}
+
+// TouchCar marks the car as changed
+func (s car) TouchCar(ctx *gofr.Context, id string) error {
+	ctx, o := begin(ctx, s.metrics, "car", "TouchCar")
+	err := s.store.TouchCar(ctx, id)
+	o.end(err)
+
+	return err
+}
+
+// CountCars counts the cars by status and dealership
+func (s car) CountCars(ctx *gofr.Context) ([]models.InventoryCount, error) {
+	ctx, o := begin(ctx, s.metrics, "car", "CountCars")
+	res, err := s.store.CountCars(ctx)
+	o.endRows(len(res), err)
+
+	return res, err
+}
+
+// call is a store method being observed, it is timed and traced
+type call struct {
+	span          *tracing.Span
+	metrics       metrics.Recorder
+	store, method string
+	start         time.Time
+}
+
+// begin starts observing a call, the store is to be called with the context returned, which carries its span
+func begin(ctx *gofr.Context, m metrics.Recorder, store, method string) (*gofr.Context, call) {
+	ctx, span := tracing.Start(ctx, "store."+store+"."+method)
+
+	return ctx, call{span: span, metrics: m, store: store, method: method, start: time.Now()}
+}
+
+func (c call) end(err error) {
+	metrics.Since(c.metrics, c.store, c.method, c.start)
+	c.span.End(err)
+}
+
+// endRows ends a call that returned n entities
+func (c call) endRows(n int, err error) {
+	c.span.SetRows(n)
+	c.end(err)
+}
@@ -0,0 +1,191 @@
+package instrumented
+
+import (
+	"context"
+	"testing"
+
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	carStore "Project/CarDealearship/stores/car"
+	"Project/CarDealearship/tracing"
+
+	"developer.zopsmart.com/go/gofr/pkg/datastore"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recorder keeps the labels of the observed latencies
+type recorder struct {
+	observed [][]string
+}
+
+func (r *recorder) IncCounter(string, ...string) error {
+	return nil
+}
+
+func (r *recorder) SetGauge(string, float64, ...string) error {
+	return nil
+}
+
+func (r *recorder) ObserveHistogram(name string, value float64, labels ...string) error {
+	if name == "store_query_duration_seconds" && value >= 0 {
+		r.observed = append(r.observed, labels)
+	}
+
+	return nil
+}
+
+// spanned matches the copies of ctx that carry a span, which the calls are made with
+type spanned struct {
+	ctx *gofr.Context
+}
+
+func (m spanned) Matches(x interface{}) bool {
+	c, ok := x.(*gofr.Context)
+
+	return ok && c != m.ctx && c.Gofr == m.ctx.Gofr && trace.SpanFromContext(c).SpanContext().IsValid()
+}
+
+func (m spanned) String() string {
+	return "a copy of the context carrying a span"
+}
+
+func TestCar(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	exporter := newExporter()
+	mockCar := stores.NewMockCar(ctrl)
+	m := &recorder{}
+	s := NewCar(mockCar, m)
+
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{})
+	ctx.Context = context.TODO()
+
+	c := models.Car{ID: uuid.New(), Name: "M3", Brand: "BMW"}
+	id := c.ID.String()
+	fn := func(models.Car) error { return nil }
+
+	gomock.InOrder(
+		mockCar.EXPECT().GetCarByID(spanned{ctx}, id).Return(c, nil),
+		mockCar.EXPECT().GetCarsByBrand(spanned{ctx}, "BMW").Return([]models.Car{c}, nil),
+		mockCar.EXPECT().GetCarsByIDs(spanned{ctx}, []string{id}).Return([]models.Car{c}, nil),
+		mockCar.EXPECT().GetAllCars(spanned{ctx}).Return([]models.Car{c}, nil),
+		mockCar.EXPECT().GetDuplicateCandidates(spanned{ctx}, &c).Return(nil, nil),
+		mockCar.EXPECT().StreamCars(spanned{ctx}, "BMW", gomock.Any()).Return(nil),
+		mockCar.EXPECT().StreamCarsBySpec(spanned{ctx}, gomock.Any()).
+			DoAndReturn(func(_ *gofr.Context, fn func(models.Car) error) error { return fn(c) }),
+		mockCar.EXPECT().CreateCar(spanned{ctx}, &c).Return(c, nil),
+		mockCar.EXPECT().UpdateCar(spanned{ctx}, id, &c).Return(c, nil),
+		mockCar.EXPECT().TouchCar(spanned{ctx}, id).Return(nil),
+		mockCar.EXPECT().CountCars(spanned{ctx}).Return([]models.InventoryCount{{Status: "AVAILABLE", Count: 1}}, nil),
+		mockCar.EXPECT().DeleteCar(spanned{ctx}, id).Return(errors.Error("db down")),
+	)
+
+	res, _ := s.GetCarByID(ctx, id)
+	assert.Equal(t, c, res)
+
+	cars, _ := s.GetCarsByBrand(ctx, "BMW")
+	assert.Equal(t, []models.Car{c}, cars)
+
+	cars, _ = s.GetCarsByIDs(ctx, []string{id})
+	assert.Equal(t, []models.Car{c}, cars)
+
+	cars, _ = s.GetAllCars(ctx)
+	assert.Equal(t, []models.Car{c}, cars)
+
+	cars, _ = s.GetDuplicateCandidates(ctx, &c)
+	assert.Empty(t, cars)
+
+	assert.Equal(t, nil, s.StreamCars(ctx, "BMW", fn))
+	assert.Equal(t, nil, s.StreamCarsBySpec(ctx, fn))
+
+	res, _ = s.CreateCar(ctx, &c)
+	assert.Equal(t, c, res)
+
+	res, _ = s.UpdateCar(ctx, id, &c)
+	assert.Equal(t, c, res)
+
+	assert.Equal(t, nil, s.TouchCar(ctx, id))
+
+	counts, _ := s.CountCars(ctx)
+	assert.Equal(t, []models.InventoryCount{{Status: "AVAILABLE", Count: 1}}, counts)
+
+	assert.Equal(t, errors.Error("db down"), s.DeleteCar(ctx, id), "errors are returned as they are")
+
+	assert.Equal(t, [][]string{{"car", "GetCarByID"}, {"car", "GetCarsByBrand"}, {"car", "GetCarsByIDs"},
+		{"car", "GetAllCars"}, {"car", "GetDuplicateCandidates"}, {"car", "StreamCars"}, {"car", "StreamCarsBySpec"},
+		{"car", "CreateCar"}, {"car", "UpdateCar"}, {"car", "TouchCar"}, {"car", "CountCars"}, {"car", "DeleteCar"}},
+		m.observed)
+
+	spans := exporter.GetSpans()
+	if assert.Len(t, spans, 12) {
+		assert.Equal(t, "store.car.GetCarByID", spans[0].Name)
+		assert.Empty(t, spans[0].Attributes)
+		assert.Equal(t, "store.car.GetCarsByBrand", spans[1].Name)
+		assert.Equal(t, []attribute.KeyValue{tracing.RowsKey.Int(1)}, spans[1].Attributes)
+		assert.Equal(t, "store.car.StreamCars", spans[5].Name)
+		assert.Equal(t, []attribute.KeyValue{tracing.RowsKey.Int(0)}, spans[5].Attributes)
+		assert.Equal(t, "store.car.StreamCarsBySpec", spans[6].Name)
+		assert.Equal(t, []attribute.KeyValue{tracing.RowsKey.Int(1)}, spans[6].Attributes)
+		assert.Equal(t, "store.car.DeleteCar", spans[11].Name)
+		assert.Equal(t, codes.Error, spans[11].Status.Code)
+	}
+}
+
+// TestCarPropagation tests that the statements of the store are traced as children of the span of its method
+func TestCarPropagation(t *testing.T) {
+	exporter := newExporter()
+
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+	s := NewCar(carStore.New(), nil)
+
+	mock.ExpectExec("DELETE FROM Car WHERE ID=?").WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.Equal(t, nil, s.DeleteCar(ctx, "1"))
+	assert.Equal(t, context.TODO(), ctx.Context, "ctx is left as it is")
+
+	spans := exporter.GetSpans()
+	if assert.Len(t, spans, 2) {
+		assert.Equal(t, "SQL DELETE", spans[0].Name)
+		assert.Equal(t, "store.car.DeleteCar", spans[1].Name)
+		assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+		assert.Equal(t, spans[1].SpanContext.TraceID(), spans[0].SpanContext.TraceID())
+	}
+}
+
+func newExporter() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	return exporter
+}
+
+func TestCarWithoutMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCar := stores.NewMockCar(ctrl)
+	s := NewCar(mockCar, nil)
+
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{})
+	ctx.Context = context.TODO()
+
+	mockCar.EXPECT().TouchCar(spanned{ctx}, "1").Return(nil)
+
+	assert.Equal(t, nil, s.TouchCar(ctx, "1"))
+}
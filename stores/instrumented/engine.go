@@ -0,0 +1,65 @@
+package instrumented
+
+import (
+	"Project/CarDealearship/metrics"
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+type engine struct {
+	store   stores.Engine
+	metrics metrics.Recorder
+}
+
+// nolint:revive // need not be exported
+// NewEngine factory function, the latencies of s are recorded with m, which can be nil
+func NewEngine(s stores.Engine, m metrics.Recorder) engine {
+	return engine{store: s, metrics: metrics.Or(m)}
+}
+
+// EngineGetByID reads the engine
+func (s engine) EngineGetByID(ctx *gofr.Context, id string) (models.Engine, error) {
+	ctx, o := begin(ctx, s.metrics, "engine", "EngineGetByID")
+	res, err := s.store.EngineGetByID(ctx, id)
+	o.end(err)
+
+	return res, err
+}
+
+// GetEnginesByIDs reads the engines
+func (s engine) GetEnginesByIDs(ctx *gofr.Context, ids []string) ([]models.Engine, error) {
+	ctx, o := begin(ctx, s.metrics, "engine", "GetEnginesByIDs")
+	res, err := s.store.GetEnginesByIDs(ctx, ids)
+	o.endRows(len(res), err)
+
+	return res, err
+}
+
+// EngineCreate creates the engine
+func (s engine) EngineCreate(ctx *gofr.Context, e *models.Engine) (models.Engine, error) {
+	ctx, o := begin(ctx, s.metrics, "engine", "EngineCreate")
+	res, err := s.store.EngineCreate(ctx, e)
+	o.end(err)
+
+	return res, err
+}
+
+// EngineDelete deletes the engine
+func (s engine) EngineDelete(ctx *gofr.Context, id string) error {
+	ctx, o := begin(ctx, s.metrics, "engine", "EngineDelete")
+	err := s.store.EngineDelete(ctx, id)
+	o.end(err)
+
+	return err
+}
+
+// EngineUpdate updates the engine
+func (s engine) EngineUpdate(ctx *gofr.Context, id string, e *models.Engine) (models.Engine, error) {
+	ctx, o := begin(ctx, s.metrics, "engine", "EngineUpdate")
+	res, err := s.store.EngineUpdate(ctx, id, e)
+	o.end(err)
+
+	return res, err
+}
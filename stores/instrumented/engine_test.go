@@ -0,0 +1,68 @@
+package instrumented
+
+import (
+	"context"
+	"testing"
+
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"Project/CarDealearship/tracing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestEngine(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	exporter := newExporter()
+	mockEngine := stores.NewMockEngine(ctrl)
+	m := &recorder{}
+	s := NewEngine(mockEngine, m)
+
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{})
+	ctx.Context = context.TODO()
+
+	e := models.Engine{EngineID: uuid.New(), Displacement: 2998, Cylinders: 6}
+	id := e.EngineID.String()
+
+	gomock.InOrder(
+		mockEngine.EXPECT().EngineGetByID(spanned{ctx}, id).Return(e, nil),
+		mockEngine.EXPECT().GetEnginesByIDs(spanned{ctx}, []string{id}).Return([]models.Engine{e}, nil),
+		mockEngine.EXPECT().EngineCreate(spanned{ctx}, &e).Return(e, nil),
+		mockEngine.EXPECT().EngineUpdate(spanned{ctx}, id, &e).Return(models.Engine{}, errors.Error("db down")),
+		mockEngine.EXPECT().EngineDelete(spanned{ctx}, id).Return(nil),
+	)
+
+	res, _ := s.EngineGetByID(ctx, id)
+	assert.Equal(t, e, res)
+
+	engines, _ := s.GetEnginesByIDs(ctx, []string{id})
+	assert.Equal(t, []models.Engine{e}, engines)
+
+	res, _ = s.EngineCreate(ctx, &e)
+	assert.Equal(t, e, res)
+
+	_, err := s.EngineUpdate(ctx, id, &e)
+	assert.Equal(t, errors.Error("db down"), err, "errors are returned as they are")
+
+	assert.Equal(t, nil, s.EngineDelete(ctx, id))
+
+	assert.Equal(t, [][]string{{"engine", "EngineGetByID"}, {"engine", "GetEnginesByIDs"},
+		{"engine", "EngineCreate"}, {"engine", "EngineUpdate"}, {"engine", "EngineDelete"}}, m.observed)
+
+	spans := exporter.GetSpans()
+	if assert.Len(t, spans, 5) {
+		assert.Equal(t, "store.engine.GetEnginesByIDs", spans[1].Name)
+		assert.Equal(t, []attribute.KeyValue{tracing.RowsKey.Int(1)}, spans[1].Attributes)
+		assert.Equal(t, "store.engine.EngineUpdate", spans[3].Name)
+		assert.Equal(t, codes.Error, spans[3].Status.Code)
+		assert.Equal(t, "db down", spans[3].Status.Description)
+	}
+}
@@ -2,6 +2,8 @@ package stores
 
 import (
 	"Project/CarDealearship/models"
+	"io"
+	"time"
 
 	"developer.zopsmart.com/go/gofr/pkg/gofr"
 )
@@ -9,14 +11,79 @@ import (
 type Car interface {
 	GetCarByID(ctx *gofr.Context, id string) (models.Car, error)
 	GetCarsByBrand(ctx *gofr.Context, brand string) ([]models.Car, error)
+	GetCarsByIDs(ctx *gofr.Context, ids []string) ([]models.Car, error)
+	GetAllCars(ctx *gofr.Context) ([]models.Car, error)
+	GetDuplicateCandidates(ctx *gofr.Context, car *models.Car) ([]models.Car, error)
+	StreamCars(ctx *gofr.Context, brand string, fn func(car models.Car) error) error
+	StreamCarsBySpec(ctx *gofr.Context, fn func(car models.Car) error) error
 	CreateCar(ctx *gofr.Context, car *models.Car) (models.Car, error)
 	DeleteCar(ctx *gofr.Context, id string) error
 	UpdateCar(ctx *gofr.Context, id string, car *models.Car) (models.Car, error)
+	TouchCar(ctx *gofr.Context, id string) error
+	CountCars(ctx *gofr.Context) ([]models.InventoryCount, error)
 }
 
 type Engine interface {
 	EngineGetByID(ctx *gofr.Context, id string) (models.Engine, error)
+	GetEnginesByIDs(ctx *gofr.Context, ids []string) ([]models.Engine, error)
 	EngineCreate(ctx *gofr.Context, engine *models.Engine) (models.Engine, error)
 	EngineDelete(ctx *gofr.Context, id string) error
 	EngineUpdate(ctx *gofr.Context, id string, engine *models.Engine) (models.Engine, error)
 }
+
+type Media interface {
+	GetMediaByCarID(ctx *gofr.Context, carID string) ([]models.Media, error)
+	GetMediaByID(ctx *gofr.Context, id string) (models.Media, error)
+	CreateMedia(ctx *gofr.Context, media *models.Media) (models.Media, error)
+	DeleteMedia(ctx *gofr.Context, id string) error
+	DeleteMediaByCarID(ctx *gofr.Context, carID string) error
+	UpdateMediaPosition(ctx *gofr.Context, id string, position int) error
+	SetCoverMedia(ctx *gofr.Context, carID, id string) error
+}
+
+type Blob interface {
+	Put(ctx *gofr.Context, key, contentType string, body io.Reader, size int64) error
+	Get(ctx *gofr.Context, key string) (io.ReadCloser, string, error)
+	Delete(ctx *gofr.Context, key string) error
+	URL(key string) string
+}
+
+type SearchIndex interface {
+	Index(ctx *gofr.Context, car models.Car) error
+	Remove(ctx *gofr.Context, id string) error
+	Search(ctx *gofr.Context, query string, limit int) ([]models.SearchHit, error)
+}
+
+type Dealership interface {
+	GetDealershipsByIDs(ctx *gofr.Context, ids []string) ([]models.Dealership, error)
+}
+
+type APIKey interface {
+	GetAPIKeyByHash(ctx *gofr.Context, hash string) (models.APIKey, error)
+}
+
+type Transaction interface {
+	WithTransaction(ctx *gofr.Context, fn func(ctx *gofr.Context) error) error
+}
+
+type Outbox interface {
+	AddEvent(ctx *gofr.Context, event *models.Event) error
+	GetPendingEvents(ctx *gofr.Context, limit int) ([]models.Event, error)
+	MarkEventsPublished(ctx *gofr.Context, ids []string) error
+	DeletePublishedEvents(ctx *gofr.Context, retention time.Duration, limit int) (int, error)
+	GetEventsAfter(ctx *gofr.Context, seq int64, limit int) ([]models.Event, error)
+	GetLastSeq(ctx *gofr.Context) (int64, error)
+}
+
+type Webhook interface {
+	GetWebhooks(ctx *gofr.Context) ([]models.Webhook, error)
+	GetWebhookByID(ctx *gofr.Context, id string) (models.Webhook, error)
+	CreateWebhook(ctx *gofr.Context, webhook *models.Webhook) (models.Webhook, error)
+	UpdateWebhook(ctx *gofr.Context, id string, webhook *models.Webhook) (models.Webhook, error)
+	DeleteWebhook(ctx *gofr.Context, id string) error
+	CreateDelivery(ctx *gofr.Context, delivery *models.WebhookDelivery) error
+	GetDueDeliveries(ctx *gofr.Context, limit int) ([]models.WebhookDelivery, error)
+	GetDeliveries(ctx *gofr.Context, webhookID, status string, limit int) ([]models.WebhookDelivery, error)
+	GetDeliveryByID(ctx *gofr.Context, id string) (models.WebhookDelivery, error)
+	UpdateDelivery(ctx *gofr.Context, delivery *models.WebhookDelivery) error
+}
@@ -0,0 +1,126 @@
+package media
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores/transaction"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+const mediaColumns = "id,car_id,kind,content_type,file_name,size,position,is_cover,storage_key,url," +
+	"thumbnail_key,thumbnail_url,created_at"
+
+type store struct{}
+
+// nolint:revive // need not be exported
+// New factory function
+func New() store {
+	return store{}
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(row scanner) (models.Media, error) {
+	var m models.Media
+
+	err := row.Scan(&m.ID, &m.CarID, &m.Kind, &m.ContentType, &m.FileName, &m.Size, &m.Position, &m.IsCover,
+		&m.StorageKey, &m.URL, &m.ThumbnailKey, &m.ThumbnailURL, &m.CreatedAt)
+
+	return m, err
+}
+
+// GetMediaByCarID is the datastore layer function to get all the media of a car, cover first and then by position
+func (s store) GetMediaByCarID(ctx *gofr.Context, carID string) ([]models.Media, error) {
+	media := make([]models.Media, 0)
+
+	rows, err := transaction.DB(ctx).QueryContext(ctx, "SELECT "+mediaColumns+" FROM Media WHERE car_id=? "+
+		"ORDER BY is_cover DESC, position ASC;", carID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		m, err := scan(rows)
+		if err != nil {
+			return nil, errors.Error("Scan Error")
+		}
+
+		media = append(media, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// GetMediaByID is the datastore layer function to get a single media item by its id
+func (s store) GetMediaByID(ctx *gofr.Context, id string) (models.Media, error) {
+	m, err := scan(transaction.DB(ctx).QueryRowContext(ctx, "SELECT "+mediaColumns+" FROM Media WHERE id=?;", id))
+	if err != nil {
+		return models.Media{}, err
+	}
+
+	return m, nil
+}
+
+// CreateMedia is the datastore layer function to store the metadata of an uploaded file
+func (s store) CreateMedia(ctx *gofr.Context, m *models.Media) (models.Media, error) {
+	_, err := transaction.DB(ctx).ExecContext(ctx,
+		"INSERT INTO Media ("+mediaColumns+") VALUES(?,?,?,?,?,?,?,?,?,?,?,?,?)",
+		m.ID.String(), m.CarID.String(), m.Kind, m.ContentType, m.FileName, m.Size, m.Position, m.IsCover,
+		m.StorageKey, m.URL, m.ThumbnailKey, m.ThumbnailURL, m.CreatedAt)
+	if err != nil {
+		return models.Media{}, err
+	}
+
+	return *m, nil
+}
+
+// DeleteMedia is the datastore layer function to delete the metadata of a media item
+func (s store) DeleteMedia(ctx *gofr.Context, id string) error {
+	_, err := transaction.DB(ctx).ExecContext(ctx, "DELETE FROM Media WHERE id=?", id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteMediaByCarID is the datastore layer function to delete the metadata of every media item of a car
+func (s store) DeleteMediaByCarID(ctx *gofr.Context, carID string) error {
+	_, err := transaction.DB(ctx).ExecContext(ctx, "DELETE FROM Media WHERE car_id=?", carID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateMediaPosition is the datastore layer function to move a media item to the given position
+func (s store) UpdateMediaPosition(ctx *gofr.Context, id string, position int) error {
+	_, err := transaction.DB(ctx).ExecContext(ctx, "UPDATE Media SET position=? WHERE id=?", position, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetCoverMedia is the datastore layer function to mark one media item as the cover of its car
+func (s store) SetCoverMedia(ctx *gofr.Context, carID, id string) error {
+	_, err := transaction.DB(ctx).ExecContext(ctx, "UPDATE Media SET is_cover=(id=?) WHERE car_id=?", id, carID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
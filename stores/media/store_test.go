@@ -0,0 +1,158 @@
+package media
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/datastore"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var columns = []string{"id", "car_id", "kind", "content_type", "file_name", "size", "position", "is_cover",
+	"storage_key", "url", "thumbnail_key", "thumbnail_url", "created_at"}
+
+func newContext(t *testing.T) (*gofr.Context, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	return ctx, mock
+}
+
+// TestGetMediaByCarID tests the datastore function GetMediaByCarID
+func TestGetMediaByCarID(t *testing.T) {
+	ctx, mock := newContext(t)
+	s := New()
+
+	carID := uuid.New()
+	id := uuid.New()
+	now := time.Now()
+
+	m := models.Media{ID: id, CarID: carID, Kind: models.MediaImage, ContentType: "image/png", FileName: "front.png",
+		Size: 10, Position: 0, IsCover: true, StorageKey: id.String() + ".png", URL: "http://m/" + id.String() + ".png",
+		ThumbnailKey: id.String() + "_thumb.jpg", ThumbnailURL: "http://m/" + id.String() + "_thumb.jpg", CreatedAt: now}
+
+	query := "SELECT " + mediaColumns + " FROM Media WHERE car_id=? ORDER BY is_cover DESC, position ASC;"
+
+	mock.ExpectQuery(query).WithArgs(carID.String()).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(id.String(), carID.String(), m.Kind, m.ContentType, m.FileName,
+			m.Size, m.Position, m.IsCover, m.StorageKey, m.URL, m.ThumbnailKey, m.ThumbnailURL, now))
+	mock.ExpectQuery(query).WithArgs("bad").WillReturnError(errors.Error("query error"))
+	mock.ExpectQuery(query).WithArgs("short").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id.String()))
+
+	testCases := []struct {
+		desc   string
+		carID  string
+		output []models.Media
+		err    error
+	}{
+		{desc: "success", carID: carID.String(), output: []models.Media{m}},
+		{desc: "query error", carID: "bad", err: errors.Error("query error")},
+		{desc: "scan error", carID: "short", err: errors.Error("Scan Error")},
+	}
+
+	for i, tc := range testCases {
+		res, err := s.GetMediaByCarID(ctx, tc.carID)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.output, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestGetMediaByID tests the datastore function GetMediaByID
+func TestGetMediaByID(t *testing.T) {
+	ctx, mock := newContext(t)
+	s := New()
+
+	id := uuid.New()
+	carID := uuid.New()
+	now := time.Now()
+
+	query := "SELECT " + mediaColumns + " FROM Media WHERE id=?;"
+
+	mock.ExpectQuery(query).WithArgs(id.String()).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(id.String(), carID.String(), models.MediaDocument,
+			"application/pdf", "invoice.pdf", 20, 1, false, "k", "u", "", "", now))
+	mock.ExpectQuery(query).WithArgs("missing").WillReturnError(errors.EntityNotFound{Entity: "Media"})
+
+	res, err := s.GetMediaByID(ctx, id.String())
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, models.Media{ID: id, CarID: carID, Kind: models.MediaDocument, ContentType: "application/pdf",
+		FileName: "invoice.pdf", Size: 20, Position: 1, StorageKey: "k", URL: "u", CreatedAt: now}, res)
+
+	res, err = s.GetMediaByID(ctx, "missing")
+
+	assert.Equal(t, errors.EntityNotFound{Entity: "Media"}, err)
+	assert.Equal(t, models.Media{}, res)
+}
+
+// TestCreateMedia tests the datastore function CreateMedia
+func TestCreateMedia(t *testing.T) {
+	ctx, mock := newContext(t)
+	s := New()
+
+	now := time.Now()
+	m := models.Media{ID: uuid.New(), CarID: uuid.New(), Kind: models.MediaImage, ContentType: "image/jpeg",
+		FileName: "side.jpg", Size: 5, Position: 2, StorageKey: "k", URL: "u", CreatedAt: now}
+
+	query := "INSERT INTO Media (" + mediaColumns + ") VALUES(?,?,?,?,?,?,?,?,?,?,?,?,?)"
+
+	mock.ExpectExec(query).WithArgs(m.ID.String(), m.CarID.String(), m.Kind, m.ContentType, m.FileName, m.Size,
+		m.Position, m.IsCover, m.StorageKey, m.URL, m.ThumbnailKey, m.ThumbnailURL, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(query).WillReturnError(errors.Error("insert error"))
+
+	res, err := s.CreateMedia(ctx, &m)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, m, res)
+
+	res, err = s.CreateMedia(ctx, &m)
+
+	assert.Equal(t, errors.Error("insert error"), err)
+	assert.Equal(t, models.Media{}, res)
+}
+
+// TestUpdates tests the datastore functions that modify existing media
+func TestUpdates(t *testing.T) {
+	ctx, mock := newContext(t)
+	s := New()
+
+	id := uuid.New().String()
+	carID := uuid.New().String()
+	execErr := errors.Error("exec error")
+
+	mock.ExpectExec("DELETE FROM Media WHERE id=?").WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM Media WHERE id=?").WithArgs(id).WillReturnError(execErr)
+	mock.ExpectExec("DELETE FROM Media WHERE car_id=?").WithArgs(carID).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM Media WHERE car_id=?").WithArgs(carID).WillReturnError(execErr)
+	mock.ExpectExec("UPDATE Media SET position=? WHERE id=?").WithArgs(3, id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE Media SET position=? WHERE id=?").WithArgs(3, id).WillReturnError(execErr)
+	mock.ExpectExec("UPDATE Media SET is_cover=(id=?) WHERE car_id=?").WithArgs(id, carID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE Media SET is_cover=(id=?) WHERE car_id=?").WithArgs(id, carID).WillReturnError(execErr)
+
+	assert.Equal(t, nil, s.DeleteMedia(ctx, id))
+	assert.Equal(t, execErr, s.DeleteMedia(ctx, id))
+	assert.Equal(t, nil, s.DeleteMediaByCarID(ctx, carID))
+	assert.Equal(t, execErr, s.DeleteMediaByCarID(ctx, carID))
+	assert.Equal(t, nil, s.UpdateMediaPosition(ctx, id, 3))
+	assert.Equal(t, execErr, s.UpdateMediaPosition(ctx, id, 3))
+	assert.Equal(t, nil, s.SetCoverMedia(ctx, carID, id))
+	assert.Equal(t, execErr, s.SetCoverMedia(ctx, carID, id))
+}
@@ -6,7 +6,9 @@ package stores
 
 import (
 	models "Project/CarDealearship/models"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gofr "developer.zopsmart.com/go/gofr/pkg/gofr"
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +37,21 @@ func (m *MockCar) EXPECT() *MockCarMockRecorder {
 	return m.recorder
 }
 
+// CountCars mocks base method.
+func (m *MockCar) CountCars(ctx *gofr.Context) ([]models.InventoryCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCars", ctx)
+	ret0, _ := ret[0].([]models.InventoryCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCars indicates an expected call of CountCars.
+func (mr *MockCarMockRecorder) CountCars(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCars", reflect.TypeOf((*MockCar)(nil).CountCars), ctx)
+}
+
 // CreateCar mocks base method.
 func (m *MockCar) CreateCar(ctx *gofr.Context, car *models.Car) (models.Car, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +81,21 @@ func (mr *MockCarMockRecorder) DeleteCar(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCar", reflect.TypeOf((*MockCar)(nil).DeleteCar), ctx, id)
 }
 
+// GetAllCars mocks base method.
+func (m *MockCar) GetAllCars(ctx *gofr.Context) ([]models.Car, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllCars", ctx)
+	ret0, _ := ret[0].([]models.Car)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllCars indicates an expected call of GetAllCars.
+func (mr *MockCarMockRecorder) GetAllCars(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCars", reflect.TypeOf((*MockCar)(nil).GetAllCars), ctx)
+}
+
 // GetCarByID mocks base method.
 func (m *MockCar) GetCarByID(ctx *gofr.Context, id string) (models.Car, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +126,78 @@ func (mr *MockCarMockRecorder) GetCarsByBrand(ctx, brand interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCarsByBrand", reflect.TypeOf((*MockCar)(nil).GetCarsByBrand), ctx, brand)
 }
 
+// GetCarsByIDs mocks base method.
+func (m *MockCar) GetCarsByIDs(ctx *gofr.Context, ids []string) ([]models.Car, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCarsByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.Car)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCarsByIDs indicates an expected call of GetCarsByIDs.
+func (mr *MockCarMockRecorder) GetCarsByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCarsByIDs", reflect.TypeOf((*MockCar)(nil).GetCarsByIDs), ctx, ids)
+}
+
+// GetDuplicateCandidates mocks base method.
+func (m *MockCar) GetDuplicateCandidates(ctx *gofr.Context, car *models.Car) ([]models.Car, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDuplicateCandidates", ctx, car)
+	ret0, _ := ret[0].([]models.Car)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDuplicateCandidates indicates an expected call of GetDuplicateCandidates.
+func (mr *MockCarMockRecorder) GetDuplicateCandidates(ctx, car interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDuplicateCandidates", reflect.TypeOf((*MockCar)(nil).GetDuplicateCandidates), ctx, car)
+}
+
+// StreamCars mocks base method.
+func (m *MockCar) StreamCars(ctx *gofr.Context, brand string, fn func(models.Car) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamCars", ctx, brand, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamCars indicates an expected call of StreamCars.
+func (mr *MockCarMockRecorder) StreamCars(ctx, brand, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamCars", reflect.TypeOf((*MockCar)(nil).StreamCars), ctx, brand, fn)
+}
+
+// StreamCarsBySpec mocks base method.
+func (m *MockCar) StreamCarsBySpec(ctx *gofr.Context, fn func(models.Car) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamCarsBySpec", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamCarsBySpec indicates an expected call of StreamCarsBySpec.
+func (mr *MockCarMockRecorder) StreamCarsBySpec(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamCarsBySpec", reflect.TypeOf((*MockCar)(nil).StreamCarsBySpec), ctx, fn)
+}
+
+// TouchCar mocks base method.
+func (m *MockCar) TouchCar(ctx *gofr.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchCar", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchCar indicates an expected call of TouchCar.
+func (mr *MockCarMockRecorder) TouchCar(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchCar", reflect.TypeOf((*MockCar)(nil).TouchCar), ctx, id)
+}
+
 // UpdateCar mocks base method.
 func (m *MockCar) UpdateCar(ctx *gofr.Context, id string, car *models.Car) (models.Car, error) {
 	m.ctrl.T.Helper()
@@ -190,3 +294,683 @@ func (mr *MockEngineMockRecorder) EngineUpdate(ctx, id, engine interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EngineUpdate", reflect.TypeOf((*MockEngine)(nil).EngineUpdate), ctx, id, engine)
 }
+
+// GetEnginesByIDs mocks base method.
+func (m *MockEngine) GetEnginesByIDs(ctx *gofr.Context, ids []string) ([]models.Engine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEnginesByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.Engine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEnginesByIDs indicates an expected call of GetEnginesByIDs.
+func (mr *MockEngineMockRecorder) GetEnginesByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnginesByIDs", reflect.TypeOf((*MockEngine)(nil).GetEnginesByIDs), ctx, ids)
+}
+
+// MockMedia is a mock of Media interface.
+type MockMedia struct {
+	ctrl     *gomock.Controller
+	recorder *MockMediaMockRecorder
+}
+
+// MockMediaMockRecorder is the mock recorder for MockMedia.
+type MockMediaMockRecorder struct {
+	mock *MockMedia
+}
+
+// NewMockMedia creates a new mock instance.
+func NewMockMedia(ctrl *gomock.Controller) *MockMedia {
+	mock := &MockMedia{ctrl: ctrl}
+	mock.recorder = &MockMediaMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMedia) EXPECT() *MockMediaMockRecorder {
+	return m.recorder
+}
+
+// CreateMedia mocks base method.
+func (m *MockMedia) CreateMedia(ctx *gofr.Context, media *models.Media) (models.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMedia", ctx, media)
+	ret0, _ := ret[0].(models.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMedia indicates an expected call of CreateMedia.
+func (mr *MockMediaMockRecorder) CreateMedia(ctx, media interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMedia", reflect.TypeOf((*MockMedia)(nil).CreateMedia), ctx, media)
+}
+
+// DeleteMedia mocks base method.
+func (m *MockMedia) DeleteMedia(ctx *gofr.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMedia", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMedia indicates an expected call of DeleteMedia.
+func (mr *MockMediaMockRecorder) DeleteMedia(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMedia", reflect.TypeOf((*MockMedia)(nil).DeleteMedia), ctx, id)
+}
+
+// DeleteMediaByCarID mocks base method.
+func (m *MockMedia) DeleteMediaByCarID(ctx *gofr.Context, carID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMediaByCarID", ctx, carID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMediaByCarID indicates an expected call of DeleteMediaByCarID.
+func (mr *MockMediaMockRecorder) DeleteMediaByCarID(ctx, carID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMediaByCarID", reflect.TypeOf((*MockMedia)(nil).DeleteMediaByCarID), ctx, carID)
+}
+
+// GetMediaByCarID mocks base method.
+func (m *MockMedia) GetMediaByCarID(ctx *gofr.Context, carID string) ([]models.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMediaByCarID", ctx, carID)
+	ret0, _ := ret[0].([]models.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMediaByCarID indicates an expected call of GetMediaByCarID.
+func (mr *MockMediaMockRecorder) GetMediaByCarID(ctx, carID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMediaByCarID", reflect.TypeOf((*MockMedia)(nil).GetMediaByCarID), ctx, carID)
+}
+
+// GetMediaByID mocks base method.
+func (m *MockMedia) GetMediaByID(ctx *gofr.Context, id string) (models.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMediaByID", ctx, id)
+	ret0, _ := ret[0].(models.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMediaByID indicates an expected call of GetMediaByID.
+func (mr *MockMediaMockRecorder) GetMediaByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMediaByID", reflect.TypeOf((*MockMedia)(nil).GetMediaByID), ctx, id)
+}
+
+// SetCoverMedia mocks base method.
+func (m *MockMedia) SetCoverMedia(ctx *gofr.Context, carID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCoverMedia", ctx, carID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCoverMedia indicates an expected call of SetCoverMedia.
+func (mr *MockMediaMockRecorder) SetCoverMedia(ctx, carID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCoverMedia", reflect.TypeOf((*MockMedia)(nil).SetCoverMedia), ctx, carID, id)
+}
+
+// UpdateMediaPosition mocks base method.
+func (m *MockMedia) UpdateMediaPosition(ctx *gofr.Context, id string, position int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMediaPosition", ctx, id, position)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMediaPosition indicates an expected call of UpdateMediaPosition.
+func (mr *MockMediaMockRecorder) UpdateMediaPosition(ctx, id, position interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMediaPosition", reflect.TypeOf((*MockMedia)(nil).UpdateMediaPosition), ctx, id, position)
+}
+
+// MockBlob is a mock of Blob interface.
+type MockBlob struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobMockRecorder
+}
+
+// MockBlobMockRecorder is the mock recorder for MockBlob.
+type MockBlobMockRecorder struct {
+	mock *MockBlob
+}
+
+// NewMockBlob creates a new mock instance.
+func NewMockBlob(ctrl *gomock.Controller) *MockBlob {
+	mock := &MockBlob{ctrl: ctrl}
+	mock.recorder = &MockBlobMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlob) EXPECT() *MockBlobMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockBlob) Delete(ctx *gofr.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBlobMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlob)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockBlob) Get(ctx *gofr.Context, key string) (io.ReadCloser, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBlobMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBlob)(nil).Get), ctx, key)
+}
+
+// Put mocks base method.
+func (m *MockBlob) Put(ctx *gofr.Context, key, contentType string, body io.Reader, size int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, contentType, body, size)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockBlobMockRecorder) Put(ctx, key, contentType, body, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockBlob)(nil).Put), ctx, key, contentType, body, size)
+}
+
+// URL mocks base method.
+func (m *MockBlob) URL(key string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "URL", key)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// URL indicates an expected call of URL.
+func (mr *MockBlobMockRecorder) URL(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URL", reflect.TypeOf((*MockBlob)(nil).URL), key)
+}
+
+// MockSearchIndex is a mock of SearchIndex interface.
+type MockSearchIndex struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchIndexMockRecorder
+}
+
+// MockSearchIndexMockRecorder is the mock recorder for MockSearchIndex.
+type MockSearchIndexMockRecorder struct {
+	mock *MockSearchIndex
+}
+
+// NewMockSearchIndex creates a new mock instance.
+func NewMockSearchIndex(ctrl *gomock.Controller) *MockSearchIndex {
+	mock := &MockSearchIndex{ctrl: ctrl}
+	mock.recorder = &MockSearchIndexMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchIndex) EXPECT() *MockSearchIndexMockRecorder {
+	return m.recorder
+}
+
+// Index mocks base method.
+func (m *MockSearchIndex) Index(ctx *gofr.Context, car models.Car) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Index", ctx, car)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Index indicates an expected call of Index.
+func (mr *MockSearchIndexMockRecorder) Index(ctx, car interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Index", reflect.TypeOf((*MockSearchIndex)(nil).Index), ctx, car)
+}
+
+// Remove mocks base method.
+func (m *MockSearchIndex) Remove(ctx *gofr.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockSearchIndexMockRecorder) Remove(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockSearchIndex)(nil).Remove), ctx, id)
+}
+
+// Search mocks base method.
+func (m *MockSearchIndex) Search(ctx *gofr.Context, query string, limit int) ([]models.SearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, limit)
+	ret0, _ := ret[0].([]models.SearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSearchIndexMockRecorder) Search(ctx, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearchIndex)(nil).Search), ctx, query, limit)
+}
+
+// MockDealership is a mock of Dealership interface.
+type MockDealership struct {
+	ctrl     *gomock.Controller
+	recorder *MockDealershipMockRecorder
+}
+
+// MockDealershipMockRecorder is the mock recorder for MockDealership.
+type MockDealershipMockRecorder struct {
+	mock *MockDealership
+}
+
+// NewMockDealership creates a new mock instance.
+func NewMockDealership(ctrl *gomock.Controller) *MockDealership {
+	mock := &MockDealership{ctrl: ctrl}
+	mock.recorder = &MockDealershipMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDealership) EXPECT() *MockDealershipMockRecorder {
+	return m.recorder
+}
+
+// GetDealershipsByIDs mocks base method.
+func (m *MockDealership) GetDealershipsByIDs(ctx *gofr.Context, ids []string) ([]models.Dealership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDealershipsByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.Dealership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDealershipsByIDs indicates an expected call of GetDealershipsByIDs.
+func (mr *MockDealershipMockRecorder) GetDealershipsByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDealershipsByIDs", reflect.TypeOf((*MockDealership)(nil).GetDealershipsByIDs), ctx, ids)
+}
+
+// MockAPIKey is a mock of APIKey interface.
+type MockAPIKey struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyMockRecorder
+}
+
+// MockAPIKeyMockRecorder is the mock recorder for MockAPIKey.
+type MockAPIKeyMockRecorder struct {
+	mock *MockAPIKey
+}
+
+// NewMockAPIKey creates a new mock instance.
+func NewMockAPIKey(ctrl *gomock.Controller) *MockAPIKey {
+	mock := &MockAPIKey{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKey) EXPECT() *MockAPIKeyMockRecorder {
+	return m.recorder
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockAPIKey) GetAPIKeyByHash(ctx *gofr.Context, hash string) (models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockAPIKeyMockRecorder) GetAPIKeyByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockAPIKey)(nil).GetAPIKeyByHash), ctx, hash)
+}
+
+// MockTransaction is a mock of Transaction interface.
+type MockTransaction struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionMockRecorder
+}
+
+// MockTransactionMockRecorder is the mock recorder for MockTransaction.
+type MockTransactionMockRecorder struct {
+	mock *MockTransaction
+}
+
+// NewMockTransaction creates a new mock instance.
+func NewMockTransaction(ctrl *gomock.Controller) *MockTransaction {
+	mock := &MockTransaction{ctrl: ctrl}
+	mock.recorder = &MockTransactionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransaction) EXPECT() *MockTransactionMockRecorder {
+	return m.recorder
+}
+
+// WithTransaction mocks base method.
+func (m *MockTransaction) WithTransaction(ctx *gofr.Context, fn func(*gofr.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTransaction indicates an expected call of WithTransaction.
+func (mr *MockTransactionMockRecorder) WithTransaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTransaction", reflect.TypeOf((*MockTransaction)(nil).WithTransaction), ctx, fn)
+}
+
+// MockOutbox is a mock of Outbox interface.
+type MockOutbox struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxMockRecorder
+}
+
+// MockOutboxMockRecorder is the mock recorder for MockOutbox.
+type MockOutboxMockRecorder struct {
+	mock *MockOutbox
+}
+
+// NewMockOutbox creates a new mock instance.
+func NewMockOutbox(ctrl *gomock.Controller) *MockOutbox {
+	mock := &MockOutbox{ctrl: ctrl}
+	mock.recorder = &MockOutboxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutbox) EXPECT() *MockOutboxMockRecorder {
+	return m.recorder
+}
+
+// AddEvent mocks base method.
+func (m *MockOutbox) AddEvent(ctx *gofr.Context, event *models.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEvent indicates an expected call of AddEvent.
+func (mr *MockOutboxMockRecorder) AddEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvent", reflect.TypeOf((*MockOutbox)(nil).AddEvent), ctx, event)
+}
+
+// DeletePublishedEvents mocks base method.
+func (m *MockOutbox) DeletePublishedEvents(ctx *gofr.Context, retention time.Duration, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePublishedEvents", ctx, retention, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePublishedEvents indicates an expected call of DeletePublishedEvents.
+func (mr *MockOutboxMockRecorder) DeletePublishedEvents(ctx, retention, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePublishedEvents", reflect.TypeOf((*MockOutbox)(nil).DeletePublishedEvents), ctx, retention, limit)
+}
+
+// GetEventsAfter mocks base method.
+func (m *MockOutbox) GetEventsAfter(ctx *gofr.Context, seq int64, limit int) ([]models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventsAfter", ctx, seq, limit)
+	ret0, _ := ret[0].([]models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventsAfter indicates an expected call of GetEventsAfter.
+func (mr *MockOutboxMockRecorder) GetEventsAfter(ctx, seq, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsAfter", reflect.TypeOf((*MockOutbox)(nil).GetEventsAfter), ctx, seq, limit)
+}
+
+// GetLastSeq mocks base method.
+func (m *MockOutbox) GetLastSeq(ctx *gofr.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastSeq", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastSeq indicates an expected call of GetLastSeq.
+func (mr *MockOutboxMockRecorder) GetLastSeq(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastSeq", reflect.TypeOf((*MockOutbox)(nil).GetLastSeq), ctx)
+}
+
+// GetPendingEvents mocks base method.
+func (m *MockOutbox) GetPendingEvents(ctx *gofr.Context, limit int) ([]models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingEvents", ctx, limit)
+	ret0, _ := ret[0].([]models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingEvents indicates an expected call of GetPendingEvents.
+func (mr *MockOutboxMockRecorder) GetPendingEvents(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingEvents", reflect.TypeOf((*MockOutbox)(nil).GetPendingEvents), ctx, limit)
+}
+
+// MarkEventsPublished mocks base method.
+func (m *MockOutbox) MarkEventsPublished(ctx *gofr.Context, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEventsPublished", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEventsPublished indicates an expected call of MarkEventsPublished.
+func (mr *MockOutboxMockRecorder) MarkEventsPublished(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEventsPublished", reflect.TypeOf((*MockOutbox)(nil).MarkEventsPublished), ctx, ids)
+}
+
+// MockWebhook is a mock of Webhook interface.
+type MockWebhook struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookMockRecorder
+}
+
+// MockWebhookMockRecorder is the mock recorder for MockWebhook.
+type MockWebhookMockRecorder struct {
+	mock *MockWebhook
+}
+
+// NewMockWebhook creates a new mock instance.
+func NewMockWebhook(ctrl *gomock.Controller) *MockWebhook {
+	mock := &MockWebhook{ctrl: ctrl}
+	mock.recorder = &MockWebhookMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhook) EXPECT() *MockWebhookMockRecorder {
+	return m.recorder
+}
+
+// CreateDelivery mocks base method.
+func (m *MockWebhook) CreateDelivery(ctx *gofr.Context, delivery *models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDelivery indicates an expected call of CreateDelivery.
+func (mr *MockWebhookMockRecorder) CreateDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelivery", reflect.TypeOf((*MockWebhook)(nil).CreateDelivery), ctx, delivery)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhook) CreateWebhook(ctx *gofr.Context, webhook *models.Webhook) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookMockRecorder) CreateWebhook(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhook)(nil).CreateWebhook), ctx, webhook)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhook) DeleteWebhook(ctx *gofr.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhook)(nil).DeleteWebhook), ctx, id)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockWebhook) GetDeliveries(ctx *gofr.Context, webhookID, status string, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, webhookID, status, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockWebhookMockRecorder) GetDeliveries(ctx, webhookID, status, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockWebhook)(nil).GetDeliveries), ctx, webhookID, status, limit)
+}
+
+// GetDeliveryByID mocks base method.
+func (m *MockWebhook) GetDeliveryByID(ctx *gofr.Context, id string) (models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveryByID", ctx, id)
+	ret0, _ := ret[0].(models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryByID indicates an expected call of GetDeliveryByID.
+func (mr *MockWebhookMockRecorder) GetDeliveryByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryByID", reflect.TypeOf((*MockWebhook)(nil).GetDeliveryByID), ctx, id)
+}
+
+// GetDueDeliveries mocks base method.
+func (m *MockWebhook) GetDueDeliveries(ctx *gofr.Context, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueDeliveries", ctx, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueDeliveries indicates an expected call of GetDueDeliveries.
+func (mr *MockWebhookMockRecorder) GetDueDeliveries(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueDeliveries", reflect.TypeOf((*MockWebhook)(nil).GetDueDeliveries), ctx, limit)
+}
+
+// GetWebhookByID mocks base method.
+func (m *MockWebhook) GetWebhookByID(ctx *gofr.Context, id string) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookByID", ctx, id)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookByID indicates an expected call of GetWebhookByID.
+func (mr *MockWebhookMockRecorder) GetWebhookByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByID", reflect.TypeOf((*MockWebhook)(nil).GetWebhookByID), ctx, id)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockWebhook) GetWebhooks(ctx *gofr.Context) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockWebhookMockRecorder) GetWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockWebhook)(nil).GetWebhooks), ctx)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockWebhook) UpdateDelivery(ctx *gofr.Context, delivery *models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockWebhookMockRecorder) UpdateDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockWebhook)(nil).UpdateDelivery), ctx, delivery)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhook) UpdateWebhook(ctx *gofr.Context, id string, webhook *models.Webhook) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, id, webhook)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookMockRecorder) UpdateWebhook(ctx, id, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhook)(nil).UpdateWebhook), ctx, id, webhook)
+}
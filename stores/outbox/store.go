@@ -0,0 +1,143 @@
+package outbox
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores/transaction"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+type store struct{}
+
+// nolint:revive // need not be exported
+// New factory function
+func New() store {
+	return store{}
+}
+
+// AddEvent is the datastore layer function to record an event in the outbox. It joins the transaction of ctx,
+// so the event is stored if and only if the change it describes is.
+func (s store) AddEvent(ctx *gofr.Context, event *models.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = transaction.DB(ctx).ExecContext(ctx, "INSERT INTO Outbox (id,type,car_id,payload,occurred_at) "+
+		"VALUES(?,?,?,?,?)", event.ID.String(), event.Type, event.CarID.String(), payload, event.OccurredAt)
+
+	return err
+}
+
+// GetPendingEvents is the datastore layer function to get at most limit events not yet published, oldest first.
+// Inside a transaction the rows stay locked until it ends and are skipped by other relays.
+func (s store) GetPendingEvents(ctx *gofr.Context, limit int) ([]models.Event, error) {
+	rows, err := transaction.DB(ctx).QueryContext(ctx, "SELECT payload FROM Outbox WHERE published_at IS NULL "+
+		"ORDER BY seq LIMIT ? FOR UPDATE SKIP LOCKED", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	events := make([]models.Event, 0)
+
+	for rows.Next() {
+		var (
+			payload []byte
+			event   models.Event
+		)
+
+		if err = rows.Scan(&payload); err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(payload, &event); err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// MarkEventsPublished is the datastore layer function to mark the events with the given ids as published
+func (s store) MarkEventsPublished(ctx *gofr.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+
+	_, err := transaction.DB(ctx).ExecContext(ctx, "UPDATE Outbox SET published_at=NOW(6) WHERE id IN ("+
+		strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")+")", args...)
+
+	return err
+}
+
+// DeletePublishedEvents is the datastore layer function to delete at most limit events published longer than
+// retention ago, the oldest first, and returns how many were deleted. Pending events are kept.
+func (s store) DeletePublishedEvents(ctx *gofr.Context, retention time.Duration, limit int) (int, error) {
+	res, err := transaction.DB(ctx).ExecContext(ctx, "DELETE FROM Outbox WHERE published_at<NOW(6)-INTERVAL ? SECOND "+
+		"ORDER BY published_at LIMIT ?", int64(retention/time.Second), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+
+	return int(n), err
+}
+
+// GetEventsAfter is the datastore layer function to get at most limit events, published or not, recorded after
+// the one at position seq, in the order they were recorded. The events have their Seq set.
+func (s store) GetEventsAfter(ctx *gofr.Context, seq int64, limit int) ([]models.Event, error) {
+	rows, err := transaction.DB(ctx).QueryContext(ctx, "SELECT seq,payload FROM Outbox WHERE seq>? ORDER BY seq LIMIT ?",
+		seq, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	events := make([]models.Event, 0)
+
+	for rows.Next() {
+		var (
+			payload []byte
+			event   models.Event
+		)
+
+		if err = rows.Scan(&event.Seq, &payload); err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(payload, &event); err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// GetLastSeq is the datastore layer function to get the position of the latest event, 0 when there is none
+func (s store) GetLastSeq(ctx *gofr.Context) (int64, error) {
+	var seq int64
+
+	err := transaction.DB(ctx).QueryRowContext(ctx, "SELECT COALESCE(MAX(seq),0) FROM Outbox").Scan(&seq)
+
+	return seq, err
+}
@@ -0,0 +1,251 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/datastore"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	insert  = "INSERT INTO Outbox (id,type,car_id,payload,occurred_at) VALUES(?,?,?,?,?)"
+	pending = "SELECT payload FROM Outbox WHERE published_at IS NULL ORDER BY seq LIMIT ? FOR UPDATE SKIP LOCKED"
+)
+
+func newEvent() models.Event {
+	return models.Event{ID: uuid.New(), Type: models.EventCarCreated, CarID: uuid.New(),
+		OccurredAt: time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC), Data: json.RawMessage(`{"Name":"X5"}`)}
+}
+
+func TestAddEvent(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	s := New()
+
+	event := newEvent()
+	payload, _ := json.Marshal(event)
+
+	testCases := []struct {
+		desc string
+		mock *sqlmock.ExpectedExec
+		err  error
+	}{
+		{
+			desc: "success",
+			mock: mock.ExpectExec(insert).
+				WithArgs(event.ID.String(), event.Type, event.CarID.String(), payload, event.OccurredAt).
+				WillReturnResult(sqlmock.NewResult(1, 1)),
+		},
+		{
+			desc: "db error", err: errors.Error("db down"),
+			mock: mock.ExpectExec(insert).
+				WithArgs(event.ID.String(), event.Type, event.CarID.String(), payload, event.OccurredAt).
+				WillReturnError(errors.Error("db down")),
+		},
+	}
+
+	for i, tc := range testCases {
+		err := s.AddEvent(ctx, &event)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPendingEvents(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	s := New()
+
+	event1, event2 := newEvent(), newEvent()
+	payload1, _ := json.Marshal(event1)
+	payload2, _ := json.Marshal(event2)
+
+	testCases := []struct {
+		desc     string
+		mock     *sqlmock.ExpectedQuery
+		expected []models.Event
+		err      error
+	}{
+		{
+			desc: "oldest first",
+			mock: mock.ExpectQuery(pending).WithArgs(10).
+				WillReturnRows(sqlmock.NewRows([]string{"payload"}).AddRow(payload1).AddRow(payload2)),
+			expected: []models.Event{event1, event2},
+		},
+		{
+			desc: "none pending", expected: []models.Event{},
+			mock: mock.ExpectQuery(pending).WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{"payload"})),
+		},
+		{
+			desc: "query error", err: errors.Error("db down"),
+			mock: mock.ExpectQuery(pending).WithArgs(10).WillReturnError(errors.Error("db down")),
+		},
+	}
+
+	for i, tc := range testCases {
+		res, err := s.GetPendingEvents(ctx, 10)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.expected, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	mock.ExpectQuery(pending).WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{"payload"}).AddRow("{"))
+
+	_, err := s.GetPendingEvents(ctx, 10)
+	assert.Error(t, err, "a corrupt payload is an error")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkEventsPublished(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	s := New()
+
+	id1, id2 := uuid.NewString(), uuid.NewString()
+
+	testCases := []struct {
+		desc string
+		ids  []string
+		mock *sqlmock.ExpectedExec
+		err  error
+	}{
+		{
+			desc: "success", ids: []string{id1, id2},
+			mock: mock.ExpectExec("UPDATE Outbox SET published_at=NOW(6) WHERE id IN (?,?)").WithArgs(id1, id2).
+				WillReturnResult(sqlmock.NewResult(0, 2)),
+		},
+		{desc: "no ids"},
+		{
+			desc: "db error", ids: []string{id1}, err: errors.Error("db down"),
+			mock: mock.ExpectExec("UPDATE Outbox SET published_at=NOW(6) WHERE id IN (?)").WithArgs(id1).
+				WillReturnError(errors.Error("db down")),
+		},
+	}
+
+	for i, tc := range testCases {
+		err := s.MarkEventsPublished(ctx, tc.ids)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletePublishedEvents(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	s := New()
+
+	const deleteEvents = "DELETE FROM Outbox WHERE published_at<NOW(6)-INTERVAL ? SECOND ORDER BY published_at LIMIT ?"
+
+	mock.ExpectExec(deleteEvents).WithArgs(int64(604800), 100).WillReturnResult(sqlmock.NewResult(0, 42))
+	mock.ExpectExec(deleteEvents).WithArgs(int64(604800), 100).WillReturnError(errors.Error("db down"))
+
+	n, err := s.DeletePublishedEvents(ctx, 7*24*time.Hour, 100)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 42, n)
+
+	_, err = s.DeletePublishedEvents(ctx, 7*24*time.Hour, 100)
+	assert.Equal(t, errors.Error("db down"), err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEventsAfter(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	s := New()
+
+	const after = "SELECT seq,payload FROM Outbox WHERE seq>? ORDER BY seq LIMIT ?"
+
+	event1, event2 := newEvent(), newEvent()
+	payload1, _ := json.Marshal(event1)
+	payload2, _ := json.Marshal(event2)
+
+	event1.Seq, event2.Seq = 42, 44
+
+	testCases := []struct {
+		desc     string
+		mock     *sqlmock.ExpectedQuery
+		expected []models.Event
+		err      error
+	}{
+		{
+			desc: "in order", expected: []models.Event{event1, event2},
+			mock: mock.ExpectQuery(after).WithArgs(41, 10).WillReturnRows(sqlmock.NewRows([]string{"seq", "payload"}).
+				AddRow(42, payload1).AddRow(44, payload2)),
+		},
+		{
+			desc: "none after", expected: []models.Event{},
+			mock: mock.ExpectQuery(after).WithArgs(41, 10).WillReturnRows(sqlmock.NewRows([]string{"seq", "payload"})),
+		},
+		{
+			desc: "query error", err: errors.Error("db down"),
+			mock: mock.ExpectQuery(after).WithArgs(41, 10).WillReturnError(errors.Error("db down")),
+		},
+	}
+
+	for i, tc := range testCases {
+		res, err := s.GetEventsAfter(ctx, 41, 10)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.expected, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	mock.ExpectQuery(after).WithArgs(41, 10).WillReturnRows(sqlmock.NewRows([]string{"seq", "payload"}).AddRow(42, "{"))
+
+	_, err := s.GetEventsAfter(ctx, 41, 10)
+	assert.Error(t, err, "a corrupt payload is an error")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLastSeq(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	defer db.Close()
+	s := New()
+
+	const last = "SELECT COALESCE(MAX(seq),0) FROM Outbox"
+
+	mock.ExpectQuery(last).WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(42))
+	mock.ExpectQuery(last).WillReturnError(errors.Error("db down"))
+
+	seq, err := s.GetLastSeq(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(42), seq)
+
+	_, err = s.GetLastSeq(ctx)
+	assert.Equal(t, errors.Error("db down"), err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
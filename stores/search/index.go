@@ -0,0 +1,294 @@
+package search
+
+import (
+	"Project/CarDealearship/models"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+)
+
+// field weights, a match on the name counts more than a match on an engine spec
+const (
+	nameWeight   = 3.0
+	brandWeight  = 2.5
+	yearWeight   = 1.5
+	fuelWeight   = 1.5
+	engineWeight = 1.0
+)
+
+// how much a query token matching a term exactly, by prefix or within an edit distance contributes
+const (
+	exactMatch  = 1.0
+	prefixMatch = 0.8
+	oneEdit     = 0.6
+	twoEdits    = 0.4
+)
+
+type index struct {
+	mu       sync.RWMutex
+	postings map[string]map[uuid.UUID]float64
+	docs     map[uuid.UUID]map[string]float64
+}
+
+// nolint:revive // need not be exported
+// New returns an empty in-process inverted index
+func New() *index {
+	return &index{postings: map[string]map[uuid.UUID]float64{}, docs: map[uuid.UUID]map[string]float64{}}
+}
+
+// Index adds the car to the index, replacing any previous version of it
+func (i *index) Index(ctx *gofr.Context, car models.Car) error {
+	terms := map[string]float64{}
+
+	add := func(text string, weight float64) {
+		for _, t := range tokenize(text) {
+			terms[t] += weight
+		}
+	}
+
+	add(car.Name, nameWeight)
+	add(car.Brand, brandWeight)
+	add(car.FuelType, fuelWeight)
+
+	if car.Year != 0 {
+		add(strconv.Itoa(car.Year), yearWeight)
+	}
+
+	e := car.Engine
+	if e.Displacement != 0 {
+		add(strconv.Itoa(e.Displacement)+" "+strconv.Itoa(e.Displacement)+"cc", engineWeight)
+	}
+
+	if e.Cylinders != 0 {
+		add(strconv.Itoa(e.Cylinders)+" cylinders "+strconv.Itoa(e.Cylinders)+"cyl", engineWeight)
+	}
+
+	if e.Range != 0 {
+		add("range "+strconv.Itoa(e.Range)+" "+strconv.Itoa(e.Range)+"km", engineWeight)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(car.ID)
+
+	for t, w := range terms {
+		if i.postings[t] == nil {
+			i.postings[t] = map[uuid.UUID]float64{}
+		}
+
+		i.postings[t][car.ID] = w
+	}
+
+	i.docs[car.ID] = terms
+
+	return nil
+}
+
+// Remove drops the car from the index
+func (i *index) Remove(ctx *gofr.Context, id string) error {
+	docID, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(docID)
+
+	return nil
+}
+
+func (i *index) remove(id uuid.UUID) {
+	for t := range i.docs[id] {
+		delete(i.postings[t], id)
+
+		if len(i.postings[t]) == 0 {
+			delete(i.postings, t)
+		}
+	}
+
+	delete(i.docs, id)
+}
+
+// Search returns up to limit cars matching the query, most relevant first. Every query token is matched
+// against the indexed terms exactly, as a prefix or with a small number of typos, and a car's score is
+// scaled by the share of query tokens it matched so cars matching the whole query rank first.
+func (i *index) Search(ctx *gofr.Context, query string, limit int) ([]models.SearchHit, error) {
+	tokens := tokenize(query)
+	if len(tokens) == 0 {
+		return []models.SearchHit{}, nil
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	scores := map[uuid.UUID]float64{}
+	matched := map[uuid.UUID]int{}
+	n := float64(len(i.docs))
+
+	for _, token := range tokens {
+		best := map[uuid.UUID]float64{}
+
+		for term, postings := range i.postings {
+			factor := match(token, term)
+			if factor == 0 {
+				continue
+			}
+
+			idf := math.Log(1 + n/float64(len(postings)))
+
+			for id, weight := range postings {
+				if s := factor * idf * weight; s > best[id] {
+					best[id] = s
+				}
+			}
+		}
+
+		for id, s := range best {
+			scores[id] += s
+			matched[id]++
+		}
+	}
+
+	hits := make([]models.SearchHit, 0, len(scores))
+	for id, s := range scores {
+		hits = append(hits, models.SearchHit{ID: id, Score: s * float64(matched[id]) / float64(len(tokens))})
+	}
+
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+
+		return hits[a].ID.String() < hits[b].ID.String()
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits, nil
+}
+
+// match returns how well a query token matches an indexed term, 0 when it does not match at all
+func match(token, term string) float64 {
+	switch {
+	case token == term:
+		return exactMatch
+	case len(token) >= 3 && strings.HasPrefix(term, token):
+		return prefixMatch
+	}
+
+	// numbers such as years and engine specs must match exactly
+	if isNumber(token) {
+		return 0
+	}
+
+	max := maxEdits(token)
+	if max == 0 {
+		return 0
+	}
+
+	switch d := distance(token, term, max); {
+	case d > max:
+		return 0
+	case d == 1:
+		return oneEdit
+	case d == 2:
+		return twoEdits
+	}
+
+	return 0
+}
+
+// maxEdits is the number of typos tolerated for a token, short tokens must be spelled correctly
+func maxEdits(token string) int {
+	switch n := len([]rune(token)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	}
+
+	return 0
+}
+
+// distance returns the Levenshtein distance between a and b, or max+1 when it exceeds max
+func distance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > max {
+		return max + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minOf(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			rowMin = minOf(rowMin, cur[j])
+		}
+
+		if rowMin > max {
+			return max + 1
+		}
+
+		prev, cur = cur, prev
+	}
+
+	if prev[len(rb)] > max {
+		return max + 1
+	}
+
+	return prev[len(rb)]
+}
+
+// tokenize lower cases the text and splits it on everything that is not a letter or a digit
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+func minOf(a int, rest ...int) int {
+	for _, b := range rest {
+		if b < a {
+			a = b
+		}
+	}
+
+	return a
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+
+	return a
+}
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"Project/CarDealearship/models"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func ids(hits []models.SearchHit) []uuid.UUID {
+	res := make([]uuid.UUID, len(hits))
+	for i := range hits {
+		res[i] = hits[i].ID
+	}
+
+	return res
+}
+
+// TestSearch tests relevance ranking, typo tolerance and prefix matching of the index
+func TestSearch(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	idx := New()
+
+	// both Teslas score the same for "tesla", ties are ranked by id
+	model3 := models.Car{ID: uuid.MustParse("11111111-1111-4111-8111-111111111111"), Name: "Model 3 Long Range",
+		Year: 2020, Brand: "Tesla", FuelType: "Electric", Engine: models.Engine{Range: 560}}
+	modelS := models.Car{ID: uuid.MustParse("22222222-2222-4222-8222-222222222222"), Name: "Model S",
+		Year: 2018, Brand: "Tesla", FuelType: "Electric", Engine: models.Engine{Range: 600}}
+	cayenne := models.Car{ID: uuid.New(), Name: "Cayenne", Year: 2020, Brand: "Porsche", FuelType: "Petrol",
+		Engine: models.Engine{Displacement: 2900, Cylinders: 6}}
+	x5 := models.Car{ID: uuid.New(), Name: "X5", Year: 2019, Brand: "BMW", FuelType: "Diesel",
+		Engine: models.Engine{Displacement: 3000, Cylinders: 6}}
+
+	for _, c := range []models.Car{model3, modelS, cayenne, x5} {
+		assert.Equal(t, nil, idx.Index(ctx, c))
+	}
+
+	testCases := []struct {
+		desc   string
+		query  string
+		limit  int
+		output []uuid.UUID
+	}{
+		{desc: "best match first", query: "red tesla 2020 long range", output: []uuid.UUID{model3.ID, modelS.ID, cayenne.ID}},
+		{desc: "typo in brand", query: "porshe", output: []uuid.UUID{cayenne.ID}},
+		{desc: "prefix", query: "cay", output: []uuid.UUID{cayenne.ID}},
+		{desc: "engine spec", query: "3000cc", output: []uuid.UUID{x5.ID}},
+		{desc: "numbers are not fuzzy", query: "2021", output: []uuid.UUID{}},
+		{desc: "limit", query: "tesla", limit: 1, output: []uuid.UUID{model3.ID}},
+		{desc: "empty query", query: " ", output: []uuid.UUID{}},
+		{desc: "short tokens need exact match", query: "x6", output: []uuid.UUID{}},
+	}
+
+	for i, tc := range testCases {
+		hits, err := idx.Search(ctx, tc.query, tc.limit)
+
+		assert.Equal(t, nil, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.output, ids(hits), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestIndexUpdateAndRemove tests that re-indexing replaces a car and removing drops it
+func TestIndexUpdateAndRemove(t *testing.T) {
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	idx := New()
+
+	car := models.Car{ID: uuid.New(), Name: "Roma", Year: 2021, Brand: "Ferrari", FuelType: "Petrol"}
+
+	assert.Equal(t, nil, idx.Index(ctx, car))
+
+	car.Name = "Portofino"
+	assert.Equal(t, nil, idx.Index(ctx, car))
+
+	hits, _ := idx.Search(ctx, "roma", 0)
+	assert.Equal(t, []uuid.UUID{}, ids(hits))
+
+	hits, _ = idx.Search(ctx, "portofino", 0)
+	assert.Equal(t, []uuid.UUID{car.ID}, ids(hits))
+
+	assert.Equal(t, nil, idx.Remove(ctx, car.ID.String()))
+	assert.Equal(t, nil, idx.Remove(ctx, "not-a-uuid"))
+
+	hits, _ = idx.Search(ctx, "ferrari", 0)
+	assert.Equal(t, []uuid.UUID{}, ids(hits))
+	assert.Equal(t, 0, len(idx.postings))
+}
+
+// TestDistance tests the bounded Levenshtein distance
+func TestDistance(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		max  int
+		out  int
+	}{
+		{"tesla", "tesla", 1, 0},
+		{"tesle", "tesla", 1, 1},
+		{"mercedez", "mercedes", 2, 1},
+		{"ferari", "ferrari", 1, 1},
+		{"bmw", "audi", 1, 2},
+		{"electirc", "electric", 2, 2},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.out, distance(tc.a, tc.b, tc.max), "TEST[%d] %s/%s", i, tc.a, tc.b)
+	}
+}
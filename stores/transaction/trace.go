@@ -0,0 +1,146 @@
+package transaction
+
+import (
+	"Project/CarDealearship/tracing"
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traced runs the statements on a connection, each in a span recording its sanitized text and whether it failed
+type traced struct {
+	exec conn
+}
+
+// ExecContext runs the statement, the span records the rows it affected
+func (t traced) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startStatement(ctx, query)
+	defer span.End()
+
+	res, err := t.exec.ExecContext(ctx, query, args...)
+	if err != nil {
+		tracing.Fail(span, err)
+		return res, err
+	}
+
+	if n, err := res.RowsAffected(); err == nil {
+		span.SetAttributes(tracing.RowsAffectedKey.Int64(n))
+	}
+
+	return res, nil
+}
+
+// QueryContext runs the query, the span ends once its rows are read or closed and records how many were read
+func (t traced) QueryContext(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	ctx, span := startStatement(ctx, query)
+
+	rows, err := t.exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		tracing.Fail(span, err)
+		span.End()
+
+		return nil, err
+	}
+
+	return &Rows{Rows: rows, span: span}, nil
+}
+
+// QueryRowContext runs the query of a single row, the span ends once the row is scanned or its error is read
+func (t traced) QueryRowContext(ctx context.Context, query string, args ...interface{}) *Row {
+	ctx, span := startStatement(ctx, query)
+
+	return &Row{Row: t.exec.QueryRowContext(ctx, query, args...), span: span}
+}
+
+func startStatement(ctx context.Context, query string) (context.Context, trace.Span) {
+	op := tracing.Operation(query)
+
+	return tracing.Tracer().Start(ctx, "SQL "+op, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		tracing.SystemKey.String("mysql"), tracing.OperationKey.String(op),
+		tracing.StatementKey.String(tracing.Sanitize(query))))
+}
+
+// Rows are the rows of a query, the span of the query ends along with them
+type Rows struct {
+	*sql.Rows
+	span  trace.Span
+	n     int
+	ended bool
+}
+
+// Next prepares the next row, the rows end when there is none left
+func (r *Rows) Next() bool {
+	if r.Rows.Next() {
+		r.n++
+		return true
+	}
+
+	r.end()
+
+	return false
+}
+
+// Close closes the rows, ending the span of the query when they were not read to the end
+func (r *Rows) Close() error {
+	err := r.Rows.Close()
+	r.end()
+
+	return err
+}
+
+func (r *Rows) end() {
+	if r.ended {
+		return
+	}
+
+	r.ended = true
+
+	tracing.Fail(r.span, r.Rows.Err())
+	r.span.SetAttributes(tracing.RowsKey.Int(r.n))
+	r.span.End()
+}
+
+// Row is the row of a query, the span of the query ends once it is scanned or its error is read
+type Row struct {
+	*sql.Row
+	span  trace.Span
+	ended bool
+}
+
+// Scan copies the columns of the row into dest, a missing row is recorded as no rows rather than as a failure
+func (r *Row) Scan(dest ...interface{}) error {
+	err := r.Row.Scan(dest...)
+
+	switch err {
+	case nil:
+		r.end(nil, tracing.RowsKey.Int(1))
+	case sql.ErrNoRows:
+		r.end(nil, tracing.RowsKey.Int(0))
+	default:
+		r.end(err)
+	}
+
+	return err
+}
+
+// Err returns the error of the query, ending its span when the row is not scanned
+func (r *Row) Err() error {
+	err := r.Row.Err()
+	r.end(err)
+
+	return err
+}
+
+func (r *Row) end(err error, rows ...attribute.KeyValue) {
+	if r.ended {
+		return
+	}
+
+	r.ended = true
+
+	r.span.SetAttributes(rows...)
+	tracing.Fail(r.span, err)
+	r.span.End()
+}
@@ -0,0 +1,164 @@
+package transaction
+
+import (
+	"database/sql"
+	"testing"
+
+	"Project/CarDealearship/tracing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newExporter() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	return exporter
+}
+
+// TestTraced tests that every statement run through DB gets a span, a child of the span of the caller, which
+// records the rows the statement affected or read
+func TestTraced(t *testing.T) {
+	exporter := newExporter()
+	ctx, mock := newContext(t)
+
+	update := "UPDATE Car SET name=? WHERE brand='BMW'"
+	query := "SELECT id FROM Car WHERE id IN (?,?)"
+	row := "SELECT name FROM Car WHERE id=?"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(update).WithArgs("X5").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(query).WithArgs("1", "2").WillReturnError(errors.Error("db down"))
+	mock.ExpectQuery(query).WithArgs("1", "2").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1").AddRow("2"))
+	mock.ExpectQuery(query).WithArgs("1", "2").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1").AddRow("2"))
+	mock.ExpectQuery(row).WithArgs("1").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("X5"))
+	mock.ExpectQuery(row).WithArgs("3").WillReturnRows(sqlmock.NewRows([]string{"name"}))
+	mock.ExpectCommit()
+
+	ctx, span := tracing.Start(ctx, "store.car.UpdateCar")
+	read := 0
+
+	err := New().WithTransaction(ctx, func(ctx *gofr.Context) error {
+		if _, err := DB(ctx).ExecContext(ctx, update, "X5"); err != nil {
+			return err
+		}
+
+		if _, err := DB(ctx).QueryContext(ctx, query, "1", "2"); err == nil {
+			return errors.Error("the query did not fail")
+		}
+
+		// rows read to the end
+		rows, err := DB(ctx).QueryContext(ctx, query, "1", "2")
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			read++
+		}
+
+		// rows closed after the first one
+		rows, err = DB(ctx).QueryContext(ctx, query, "1", "2")
+		if err != nil {
+			return err
+		}
+
+		rows.Next()
+		_ = rows.Close()
+
+		var name string
+
+		if err := DB(ctx).QueryRowContext(ctx, row, "1").Scan(&name); err != nil {
+			return err
+		}
+
+		if err := DB(ctx).QueryRowContext(ctx, row, "3").Scan(&name); err != sql.ErrNoRows {
+			return errors.Error("the row was found")
+		}
+
+		return nil
+	})
+
+	span.End(err)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, mock.ExpectationsWereMet())
+	assert.Equal(t, 2, read)
+
+	spans := exporter.GetSpans()
+	if !assert.Len(t, spans, 7) {
+		return
+	}
+
+	parent := spans[6].SpanContext
+	selectID := []attribute.KeyValue{tracing.SystemKey.String("mysql"), tracing.OperationKey.String("SELECT"),
+		tracing.StatementKey.String("SELECT id FROM Car WHERE id IN (?)")}
+	selectName := []attribute.KeyValue{tracing.SystemKey.String("mysql"), tracing.OperationKey.String("SELECT"),
+		tracing.StatementKey.String(row)}
+
+	tests := []struct {
+		desc   string
+		name   string
+		attrs  []attribute.KeyValue
+		status codes.Code
+	}{
+		{"exec", "SQL UPDATE", []attribute.KeyValue{tracing.SystemKey.String("mysql"),
+			tracing.OperationKey.String("UPDATE"), tracing.StatementKey.String("UPDATE Car SET name=? WHERE brand=?"),
+			tracing.RowsAffectedKey.Int64(2)}, codes.Unset},
+		{"failed query", "SQL SELECT", selectID, codes.Error},
+		{"rows read", "SQL SELECT", append(selectID[:3:3], tracing.RowsKey.Int(2)), codes.Unset},
+		{"rows closed", "SQL SELECT", append(selectID[:3:3], tracing.RowsKey.Int(1)), codes.Unset},
+		{"row", "SQL SELECT", append(selectName[:3:3], tracing.RowsKey.Int(1)), codes.Unset},
+		{"no row", "SQL SELECT", append(selectName[:3:3], tracing.RowsKey.Int(0)), codes.Unset},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.name, spans[i].Name, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, trace.SpanKindClient, spans[i].SpanKind, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.attrs, spans[i].Attributes, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.status, spans[i].Status.Code, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, parent.SpanID(), spans[i].Parent.SpanID(), "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, parent.TraceID(), spans[i].SpanContext.TraceID(), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+// TestTracedRowErr tests that the span of a single row query ends when its error is read rather than the row
+// scanned, and only once when both are done
+func TestTracedRowErr(t *testing.T) {
+	exporter := newExporter()
+	ctx, mock := newContext(t)
+
+	row := "SELECT name FROM Car WHERE id=?"
+
+	mock.ExpectQuery(row).WithArgs("1").WillReturnError(errors.Error("db down"))
+	mock.ExpectQuery(row).WithArgs("2").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("X5"))
+
+	failed := DB(ctx).QueryRowContext(ctx, row, "1")
+	assert.Equal(t, errors.Error("db down"), failed.Err())
+
+	found := DB(ctx).QueryRowContext(ctx, row, "2")
+	assert.Equal(t, nil, found.Err())
+
+	var name string
+
+	assert.Equal(t, nil, found.Scan(&name))
+	assert.Equal(t, nil, mock.ExpectationsWereMet())
+
+	spans := exporter.GetSpans()
+	if !assert.Len(t, spans, 2) {
+		return
+	}
+
+	assert.Equal(t, codes.Error, spans[0].Status.Code, "the failed query")
+	assert.Equal(t, codes.Unset, spans[1].Status.Code, "the row read")
+	assert.NotContains(t, spans[1].Attributes, tracing.RowsKey.Int(1), "the span ended before the scan")
+}
@@ -0,0 +1,118 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+type txKey struct{}
+
+// state is what the context of a transaction holds
+type state struct {
+	tx          *sql.Tx
+	afterCommit []func()
+}
+
+// Executor runs queries either on the connection pool or inside a transaction
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *Row
+}
+
+// conn is the connection pool or a transaction, which the executor runs the queries on
+type conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// DB returns the transaction started by WithTransaction for this context, or the connection pool when
+// the context is not inside a transaction. Stores use it so their queries join the caller's transaction, every
+// statement is traced.
+func DB(ctx *gofr.Context) Executor {
+	if st := current(ctx); st != nil {
+		return traced{exec: st.tx}
+	}
+
+	return traced{exec: ctx.DB()}
+}
+
+// InTransaction reports whether ctx is inside a transaction started by WithTransaction
+func InTransaction(ctx *gofr.Context) bool {
+	return current(ctx) != nil
+}
+
+// AfterCommit runs fn once the transaction of ctx has committed, right away when ctx is not inside one. fn is not
+// run when the transaction is rolled back.
+func AfterCommit(ctx *gofr.Context, fn func()) {
+	st := current(ctx)
+	if st == nil {
+		fn()
+		return
+	}
+
+	st.afterCommit = append(st.afterCommit, fn)
+}
+
+func current(ctx *gofr.Context) *state {
+	if ctx.Context == nil {
+		return nil
+	}
+
+	st, _ := ctx.Value(txKey{}).(*state)
+
+	return st
+}
+
+type transaction struct{}
+
+// nolint:revive // need not be exported
+// New factory function
+func New() transaction {
+	return transaction{}
+}
+
+// WithTransaction runs fn inside a database transaction, committing when fn succeeds and rolling back
+// when it fails or panics. fn is given a copy of ctx carrying the transaction, ctx is left as it is since the
+// database driver may still be watching it. Calls nested inside fn join the outer transaction.
+func (t transaction) WithTransaction(ctx *gofr.Context, fn func(ctx *gofr.Context) error) (err error) {
+	if InTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	tx, err := ctx.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	st := &state{tx: tx}
+	c := *ctx
+	c.Context = context.WithValue(ctx.Context, txKey{}, st)
+
+	committed := false
+
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = fn(&c); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	committed = true
+
+	for _, f := range st.afterCommit {
+		f()
+	}
+
+	return nil
+}
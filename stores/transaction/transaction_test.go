@@ -0,0 +1,133 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/datastore"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContext(t *testing.T) (*gofr.Context, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	return ctx, mock
+}
+
+// TestWithTransactionCommit tests that queries run through DB join the transaction which is committed
+func TestWithTransactionCommit(t *testing.T) {
+	ctx, mock := newContext(t)
+	parent := ctx.Context
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO Engine (id) VALUES(?)").WithArgs("1").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO Car (id) VALUES(?)").WithArgs("1").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	outer := ctx
+
+	err := New().WithTransaction(ctx, func(ctx *gofr.Context) error {
+		assert.Equal(t, false, InTransaction(outer), "the context of the caller is left as it is")
+
+		if _, err := DB(ctx).ExecContext(ctx, "INSERT INTO Engine (id) VALUES(?)", "1"); err != nil {
+			return err
+		}
+
+		// nested calls join the outer transaction
+		return New().WithTransaction(ctx, func(ctx *gofr.Context) error {
+			_, err := DB(ctx).ExecContext(ctx, "INSERT INTO Car (id) VALUES(?)", "1")
+			return err
+		})
+	})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, mock.ExpectationsWereMet())
+	assert.Equal(t, parent, ctx.Context)
+}
+
+// TestWithTransactionRollback tests that a failing function rolls the transaction back
+func TestWithTransactionRollback(t *testing.T) {
+	ctx, mock := newContext(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO Car (id) VALUES(?)").WithArgs("1").WillReturnError(errors.Error("duplicate"))
+	mock.ExpectRollback()
+
+	err := New().WithTransaction(ctx, func(ctx *gofr.Context) error {
+		_, err := DB(ctx).ExecContext(ctx, "INSERT INTO Car (id) VALUES(?)", "1")
+		return err
+	})
+
+	assert.Equal(t, errors.Error("duplicate"), err)
+	assert.Equal(t, nil, mock.ExpectationsWereMet())
+}
+
+// TestWithTransactionBeginError tests that fn is not run when the transaction cannot be started
+func TestWithTransactionBeginError(t *testing.T) {
+	ctx, mock := newContext(t)
+
+	mock.ExpectBegin().WillReturnError(errors.Error("connection refused"))
+
+	called := false
+	err := New().WithTransaction(ctx, func(ctx *gofr.Context) error {
+		called = true
+		return nil
+	})
+
+	assert.Equal(t, errors.Error("connection refused"), err)
+	assert.Equal(t, false, called)
+}
+
+// TestAfterCommit tests that the functions registered in a transaction run once it commits and only then
+func TestAfterCommit(t *testing.T) {
+	ctx, mock := newContext(t)
+
+	var calls []string
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err := New().WithTransaction(ctx, func(ctx *gofr.Context) error {
+		assert.Equal(t, true, InTransaction(ctx))
+
+		AfterCommit(ctx, func() { calls = append(calls, "first") })
+
+		return New().WithTransaction(ctx, func(ctx *gofr.Context) error {
+			AfterCommit(ctx, func() { calls = append(calls, "nested") })
+			assert.Equal(t, []string(nil), calls, "nothing runs before the commit")
+
+			return nil
+		})
+	})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"first", "nested"}, calls)
+	assert.Equal(t, false, InTransaction(ctx))
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	calls = nil
+	err = New().WithTransaction(ctx, func(ctx *gofr.Context) error {
+		AfterCommit(ctx, func() { calls = append(calls, "rolled back") })
+		return errors.Error("duplicate")
+	})
+
+	assert.Equal(t, errors.Error("duplicate"), err)
+	assert.Equal(t, []string(nil), calls, "nothing runs after a rollback")
+
+	AfterCommit(ctx, func() { calls = append(calls, "no transaction") })
+	assert.Equal(t, []string{"no transaction"}, calls, "outside a transaction functions run right away")
+	assert.Equal(t, nil, mock.ExpectationsWereMet())
+}
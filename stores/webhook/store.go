@@ -0,0 +1,202 @@
+package webhook
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores/transaction"
+	"database/sql"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+)
+
+const (
+	webhookColumns  = "id,url,events,active,secret,created_at"
+	deliveryColumns = "id,webhook_id,event_id,event_type,payload,status,attempts,response_code,last_error," +
+		"next_attempt_at,created_at,delivered_at"
+)
+
+type store struct{}
+
+// nolint:revive // need not be exported
+// New factory function
+func New() store {
+	return store{}
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhook(row scanner) (models.Webhook, error) {
+	var (
+		w      models.Webhook
+		events string
+	)
+
+	if err := row.Scan(&w.ID, &w.URL, &events, &w.Active, &w.Secret, &w.CreatedAt); err != nil {
+		return models.Webhook{}, err
+	}
+
+	w.Events = strings.FieldsFunc(events, func(r rune) bool { return r == ',' })
+
+	return w, nil
+}
+
+func scanDelivery(row scanner) (models.WebhookDelivery, error) {
+	var (
+		d                 models.WebhookDelivery
+		payload           []byte
+		next, deliveredAt sql.NullTime
+	)
+
+	err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.ResponseCode, &d.LastError, &next, &d.CreatedAt, &deliveredAt)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	d.Payload = payload
+
+	if next.Valid {
+		d.NextAttemptAt = &next.Time
+	}
+
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+
+	return d, nil
+}
+
+// GetWebhooks is the datastore layer function to get every webhook, oldest first
+func (s store) GetWebhooks(ctx *gofr.Context) ([]models.Webhook, error) {
+	rows, err := transaction.DB(ctx).QueryContext(ctx, "SELECT "+webhookColumns+" FROM Webhook ORDER BY created_at;")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	webhooks := make([]models.Webhook, 0)
+
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, errors.Error("Scan Error")
+		}
+
+		webhooks = append(webhooks, w)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// GetWebhookByID is the datastore layer function to get a webhook by its id
+func (s store) GetWebhookByID(ctx *gofr.Context, id string) (models.Webhook, error) {
+	return scanWebhook(transaction.DB(ctx).QueryRowContext(ctx, "SELECT "+webhookColumns+" FROM Webhook WHERE id=?;",
+		id))
+}
+
+// CreateWebhook is the datastore layer function to create a webhook
+func (s store) CreateWebhook(ctx *gofr.Context, w *models.Webhook) (models.Webhook, error) {
+	_, err := transaction.DB(ctx).ExecContext(ctx, "INSERT INTO Webhook ("+webhookColumns+") VALUES(?,?,?,?,?,?)",
+		w.ID.String(), w.URL, strings.Join(w.Events, ","), w.Active, w.Secret, w.CreatedAt)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	return *w, nil
+}
+
+// UpdateWebhook is the datastore layer function to change the url, the events and the state of a webhook
+func (s store) UpdateWebhook(ctx *gofr.Context, id string, w *models.Webhook) (models.Webhook, error) {
+	_, err := transaction.DB(ctx).ExecContext(ctx, "UPDATE Webhook SET url=?,events=?,active=? WHERE id=?",
+		w.URL, strings.Join(w.Events, ","), w.Active, id)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	return *w, nil
+}
+
+// DeleteWebhook is the datastore layer function to delete a webhook, its deliveries are deleted along with it
+func (s store) DeleteWebhook(ctx *gofr.Context, id string) error {
+	_, err := transaction.DB(ctx).ExecContext(ctx, "DELETE FROM Webhook WHERE id=?", id)
+	return err
+}
+
+// CreateDelivery is the datastore layer function to record a delivery. An event already recorded for the
+// webhook is skipped, so that an event published twice is only delivered once.
+func (s store) CreateDelivery(ctx *gofr.Context, d *models.WebhookDelivery) error {
+	_, err := transaction.DB(ctx).ExecContext(ctx, "INSERT IGNORE INTO WebhookDelivery ("+deliveryColumns+") "+
+		"VALUES(?,?,?,?,?,?,?,?,?,?,?,?)", d.ID.String(), d.WebhookID.String(), d.EventID.String(), d.EventType,
+		[]byte(d.Payload), d.Status, d.Attempts, d.ResponseCode, d.LastError, d.NextAttemptAt, d.CreatedAt,
+		d.DeliveredAt)
+
+	return err
+}
+
+// GetDueDeliveries is the datastore layer function to get at most limit pending deliveries whose next attempt
+// is due, most overdue first. Inside a transaction the rows stay locked until it ends and are skipped by other
+// dispatchers.
+func (s store) GetDueDeliveries(ctx *gofr.Context, limit int) ([]models.WebhookDelivery, error) {
+	return s.getDeliveries(ctx, "SELECT "+deliveryColumns+" FROM WebhookDelivery WHERE status=? AND "+
+		"next_attempt_at<=NOW(6) ORDER BY next_attempt_at LIMIT ? FOR UPDATE SKIP LOCKED", models.DeliveryPending,
+		limit)
+}
+
+// GetDeliveries is the datastore layer function to get the latest limit deliveries of a webhook, newest first,
+// only the ones with the given status unless it is empty
+func (s store) GetDeliveries(ctx *gofr.Context, webhookID, status string, limit int) ([]models.WebhookDelivery,
+	error) {
+	if status == "" {
+		return s.getDeliveries(ctx, "SELECT "+deliveryColumns+" FROM WebhookDelivery WHERE webhook_id=? "+
+			"ORDER BY created_at DESC LIMIT ?", webhookID, limit)
+	}
+
+	return s.getDeliveries(ctx, "SELECT "+deliveryColumns+" FROM WebhookDelivery WHERE webhook_id=? AND status=? "+
+		"ORDER BY created_at DESC LIMIT ?", webhookID, status, limit)
+}
+
+func (s store) getDeliveries(ctx *gofr.Context, query string, args ...interface{}) ([]models.WebhookDelivery,
+	error) {
+	rows, err := transaction.DB(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, errors.Error("Scan Error")
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// GetDeliveryByID is the datastore layer function to get a delivery by its id
+func (s store) GetDeliveryByID(ctx *gofr.Context, id string) (models.WebhookDelivery, error) {
+	return scanDelivery(transaction.DB(ctx).QueryRowContext(ctx, "SELECT "+deliveryColumns+
+		" FROM WebhookDelivery WHERE id=?;", id))
+}
+
+// UpdateDelivery is the datastore layer function to record the outcome of an attempt of a delivery
+func (s store) UpdateDelivery(ctx *gofr.Context, d *models.WebhookDelivery) error {
+	_, err := transaction.DB(ctx).ExecContext(ctx, "UPDATE WebhookDelivery SET status=?,attempts=?,response_code=?,"+
+		"last_error=?,next_attempt_at=?,delivered_at=? WHERE id=?", d.Status, d.Attempts, d.ResponseCode,
+		d.LastError, d.NextAttemptAt, d.DeliveredAt, d.ID.String())
+
+	return err
+}
@@ -0,0 +1,253 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"Project/CarDealearship/models"
+
+	"developer.zopsmart.com/go/gofr/pkg/datastore"
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	webhookCols  = []string{"id", "url", "events", "active", "secret", "created_at"}
+	deliveryCols = []string{"id", "webhook_id", "event_id", "event_type", "payload", "status", "attempts",
+		"response_code", "last_error", "next_attempt_at", "created_at", "delivered_at"}
+	created = time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+)
+
+func newContext(t *testing.T) (*gofr.Context, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	t.Cleanup(func() { db.Close() })
+
+	ctx := gofr.NewContext(nil, nil, &gofr.Gofr{DataStore: datastore.DataStore{ORM: db}})
+	ctx.Context = context.TODO()
+
+	return ctx, mock
+}
+
+func TestGetWebhooks(t *testing.T) {
+	ctx, mock := newContext(t)
+	s := New()
+
+	id1, id2 := uuid.New(), uuid.New()
+	query := "SELECT id,url,events,active,secret,created_at FROM Webhook ORDER BY created_at;"
+
+	testCases := []struct {
+		desc     string
+		mock     *sqlmock.ExpectedQuery
+		expected []models.Webhook
+		err      error
+	}{
+		{
+			desc: "success",
+			mock: mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(webhookCols).
+				AddRow(id1.String(), "https://crm.example.com/hooks", "CarCreated,CarDeleted", true, "s1", created).
+				AddRow(id2.String(), "https://web.example.com/hooks", "", false, "s2", created)),
+			expected: []models.Webhook{
+				{ID: id1, URL: "https://crm.example.com/hooks", Events: []string{"CarCreated", "CarDeleted"},
+					Active: true, Secret: "s1", CreatedAt: created},
+				{ID: id2, URL: "https://web.example.com/hooks", Events: []string{}, Secret: "s2", CreatedAt: created},
+			},
+		},
+		{
+			desc: "scan error", err: errors.Error("Scan Error"),
+			mock: mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id1.String())),
+		},
+		{
+			desc: "query error", err: errors.Error("db down"),
+			mock: mock.ExpectQuery(query).WillReturnError(errors.Error("db down")),
+		},
+	}
+
+	for i, tc := range testCases {
+		res, err := s.GetWebhooks(ctx)
+
+		assert.Equal(t, tc.err, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.expected, res, "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetWebhookByID(t *testing.T) {
+	ctx, mock := newContext(t)
+	s := New()
+
+	id := uuid.New()
+	query := "SELECT id,url,events,active,secret,created_at FROM Webhook WHERE id=?;"
+
+	mock.ExpectQuery(query).WithArgs(id.String()).WillReturnRows(sqlmock.NewRows(webhookCols).
+		AddRow(id.String(), "https://crm.example.com/hooks", "CarUpdated", true, "s1", created))
+	mock.ExpectQuery(query).WithArgs("missing").WillReturnError(errors.Error("sql: no rows in result set"))
+
+	res, err := s.GetWebhookByID(ctx, id.String())
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, models.Webhook{ID: id, URL: "https://crm.example.com/hooks", Events: []string{"CarUpdated"},
+		Active: true, Secret: "s1", CreatedAt: created}, res)
+
+	res, err = s.GetWebhookByID(ctx, "missing")
+
+	assert.Equal(t, errors.Error("sql: no rows in result set"), err)
+	assert.Equal(t, models.Webhook{}, res)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWriteWebhook(t *testing.T) {
+	ctx, mock := newContext(t)
+	s := New()
+
+	w := models.Webhook{ID: uuid.New(), URL: "https://crm.example.com/hooks",
+		Events: []string{"CarCreated", "CarUpdated"}, Active: true, Secret: "s1", CreatedAt: created}
+	insert := "INSERT INTO Webhook (id,url,events,active,secret,created_at) VALUES(?,?,?,?,?,?)"
+	update := "UPDATE Webhook SET url=?,events=?,active=? WHERE id=?"
+
+	mock.ExpectExec(insert).WithArgs(w.ID.String(), w.URL, "CarCreated,CarUpdated", true, "s1", created).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insert).WithArgs(w.ID.String(), w.URL, "CarCreated,CarUpdated", true, "s1", created).
+		WillReturnError(errors.Error("duplicate"))
+	mock.ExpectExec(update).WithArgs(w.URL, "CarCreated,CarUpdated", true, w.ID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(update).WithArgs(w.URL, "CarCreated,CarUpdated", true, w.ID.String()).
+		WillReturnError(errors.Error("db down"))
+	mock.ExpectExec("DELETE FROM Webhook WHERE id=?").WithArgs(w.ID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := s.CreateWebhook(ctx, &w)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, w, res)
+
+	res, err = s.CreateWebhook(ctx, &w)
+	assert.Equal(t, errors.Error("duplicate"), err)
+	assert.Equal(t, models.Webhook{}, res)
+
+	res, err = s.UpdateWebhook(ctx, w.ID.String(), &w)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, w, res)
+
+	res, err = s.UpdateWebhook(ctx, w.ID.String(), &w)
+	assert.Equal(t, errors.Error("db down"), err)
+	assert.Equal(t, models.Webhook{}, res)
+
+	assert.Equal(t, nil, s.DeleteWebhook(ctx, w.ID.String()))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateDelivery(t *testing.T) {
+	ctx, mock := newContext(t)
+	s := New()
+
+	d := models.WebhookDelivery{ID: uuid.New(), WebhookID: uuid.New(), EventID: uuid.New(), EventType: "CarCreated",
+		Payload: json.RawMessage(`{"Type":"CarCreated"}`), Status: models.DeliveryPending, NextAttemptAt: &created,
+		CreatedAt: created}
+
+	mock.ExpectExec("INSERT IGNORE INTO WebhookDelivery (id,webhook_id,event_id,event_type,payload,status,attempts,"+
+		"response_code,last_error,next_attempt_at,created_at,delivered_at) VALUES(?,?,?,?,?,?,?,?,?,?,?,?)").
+		WithArgs(d.ID.String(), d.WebhookID.String(), d.EventID.String(), "CarCreated", []byte(d.Payload),
+			models.DeliveryPending, 0, 0, "", created, created, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.Equal(t, nil, s.CreateDelivery(ctx, &d))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeliveries(t *testing.T) {
+	ctx, mock := newContext(t)
+	s := New()
+
+	webhookID := uuid.New()
+	d1 := models.WebhookDelivery{ID: uuid.New(), WebhookID: webhookID, EventID: uuid.New(), EventType: "CarCreated",
+		Payload: json.RawMessage(`{}`), Status: models.DeliveryPending, Attempts: 2, ResponseCode: 503,
+		LastError: "503 Service Unavailable", NextAttemptAt: &created, CreatedAt: created}
+	d2 := models.WebhookDelivery{ID: uuid.New(), WebhookID: webhookID, EventID: uuid.New(), EventType: "CarSold",
+		Payload: json.RawMessage(`{}`), Status: models.DeliveryDelivered, Attempts: 1, ResponseCode: 200,
+		CreatedAt: created, DeliveredAt: &created}
+
+	rows := func(deliveries ...models.WebhookDelivery) *sqlmock.Rows {
+		r := sqlmock.NewRows(deliveryCols)
+
+		for _, d := range deliveries {
+			var next, delivered interface{}
+			if d.NextAttemptAt != nil {
+				next = *d.NextAttemptAt
+			}
+
+			if d.DeliveredAt != nil {
+				delivered = *d.DeliveredAt
+			}
+
+			r.AddRow(d.ID.String(), d.WebhookID.String(), d.EventID.String(), d.EventType, []byte(d.Payload),
+				d.Status, d.Attempts, d.ResponseCode, d.LastError, next, d.CreatedAt, delivered)
+		}
+
+		return r
+	}
+
+	columns := "id,webhook_id,event_id,event_type,payload,status,attempts,response_code,last_error,next_attempt_at," +
+		"created_at,delivered_at"
+
+	mock.ExpectQuery("SELECT "+columns+" FROM WebhookDelivery WHERE webhook_id=? ORDER BY created_at DESC LIMIT ?").
+		WithArgs(webhookID.String(), 50).WillReturnRows(rows(d1, d2))
+	mock.ExpectQuery("SELECT "+columns+" FROM WebhookDelivery WHERE webhook_id=? AND status=? "+
+		"ORDER BY created_at DESC LIMIT ?").WithArgs(webhookID.String(), models.DeliveryDead, 50).
+		WillReturnRows(rows())
+	mock.ExpectQuery("SELECT "+columns+" FROM WebhookDelivery WHERE status=? AND next_attempt_at<=NOW(6) "+
+		"ORDER BY next_attempt_at LIMIT ? FOR UPDATE SKIP LOCKED").WithArgs(models.DeliveryPending, 10).
+		WillReturnRows(rows(d1))
+	mock.ExpectQuery("SELECT " + columns + " FROM WebhookDelivery WHERE id=?;").WithArgs(d2.ID.String()).
+		WillReturnRows(rows(d2))
+	mock.ExpectQuery("SELECT "+columns+" FROM WebhookDelivery WHERE status=? AND next_attempt_at<=NOW(6) "+
+		"ORDER BY next_attempt_at LIMIT ? FOR UPDATE SKIP LOCKED").WithArgs(models.DeliveryPending, 10).
+		WillReturnError(errors.Error("db down"))
+
+	res, err := s.GetDeliveries(ctx, webhookID.String(), "", 50)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.WebhookDelivery{d1, d2}, res)
+
+	res, err = s.GetDeliveries(ctx, webhookID.String(), models.DeliveryDead, 50)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.WebhookDelivery{}, res)
+
+	res, err = s.GetDueDeliveries(ctx, 10)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []models.WebhookDelivery{d1}, res)
+
+	d, err := s.GetDeliveryByID(ctx, d2.ID.String())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, d2, d)
+
+	res, err = s.GetDueDeliveries(ctx, 10)
+	assert.Equal(t, errors.Error("db down"), err)
+	assert.Equal(t, []models.WebhookDelivery(nil), res)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDelivery(t *testing.T) {
+	ctx, mock := newContext(t)
+	s := New()
+
+	d := models.WebhookDelivery{ID: uuid.New(), Status: models.DeliveryDelivered, Attempts: 3, ResponseCode: 204,
+		DeliveredAt: &created}
+	update := "UPDATE WebhookDelivery SET status=?,attempts=?,response_code=?,last_error=?,next_attempt_at=?," +
+		"delivered_at=? WHERE id=?"
+
+	mock.ExpectExec(update).WithArgs(models.DeliveryDelivered, 3, 204, "", nil, created, d.ID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(update).WithArgs(models.DeliveryDelivered, 3, 204, "", nil, created, d.ID.String()).
+		WillReturnError(errors.Error("db down"))
+
+	assert.Equal(t, nil, s.UpdateDelivery(ctx, &d))
+	assert.Equal(t, errors.Error("db down"), s.UpdateDelivery(ctx, &d))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
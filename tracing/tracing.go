@@ -0,0 +1,101 @@
+// Package tracing adds the spans of the services and the stores to the trace gofr starts for every request. Spans
+// are created with the global tracer provider, which gofr sets up with the configured exporter.
+package tracing
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attributes of the spans of the stores and of their SQL statements
+const (
+	RowsKey         = attribute.Key("db.rows")
+	RowsAffectedKey = attribute.Key("db.rows_affected")
+	StatementKey    = attribute.Key("db.statement")
+	OperationKey    = attribute.Key("db.operation")
+	SystemKey       = attribute.Key("db.system")
+)
+
+// instrumentation is the name of the tracer of the application
+const instrumentation = "Project/CarDealearship"
+
+// Span is a span started for a call on a gofr context
+type Span struct {
+	span trace.Span
+}
+
+// Tracer returns the tracer of the application
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// Start starts a span named name, a child of the span ctx carries. It returns a copy of ctx carrying the span, the
+// calls made with the copy are part of the span. ctx is left as it is, since it may be shared by calls running
+// concurrently, like the resolvers of a GraphQL request.
+func Start(ctx *gofr.Context, name string, attrs ...attribute.KeyValue) (*gofr.Context, *Span) {
+	parent := ctx.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	c := *ctx
+	s := &Span{}
+	c.Context, s.span = Tracer().Start(parent, name, trace.WithAttributes(attrs...))
+
+	return &c, s
+}
+
+// SetRows records the number of rows or entities the call returned
+func (s *Span) SetRows(n int) {
+	s.span.SetAttributes(RowsKey.Int(n))
+}
+
+// End ends the span, recording err when the call failed
+func (s *Span) End(err error) {
+	Fail(s.span, err)
+	s.span.End()
+}
+
+// Fail records err on span, a nil err is not recorded
+func Fail(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+var (
+	stringLiteral = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'`)
+	numberLiteral = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	inList        = regexp.MustCompile(`(?i)\bIN\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	space         = regexp.MustCompile(`\s+`)
+)
+
+// Sanitize returns the text of an SQL statement that is safe to record: its literals are replaced by ? and the
+// lists of placeholders of IN conditions are collapsed, so that statements differing in their values read alike
+func Sanitize(query string) string {
+	query = stringLiteral.ReplaceAllString(query, "?")
+	query = numberLiteral.ReplaceAllString(query, "?")
+	query = inList.ReplaceAllString(query, "IN (?)")
+
+	return strings.TrimSpace(space.ReplaceAllString(query, " "))
+}
+
+// Operation returns the first keyword of an SQL statement, like SELECT
+func Operation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.ToUpper(fields[0])
+}
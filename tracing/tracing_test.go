@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newExporter() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	return exporter
+}
+
+func TestStart(t *testing.T) {
+	exporter := newExporter()
+
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = context.TODO()
+	parent := ctx.Context
+
+	outerCtx, outer := Start(ctx, "outer", attribute.String("car.id", "1"))
+	innerCtx, inner := Start(outerCtx, "inner")
+
+	assert.Equal(t, trace.SpanFromContext(innerCtx).SpanContext(), inner.span.SpanContext(),
+		"the copy carries the inner span")
+	assert.Equal(t, trace.SpanFromContext(outerCtx).SpanContext(), outer.span.SpanContext(),
+		"the copy carries the outer span")
+	assert.Equal(t, parent, ctx.Context, "ctx is left as it is")
+	assert.Equal(t, ctx.Gofr, innerCtx.Gofr)
+
+	inner.SetRows(3)
+	inner.End(errors.Error("db down"))
+	outer.End(nil)
+
+	spans := exporter.GetSpans()
+	if assert.Len(t, spans, 2) {
+		assert.Equal(t, "inner", spans[0].Name)
+		assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+		assert.Equal(t, spans[1].SpanContext.TraceID(), spans[0].SpanContext.TraceID())
+		assert.Equal(t, []attribute.KeyValue{RowsKey.Int(3)}, spans[0].Attributes)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+		assert.Equal(t, "db down", spans[0].Status.Description)
+		assert.Len(t, spans[0].Events, 1, "the error is recorded")
+
+		assert.Equal(t, "outer", spans[1].Name)
+		assert.False(t, spans[1].Parent.IsValid())
+		assert.Equal(t, []attribute.KeyValue{attribute.String("car.id", "1")}, spans[1].Attributes)
+		assert.Equal(t, codes.Unset, spans[1].Status.Code)
+	}
+}
+
+func TestStartWithoutContext(t *testing.T) {
+	exporter := newExporter()
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	ctx.Context = nil
+
+	c, span := Start(ctx, "span")
+	span.End(nil)
+
+	assert.Nil(t, ctx.Context)
+	assert.NotNil(t, c.Context)
+	assert.Len(t, exporter.GetSpans(), 1)
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		desc  string
+		query string
+		want  string
+	}{
+		{"placeholders", "SELECT id FROM Car WHERE ID=?;", "SELECT id FROM Car WHERE ID=?;"},
+		{"string literal", "SELECT id FROM Car WHERE brand='BMW' AND name = 'it''s'",
+			"SELECT id FROM Car WHERE brand=? AND name = ?"},
+		{"escaped quote", `SELECT id FROM Car WHERE name='a\'b'`, "SELECT id FROM Car WHERE name=?"},
+		{"numbers", "SELECT id FROM Car WHERE year>2019 AND cost_price<1.5 LIMIT 10",
+			"SELECT id FROM Car WHERE year>? AND cost_price<? LIMIT ?"},
+		{"identifiers with digits", "SELECT sha256 FROM v2_keys", "SELECT sha256 FROM v2_keys"},
+		{"in list", "SELECT id FROM Car WHERE id IN (?, ?,?)", "SELECT id FROM Car WHERE id IN (?)"},
+		{"in literals", "SELECT id FROM Car WHERE year in (2019,2020)", "SELECT id FROM Car WHERE year IN (?)"},
+		{"whitespace", "  UPDATE Car\n\tSET name=?  WHERE id=? ", "UPDATE Car SET name=? WHERE id=?"},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.want, Sanitize(tc.query), "TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT id FROM Car", "SELECT"},
+		{"\n insert INTO Car (id) VALUES(?)", "INSERT"},
+		{"", ""},
+	}
+
+	for i, tc := range tests {
+		assert.Equal(t, tc.want, Operation(tc.query), "TEST[%d], failed.\n%s", i, tc.query)
+	}
+}
@@ -0,0 +1,286 @@
+package webhooks
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+)
+
+const (
+	maxErrorLength = 1024
+	maxDrain       = 64 << 10
+)
+
+// Config tunes the dispatcher
+type Config struct {
+	// BatchSize is the number of deliveries claimed at a time
+	BatchSize int
+	// Workers is the number of deliveries of a batch sent at a time, one when it is not set
+	Workers int
+	// MaxAttempts is the number of attempts after which a delivery that keeps failing is dead
+	MaxAttempts int
+	// RetryBase is the wait after the first failed attempt, it doubles with every attempt up to RetryMax
+	RetryBase time.Duration
+	RetryMax  time.Duration
+	// Lease hides claimed deliveries from other dispatchers while they are sent, it must exceed the time a batch
+	// takes to be sent, the timeout of the client for every Workers deliveries of the batch. The deliveries of a
+	// dispatcher that stops mid batch are sent again once it expires.
+	Lease time.Duration
+}
+
+type dispatcher struct {
+	store  stores.Webhook
+	tx     stores.Transaction
+	client *http.Client
+	config Config
+	now    func() time.Time
+}
+
+// nolint:revive // need not be exported
+// NewDispatcher factory function
+func NewDispatcher(s stores.Webhook, tx stores.Transaction, client *http.Client, config Config) dispatcher {
+	return dispatcher{store: s, tx: tx, client: client, config: config, now: time.Now}
+}
+
+// Dispatch sends the due deliveries until none is left and returns how many were delivered. A failed attempt
+// is not an error, it is recorded on the delivery and retried later.
+func (d dispatcher) Dispatch(ctx *gofr.Context) (int, error) {
+	delivered := 0
+
+	for {
+		batch, err := d.claim(ctx)
+		if err != nil {
+			return delivered, err
+		}
+
+		n, err := d.send(ctx, batch)
+		delivered += n
+
+		if err != nil {
+			return delivered, err
+		}
+
+		if len(batch) < d.config.BatchSize {
+			return delivered, nil
+		}
+	}
+}
+
+// claim takes a batch of due deliveries and postpones them by the lease, so that they are sent outside of a
+// transaction without other dispatchers sending them too
+func (d dispatcher) claim(ctx *gofr.Context) ([]models.WebhookDelivery, error) {
+	var batch []models.WebhookDelivery
+
+	err := d.tx.WithTransaction(ctx, func(ctx *gofr.Context) error {
+		deliveries, err := d.store.GetDueDeliveries(ctx, d.config.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		leased := d.now().UTC().Add(d.config.Lease)
+
+		for i := range deliveries {
+			deliveries[i].NextAttemptAt = &leased
+
+			if err = d.store.UpdateDelivery(ctx, &deliveries[i]); err != nil {
+				return err
+			}
+		}
+
+		batch = deliveries
+
+		return nil
+	})
+
+	return batch, err
+}
+
+// target is the webhook the deliveries of a batch are sent to, or the error it could not be looked up with
+type target struct {
+	webhook models.Webhook
+	err     error
+}
+
+// send attempts the deliveries of a batch, Workers at a time, and returns how many were delivered. The first
+// error recording an outcome is returned once every attempt is done.
+func (d dispatcher) send(ctx *gofr.Context, batch []models.WebhookDelivery) (int, error) {
+	targets := d.targets(ctx, batch)
+	workers := make(chan struct{}, d.workers())
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		delivered int
+		firstErr  error
+	)
+
+	for i := range batch {
+		wg.Add(1)
+
+		workers <- struct{}{}
+
+		go func(delivery *models.WebhookDelivery) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			ok, err := d.attempt(ctx, delivery, targets[delivery.WebhookID])
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if ok {
+				delivered++
+			}
+
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}(&batch[i])
+	}
+
+	wg.Wait()
+
+	return delivered, firstErr
+}
+
+// targets looks up the webhooks of the deliveries of a batch, once each
+func (d dispatcher) targets(ctx *gofr.Context, batch []models.WebhookDelivery) map[uuid.UUID]target {
+	targets := make(map[uuid.UUID]target)
+
+	for i := range batch {
+		if _, ok := targets[batch[i].WebhookID]; !ok {
+			w, err := d.store.GetWebhookByID(ctx, batch[i].WebhookID.String())
+			targets[batch[i].WebhookID] = target{webhook: w, err: err}
+		}
+	}
+
+	return targets
+}
+
+func (d dispatcher) workers() int {
+	if d.config.Workers < 1 {
+		return 1
+	}
+
+	return d.config.Workers
+}
+
+// attempt posts a delivery to its webhook and records the outcome. The deliveries of a webhook deactivated since
+// they were recorded, or that could not be looked up, are dead, they can be retried later.
+func (d dispatcher) attempt(ctx *gofr.Context, delivery *models.WebhookDelivery, t target) (bool, error) {
+	var err error
+
+	switch {
+	case t.err != nil:
+		delivery.ResponseCode, err = 0, errors.Error("webhook lookup failed: "+t.err.Error())
+	case t.webhook.Active:
+		delivery.Attempts++
+		delivery.ResponseCode, err = d.post(ctx, &t.webhook, delivery)
+	default:
+		delivery.ResponseCode, err = 0, errors.Error("webhook is inactive")
+	}
+
+	now := d.now().UTC()
+
+	switch {
+	case err == nil:
+		delivery.Status, delivery.LastError, delivery.NextAttemptAt, delivery.DeliveredAt =
+			models.DeliveryDelivered, "", nil, &now
+	case t.err != nil || !t.webhook.Active || delivery.Attempts >= d.config.MaxAttempts:
+		delivery.Status, delivery.LastError, delivery.NextAttemptAt = models.DeliveryDead, truncate(err.Error()), nil
+	default:
+		next := now.Add(d.backoff(delivery.Attempts))
+		delivery.LastError, delivery.NextAttemptAt = truncate(err.Error()), &next
+	}
+
+	if err := d.store.UpdateDelivery(ctx, delivery); err != nil {
+		return false, err
+	}
+
+	return delivery.Status == models.DeliveryDelivered, nil
+}
+
+// post sends the payload of a delivery signed with the secret of the webhook, any 2xx response delivers it
+func (d dispatcher) post(ctx *gofr.Context, w *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := d.now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(w.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	// draining the body lets the connection be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrain))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, errors.Error("unexpected response " + resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff is the wait after the given number of failed attempts, RetryBase doubled for every attempt after
+// the first and capped at RetryMax
+func (d dispatcher) backoff(attempts int) time.Duration {
+	wait := d.config.RetryBase
+
+	for i := 1; i < attempts && wait < d.config.RetryMax; i++ {
+		wait *= 2
+	}
+
+	if wait > d.config.RetryMax {
+		return d.config.RetryMax
+	}
+
+	return wait
+}
+
+// Run dispatches the due deliveries every interval until the context of ctx is done
+func (d dispatcher) Run(ctx *gofr.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := d.Dispatch(ctx); err != nil {
+			ctx.Logger.Errorf("error in dispatching webhook deliveries, %v delivered: %v", n, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func truncate(s string) string {
+	if len(s) > maxErrorLength {
+		return s[:maxErrorLength]
+	}
+
+	return s
+}
@@ -0,0 +1,334 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var config = Config{BatchSize: 10, MaxAttempts: 3, RetryBase: time.Minute, RetryMax: 5 * time.Minute,
+	Lease: time.Minute}
+
+func runInTransaction(ctx *gofr.Context, fn func(ctx *gofr.Context) error) error {
+	return fn(ctx)
+}
+
+// receiver is a partner endpoint answering with status and keeping the requests it verified
+type receiver struct {
+	secret   string
+	status   int
+	received []*http.Request
+	bodies   [][]byte
+	invalid  int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	timestamp, _ := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+
+	if !Verify(r.secret, req.Header.Get(HeaderSignature), timestamp, body) {
+		r.invalid++
+	}
+
+	r.received = append(r.received, req)
+	r.bodies = append(r.bodies, body)
+
+	w.WriteHeader(r.status)
+}
+
+func TestDispatch(t *testing.T) {
+	now := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	leased := now.Add(config.Lease)
+	payload := json.RawMessage(`{"Type":"CarCreated"}`)
+
+	testCases := []struct {
+		desc      string
+		status    int
+		closed    bool
+		inactive  bool
+		attempts  int
+		delivered int
+		expected  func(d models.WebhookDelivery) models.WebhookDelivery
+	}{
+		{
+			desc: "delivered", status: http.StatusNoContent, delivered: 1,
+			expected: func(d models.WebhookDelivery) models.WebhookDelivery {
+				d.Status, d.Attempts, d.ResponseCode, d.NextAttemptAt, d.DeliveredAt =
+					models.DeliveryDelivered, 1, http.StatusNoContent, nil, &now
+				return d
+			},
+		},
+		{
+			desc: "retried with backoff", status: http.StatusServiceUnavailable, attempts: 1,
+			expected: func(d models.WebhookDelivery) models.WebhookDelivery {
+				next := now.Add(2 * time.Minute)
+				d.Attempts, d.ResponseCode, d.NextAttemptAt = 2, http.StatusServiceUnavailable, &next
+				d.LastError = "unexpected response 503 Service Unavailable"
+
+				return d
+			},
+		},
+		{
+			desc: "dead after the last attempt", status: http.StatusInternalServerError, attempts: 2,
+			expected: func(d models.WebhookDelivery) models.WebhookDelivery {
+				d.Status, d.Attempts, d.ResponseCode, d.NextAttemptAt = models.DeliveryDead, 3,
+					http.StatusInternalServerError, nil
+				d.LastError = "unexpected response 500 Internal Server Error"
+
+				return d
+			},
+		},
+		{
+			desc: "inactive webhook", status: http.StatusOK, inactive: true, attempts: 1,
+			expected: func(d models.WebhookDelivery) models.WebhookDelivery {
+				d.Status, d.NextAttemptAt, d.LastError = models.DeliveryDead, nil, "webhook is inactive"
+				return d
+			},
+		},
+		{
+			desc: "unreachable", closed: true,
+			expected: func(d models.WebhookDelivery) models.WebhookDelivery {
+				next := now.Add(time.Minute)
+				d.Attempts, d.NextAttemptAt = 1, &next
+
+				return d
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		ctrl := gomock.NewController(t)
+		mockStore := stores.NewMockWebhook(ctrl)
+		mockTx := stores.NewMockTransaction(ctrl)
+		ctx := gofr.NewContext(nil, nil, gofr.New())
+
+		r := &receiver{secret: "s3cret", status: tc.status}
+		server := httptest.NewServer(r)
+
+		if tc.closed {
+			server.Close()
+		}
+
+		webhook := models.Webhook{ID: uuid.New(), URL: server.URL + "/hooks", Active: !tc.inactive, Secret: "s3cret"}
+		delivery := models.WebhookDelivery{ID: uuid.New(), WebhookID: webhook.ID, EventID: uuid.New(),
+			EventType: models.EventCarCreated, Payload: payload, Status: models.DeliveryPending, Attempts: tc.attempts,
+			NextAttemptAt: &now, CreatedAt: now}
+
+		var updates []models.WebhookDelivery
+
+		mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+		mockStore.EXPECT().GetDueDeliveries(ctx, 10).Return([]models.WebhookDelivery{delivery}, nil)
+		mockStore.EXPECT().GetWebhookByID(ctx, webhook.ID.String()).Return(webhook, nil)
+		mockStore.EXPECT().UpdateDelivery(ctx, gomock.Any()).
+			DoAndReturn(func(_ *gofr.Context, d *models.WebhookDelivery) error {
+				updated := *d
+				if d.NextAttemptAt != nil {
+					next := *d.NextAttemptAt
+					updated.NextAttemptAt = &next
+				}
+
+				updates = append(updates, updated)
+
+				return nil
+			}).Times(2)
+
+		d := NewDispatcher(mockStore, mockTx, server.Client(), config)
+		d.now = func() time.Time { return now }
+
+		n, err := d.Dispatch(ctx)
+
+		assert.Equal(t, nil, err, "TEST[%d], failed.\n%s", i, tc.desc)
+		assert.Equal(t, tc.delivered, n, "TEST[%d], failed.\n%s", i, tc.desc)
+
+		claimed := delivery
+		claimed.NextAttemptAt = &leased
+
+		assert.Equal(t, claimed, updates[0], "TEST[%d], failed.\n%s", i, tc.desc)
+
+		// the error of a refused connection depends on the platform
+		expected := tc.expected(claimed)
+		if tc.closed {
+			assert.NotEmpty(t, updates[1].LastError, "TEST[%d], failed.\n%s", i, tc.desc)
+			expected.LastError = updates[1].LastError
+		}
+
+		assert.Equal(t, expected, updates[1], "TEST[%d], failed.\n%s", i, tc.desc)
+
+		if !tc.closed && !tc.inactive {
+			assert.Len(t, r.received, 1, "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, 0, r.invalid, "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, []byte(payload), r.bodies[0], "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, "/hooks", r.received[0].URL.Path, "TEST[%d], failed.\n%s", i, tc.desc)
+			assert.Equal(t, models.EventCarCreated, r.received[0].Header.Get(HeaderEvent))
+			assert.Equal(t, delivery.ID.String(), r.received[0].Header.Get(HeaderDelivery))
+			assert.Equal(t, strconv.FormatInt(now.Unix(), 10), r.received[0].Header.Get(HeaderTimestamp))
+		}
+
+		server.Close()
+		ctrl.Finish()
+	}
+}
+
+func TestDispatchErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := stores.NewMockWebhook(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+	d := NewDispatcher(mockStore, mockTx, http.DefaultClient, config)
+
+	delivery := models.WebhookDelivery{ID: uuid.New(), WebhookID: uuid.New(), Status: models.DeliveryPending}
+
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction).Times(3)
+	mockStore.EXPECT().GetDueDeliveries(ctx, 10).Return(nil, errors.Error("db down"))
+	mockStore.EXPECT().GetDueDeliveries(ctx, 10).Return([]models.WebhookDelivery{delivery}, nil)
+	mockStore.EXPECT().UpdateDelivery(ctx, gomock.Any()).Return(errors.Error("db down"))
+	mockStore.EXPECT().GetDueDeliveries(ctx, 10).Return([]models.WebhookDelivery{delivery}, nil)
+	mockStore.EXPECT().UpdateDelivery(ctx, gomock.Any()).Return(nil)
+	mockStore.EXPECT().GetWebhookByID(ctx, delivery.WebhookID.String()).Return(models.Webhook{Active: true}, nil)
+	mockStore.EXPECT().UpdateDelivery(ctx, gomock.Any()).Return(errors.Error("db down"))
+
+	for i := 0; i < 3; i++ {
+		n, err := d.Dispatch(ctx)
+
+		assert.Equal(t, errors.Error("db down"), err, "TEST[%d], failed.", i)
+		assert.Equal(t, 0, n, "TEST[%d], failed.", i)
+	}
+}
+
+// TestDispatchLookupError tests that the deliveries of a webhook that cannot be looked up are dead while the
+// other deliveries of the batch are still sent
+func TestDispatchLookupError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := stores.NewMockWebhook(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	server := httptest.NewServer(&receiver{secret: "s3cret", status: http.StatusOK})
+	defer server.Close()
+
+	d := NewDispatcher(mockStore, mockTx, server.Client(), config)
+
+	webhook := models.Webhook{ID: uuid.New(), URL: server.URL, Active: true, Secret: "s3cret"}
+	lost := models.WebhookDelivery{ID: uuid.New(), WebhookID: uuid.New(), Status: models.DeliveryPending}
+	sent := models.WebhookDelivery{ID: uuid.New(), WebhookID: webhook.ID, Status: models.DeliveryPending}
+
+	var mu sync.Mutex
+
+	updates := make(map[uuid.UUID]models.WebhookDelivery)
+
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+	mockStore.EXPECT().GetDueDeliveries(ctx, 10).Return([]models.WebhookDelivery{lost, sent}, nil)
+	mockStore.EXPECT().GetWebhookByID(ctx, lost.WebhookID.String()).Return(models.Webhook{}, errors.Error("db down"))
+	mockStore.EXPECT().GetWebhookByID(ctx, webhook.ID.String()).Return(webhook, nil)
+	mockStore.EXPECT().UpdateDelivery(ctx, gomock.Any()).
+		DoAndReturn(func(_ *gofr.Context, d *models.WebhookDelivery) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			updates[d.ID] = *d
+
+			return nil
+		}).Times(4)
+
+	n, err := d.Dispatch(ctx)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, models.DeliveryDead, updates[lost.ID].Status)
+	assert.Equal(t, "webhook lookup failed: db down", updates[lost.ID].LastError)
+	assert.Equal(t, 0, updates[lost.ID].Attempts)
+	assert.Equal(t, models.DeliveryDelivered, updates[sent.ID].Status)
+}
+
+// TestDispatchWorkers tests that the deliveries of a batch are sent Workers at a time
+func TestDispatchWorkers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := stores.NewMockWebhook(ctrl)
+	mockTx := stores.NewMockTransaction(ctrl)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	var (
+		mu        sync.Mutex
+		inFlight  int
+		maxFlight int
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxFlight {
+			maxFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(100 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := config
+	c.BatchSize, c.Workers = 6, 3
+	d := NewDispatcher(mockStore, mockTx, server.Client(), c)
+
+	webhook := models.Webhook{ID: uuid.New(), URL: server.URL, Active: true, Secret: "s3cret"}
+	batch := make([]models.WebhookDelivery, 5)
+
+	for i := range batch {
+		batch[i] = models.WebhookDelivery{ID: uuid.New(), WebhookID: webhook.ID, Status: models.DeliveryPending}
+	}
+
+	mockTx.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(runInTransaction)
+	mockStore.EXPECT().GetDueDeliveries(ctx, 6).Return(batch, nil)
+	mockStore.EXPECT().GetWebhookByID(ctx, webhook.ID.String()).Return(webhook, nil)
+	mockStore.EXPECT().UpdateDelivery(ctx, gomock.Any()).Return(nil).Times(10)
+
+	n, err := d.Dispatch(ctx)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, 3, maxFlight)
+}
+
+func TestBackoff(t *testing.T) {
+	d := NewDispatcher(nil, nil, nil, config)
+
+	testCases := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: time.Minute},
+		{attempts: 2, expected: 2 * time.Minute},
+		{attempts: 3, expected: 4 * time.Minute},
+		{attempts: 4, expected: 5 * time.Minute},
+		{attempts: 100, expected: 5 * time.Minute},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.expected, d.backoff(tc.attempts), "TEST[%d], failed.", i)
+	}
+}
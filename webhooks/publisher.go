@@ -0,0 +1,68 @@
+package webhooks
+
+import (
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+	"encoding/json"
+	"time"
+
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/google/uuid"
+)
+
+type publisher struct {
+	store stores.Webhook
+	now   func() time.Time
+}
+
+// nolint:revive // need not be exported
+// NewPublisher factory function
+func NewPublisher(s stores.Webhook) publisher {
+	return publisher{store: s, now: time.Now}
+}
+
+// Publish records a delivery of the event, due at once, for every active webhook subscribed to its type. Run by
+// the event relay, the deliveries are recorded in the transaction marking the event published.
+func (p publisher) Publish(ctx *gofr.Context, event models.Event) error {
+	webhooks, err := p.store.GetWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := p.now().UTC()
+
+	for i := range webhooks {
+		if !subscribes(&webhooks[i], event.Type) {
+			continue
+		}
+
+		err = p.store.CreateDelivery(ctx, &models.WebhookDelivery{ID: uuid.New(), WebhookID: webhooks[i].ID,
+			EventID: event.ID, EventType: event.Type, Payload: payload, Status: models.DeliveryPending,
+			NextAttemptAt: &now, CreatedAt: now})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// subscribes reports whether the webhook is active and receives events of the type
+func subscribes(w *models.Webhook, eventType string) bool {
+	if !w.Active {
+		return false
+	}
+
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+
+	return len(w.Events) == 0
+}
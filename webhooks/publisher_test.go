@@ -0,0 +1,67 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"Project/CarDealearship/models"
+	"Project/CarDealearship/stores"
+
+	"developer.zopsmart.com/go/gofr/pkg/errors"
+	"developer.zopsmart.com/go/gofr/pkg/gofr"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := stores.NewMockWebhook(ctrl)
+	ctx := gofr.NewContext(nil, nil, gofr.New())
+
+	now := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	p := NewPublisher(mockStore)
+	p.now = func() time.Time { return now }
+
+	event := models.Event{ID: uuid.New(), Type: models.EventCarDeleted, CarID: uuid.New(), OccurredAt: now,
+		Data: json.RawMessage(`{"Name":"X5"}`)}
+	payload, _ := json.Marshal(event)
+
+	all := models.Webhook{ID: uuid.New(), Active: true}
+	deletes := models.Webhook{ID: uuid.New(), Active: true, Events: []string{models.EventCarCreated,
+		models.EventCarDeleted}}
+	creates := models.Webhook{ID: uuid.New(), Active: true, Events: []string{models.EventCarCreated}}
+	inactive := models.Webhook{ID: uuid.New()}
+
+	var recorded []models.WebhookDelivery
+
+	record := func(_ *gofr.Context, d *models.WebhookDelivery) error {
+		recorded = append(recorded, *d)
+		return nil
+	}
+
+	mockStore.EXPECT().GetWebhooks(ctx).Return([]models.Webhook{all, deletes, creates, inactive}, nil)
+	mockStore.EXPECT().CreateDelivery(ctx, gomock.Any()).DoAndReturn(record).Times(2)
+
+	err := p.Publish(ctx, event)
+
+	assert.Equal(t, nil, err)
+	assert.Len(t, recorded, 2)
+
+	for i, webhookID := range []uuid.UUID{all.ID, deletes.ID} {
+		assert.NotEqual(t, uuid.Nil, recorded[i].ID)
+		assert.Equal(t, models.WebhookDelivery{ID: recorded[i].ID, WebhookID: webhookID, EventID: event.ID,
+			EventType: models.EventCarDeleted, Payload: payload, Status: models.DeliveryPending, NextAttemptAt: &now,
+			CreatedAt: now}, recorded[i])
+	}
+
+	mockStore.EXPECT().GetWebhooks(ctx).Return(nil, errors.Error("db down"))
+	assert.Equal(t, errors.Error("db down"), p.Publish(ctx, event))
+
+	mockStore.EXPECT().GetWebhooks(ctx).Return([]models.Webhook{all}, nil)
+	mockStore.EXPECT().CreateDelivery(ctx, gomock.Any()).Return(errors.Error("db down"))
+	assert.Equal(t, errors.Error("db down"), p.Publish(ctx, event))
+}
@@ -0,0 +1,37 @@
+// Package webhooks delivers the car events to the webhooks of partners. The publisher records a delivery of
+// every event for the webhooks subscribed to it and the dispatcher posts them, retrying failed ones with an
+// exponential backoff until they are delivered or run out of attempts and are dead.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Headers of a delivery
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+const signaturePrefix = "sha256="
+
+// Sign returns the signature of a body sent at timestamp, in unix seconds, with the secret of a webhook. It is
+// the hex HMAC-SHA256 of the timestamp and the body joined by a dot, prefixed with sha256=. Signing the
+// timestamp lets receivers reject replayed deliveries.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	_, _ = mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the one of the body sent at timestamp, as a receiver checks it
+func Verify(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
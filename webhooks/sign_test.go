@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"Type":"CarCreated"}`)
+
+	// printf '1646128800.{"Type":"CarCreated"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=f90fcbc0a8529a574bdf196a4c2ff246e47b491e0a98d8647eb84c544c96f4e7",
+		Sign("secret", 1646128800, body))
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"Type":"CarCreated"}`)
+	signature := Sign("secret", 1646128800, body)
+
+	testCases := []struct {
+		desc      string
+		secret    string
+		timestamp int64
+		body      []byte
+		expected  bool
+	}{
+		{desc: "valid", secret: "secret", timestamp: 1646128800, body: body, expected: true},
+		{desc: "other secret", secret: "other", timestamp: 1646128800, body: body},
+		{desc: "replayed at another time", secret: "secret", timestamp: 1646128801, body: body},
+		{desc: "tampered body", secret: "secret", timestamp: 1646128800, body: []byte(`{"Type":"CarDeleted"}`)},
+	}
+
+	for i, tc := range testCases {
+		assert.Equal(t, tc.expected, Verify(tc.secret, signature, tc.timestamp, tc.body),
+			"TEST[%d], failed.\n%s", i, tc.desc)
+	}
+}